@@ -1,17 +1,28 @@
 package app
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/vovk404/course-platform/application-api/config"
+	"github.com/vovk404/course-platform/application-api/fonts"
 	controller "github.com/vovk404/course-platform/application-api/internal/controller/http"
-	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/internal/service"
 	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/locales"
+	"github.com/vovk404/course-platform/application-api/migrations"
 	"github.com/vovk404/course-platform/application-api/pkg/auth"
+	"github.com/vovk404/course-platform/application-api/pkg/blob"
 	"github.com/vovk404/course-platform/application-api/pkg/database"
 	"github.com/vovk404/course-platform/application-api/pkg/hash"
 	"github.com/vovk404/course-platform/application-api/pkg/httpserver"
+	"github.com/vovk404/course-platform/application-api/pkg/i18n"
 	"github.com/vovk404/course-platform/application-api/pkg/logger"
+	"github.com/vovk404/course-platform/application-api/pkg/mailer"
+	"github.com/vovk404/course-platform/application-api/pkg/migrate"
+	"github.com/vovk404/course-platform/application-api/pkg/oidc"
+	"github.com/vovk404/course-platform/application-api/pkg/payment"
+	"github.com/vovk404/course-platform/application-api/pkg/pdf"
+	"github.com/vovk404/course-platform/application-api/pkg/ratelimit"
 	"os"
 	"os/signal"
 	"syscall"
@@ -25,52 +36,64 @@ func Run(cfg *config.Config) {
 	time.Sleep(2 * time.Second)
 	sql := connectToDB(cfg, log)
 
-	err := sql.DB.AutoMigrate(
-		&entity.User{},
-		&entity.Course{},
-		&entity.Account{},
-		&entity.AccountDevices{},
-		&entity.AccountSettings{},
-	)
-	if err != nil {
-		log.Fatal("automigration failed", "err", err)
+	if cfg.PostgreSQL.MigrateOnStart {
+		migrateDB(sql, log)
 	}
 
-	//TODO add foreign keys on courses.teacher_id
-
-	storages := storage.Storages{
-		UserStorage:    storage.NewUserStorage(sql),
-		AccountStorage: storage.NewAccountStorage(sql),
-		NodeStorage:    storage.NewNodeStorage(sql),
-		CourseStorage:  storage.NewCourseStorage(sql),
-	}
+	storages := storage.NewStorages(sql)
 
 	databases := map[string]database.Database{
 		"postgreSQL": sql,
 	}
 
+	authenticator, err := auth.NewAuth(auth.Config{KeyFiles: cfg.JWT.KeyFiles, TTL: cfg.JWT.TTL})
+	if err != nil {
+		log.Fatal("failed to create authenticator", "err", err)
+	}
+	certificateSigner, err := auth.NewSigner(cfg.Certificate.KeyFiles)
+	if err != nil {
+		log.Fatal("failed to create certificate signer", "err", err)
+	}
+
 	serviceOptions := &service.Options{
-		Storages: &storages,
-		Config:   cfg,
-		Logger:   log,
-		Hash:     hash.NewHash(),
-		Auth:     auth.NewAuth(),
+		Storages:         &storages,
+		Config:           cfg,
+		Logger:           log,
+		Hash:             hash.NewHash(),
+		Auth:             authenticator,
+		Signer:           certificateSigner,
+		Mailer:           newMailer(cfg),
+		OIDC:             newOIDCProvider(cfg),
+		Blob:             blob.NewFile(cfg.Blob.Dir),
+		Payment:          newPaymentProvider(cfg, log),
+		CertificateFonts: newCertificateFonts(log),
 	}
 
-	services := service.Services{
-		AuthService:    service.NewAuthService(serviceOptions),
-		AccountService: service.NewAccountService(serviceOptions),
-		NodeService:    service.NewNodeService(serviceOptions),
-		CourseService:  service.NewCourseService(serviceOptions),
+	services := service.NewServices(serviceOptions)
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	go runPeriodically(jobsCtx, log, "notify price drops", cfg.Wishlist.PriceCheckInterval, services.WishlistService.NotifyPriceDrops)
+	go runPeriodically(jobsCtx, log, "reconcile pending orders", cfg.Payment.ReconcileInterval, services.OrderService.ReconcilePendingOrders)
+	go runPeriodically(jobsCtx, log, "retry pending refunds", cfg.Refund.RetryInterval, services.RefundService.RetryPendingRefunds)
+
+	translator, err := i18n.New(locales.FS, "en")
+	if err != nil {
+		log.Fatal("failed to read locales", "err", err)
 	}
 
 	httpHandler := gin.New()
+	err = httpHandler.SetTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		log.Fatal("failed to set trusted proxies", "err", err)
+	}
 
 	controller.New(&controller.Options{
-		Handler:  httpHandler,
-		Services: services,
-		Logger:   log,
-		Config:   cfg,
+		Handler:     httpHandler,
+		Services:    services,
+		Logger:      log,
+		Config:      cfg,
+		RateLimiter: newRateLimiter(cfg, sql),
+		Translator:  translator,
 	})
 
 	httpServer := httpserver.New(
@@ -89,7 +112,7 @@ func Run(cfg *config.Config) {
 	case s := <-interrupt:
 		log.Info("app - Run - signal: " + s.String())
 
-	case err = <-httpServer.Notify():
+	case err := <-httpServer.Notify():
 		log.Error("app - Run - httpServer.Notify", "err", err)
 	}
 
@@ -99,6 +122,8 @@ func Run(cfg *config.Config) {
 		log.Error("app - Run - httpServer.Shutdown", "err", err)
 	}
 
+	stopJobs()
+
 	for _, db := range databases {
 		err = db.Close()
 		if err != nil {
@@ -107,6 +132,124 @@ func Run(cfg *config.Config) {
 	}
 }
 
+// migrateDB applies pending migrations, replicas wait for each other on the advisory lock.
+func migrateDB(sql *database.PostgreSQL, log logger.Logger) {
+	db, err := sql.DB.DB()
+	if err != nil {
+		log.Fatal("failed to get database handle", "err", err)
+	}
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatal("failed to read migrations", "err", err)
+	}
+
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		log.Info("applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	if err != nil {
+		log.Fatal("migration failed", "err", err)
+	}
+}
+
+// runPeriodically runs background job every interval until ctx is done, zero interval disables the job.
+// Every replica runs its jobs, so jobs have to be safe to run concurrently.
+func runPeriodically(ctx context.Context, log logger.Logger, name string, interval time.Duration, job func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Info("background job is disabled", "job", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := job(ctx)
+			if err != nil {
+				log.Error("background job failed", "job", name, "err", err)
+			}
+		}
+	}
+}
+
+// newMailer creates mailer configured by MAIL_DRIVER.
+func newMailer(cfg *config.Config) mailer.Mailer {
+	if cfg.Mail.Driver == "smtp" {
+		return mailer.NewSMTP(mailer.SMTPConfig{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			User:     cfg.Mail.SMTPUser,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+		})
+	}
+
+	return mailer.NewFile(cfg.Mail.From, cfg.Mail.FilePath)
+}
+
+// newPaymentProvider creates payment provider. Fake one keeps charges in memory, so orders interrupted
+// before restart are failed by reconciliation, it is meant for development only.
+func newPaymentProvider(cfg *config.Config, log logger.Logger) payment.Provider {
+	switch cfg.Payment.Driver {
+	case "stripe":
+		if cfg.Payment.StripeSecretKey == "" || cfg.Payment.StripeProductId == "" {
+			log.Fatal("stripe payment driver requires secret key and product id")
+		}
+		return payment.NewStripe(payment.StripeConfig{
+			SecretKey:             cfg.Payment.StripeSecretKey,
+			WebhookSecret:         cfg.Payment.WebhookSecret,
+			SubscriptionProductId: cfg.Payment.StripeProductId,
+		})
+	case "fake":
+		log.Warn("fake payment driver doesn't move money, use it for development only")
+		return payment.NewFake(cfg.Payment.WebhookSecret)
+	default:
+		log.Fatal("unsupported payment driver", "driver", cfg.Payment.Driver)
+		return nil
+	}
+}
+
+// newCertificateFonts parses embedded fonts of certificates.
+func newCertificateFonts(log logger.Logger) *service.CertificateFonts {
+	regular, err := pdf.ParseTrueType(fonts.DejaVuSans)
+	if err != nil {
+		log.Fatal("failed to parse certificate font", "err", err)
+	}
+	bold, err := pdf.ParseTrueType(fonts.DejaVuSansBold)
+	if err != nil {
+		log.Fatal("failed to parse certificate font", "err", err)
+	}
+
+	return &service.CertificateFonts{Regular: regular, Bold: bold}
+}
+
+// newOIDCProvider creates OpenID provider client, nil disables social login.
+func newOIDCProvider(cfg *config.Config) *oidc.Provider {
+	if cfg.OIDC.IssuerURL == "" {
+		return nil
+	}
+
+	return oidc.New(oidc.Config{
+		IssuerURL:    cfg.OIDC.IssuerURL,
+		ClientID:     cfg.OIDC.ClientID,
+		ClientSecret: cfg.OIDC.ClientSecret,
+		RedirectURL:  cfg.OIDC.RedirectURL,
+	})
+}
+
+// newRateLimiter creates rate limiter backend configured by RATE_LIMIT_BACKEND.
+func newRateLimiter(cfg *config.Config, sql *database.PostgreSQL) ratelimit.Limiter {
+	if cfg.RateLimit.Backend == "postgresql" {
+		return ratelimit.NewPostgreSQL(sql)
+	}
+
+	return ratelimit.NewMemory()
+}
+
 // Try to connect to Postgress 10 times before throwing an error, postgress starting after the application-api.
 func connectToDB(cfg *config.Config, logger logger.Logger) *database.PostgreSQL {
 	var counts int
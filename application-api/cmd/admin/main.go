@@ -0,0 +1,245 @@
+// Package main provides admin command line tool for operational tasks.
+//
+// Usage:
+//
+//	admin create-admin -email <email> -username <name> [-password <password>]
+//	admin reset-password -email <email> [-password <password>]
+//	admin promote-teacher -email <email>
+//	admin list-courses
+//	admin unpublish-course -id <course id>
+//	admin export [-out file] [-passwords]
+//	admin import [-in file]
+//
+// Passwords are read from stdin when not passed as flags. Export leaves password hashes out unless -passwords
+// is passed, users imported without them keep their current passwords.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vovk404/course-platform/application-api/config"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/auth"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"github.com/vovk404/course-platform/application-api/pkg/hash"
+	"github.com/vovk404/course-platform/application-api/pkg/logger"
+)
+
+const usage = `usage: admin <command> [flags]
+
+commands:
+  create-admin      create user with admin privileges
+  reset-password    set new password for user
+  promote-teacher   change student to teacher
+  list-courses      print all courses as JSON
+  unpublish-course  hide course from public list
+  export            dump users and courses as JSON
+  import            create or overwrite users and courses from JSON dump`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	cfg := config.Get()
+	log := logger.New(cfg.Log.Level)
+
+	sql, err := database.NewPostgreSQL(database.PostgreSQLConfig{
+		User:     cfg.PostgreSQL.User,
+		Password: cfg.PostgreSQL.Password,
+		Host:     cfg.PostgreSQL.Host,
+		Database: cfg.PostgreSQL.Database,
+		Port:     cfg.PostgreSQL.Port,
+	})
+	if err != nil {
+		log.Fatal("failed to connect to database", "err", err)
+	}
+	defer sql.Close()
+
+	authenticator, err := auth.NewAuth(auth.Config{KeyFiles: cfg.JWT.KeyFiles, TTL: cfg.JWT.TTL})
+	if err != nil {
+		sql.Close()
+		log.Fatal("failed to create authenticator", "err", err)
+	}
+
+	storages := storage.NewStorages(sql)
+	services := service.NewServices(&service.Options{
+		Storages: &storages,
+		Config:   cfg,
+		Logger:   log,
+		Hash:     hash.NewHash(),
+		Auth:     authenticator,
+	})
+
+	err = run(context.Background(), services.AdminService, command, args)
+	if err != nil {
+		sql.Close()
+		log.Fatal(command+" failed", "err", err)
+	}
+}
+
+func run(ctx context.Context, admin service.AdminService, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+
+	switch command {
+	case "create-admin":
+		email := flags.String("email", "", "admin email")
+		username := flags.String("username", "", "admin username")
+		password := flags.String("password", "", "admin password")
+		flags.Parse(args)
+		if *email == "" || *username == "" {
+			return fmt.Errorf("email and username are required")
+		}
+		*password = readPassword(*password)
+		if *password == "" {
+			return fmt.Errorf("password is required")
+		}
+
+		user, err := admin.CreateAdmin(ctx, &service.CreateAdminOptions{
+			Email:    *email,
+			Username: *username,
+			Password: *password,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println("created admin", user.Id)
+
+	case "reset-password":
+		email := flags.String("email", "", "user email")
+		password := flags.String("password", "", "new password")
+		flags.Parse(args)
+		if *email == "" {
+			return fmt.Errorf("email is required")
+		}
+		*password = readPassword(*password)
+		if *password == "" {
+			return fmt.Errorf("password is required")
+		}
+
+		err := admin.ResetPassword(ctx, &service.ResetPasswordOptions{
+			Email:    *email,
+			Password: *password,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println("password reset for", *email)
+
+	case "promote-teacher":
+		email := flags.String("email", "", "user email")
+		flags.Parse(args)
+		if *email == "" {
+			return fmt.Errorf("email is required")
+		}
+
+		user, err := admin.PromoteToTeacher(ctx, *email)
+		if err != nil {
+			return err
+		}
+		fmt.Println("promoted to teacher", user.Id)
+
+	case "list-courses":
+		flags.Parse(args)
+
+		courses, err := admin.ListCourses(ctx)
+		if err != nil {
+			return err
+		}
+		return writeJSON(os.Stdout, courses)
+
+	case "unpublish-course":
+		id := flags.String("id", "", "course id")
+		flags.Parse(args)
+		if *id == "" {
+			return fmt.Errorf("id is required")
+		}
+
+		course, err := admin.UnpublishCourse(ctx, *id)
+		if err != nil {
+			return err
+		}
+		fmt.Println("unpublished course", course.Id)
+
+	case "export":
+		out := flags.String("out", "", "output file, stdout by default")
+		passwords := flags.Bool("passwords", false, "include password hashes")
+		flags.Parse(args)
+
+		data, err := admin.Export(ctx, &service.ExportOptions{WithPasswords: *passwords})
+		if err != nil {
+			return err
+		}
+
+		w := io.Writer(os.Stdout)
+		if *out != "" {
+			file, err := os.Create(*out)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer file.Close()
+			w = file
+		}
+		return writeJSON(w, data)
+
+	case "import":
+		in := flags.String("in", "", "input file, stdin by default")
+		flags.Parse(args)
+
+		r := io.Reader(os.Stdin)
+		if *in != "" {
+			file, err := os.Open(*in)
+			if err != nil {
+				return fmt.Errorf("failed to open input file: %w", err)
+			}
+			defer file.Close()
+			r = file
+		}
+
+		var data service.ExportData
+		err := json.NewDecoder(r).Decode(&data)
+		if err != nil {
+			return fmt.Errorf("failed to decode input: %w", err)
+		}
+
+		imported, err := admin.Import(ctx, &data)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("imported %d users and %d courses\n", imported.Users, imported.Courses)
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	return nil
+}
+
+// readPassword returns given password or reads one line from stdin,
+// so passwords don't have to end up in shell history.
+func readPassword(password string) string {
+	if password != "" {
+		return password
+	}
+
+	fmt.Fprint(os.Stderr, "password: ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,132 @@
+// Package main runs database migrations.
+//
+// Usage:
+//
+//	migrate up
+//	migrate down [steps]
+//	migrate status
+//	migrate create <name>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vovk404/course-platform/application-api/config"
+	"github.com/vovk404/course-platform/application-api/migrations"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"github.com/vovk404/course-platform/application-api/pkg/logger"
+	"github.com/vovk404/course-platform/application-api/pkg/migrate"
+)
+
+func main() {
+	dir := flag.String("dir", "migrations", "directory new migrations are created in")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate [-dir path] up | down [steps] | status | create <name>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.Get()
+	log := logger.New(cfg.Log.Level).Named("migrate")
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+
+	// create works with source files only, so no database is needed
+	if command == "create" {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		upPath, downPath, err := migrate.Create(*dir, flag.Arg(1))
+		if err != nil {
+			log.Fatal("failed to create migration", "err", err)
+		}
+		fmt.Println(upPath)
+		fmt.Println(downPath)
+		return
+	}
+
+	sql, err := database.NewPostgreSQL(database.PostgreSQLConfig{
+		User:     cfg.PostgreSQL.User,
+		Password: cfg.PostgreSQL.Password,
+		Host:     cfg.PostgreSQL.Host,
+		Database: cfg.PostgreSQL.Database,
+		Port:     cfg.PostgreSQL.Port,
+	})
+	if err != nil {
+		log.Fatal("failed to connect to database", "err", err)
+	}
+	defer sql.Close()
+
+	db, err := sql.DB.DB()
+	if err != nil {
+		log.Fatal("failed to get database handle", "err", err)
+	}
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatal("failed to read migrations", "err", err)
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %06d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal("migration failed", "err", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if flag.NArg() > 1 {
+			steps, err = strconv.Atoi(flag.Arg(1))
+			if err != nil || steps < 1 {
+				log.Fatal("steps must be a positive number", "steps", flag.Arg(1))
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %06d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal("migration failed", "err", err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("failed to get migrations status", "err", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
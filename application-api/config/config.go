@@ -7,14 +7,32 @@ import (
 	"log"
 	"reflect"
 	"sync"
+	"time"
 )
 
 type (
 	Config struct {
-		App        App
-		HTTP       HTTP
-		Log        Log
-		PostgreSQL PostgreSQL
+		App         App
+		HTTP        HTTP
+		Log         Log
+		PostgreSQL  PostgreSQL
+		Mail        Mail
+		Token       Token
+		Login       Login
+		RateLimit   RateLimit
+		TwoFactor   TwoFactor
+		OIDC        OIDC
+		JWT         JWT
+		APIKey      APIKey
+		Progress    Progress
+		Certificate Certificate
+		Quiz        Quiz
+		Blob        Blob
+		Assignment  Assignment
+		Payment     Payment
+		Refund      Refund
+		Wishlist    Wishlist
+		Subtitle    Subtitle
 	}
 
 	// App - represent application configuration.
@@ -23,8 +41,11 @@ type (
 	}
 
 	// HTTP - represents http configuration.
+	// Client IP is read from X-Forwarded-For only behind TrustedProxies, IPs or CIDRs of load balancers,
+	// otherwise it is address of the connection, so clients can't choose their IP for rate limits.
 	HTTP struct {
-		Port string `env:"HTTP_PORT" env-default:"8083"`
+		Port           string   `env:"HTTP_PORT"            env-default:"8083"`
+		TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 	}
 
 	// Log - represents logger configuration.
@@ -34,16 +55,154 @@ type (
 
 	// PostgreSQL - represents PostgreSQL database configuration.
 	PostgreSQL struct {
-		User     string `env:"POSTGRESQL_USER"     env-default:"postgres"`
-		Password string `env:"POSTGRESQL_PASSWORD" env-default:"postgres"`
-		Host     string `env:"POSTGRESQL_HOST"     env-default:"127.0.0.1"`
-		Database string `env:"POSTGRESQL_DATABASE" env-default:"api"`
-		Port     string `env:"POSTGRESQL_PORT"     env-default:"5432"`
+		User           string `env:"POSTGRESQL_USER"             env-default:"postgres"`
+		Password       string `env:"POSTGRESQL_PASSWORD"         env-default:"postgres"`
+		Host           string `env:"POSTGRESQL_HOST"             env-default:"127.0.0.1"`
+		Database       string `env:"POSTGRESQL_DATABASE"         env-default:"api"`
+		Port           string `env:"POSTGRESQL_PORT"             env-default:"5432"`
+		MigrateOnStart bool   `env:"POSTGRESQL_MIGRATE_ON_START" env-default:"true"`
 	}
 
-	// JWT - represents jwt configuration.
+	// Mail - represents outgoing email configuration.
+	Mail struct {
+		// Driver is either "smtp" or "file", file driver writes emails to FilePath or stdout.
+		Driver       string `env:"MAIL_DRIVER"        env-default:"file"`
+		From         string `env:"MAIL_FROM"          env-default:"no-reply@course-platform.local"`
+		FilePath     string `env:"MAIL_FILE_PATH"`
+		SMTPHost     string `env:"MAIL_SMTP_HOST"     env-default:"127.0.0.1"`
+		SMTPPort     string `env:"MAIL_SMTP_PORT"     env-default:"25"`
+		SMTPUser     string `env:"MAIL_SMTP_USER"`
+		SMTPPassword string `env:"MAIL_SMTP_PASSWORD"`
+	}
+
+	// Token - represents one-time token configuration.
+	Token struct {
+		VerifyEmailTTL   time.Duration `env:"TOKEN_VERIFY_EMAIL_TTL"   env-default:"48h"`
+		ResetPasswordTTL time.Duration `env:"TOKEN_RESET_PASSWORD_TTL" env-default:"1h"`
+	}
+
+	// Login - represents sign in brute-force protection configuration.
+	// Every failed attempt delays the next one exponentially from BaseDelay up to MaxDelay,
+	// reaching max failures within FailureWindow locks sign in for LockoutDuration.
+	Login struct {
+		MaxAccountFailures int           `env:"LOGIN_MAX_ACCOUNT_FAILURES" env-default:"5"`
+		MaxIPFailures      int           `env:"LOGIN_MAX_IP_FAILURES"      env-default:"20"`
+		FailureWindow      time.Duration `env:"LOGIN_FAILURE_WINDOW"       env-default:"15m"`
+		LockoutDuration    time.Duration `env:"LOGIN_LOCKOUT_DURATION"     env-default:"15m"`
+		BaseDelay          time.Duration `env:"LOGIN_BASE_DELAY"           env-default:"1s"`
+		MaxDelay           time.Duration `env:"LOGIN_MAX_DELAY"            env-default:"30s"`
+	}
+
+	// RateLimit - represents rate limiting configuration, rates are in requests per second.
+	// Backend is either "memory" (limits per replica) or "postgresql" (limits shared by replicas).
+	RateLimit struct {
+		Enabled      bool    `env:"RATE_LIMIT_ENABLED"       env-default:"true"`
+		Backend      string  `env:"RATE_LIMIT_BACKEND"       env-default:"memory"`
+		DefaultRate  float64 `env:"RATE_LIMIT_DEFAULT_RATE"  env-default:"10"`
+		DefaultBurst int     `env:"RATE_LIMIT_DEFAULT_BURST" env-default:"40"`
+		AuthRate     float64 `env:"RATE_LIMIT_AUTH_RATE"     env-default:"0.2"`
+		AuthBurst    int     `env:"RATE_LIMIT_AUTH_BURST"    env-default:"10"`
+	}
+
+	// TwoFactor - represents TOTP two-factor authentication configuration.
+	TwoFactor struct {
+		Issuer        string        `env:"TWO_FACTOR_ISSUER"         env-default:"Course Platform"`
+		ChallengeTTL  time.Duration `env:"TWO_FACTOR_CHALLENGE_TTL"  env-default:"5m"`
+		RecoveryCodes int           `env:"TWO_FACTOR_RECOVERY_CODES" env-default:"10"`
+	}
+
+	// OIDC - represents OpenID Connect social login configuration, login is disabled when IssuerURL is empty.
+	OIDC struct {
+		IssuerURL    string        `env:"OIDC_ISSUER_URL"`
+		ClientID     string        `env:"OIDC_CLIENT_ID"`
+		ClientSecret string        `env:"OIDC_CLIENT_SECRET"`
+		RedirectURL  string        `env:"OIDC_REDIRECT_URL"  env-default:"http://localhost:8083/api/v1/auth/oidc/callback"`
+		StateTTL     time.Duration `env:"OIDC_STATE_TTL"     env-default:"10m"`
+	}
+
+	// APIKey - represents personal API keys configuration.
+	// Last used time is updated at most once per UsageInterval to keep frequent requests cheap.
+	APIKey struct {
+		MaxPerUser    int           `env:"API_KEY_MAX_PER_USER"   env-default:"20"`
+		UsageInterval time.Duration `env:"API_KEY_USAGE_INTERVAL" env-default:"1m"`
+	}
+
+	// Progress - represents lesson progress configuration.
+	// Lesson is completed once CompletionRatio of its duration is watched. Watched time reported by player grows
+	// no faster than MaxPlaybackSpeed times time between heartbeats, the first heartbeat adds FirstHeartbeat at most.
+	Progress struct {
+		CompletionRatio  float64       `env:"PROGRESS_COMPLETION_RATIO"   env-default:"0.9"`
+		MaxPlaybackSpeed float64       `env:"PROGRESS_MAX_PLAYBACK_SPEED" env-default:"2"`
+		FirstHeartbeat   time.Duration `env:"PROGRESS_FIRST_HEARTBEAT"    env-default:"1m"`
+	}
+
+	// Quiz - represents quiz attempts configuration.
+	// SubmitGrace is added to time limit of attempts, so answers sent right before the deadline are accepted.
+	Quiz struct {
+		SubmitGrace time.Duration `env:"QUIZ_SUBMIT_GRACE" env-default:"10s"`
+	}
+
+	// Blob - represents uploaded files storage configuration, files are kept in Dir.
+	Blob struct {
+		Dir string `env:"BLOB_DIR" env-default:"data/blobs"`
+	}
+
+	// Assignment - represents assignment submissions configuration, MaxFileSize is in bytes.
+	Assignment struct {
+		MaxFileSize int64 `env:"ASSIGNMENT_MAX_FILE_SIZE" env-default:"20971520"`
+	}
+
+	// Payment - represents payment provider and revenue sharing configuration.
+	// CommissionPercent of every sale is kept by platform, the rest is earned by course teacher.
+	Payment struct {
+		// Driver is "stripe" or "fake", fake one doesn't move money and is meant for development only.
+		Driver            string `env:"PAYMENT_DRIVER"             env-default:"fake"`
+		Currency          string `env:"PAYMENT_CURRENCY"           env-default:"usd"`
+		CommissionPercent int    `env:"PAYMENT_COMMISSION_PERCENT" env-default:"30"`
+		// WebhookSecret verifies signatures of provider webhooks, they are rejected while it is empty.
+		WebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET"`
+		// StripeProductId is product subscriptions are priced under by stripe driver.
+		StripeSecretKey string `env:"PAYMENT_STRIPE_SECRET_KEY"`
+		StripeProductId string `env:"PAYMENT_STRIPE_PRODUCT_ID"`
+		// Orders left pending for PendingOrderTimeout are reconciled with provider every ReconcileInterval,
+		// zero interval disables reconciliation.
+		ReconcileInterval   time.Duration `env:"PAYMENT_RECONCILE_INTERVAL"    env-default:"5m"`
+		PendingOrderTimeout time.Duration `env:"PAYMENT_PENDING_ORDER_TIMEOUT" env-default:"15m"`
+	}
+
+	// Refund - represents refund policy, course can be refunded within Window after purchase
+	// while no more than MaxProgressPercent of its lessons duration is watched.
+	Refund struct {
+		Window             time.Duration `env:"REFUND_WINDOW"               env-default:"720h"`
+		MaxProgressPercent int           `env:"REFUND_MAX_PROGRESS_PERCENT" env-default:"30"`
+		// Refunds left pending for RetryInterval are retried every RetryInterval, zero interval disables retries.
+		RetryInterval time.Duration `env:"REFUND_RETRY_INTERVAL" env-default:"5m"`
+	}
+
+	// Wishlist - represents wishlist configuration, course prices are checked for drops every PriceCheckInterval
+	// and users who wishlisted cheaper courses are emailed, zero interval disables the check.
+	Wishlist struct {
+		PriceCheckInterval time.Duration `env:"WISHLIST_PRICE_CHECK_INTERVAL" env-default:"1h"`
+	}
+
+	// Subtitle - represents lesson subtitles configuration, MaxFileSize is in bytes.
+	Subtitle struct {
+		MaxFileSize int64 `env:"SUBTITLE_MAX_FILE_SIZE" env-default:"2097152"`
+	}
+
+	// Certificate - represents course certificate signing configuration.
+	// KeyFiles are PEM encoded RSA or Ed25519 private keys, the first one signs new certificates. Certificates
+	// don't expire, so keys must never be removed, a rotated key is moved down the list and kept for good.
+	Certificate struct {
+		KeyFiles []string `env:"CERTIFICATE_KEY_FILES" env-separator:","`
+	}
+
+	// JWT - represents access token configuration.
+	// KeyFiles are PEM encoded RSA or Ed25519 private keys, the first one signs new tokens and the rest
+	// only verify, so a rotated key is moved down the list and removed once its tokens expire.
 	JWT struct {
-		SignKey string `env:"JWT_SIGN_KEY"     env-default:"sajkdjk1ndansdnan"`
+		KeyFiles []string      `env:"JWT_KEY_FILES"        env-separator:","`
+		TTL      time.Duration `env:"JWT_ACCESS_TOKEN_TTL" env-default:"560h"`
 	}
 )
 
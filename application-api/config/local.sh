@@ -6,4 +6,5 @@ export POSTGRESQL_DATABASE="api"
 
 export APP_BASE_URL="http://localhost:8083"
 export LOG_LEVEL="debug"
-export JWT_SIGN_KEY="sajkdjk1ndansdnan"
\ No newline at end of file
+export JWT_KEY_FILES="keys/jwt.pem"
+export CERTIFICATE_KEY_FILES="keys/certificate.pem"
\ No newline at end of file
@@ -0,0 +1,12 @@
+// Package fonts embeds TrueType fonts of generated documents, they cover Latin, Cyrillic and Greek scripts.
+package fonts
+
+import _ "embed"
+
+// DejaVuSans and DejaVuSansBold are DejaVu fonts distributed under Bitstream Vera license, see LICENSE.
+var (
+	//go:embed DejaVuSans.ttf
+	DejaVuSans []byte
+	//go:embed DejaVuSans-Bold.ttf
+	DejaVuSansBold []byte
+)
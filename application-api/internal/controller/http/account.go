@@ -7,8 +7,11 @@ import (
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/internal/service"
 	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"net/http"
 )
 
+const _maxAccountPatchSize = 64 << 10
+
 type accountRouter struct {
 	RouterContext
 }
@@ -68,6 +71,11 @@ func (a *accountRouter) createAccount(requestContext *gin.Context) (interface{},
 		logger.Info("failed to parse request body", "err", err)
 		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
 	}
+	err = body.CreateAccountOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, createAccountResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
 	logger = logger.With("body", body)
 	logger.Debug("parsed request body")
 
@@ -155,13 +163,27 @@ type updateAccountResponseBody struct {
 	*entity.Account
 } // @name updateAccountResponseBody
 
+type updateAccountResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"account_not_found,invalid_patch,invalid_devices,invalid_language,invalid_timezone,invalid_notifications,invalid_playback_speed"`
+} // @name updateAccountResponseError
+
+func (e updateAccountResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
 // @id           UpdateAccount
-// @Summary      Updates account entity account.
-// @Accept       application/json
+// @Summary      Updates account settings and devices with JSON Merge Patch (RFC 7396), null resets setting to its default.
+// @Accept       application/merge-patch+json
 // @Produce      application/json
 // @Param        id path string true "Account ID"
+// @Param        fields body entity.Account true "patch of accountSettings and accountDevices"
 // @Success      200 {object} updateAccountResponseBody
-// @Failure      422,500 {object} httpResponseError
+// @Failure      422,500 {object} updateAccountResponseError
 // @Router       /account/{id} [PATCH]
 func (a *accountRouter) updateAccount(requestContext *gin.Context) (interface{}, *httpResponseError) {
 	logger := a.logger.Named("updateAccount").WithContext(requestContext)
@@ -190,25 +212,23 @@ func (a *accountRouter) updateAccount(requestContext *gin.Context) (interface{},
 	logger = logger.With("userId", userId)
 	logger.Debug("validated uuid userId")
 
-	account, err := a.services.AccountService.GetAccount(requestContext, &service.GetAccountOptions{AccountId: accountId, UserId: userId})
-	if err != nil {
-		if errs.IsExpected(err) {
-			logger.Info(err.Error())
-			return nil, getAccountResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
-		}
-	}
-	logger = logger.With("account", account)
-
-	err = requestContext.ShouldBindJSON(&account)
+	requestContext.Request.Body = http.MaxBytesReader(requestContext.Writer, requestContext.Request.Body, _maxAccountPatchSize)
+	patch, err := requestContext.GetRawData()
 	if err != nil {
-		logger.Info("failed to parse request body", "err", err)
+		logger.Info("failed to read request body", "err", err)
 		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
 	}
-	logger = logger.With("account", account)
-	logger.Debug("parsed request body")
 
-	updatedAccount, err := a.services.AccountService.UpdateAccount(requestContext, account)
+	updatedAccount, err := a.services.AccountService.UpdateAccount(requestContext, &service.UpdateAccountOptions{
+		AccountId: accountId,
+		UserId:    userId,
+		Patch:     patch,
+	})
 	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, updateAccountResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
 		logger.Error("failed to update account: ", err)
 		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to update account", Details: err}
 	}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type apiKeyRouter struct {
+	RouterContext
+}
+
+func setupAPIKeyRoutes(options RouterOptions) {
+	router := &apiKeyRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	// managing keys requires access token, so a leaked key can't create more keys
+	routerGroup := options.Handler.Group("/me/api-keys", authMiddleware(options))
+	{
+		routerGroup.GET("", wrapHandler(options, router.getAPIKeys))
+		routerGroup.POST("", wrapHandler(options, router.createAPIKey))
+		routerGroup.DELETE("/:id", wrapHandler(options, router.revokeAPIKey))
+	}
+}
+
+type apiKeyResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"api_key_not_allowed,too_many_keys,api_key_not_found,name_required,scopes_required,invalid_scopes,invalid_expires_at"`
+} // @name apiKeyResponseError
+
+func (e apiKeyResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getAPIKeysResponseBody struct {
+	APIKeys []*entity.APIKey `json:"apiKeys"`
+} // @name getAPIKeysResponseBody
+
+// @id           GetAPIKeys
+// @Summary      Lists API keys of current user.
+// @Produce      application/json
+// @Success      200 {object} getAPIKeysResponseBody
+// @Failure      422,500 {object} apiKeyResponseError
+// @Router       /me/api-keys [GET]
+func (a *apiKeyRouter) getAPIKeys(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("getAPIKeys").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	keys, err := a.services.APIKeyService.GetAPIKeys(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get api keys", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get api keys", Details: err}
+	}
+
+	logger.Info("successfully served api keys")
+	return &getAPIKeysResponseBody{APIKeys: keys}, nil
+}
+
+type createAPIKeyRequestBody struct {
+	*service.CreateAPIKeyOptions
+} // @name createAPIKeyRequestBody
+
+type createAPIKeyResponseBody struct {
+	*service.CreateAPIKeyOutput
+} // @name createAPIKeyResponseBody
+
+// @id           CreateAPIKey
+// @Summary      Creates API key for teacher integrations, the key is returned only once.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body createAPIKeyRequestBody true "data"
+// @Success      200 {object} createAPIKeyResponseBody
+// @Failure      422,500 {object} apiKeyResponseError
+// @Router       /me/api-keys [POST]
+func (a *apiKeyRouter) createAPIKey(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("createAPIKey").WithContext(requestContext)
+
+	body := createAPIKeyRequestBody{&service.CreateAPIKeyOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.CreateAPIKeyOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, apiKeyResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	created, err := a.services.APIKeyService.CreateAPIKey(requestContext, body.CreateAPIKeyOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, apiKeyResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to create api key", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to create api key", Details: err}
+	}
+
+	logger.Info("successfully created api key")
+	return &createAPIKeyResponseBody{created}, nil
+}
+
+type revokeAPIKeyResponseBody struct {
+	Revoked bool `json:"revoked"`
+} // @name revokeAPIKeyResponseBody
+
+// @id           RevokeAPIKey
+// @Summary      Revokes API key of current user.
+// @Produce      application/json
+// @Param        id path string true "api key id"
+// @Success      200 {object} revokeAPIKeyResponseBody
+// @Failure      422,500 {object} apiKeyResponseError
+// @Router       /me/api-keys/{id} [DELETE]
+func (a *apiKeyRouter) revokeAPIKey(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("revokeAPIKey").WithContext(requestContext)
+
+	keyId := requestContext.Param("id")
+	if _, err := uuid.Parse(keyId); err != nil {
+		logger.Info("invalid api key id parameter", "param", keyId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid api key id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "keyId", keyId)
+
+	err := a.services.APIKeyService.RevokeAPIKey(requestContext, &service.RevokeAPIKeyOptions{UserId: userId, KeyId: keyId})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, apiKeyResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to revoke api key", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to revoke api key", Details: err}
+	}
+
+	logger.Info("successfully revoked api key")
+	return &revokeAPIKeyResponseBody{Revoked: true}, nil
+}
@@ -0,0 +1,394 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"mime"
+	"net/http"
+)
+
+// _multipartOverhead - room for form fields and part headers on top of submission file size.
+const _multipartOverhead = 1 << 20
+
+type assignmentRouter struct {
+	RouterContext
+}
+
+func setupAssignmentRoutes(options RouterOptions) {
+	router := &assignmentRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.GET("/course/teachers_list/grading_queue", authMiddleware(options, entity.ScopeCoursesRead), wrapHandler(options, router.getGradingQueue))
+	options.Handler.GET("/course/:id/assignments", wrapHandler(options, router.getAssignments))
+	options.Handler.POST("/course/:id/assignments", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.addAssignment))
+
+	routerGroup := options.Handler.Group("/assignments", authMiddleware(options))
+	{
+		routerGroup.POST("/:id/submissions", wrapHandler(options, router.submitAssignment))
+		routerGroup.GET("/:id/submission", wrapHandler(options, router.getMySubmission))
+	}
+
+	options.Handler.GET("/submissions/:id/file", authMiddleware(options), wrapHandler(options, router.getSubmissionFile))
+	options.Handler.POST("/submissions/:id/grade", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.gradeSubmission))
+}
+
+type assignmentResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,assignment_not_found,submission_not_found,not_submitted,not_allowed,grade_not_allowed,not_enrolled,file_too_large,past_deadline,already_graded,invalid_score,invalid_title,invalid_max_score,invalid_due_at,invalid_late_policy"`
+} // @name assignmentResponseError
+
+func (e assignmentResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type addAssignmentRequestBody struct {
+	*service.AddAssignmentOptions
+} // @name addAssignmentRequestBody
+
+type assignmentResponseBody struct {
+	*entity.Assignment
+} // @name assignmentResponseBody
+
+// @id           AddAssignment
+// @Summary      Adds assignment to course, only course teacher can add assignments.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body addAssignmentRequestBody true "data"
+// @Success      200 {object} assignmentResponseBody
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /course/{id}/assignments [POST]
+func (r *assignmentRouter) addAssignment(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addAssignment").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := addAssignmentRequestBody{&service.AddAssignmentOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddAssignmentOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	assignment, err := r.services.AssignmentService.AddAssignment(requestContext, body.AddAssignmentOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add assignment", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add assignment", Details: err}
+	}
+
+	logger.Info("successfully added assignment")
+	return &assignmentResponseBody{assignment}, nil
+}
+
+type getAssignmentsResponseBody struct {
+	Assignments []*entity.Assignment `json:"assignments"`
+} // @name getAssignmentsResponseBody
+
+// @id           GetAssignments
+// @Summary      Lists assignments of course ordered by due date.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Success      200 {object} getAssignmentsResponseBody
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /course/{id}/assignments [GET]
+func (r *assignmentRouter) getAssignments(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getAssignments").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+	logger = logger.With("courseId", courseId)
+
+	assignments, err := r.services.AssignmentService.GetAssignments(requestContext, courseId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get assignments", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get assignments", Details: err}
+	}
+
+	logger.Info("successfully served assignments")
+	return &getAssignmentsResponseBody{Assignments: assignments}, nil
+}
+
+type submissionResponseBody struct {
+	*entity.Submission
+} // @name submissionResponseBody
+
+// @id           SubmitAssignment
+// @Summary      Uploads submission of assignment, ungraded submission is replaced.
+// @Accept       multipart/form-data
+// @Produce      application/json
+// @Param        id path string true "assignment id"
+// @Param        file formData file true "submission file"
+// @Param        comment formData string false "comment for teacher"
+// @Success      200 {object} submissionResponseBody
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /assignments/{id}/submissions [POST]
+func (r *assignmentRouter) submitAssignment(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("submitAssignment").WithContext(requestContext)
+
+	assignmentId := requestContext.Param("id")
+	if _, err := uuid.Parse(assignmentId); err != nil {
+		logger.Info("invalid assignment id parameter", "param", assignmentId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid assignment id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "assignmentId", assignmentId)
+
+	maxFileSize := r.config.Assignment.MaxFileSize
+	requestContext.Request.Body = http.MaxBytesReader(requestContext.Writer, requestContext.Request.Body, maxFileSize+_multipartOverhead)
+	fileHeader, err := requestContext.FormFile("file")
+	if err != nil {
+		logger.Info("failed to parse submission file", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid submission file", Details: err.Error()}
+	}
+	if fileHeader.Size > maxFileSize {
+		logger.Info("file is too large", "size", fileHeader.Size)
+		return nil, assignmentResponseError{Message: service.ErrSubmitAssignmentFileTooLarge.Error(), Code: errs.GetCode(service.ErrSubmitAssignmentFileTooLarge)}.Error()
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("failed to open submission file", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to open submission file", Details: err}
+	}
+	defer file.Close()
+
+	submission, err := r.services.AssignmentService.SubmitAssignment(requestContext, &service.SubmitAssignmentOptions{
+		UserId:       userId,
+		AssignmentId: assignmentId,
+		FileName:     fileHeader.Filename,
+		ContentType:  fileHeader.Header.Get("Content-Type"),
+		Size:         fileHeader.Size,
+		File:         file,
+		Comment:      requestContext.PostForm("comment"),
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to submit assignment", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to submit assignment", Details: err}
+	}
+
+	logger.Info("successfully submitted assignment")
+	return &submissionResponseBody{submission}, nil
+}
+
+// @id           GetMySubmission
+// @Summary      Gets own submission of assignment with its grade.
+// @Produce      application/json
+// @Param        id path string true "assignment id"
+// @Success      200 {object} submissionResponseBody
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /assignments/{id}/submission [GET]
+func (r *assignmentRouter) getMySubmission(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMySubmission").WithContext(requestContext)
+
+	assignmentId := requestContext.Param("id")
+	if _, err := uuid.Parse(assignmentId); err != nil {
+		logger.Info("invalid assignment id parameter", "param", assignmentId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid assignment id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "assignmentId", assignmentId)
+
+	submission, err := r.services.AssignmentService.GetMySubmission(requestContext, &service.GetMySubmissionOptions{
+		UserId:       userId,
+		AssignmentId: assignmentId,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get submission", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get submission", Details: err}
+	}
+
+	logger.Info("successfully served submission")
+	return &submissionResponseBody{submission}, nil
+}
+
+// @id           GetSubmissionFile
+// @Summary      Downloads submitted file, available to its student and course teacher.
+// @Produce      application/octet-stream
+// @Param        id path string true "submission id"
+// @Success      200 {file} file
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /submissions/{id}/file [GET]
+func (r *assignmentRouter) getSubmissionFile(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getSubmissionFile").WithContext(requestContext)
+
+	submissionId := requestContext.Param("id")
+	if _, err := uuid.Parse(submissionId); err != nil {
+		logger.Info("invalid submission id parameter", "param", submissionId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid submission id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "submissionId", submissionId)
+
+	output, err := r.services.AssignmentService.GetSubmissionFile(requestContext, &service.GetSubmissionFileOptions{
+		UserId:       userId,
+		SubmissionId: submissionId,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get submission file", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get submission file", Details: err}
+	}
+	defer output.Content.Close()
+
+	// file is always downloaded, so uploaded html or scripts aren't rendered by browser
+	submission := output.Submission
+	requestContext.Header("Content-Type", submission.ContentType)
+	requestContext.Header("X-Content-Type-Options", "nosniff")
+	requestContext.DataFromReader(http.StatusOK, submission.Size, submission.ContentType, output.Content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": submission.FileName}),
+	})
+
+	logger.Info("successfully served submission file")
+	return nil, nil
+}
+
+type gradeSubmissionRequestBody struct {
+	*service.GradeSubmissionOptions
+} // @name gradeSubmissionRequestBody
+
+// @id           GradeSubmission
+// @Summary      Grades submission with score and feedback, late penalty of assignment is applied to final score.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "submission id"
+// @Param        fields body gradeSubmissionRequestBody true "data"
+// @Success      200 {object} submissionResponseBody
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /submissions/{id}/grade [POST]
+func (r *assignmentRouter) gradeSubmission(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("gradeSubmission").WithContext(requestContext)
+
+	submissionId := requestContext.Param("id")
+	if _, err := uuid.Parse(submissionId); err != nil {
+		logger.Info("invalid submission id parameter", "param", submissionId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid submission id parameter"}
+	}
+
+	body := gradeSubmissionRequestBody{&service.GradeSubmissionOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.SubmissionId = submissionId
+	logger = logger.With("userId", userId, "submissionId", submissionId)
+
+	submission, err := r.services.AssignmentService.GradeSubmission(requestContext, body.GradeSubmissionOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, assignmentResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to grade submission", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to grade submission", Details: err}
+	}
+
+	logger.Info("successfully graded submission")
+	return &submissionResponseBody{submission}, nil
+}
+
+type getGradingQueueResponseBody struct {
+	Submissions []*entity.GradingQueueItem `json:"submissions"`
+} // @name getGradingQueueResponseBody
+
+// @id           GetGradingQueue
+// @Summary      Lists ungraded submissions across all courses of teacher, oldest first.
+// @Produce      application/json
+// @Success      200 {object} getGradingQueueResponseBody
+// @Failure      422,500 {object} assignmentResponseError
+// @Router       /course/teachers_list/grading_queue [GET]
+func (r *assignmentRouter) getGradingQueue(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getGradingQueue").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	queue, err := r.services.AssignmentService.GetGradingQueue(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get grading queue", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get grading queue", Details: err}
+	}
+
+	logger.Info("successfully served grading queue")
+	return &getGradingQueueResponseBody{Submissions: queue}, nil
+}
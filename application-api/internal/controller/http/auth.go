@@ -4,6 +4,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/vovk404/course-platform/application-api/internal/service"
 	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/ratelimit"
+	"net/http"
 )
 
 type authRouter struct {
@@ -19,11 +21,23 @@ func setupAuthRoutes(options RouterOptions) {
 		},
 	}
 
-	routerGroup := options.Handler.Group("/auth")
+	routerGroup := options.Handler.Group("/auth", rateLimitMiddleware(options, "auth", ratelimit.Limit{
+		Rate:  options.Config.RateLimit.AuthRate,
+		Burst: options.Config.RateLimit.AuthBurst,
+	}))
 	{
 		routerGroup.POST("/sign-in", wrapHandler(options, router.signIn))
 		routerGroup.POST("/sign-up", wrapHandler(options, router.signUp))
+		routerGroup.POST("/verify-email", wrapHandler(options, router.verifyEmail))
+		routerGroup.POST("/resend-verification", authMiddleware(options), wrapHandler(options, router.resendVerification))
+		routerGroup.POST("/forgot-password", wrapHandler(options, router.forgotPassword))
+		routerGroup.POST("/reset-password", wrapHandler(options, router.resetPassword))
 	}
+
+	groupOptions := options
+	groupOptions.Handler = routerGroup
+	setupTwoFactorRoutes(groupOptions)
+	setupOIDCRoutes(groupOptions)
 }
 
 type signInRequestBody struct {
@@ -36,15 +50,19 @@ type signInResponseBody struct {
 
 type signInResponseError struct {
 	Message string `json:"message"`
-	Code    string `json:"code" enums:"user_not_found,wrong_password"`
+	Code    string `json:"code" enums:"invalid_credentials,too_many_attempts"`
 } // @name signInResponseError
 
 func (e signInResponseError) Error() *httpResponseError {
-	return &httpResponseError{
+	err := &httpResponseError{
 		Type:    ErrorTypeClient,
 		Message: e.Message,
 		Code:    e.Code,
 	}
+	if e.Code == errs.GetCode(service.ErrSignInTooManyAttempts) {
+		err.Status = http.StatusTooManyRequests
+	}
+	return err
 }
 
 // @id           SignIn
@@ -53,24 +71,29 @@ func (e signInResponseError) Error() *httpResponseError {
 // @Produce      application/json
 // @Param        fields body signInRequestBody true "data"
 // @Success      200 {object} signInResponseBody
-// @Failure      422,500 {object} signInResponseError
+// @Failure      422,429,500 {object} signInResponseError
 // @Router       /sign-in [POST]
 func (a *authRouter) signIn(requestContext *gin.Context) (interface{}, *httpResponseError) {
 	logger := a.logger.Named("signIn").WithContext(requestContext)
 
-	var body signInRequestBody
+	body := signInRequestBody{&service.SignInOptions{}}
 	err := requestContext.ShouldBindJSON(&body)
 	if err != nil {
 		logger.Info("failed to parse request body", "err", err)
 		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
 	}
-	logger = logger.With("body", body)
+	// X-Forwarded-For is read only behind HTTP_TRUSTED_PROXIES, so attempts can't be spread over forged IPs
+	body.SignInOptions.IP = requestContext.ClientIP()
+	logger = logger.With("email", body.Email, "ip", body.IP)
 	logger.Debug("parsed request body")
 
 	signed, err := a.services.AuthService.SignIn(requestContext, body.SignInOptions)
 	if err != nil {
 		if errs.IsExpected(err) {
 			logger.Info(err.Error())
+			if retryAfter, ok := errs.GetDetails(err)["retryAfter"]; ok {
+				requestContext.Header("Retry-After", retryAfter)
+			}
 			return nil, signInResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
 		}
 		logger.Error("failed to sign in", "err", err)
@@ -142,3 +165,201 @@ func (a *authRouter) signUp(requestContext *gin.Context) (interface{}, *httpResp
 	logger.Info("user created and returned")
 	return &signUpResponseBody{createdUser}, nil
 }
+
+type verifyEmailRequestBody struct {
+	*service.VerifyEmailOptions
+} // @name verifyEmailRequestBody
+
+type verifyEmailResponseBody struct {
+	Verified bool `json:"verified"`
+} // @name verifyEmailResponseBody
+
+type verifyEmailResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"invalid_token"`
+} // @name verifyEmailResponseError
+
+func (e verifyEmailResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+// @id           VerifyEmail
+// @Summary      Verifies user email via token sent on sign up.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body verifyEmailRequestBody true "data"
+// @Success      200 {object} verifyEmailResponseBody
+// @Failure      422,500 {object} verifyEmailResponseError
+// @Router       /auth/verify-email [POST]
+func (a *authRouter) verifyEmail(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("verifyEmail").WithContext(requestContext)
+
+	body := verifyEmailRequestBody{&service.VerifyEmailOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	err = a.services.AuthService.VerifyEmail(requestContext, body.VerifyEmailOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, verifyEmailResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to verify email", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to verify email", Details: err}
+	}
+
+	logger.Info("successfully verified email")
+	return &verifyEmailResponseBody{Verified: true}, nil
+}
+
+type resendVerificationResponseBody struct {
+	Sent bool `json:"sent"`
+} // @name resendVerificationResponseBody
+
+type resendVerificationResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"already_verified"`
+} // @name resendVerificationResponseError
+
+func (e resendVerificationResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+// @id           ResendVerification
+// @Summary      Sends new email verification token to current user, limited like other auth routes.
+// @Produce      application/json
+// @Success      200 {object} resendVerificationResponseBody
+// @Failure      422,500 {object} resendVerificationResponseError
+// @Router       /auth/resend-verification [POST]
+func (a *authRouter) resendVerification(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("resendVerification").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	err := a.services.AuthService.ResendVerificationEmail(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, resendVerificationResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to resend verification email", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to resend verification email", Details: err}
+	}
+
+	logger.Info("successfully resent verification email")
+	return &resendVerificationResponseBody{Sent: true}, nil
+}
+
+type forgotPasswordRequestBody struct {
+	*service.ForgotPasswordOptions
+} // @name forgotPasswordRequestBody
+
+type forgotPasswordResponseBody struct {
+	Message string `json:"message"`
+} // @name forgotPasswordResponseBody
+
+// @id           ForgotPassword
+// @Summary      Sends password reset token to user email if such user exists.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body forgotPasswordRequestBody true "data"
+// @Success      200 {object} forgotPasswordResponseBody
+// @Failure      422,500 {object} httpResponseError
+// @Router       /auth/forgot-password [POST]
+func (a *authRouter) forgotPassword(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("forgotPassword").WithContext(requestContext)
+
+	body := forgotPasswordRequestBody{&service.ForgotPasswordOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger = logger.With("body", body)
+	logger.Debug("parsed request body")
+
+	err = a.services.AuthService.ForgotPassword(requestContext, body.ForgotPasswordOptions)
+	if err != nil {
+		logger.Error("failed to handle forgot password", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to handle forgot password", Details: err}
+	}
+
+	logger.Info("successfully handled forgot password")
+	return &forgotPasswordResponseBody{Message: "if the email is registered, a reset token has been sent to it"}, nil
+}
+
+type resetPasswordRequestBody struct {
+	*service.ResetPasswordWithTokenOptions
+} // @name resetPasswordRequestBody
+
+type resetPasswordResponseBody struct {
+	Reset bool `json:"reset"`
+} // @name resetPasswordResponseBody
+
+type resetPasswordResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"invalid_token"`
+} // @name resetPasswordResponseError
+
+func (e resetPasswordResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+// @id           ResetPassword
+// @Summary      Sets new password via token sent by forgot password.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body resetPasswordRequestBody true "data"
+// @Success      200 {object} resetPasswordResponseBody
+// @Failure      422,500 {object} resetPasswordResponseError
+// @Router       /auth/reset-password [POST]
+func (a *authRouter) resetPassword(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("resetPassword").WithContext(requestContext)
+
+	body := resetPasswordRequestBody{&service.ResetPasswordWithTokenOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.ResetPasswordWithTokenOptions.Validate()
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	err = a.services.AuthService.ResetPassword(requestContext, body.ResetPasswordWithTokenOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, resetPasswordResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to reset password", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to reset password", Details: err}
+	}
+
+	logger.Info("successfully reset password")
+	return &resetPasswordResponseBody{Reset: true}, nil
+}
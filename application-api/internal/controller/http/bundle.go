@@ -0,0 +1,232 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type bundleRouter struct {
+	RouterContext
+}
+
+func setupBundleRoutes(options RouterOptions) {
+	router := &bundleRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/bundles")
+	{
+		routerGroup.GET("", wrapHandler(options, router.getBundles))
+		routerGroup.POST("", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.createBundle))
+		routerGroup.GET("/:id", wrapHandler(options, router.getBundle))
+		routerGroup.POST("/:id/purchase", authMiddleware(options), wrapHandler(options, router.purchaseBundle))
+	}
+	options.Handler.GET("/teacher/bundles", authMiddleware(options, entity.ScopeCoursesRead), wrapHandler(options, router.getMyBundles))
+}
+
+type bundleResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"bundle_not_teacher,bundle_course_not_allowed,bundle_not_found,own_bundle,bundle_courses_owned,purchase_in_progress,payment_declined,invalid_bundle_name,invalid_price,invalid_courses,invalid_courses_count,email_not_verified"`
+} // @name bundleResponseError
+
+func (e bundleResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type createBundleRequestBody struct {
+	*service.CreateBundleOptions
+} // @name createBundleRequestBody
+
+type bundleResponseBody struct {
+	*entity.Bundle
+} // @name bundleResponseBody
+
+// @id           CreateBundle
+// @Summary      Creates bundle of own published courses, price is in minor units.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body createBundleRequestBody true "data"
+// @Success      200 {object} bundleResponseBody
+// @Failure      422,500 {object} bundleResponseError
+// @Router       /bundles [POST]
+func (r *bundleRouter) createBundle(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("createBundle").WithContext(requestContext)
+
+	body := createBundleRequestBody{&service.CreateBundleOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.CreateBundleOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, bundleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	bundle, err := r.services.BundleService.CreateBundle(requestContext, body.CreateBundleOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, bundleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to create bundle", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to create bundle", Details: err}
+	}
+
+	logger.Info("successfully created bundle")
+	return &bundleResponseBody{bundle}, nil
+}
+
+type getBundlesResponseBody struct {
+	Bundles []*entity.Bundle `json:"bundles"`
+} // @name getBundlesResponseBody
+
+// @id           GetBundles
+// @Summary      Lists published bundles with their courses, newest first.
+// @Produce      application/json
+// @Success      200 {object} getBundlesResponseBody
+// @Failure      422,500 {object} bundleResponseError
+// @Router       /bundles [GET]
+func (r *bundleRouter) getBundles(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getBundles").WithContext(requestContext)
+
+	bundles, err := r.services.BundleService.GetBundles(requestContext)
+	if err != nil {
+		logger.Error("failed to get bundles", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get bundles", Details: err}
+	}
+
+	logger.Info("successfully served bundles")
+	return &getBundlesResponseBody{Bundles: bundles}, nil
+}
+
+// @id           GetMyBundles
+// @Summary      Lists bundles of current teacher, unpublished ones included.
+// @Produce      application/json
+// @Success      200 {object} getBundlesResponseBody
+// @Failure      422,500 {object} bundleResponseError
+// @Router       /teacher/bundles [GET]
+func (r *bundleRouter) getMyBundles(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyBundles").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	bundles, err := r.services.BundleService.GetMyBundles(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get bundles", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get bundles", Details: err}
+	}
+
+	logger.Info("successfully served bundles")
+	return &getBundlesResponseBody{Bundles: bundles}, nil
+}
+
+// @id           GetBundle
+// @Summary      Gets published bundle with its courses.
+// @Produce      application/json
+// @Param        id path string true "bundle id"
+// @Success      200 {object} bundleResponseBody
+// @Failure      422,500 {object} bundleResponseError
+// @Router       /bundles/{id} [GET]
+func (r *bundleRouter) getBundle(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getBundle").WithContext(requestContext)
+
+	bundleId := requestContext.Param("id")
+	if _, err := uuid.Parse(bundleId); err != nil {
+		logger.Info("invalid bundle id parameter", "param", bundleId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid bundle id parameter"}
+	}
+	logger = logger.With("bundleId", bundleId)
+
+	bundle, err := r.services.BundleService.GetBundle(requestContext, bundleId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, bundleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get bundle", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get bundle", Details: err}
+	}
+
+	logger.Info("successfully served bundle")
+	return &bundleResponseBody{bundle}, nil
+}
+
+type purchaseBundleRequestBody struct {
+	*service.PurchaseBundleOptions
+} // @name purchaseBundleRequestBody
+
+// @id           PurchaseBundle
+// @Summary      Buys courses of bundle not owned yet with one order, bundle price is split between courses by their prices.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "bundle id"
+// @Param        fields body purchaseBundleRequestBody true "data"
+// @Success      200 {object} orderResponseBody
+// @Failure      422,500 {object} bundleResponseError
+// @Router       /bundles/{id}/purchase [POST]
+func (r *bundleRouter) purchaseBundle(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("purchaseBundle").WithContext(requestContext)
+
+	bundleId := requestContext.Param("id")
+	if _, err := uuid.Parse(bundleId); err != nil {
+		logger.Info("invalid bundle id parameter", "param", bundleId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid bundle id parameter"}
+	}
+
+	body := purchaseBundleRequestBody{&service.PurchaseBundleOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.BundleId = bundleId
+	logger = logger.With("userId", userId, "bundleId", bundleId)
+
+	order, err := r.services.OrderService.PurchaseBundle(requestContext, body.PurchaseBundleOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, bundleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to purchase bundle", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to purchase bundle", Details: err}
+	}
+
+	logger.Info("successfully purchased bundle")
+	return &orderResponseBody{order}, nil
+}
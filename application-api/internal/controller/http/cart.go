@@ -0,0 +1,202 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type cartRouter struct {
+	RouterContext
+}
+
+func setupCartRoutes(options RouterOptions) {
+	router := &cartRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/cart", authMiddleware(options))
+	{
+		routerGroup.GET("", wrapHandler(options, router.getCart))
+		routerGroup.POST("/items", wrapHandler(options, router.addToCart))
+		routerGroup.DELETE("/items/:courseId", wrapHandler(options, router.removeFromCart))
+		routerGroup.POST("/checkout", wrapHandler(options, router.checkout))
+	}
+}
+
+type cartResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"invalid_course_id,course_not_found,course_free,own_course,already_owned,cart_courses_owned,purchase_in_progress,cart_empty,course_unavailable,price_changed,payment_declined,email_not_verified"`
+} // @name cartResponseError
+
+func (e cartResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type cartResponseBody struct {
+	*entity.Cart
+} // @name cartResponseBody
+
+// @id           GetCart
+// @Summary      Gets cart of current user, prices are in minor units.
+// @Produce      application/json
+// @Success      200 {object} cartResponseBody
+// @Failure      422,500 {object} cartResponseError
+// @Router       /cart [GET]
+func (r *cartRouter) getCart(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getCart").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	cart, err := r.services.CartService.GetCart(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get cart", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get cart", Details: err}
+	}
+
+	logger.Info("successfully served cart")
+	return &cartResponseBody{cart}, nil
+}
+
+type addToCartRequestBody struct {
+	*service.AddToCartOptions
+} // @name addToCartRequestBody
+
+// @id           AddToCart
+// @Summary      Adds paid course to cart of current user at its current price.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body addToCartRequestBody true "data"
+// @Success      200 {object} cartResponseBody
+// @Failure      422,500 {object} cartResponseError
+// @Router       /cart/items [POST]
+func (r *cartRouter) addToCart(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addToCart").WithContext(requestContext)
+
+	body := addToCartRequestBody{&service.AddToCartOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddToCartOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, cartResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId, "courseId", body.CourseId)
+
+	cart, err := r.services.CartService.AddToCart(requestContext, body.AddToCartOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, cartResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add course to cart", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add course to cart", Details: err}
+	}
+
+	logger.Info("successfully added course to cart")
+	return &cartResponseBody{cart}, nil
+}
+
+// @id           RemoveFromCart
+// @Summary      Removes course from cart of current user.
+// @Produce      application/json
+// @Param        courseId path string true "course id"
+// @Success      200 {object} cartResponseBody
+// @Failure      422,500 {object} cartResponseError
+// @Router       /cart/items/{courseId} [DELETE]
+func (r *cartRouter) removeFromCart(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("removeFromCart").WithContext(requestContext)
+
+	courseId := requestContext.Param("courseId")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	cart, err := r.services.CartService.RemoveFromCart(requestContext, &service.RemoveFromCartOptions{UserId: userId, CourseId: courseId})
+	if err != nil {
+		logger.Error("failed to remove course from cart", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to remove course from cart", Details: err}
+	}
+
+	logger.Info("successfully removed course from cart")
+	return &cartResponseBody{cart}, nil
+}
+
+type checkoutRequestBody struct {
+	*service.CheckoutOptions
+} // @name checkoutRequestBody
+
+// @id           Checkout
+// @Summary      Buys all courses in cart with one order, cart is revalidated first and changed cart has to be reviewed.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body checkoutRequestBody true "data"
+// @Success      200 {object} orderResponseBody
+// @Failure      422,500 {object} cartResponseError
+// @Router       /cart/checkout [POST]
+func (r *cartRouter) checkout(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("checkout").WithContext(requestContext)
+
+	body := checkoutRequestBody{&service.CheckoutOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	order, err := r.services.OrderService.Checkout(requestContext, body.CheckoutOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, cartResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to checkout cart", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to checkout cart", Details: err}
+	}
+
+	logger.Info("successfully checked out cart")
+	return &orderResponseBody{order}, nil
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"net/http"
+)
+
+type certificateRouter struct {
+	RouterContext
+}
+
+func setupCertificateRoutes(options RouterOptions) {
+	router := &certificateRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.GET("/me/certificates", authMiddleware(options), wrapHandler(options, router.getMyCertificates))
+
+	routerGroup := options.Handler.Group("/certificates")
+	{
+		routerGroup.GET("/:id/verify", wrapHandler(options, router.verifyCertificate))
+		routerGroup.GET("/:id/pdf", authMiddleware(options), wrapHandler(options, router.getCertificatePDF))
+		routerGroup.POST("/:id/revoke", authMiddleware(options), wrapHandler(options, router.revokeCertificate))
+	}
+}
+
+type certificateResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"certificate_not_found,certificate_revoked,revoke_not_allowed"`
+} // @name certificateResponseError
+
+func (e certificateResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getMyCertificatesResponseBody struct {
+	Certificates []*entity.Certificate `json:"certificates"`
+} // @name getMyCertificatesResponseBody
+
+// @id           GetMyCertificates
+// @Summary      Lists certificates of current user, newest first.
+// @Produce      application/json
+// @Success      200 {object} getMyCertificatesResponseBody
+// @Failure      422,500 {object} certificateResponseError
+// @Router       /me/certificates [GET]
+func (r *certificateRouter) getMyCertificates(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyCertificates").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	certificates, err := r.services.CertificateService.GetMyCertificates(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get certificates", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get certificates", Details: err}
+	}
+
+	logger.Info("successfully served certificates")
+	return &getMyCertificatesResponseBody{Certificates: certificates}, nil
+}
+
+type verifyCertificateResponseBody struct {
+	*service.VerifyCertificateOutput
+} // @name verifyCertificateResponseBody
+
+// @id           VerifyCertificate
+// @Summary      Checks that certificate was issued by the platform and isn't revoked.
+// @Produce      application/json
+// @Param        id path string true "certificate id"
+// @Success      200 {object} verifyCertificateResponseBody
+// @Failure      404,422,500 {object} certificateResponseError
+// @Router       /certificates/{id}/verify [GET]
+func (r *certificateRouter) verifyCertificate(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("verifyCertificate").WithContext(requestContext)
+
+	certificateId := requestContext.Param("id")
+	if _, err := uuid.Parse(certificateId); err != nil {
+		logger.Info("invalid certificate id parameter", "param", certificateId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid certificate id parameter"}
+	}
+	logger = logger.With("certificateId", certificateId)
+
+	output, err := r.services.CertificateService.VerifyCertificate(requestContext, certificateId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			responseErr := certificateResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+			responseErr.Status = http.StatusNotFound
+			return nil, responseErr
+		}
+		logger.Error("failed to verify certificate", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to verify certificate", Details: err}
+	}
+
+	logger.Info("successfully verified certificate", "valid", output.Valid)
+	return &verifyCertificateResponseBody{output}, nil
+}
+
+// @id           GetCertificatePDF
+// @Summary      Downloads own certificate as PDF document.
+// @Produce      application/pdf
+// @Param        id path string true "certificate id"
+// @Success      200 {file} file
+// @Failure      422,500 {object} certificateResponseError
+// @Router       /certificates/{id}/pdf [GET]
+func (r *certificateRouter) getCertificatePDF(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getCertificatePDF").WithContext(requestContext)
+
+	certificateId := requestContext.Param("id")
+	if _, err := uuid.Parse(certificateId); err != nil {
+		logger.Info("invalid certificate id parameter", "param", certificateId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid certificate id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "certificateId", certificateId)
+
+	document, err := r.services.CertificateService.GetCertificatePDF(requestContext, &service.GetCertificatePDFOptions{
+		UserId:        userId,
+		CertificateId: certificateId,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, certificateResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get certificate pdf", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get certificate pdf", Details: err}
+	}
+
+	// response is written here, content type set by cors middleware has to be replaced first
+	requestContext.Header("Content-Type", "application/pdf")
+	requestContext.Header("Content-Disposition", `attachment; filename="certificate-`+certificateId+`.pdf"`)
+	requestContext.Data(http.StatusOK, "application/pdf", document)
+
+	logger.Info("successfully served certificate pdf")
+	return nil, nil
+}
+
+type revokeCertificateRequestBody struct {
+	*service.RevokeCertificateOptions
+} // @name revokeCertificateRequestBody
+
+type certificateResponseBody struct {
+	*entity.Certificate
+} // @name certificateResponseBody
+
+// @id           RevokeCertificate
+// @Summary      Revokes certificate, revoked certificates fail verification.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "certificate id"
+// @Param        fields body revokeCertificateRequestBody true "data"
+// @Success      200 {object} certificateResponseBody
+// @Failure      422,500 {object} certificateResponseError
+// @Router       /certificates/{id}/revoke [POST]
+func (r *certificateRouter) revokeCertificate(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("revokeCertificate").WithContext(requestContext)
+
+	certificateId := requestContext.Param("id")
+	if _, err := uuid.Parse(certificateId); err != nil {
+		logger.Info("invalid certificate id parameter", "param", certificateId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid certificate id parameter"}
+	}
+
+	body := revokeCertificateRequestBody{&service.RevokeCertificateOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CertificateId = certificateId
+	logger = logger.With("userId", userId, "certificateId", certificateId)
+
+	certificate, err := r.services.CertificateService.RevokeCertificate(requestContext, body.RevokeCertificateOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, certificateResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to revoke certificate", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to revoke certificate", Details: err}
+	}
+
+	logger.Info("successfully revoked certificate")
+	return &certificateResponseBody{certificate}, nil
+}
@@ -9,24 +9,33 @@ import (
 	"github.com/google/uuid"
 	"github.com/vovk404/course-platform/application-api/config"
 	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/i18n"
 	"github.com/vovk404/course-platform/application-api/pkg/logger"
+	"github.com/vovk404/course-platform/application-api/pkg/ratelimit"
+	"math"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
 )
 
 type Options struct {
-	Handler  *gin.Engine
-	Logger   logger.Logger
-	Services service.Services
-	Config   *config.Config
+	Handler     *gin.Engine
+	Logger      logger.Logger
+	Services    service.Services
+	Config      *config.Config
+	RateLimiter ratelimit.Limiter
+	Translator  *i18n.Translator
 }
 
 type RouterOptions struct {
-	Handler  *gin.RouterGroup
-	Logger   logger.Logger
-	Services service.Services
-	Config   *config.Config
+	Handler     *gin.RouterGroup
+	Logger      logger.Logger
+	Services    service.Services
+	Config      *config.Config
+	RateLimiter ratelimit.Limiter
+	Translator  *i18n.Translator
 }
 
 type RouterContext struct {
@@ -43,17 +52,48 @@ func New(options *Options) {
 	)
 
 	routerOptions := RouterOptions{
-		Handler:  options.Handler.Group("/api/v1"),
+		Handler:     options.Handler.Group("/api/v1"),
+		Services:    options.Services,
+		Logger:      options.Logger.Named("HTTPController"),
+		Config:      options.Config,
+		RateLimiter: options.RateLimiter,
+		Translator:  options.Translator,
+	}
+	routerOptions.Handler.Use(rateLimitMiddleware(routerOptions, "default", ratelimit.Limit{
+		Rate:  options.Config.RateLimit.DefaultRate,
+		Burst: options.Config.RateLimit.DefaultBurst,
+	}))
+
+	setupWellKnownRoutes(RouterOptions{
+		Handler:  options.Handler.Group("/.well-known"),
 		Services: options.Services,
 		Logger:   options.Logger.Named("HTTPController"),
 		Config:   options.Config,
-	}
+	})
 
 	// routes
 	{
 		setupAuthRoutes(routerOptions)
 		setupAccountRoutes(routerOptions)
 		setupCourseRoutes(routerOptions)
+		setupAPIKeyRoutes(routerOptions)
+		setupReviewRoutes(routerOptions)
+		setupCurriculumRoutes(routerOptions)
+		setupProgressRoutes(routerOptions)
+		setupCertificateRoutes(routerOptions)
+		setupQuizRoutes(routerOptions)
+		setupAssignmentRoutes(routerOptions)
+		setupDiscussionRoutes(routerOptions)
+		setupOrderRoutes(routerOptions)
+		setupLedgerRoutes(routerOptions)
+		setupRefundRoutes(routerOptions)
+		setupCartRoutes(routerOptions)
+		setupBundleRoutes(routerOptions)
+		setupSubscriptionRoutes(routerOptions)
+		setupGiftCodeRoutes(routerOptions)
+		setupWishlistRoutes(routerOptions)
+		setupTeacherRoutes(routerOptions)
+		setupSubtitleRoutes(routerOptions)
 	}
 }
 
@@ -77,8 +117,10 @@ func corsMiddleware(c *gin.Context) {
 }
 
 // httpResponseError provides a base error type for all errors.
+// Status overrides default status code of client errors.
 type httpResponseError struct {
 	Type          httpErrType `json:"-"`
+	Status        int         `json:"-"`
 	Message       string      `json:"message"`
 	Code          string      `json:"code,omitempty"`
 	Details       interface{} `json:"details,omitempty"`
@@ -142,7 +184,16 @@ func wrapHandler(options RouterOptions, handler func(c *gin.Context) (interface{
 
 			} else {
 				lgr.Info("client error")
-				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, err)
+				status := http.StatusUnprocessableEntity
+				if err.Status != 0 {
+					status = err.Status
+				}
+				if err.Code != "" && options.Translator != nil {
+					language := options.Translator.Negotiate(requestLanguages(c, options.Services))
+					err.Message = options.Translator.Translate(language, err.Code, err.Message)
+					c.Header("Content-Language", language)
+				}
+				c.AbortWithStatusJSON(status, err)
 			}
 			return
 		}
@@ -151,7 +202,9 @@ func wrapHandler(options RouterOptions, handler func(c *gin.Context) (interface{
 	}
 }
 
-func authMiddleware(routerOptions RouterOptions) gin.HandlerFunc {
+// authMiddleware authenticates requests with access token, or with API key when route lists scopes.
+// API keys must be granted all listed scopes, routes without scopes accept access tokens only.
+func authMiddleware(routerOptions RouterOptions, scopes ...string) gin.HandlerFunc {
 	logger := routerOptions.Logger.Named("authMiddleware")
 	return wrapHandler(routerOptions, func(requestContext *gin.Context) (interface{}, *httpResponseError) {
 		tokenStringRaw := requestContext.GetHeader("Authorization")
@@ -163,6 +216,10 @@ func authMiddleware(routerOptions RouterOptions) gin.HandlerFunc {
 		}
 		logger.Debug("got tokenString")
 
+		if strings.HasPrefix(tokenString, service.APIKeyPrefix) {
+			return authenticateAPIKey(routerOptions, requestContext, tokenString, scopes)
+		}
+
 		claims, err := routerOptions.Services.AuthService.VerifyToken(requestContext, &service.VerifyTokenOptions{AccessToken: tokenString})
 		if err != nil {
 			logger.Info(err.Error())
@@ -177,6 +234,127 @@ func authMiddleware(routerOptions RouterOptions) gin.HandlerFunc {
 	})
 }
 
+func authenticateAPIKey(routerOptions RouterOptions, requestContext *gin.Context, key string, scopes []string) (interface{}, *httpResponseError) {
+	logger := routerOptions.Logger.Named("authenticateAPIKey")
+
+	if len(scopes) == 0 {
+		logger.Info("api keys are not accepted by route", "path", requestContext.FullPath())
+		return nil, &httpResponseError{Type: ErrorTypeClient, Status: http.StatusForbidden, Message: "API keys are not accepted here", Code: "api_key_not_accepted"}
+	}
+
+	verified, err := routerOptions.Services.APIKeyService.VerifyAPIKey(requestContext, &service.VerifyAPIKeyOptions{Key: key})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, &httpResponseError{Type: ErrorTypeClient, Status: http.StatusUnauthorized, Message: err.Error(), Code: errs.GetCode(err)}
+		}
+		logger.Error("failed to verify api key", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to verify api key", Details: err}
+	}
+
+	for _, scope := range scopes {
+		granted := false
+		for _, grantedScope := range verified.Scopes {
+			if grantedScope == scope {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			logger.Info("api key lacks scope", "scope", scope, "userId", verified.UserId)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Status: http.StatusForbidden, Message: "API key lacks scope " + scope, Code: "insufficient_scope"}
+		}
+	}
+
+	requestContext.Set("userId", verified.UserId)
+	requestContext.Set("username", verified.Username)
+
+	logger.Info("successfully authenticated user with api key")
+	return nil, nil
+}
+
+// rateLimitMiddleware limits requests per route group with token bucket of given limit.
+// Authenticated clients are limited by user id, anonymous ones by IP.
+func rateLimitMiddleware(routerOptions RouterOptions, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	logger := routerOptions.Logger.Named("rateLimitMiddleware").With("group", group)
+	if !routerOptions.Config.RateLimit.Enabled || routerOptions.RateLimiter == nil {
+		return func(c *gin.Context) {}
+	}
+
+	return wrapHandler(routerOptions, func(requestContext *gin.Context) (interface{}, *httpResponseError) {
+		key := group + ":ip:" + requestContext.ClientIP()
+		if tokenString, err := getAuthToken(requestContext.GetHeader("Authorization")); err == nil {
+			claims, err := routerOptions.Services.AuthService.VerifyToken(requestContext, &service.VerifyTokenOptions{AccessToken: tokenString})
+			if err == nil {
+				key = group + ":user:" + claims.UserId
+			}
+		}
+
+		result, err := routerOptions.RateLimiter.Allow(requestContext, key, limit)
+		if err != nil {
+			// fail open, unavailable limiter backend shouldn't take the api down
+			logger.Error("failed to check rate limit", "err", err)
+			return nil, nil
+		}
+
+		requestContext.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		requestContext.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		requestContext.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+
+		if !result.Allowed {
+			requestContext.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			logger.Info("rate limit exceeded", "key", key)
+			return nil, &httpResponseError{
+				Type:    ErrorTypeClient,
+				Status:  http.StatusTooManyRequests,
+				Message: "rate limit exceeded",
+				Code:    "rate_limit_exceeded",
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+// requestLanguages returns languages preferred by client from the most preferred one: language of account
+// of authenticated user followed by languages of Accept-Language header. Anonymous routes don't require
+// access token, so it is verified here when it is sent.
+func requestLanguages(c *gin.Context, services service.Services) []string {
+	if languages, ok := c.Get("languages"); ok {
+		return languages.([]string)
+	}
+
+	var languages []string
+	if userId := optionalUserId(c, services); userId != "" {
+		// language of account is a preference only, request is served without it on failure
+		language, err := services.AccountService.GetAccountLanguage(c, userId)
+		if err == nil && language != "" {
+			languages = append(languages, language)
+		}
+	}
+	languages = append(languages, i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+
+	c.Set("languages", languages)
+	return languages
+}
+
+// optionalUserId returns id of user authenticated by access token on routes without authMiddleware,
+// requests without valid access token are anonymous and get empty id.
+func optionalUserId(c *gin.Context, services service.Services) string {
+	if userId := c.GetString("userId"); userId != "" {
+		return userId
+	}
+	tokenString, err := getAuthToken(c.GetHeader("Authorization"))
+	if err != nil {
+		return ""
+	}
+	claims, err := services.AuthService.VerifyToken(c, &service.VerifyTokenOptions{AccessToken: tokenString})
+	if err != nil {
+		return ""
+	}
+	return claims.UserId
+}
+
 func getAuthToken(rawToken string) (string, error) {
 	if rawToken == "" {
 		return "", fmt.Errorf("empty auth token")
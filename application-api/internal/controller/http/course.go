@@ -30,7 +30,7 @@ type getCourseResponseBody struct {
 
 type uploadCourseResponseError struct {
 	Message string `json:"message"`
-	Code    string `json:"code" enums:"user_not_found"`
+	Code    string `json:"code" enums:"user_not_found,email_not_verified,profile_required"`
 } // @name uploadCourseResponseError
 
 func (e uploadCourseResponseError) Error() *httpResponseError {
@@ -51,10 +51,14 @@ func setupCourseRoutes(options RouterOptions) {
 	}
 	routerGroup := options.Handler.Group("/course")
 	{
-		routerGroup.POST("/new", authMiddleware(options), wrapHandler(options, router.uploadCourse))
-		routerGroup.GET("/teachers_list", authMiddleware(options), wrapHandler(options, router.getListByTeacherId))
+		routerGroup.POST("/new", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.uploadCourse))
+		routerGroup.GET("/teachers_list", authMiddleware(options, entity.ScopeCoursesRead), wrapHandler(options, router.getListByTeacherId))
 		routerGroup.GET("/list", wrapHandler(options, router.getList))
 		routerGroup.GET("/:id", wrapHandler(options, router.getCourseById))
+		routerGroup.POST("/:id/enroll", authMiddleware(options), wrapHandler(options, router.enroll))
+		routerGroup.GET("/:id/translations", wrapHandler(options, router.getCourseTranslations))
+		routerGroup.PUT("/:id/translations/:language", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.setCourseTranslation))
+		routerGroup.DELETE("/:id/translations/:language", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.deleteCourseTranslation))
 	}
 }
 
@@ -107,7 +111,7 @@ func (a *courseRouter) getListByTeacherId(requestContext *gin.Context) (interfac
 
 	logger.Info("teachers courses served successfully")
 	return &getListResponseBody{
-		&service.CreateGetListOutput{list},
+		&service.CreateGetListOutput{Courses: list},
 	}, nil
 }
 
@@ -127,7 +131,7 @@ func (a *courseRouter) getList(requestContext *gin.Context) (interface{}, *httpR
 
 	logger.Info("Courses served successfully")
 	return &getListResponseBody{
-		&service.CreateGetListOutput{list},
+		&service.CreateGetListOutput{Courses: list},
 	}, nil
 }
 
@@ -138,7 +142,10 @@ func (a *courseRouter) getCourseById(requestContext *gin.Context) (interface{},
 		logger.Info("invalid course id parameter", "param", courseId)
 		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
 	}
-	course, err := a.services.CourseService.GetCourseById(requestContext, courseId)
+	course, err := a.services.CourseService.GetCourseById(requestContext, &service.GetCourseByIdOptions{
+		Id:        courseId,
+		Languages: requestLanguages(requestContext, a.services),
+	})
 
 	if course == nil || err != nil {
 		logger.Error("failed to get the course", "err", err)
@@ -148,8 +155,224 @@ func (a *courseRouter) getCourseById(requestContext *gin.Context) (interface{},
 			Details: err.Error(),
 		}
 	}
+	if course.ContentLanguage != "" {
+		requestContext.Header("Content-Language", course.ContentLanguage)
+	}
 	logger.Info("Course served successfully")
 	return &getCourseResponseBody{
 		course,
 	}, nil
 }
+
+type enrollResponseBody struct {
+	*entity.Enrollment
+} // @name enrollResponseBody
+
+type enrollResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,payment_required"`
+} // @name enrollResponseError
+
+func (e enrollResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+// @id           Enroll
+// @Summary      Enrolls current user in free course.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Success      200 {object} enrollResponseBody
+// @Failure      422,500 {object} enrollResponseError
+// @Router       /course/{id}/enroll [POST]
+func (a *courseRouter) enroll(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("enroll").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	enrollment, err := a.services.CourseService.Enroll(requestContext, &service.EnrollOptions{UserId: userId, CourseId: courseId})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, enrollResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to enroll", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to enroll", Details: err}
+	}
+
+	logger.Info("successfully enrolled")
+	return &enrollResponseBody{enrollment}, nil
+}
+
+type courseTranslationResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,not_allowed,translation_not_found,invalid_language,invalid_name,invalid_description"`
+} // @name courseTranslationResponseError
+
+func (e courseTranslationResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getCourseTranslationsResponseBody struct {
+	Translations []*entity.CourseTranslation `json:"translations"`
+} // @name getCourseTranslationsResponseBody
+
+// @id           GetCourseTranslations
+// @Summary      Lists translations of course name and description.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Success      200 {object} getCourseTranslationsResponseBody
+// @Failure      422,500 {object} courseTranslationResponseError
+// @Router       /course/{id}/translations [GET]
+func (a *courseRouter) getCourseTranslations(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("getCourseTranslations").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+	logger = logger.With("courseId", courseId)
+
+	translations, err := a.services.CourseService.GetCourseTranslations(requestContext, courseId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, courseTranslationResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get course translations", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get course translations", Details: err}
+	}
+
+	logger.Info("successfully got course translations")
+	return &getCourseTranslationsResponseBody{Translations: translations}, nil
+}
+
+type setCourseTranslationRequestBody struct {
+	*service.SetCourseTranslationOptions
+} // @name setCourseTranslationRequestBody
+
+type courseTranslationResponseBody struct {
+	*entity.CourseTranslation
+} // @name courseTranslationResponseBody
+
+// @id           SetCourseTranslation
+// @Summary      Creates or overwrites translation of course to language, only course teacher can translate it.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        language path string true "language tag, e.g. uk or pt-BR"
+// @Param        fields body setCourseTranslationRequestBody true "data"
+// @Success      200 {object} courseTranslationResponseBody
+// @Failure      422,500 {object} courseTranslationResponseError
+// @Router       /course/{id}/translations/{language} [PUT]
+func (a *courseRouter) setCourseTranslation(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("setCourseTranslation").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := setCourseTranslationRequestBody{&service.SetCourseTranslationOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	body.Language = requestContext.Param("language")
+	err = body.SetCourseTranslationOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, courseTranslationResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId, "language", body.Language)
+
+	translation, err := a.services.CourseService.SetCourseTranslation(requestContext, body.SetCourseTranslationOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, courseTranslationResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to set course translation", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to set course translation", Details: err}
+	}
+
+	logger.Info("successfully set course translation")
+	return &courseTranslationResponseBody{translation}, nil
+}
+
+type deleteCourseTranslationResponseBody struct {
+	Deleted bool `json:"deleted"`
+} // @name deleteCourseTranslationResponseBody
+
+// @id           DeleteCourseTranslation
+// @Summary      Deletes translation of course to language, only course teacher can delete it.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        language path string true "language tag, e.g. uk or pt-BR"
+// @Success      200 {object} deleteCourseTranslationResponseBody
+// @Failure      422,500 {object} courseTranslationResponseError
+// @Router       /course/{id}/translations/{language} [DELETE]
+func (a *courseRouter) deleteCourseTranslation(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := a.logger.Named("deleteCourseTranslation").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	language := requestContext.Param("language")
+	logger = logger.With("userId", userId, "courseId", courseId, "language", language)
+
+	err := a.services.CourseService.DeleteCourseTranslation(requestContext, &service.DeleteCourseTranslationOptions{
+		UserId:   userId,
+		CourseId: courseId,
+		Language: language,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, courseTranslationResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to delete course translation", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to delete course translation", Details: err}
+	}
+
+	logger.Info("successfully deleted course translation")
+	return &deleteCourseTranslationResponseBody{Deleted: true}, nil
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type curriculumRouter struct {
+	RouterContext
+}
+
+func setupCurriculumRoutes(options RouterOptions) {
+	router := &curriculumRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	courseGroup := options.Handler.Group("/course/:id")
+	{
+		courseGroup.GET("/curriculum", wrapHandler(options, router.getCurriculum))
+		courseGroup.POST("/sections", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.addSection))
+	}
+
+	routerGroup := options.Handler.Group("/sections", authMiddleware(options, entity.ScopeCoursesWrite))
+	{
+		routerGroup.POST("/:id/lessons", wrapHandler(options, router.addLesson))
+	}
+}
+
+type curriculumResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,section_not_found,not_allowed,invalid_title,invalid_duration"`
+} // @name curriculumResponseError
+
+func (e curriculumResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getCurriculumResponseBody struct {
+	*service.CurriculumOutput
+} // @name getCurriculumResponseBody
+
+// @id           GetCurriculum
+// @Summary      Lists sections of course with their lessons in order.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Success      200 {object} getCurriculumResponseBody
+// @Failure      422,500 {object} curriculumResponseError
+// @Router       /course/{id}/curriculum [GET]
+func (r *curriculumRouter) getCurriculum(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getCurriculum").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+	logger = logger.With("courseId", courseId)
+
+	curriculum, err := r.services.CourseService.GetCurriculum(requestContext, courseId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, curriculumResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get curriculum", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get curriculum", Details: err}
+	}
+
+	logger.Info("successfully served curriculum")
+	return &getCurriculumResponseBody{curriculum}, nil
+}
+
+type addSectionRequestBody struct {
+	*service.AddSectionOptions
+} // @name addSectionRequestBody
+
+type sectionResponseBody struct {
+	*entity.Section
+} // @name sectionResponseBody
+
+// @id           AddSection
+// @Summary      Adds section to course, only course teacher can add sections.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body addSectionRequestBody true "data"
+// @Success      200 {object} sectionResponseBody
+// @Failure      422,500 {object} curriculumResponseError
+// @Router       /course/{id}/sections [POST]
+func (r *curriculumRouter) addSection(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addSection").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := addSectionRequestBody{&service.AddSectionOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddSectionOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, curriculumResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	section, err := r.services.CourseService.AddSection(requestContext, body.AddSectionOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, curriculumResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add section", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add section", Details: err}
+	}
+
+	logger.Info("successfully added section")
+	return &sectionResponseBody{section}, nil
+}
+
+type addLessonRequestBody struct {
+	*service.AddLessonOptions
+} // @name addLessonRequestBody
+
+type lessonResponseBody struct {
+	*entity.Lesson
+} // @name lessonResponseBody
+
+// @id           AddLesson
+// @Summary      Adds lesson to section, only course teacher can add lessons.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "section id"
+// @Param        fields body addLessonRequestBody true "data"
+// @Success      200 {object} lessonResponseBody
+// @Failure      422,500 {object} curriculumResponseError
+// @Router       /sections/{id}/lessons [POST]
+func (r *curriculumRouter) addLesson(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addLesson").WithContext(requestContext)
+
+	sectionId := requestContext.Param("id")
+	if _, err := uuid.Parse(sectionId); err != nil {
+		logger.Info("invalid section id parameter", "param", sectionId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid section id parameter"}
+	}
+
+	body := addLessonRequestBody{&service.AddLessonOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddLessonOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, curriculumResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.SectionId = sectionId
+	logger = logger.With("userId", userId, "sectionId", sectionId)
+
+	lesson, err := r.services.CourseService.AddLesson(requestContext, body.AddLessonOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, curriculumResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add lesson", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add lesson", Details: err}
+	}
+
+	logger.Info("successfully added lesson")
+	return &lessonResponseBody{lesson}, nil
+}
@@ -0,0 +1,610 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type discussionRouter struct {
+	RouterContext
+}
+
+func setupDiscussionRoutes(options RouterOptions) {
+	router := &discussionRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.GET("/course/:id/threads", authMiddleware(options), wrapHandler(options, router.getThreads))
+	options.Handler.POST("/course/:id/threads", authMiddleware(options), wrapHandler(options, router.createThread))
+
+	routerGroup := options.Handler.Group("/threads", authMiddleware(options))
+	{
+		routerGroup.GET("/:id", wrapHandler(options, router.getThread))
+		routerGroup.GET("/:id/posts", wrapHandler(options, router.getPosts))
+		routerGroup.POST("/:id/posts", wrapHandler(options, router.createPost))
+		routerGroup.POST("/:id/accept", wrapHandler(options, router.acceptPost))
+		routerGroup.POST("/:id/upvote", wrapHandler(options, router.upvote(entity.DiscussionThread, true)))
+		routerGroup.DELETE("/:id/upvote", wrapHandler(options, router.upvote(entity.DiscussionThread, false)))
+		routerGroup.POST("/:id/report", wrapHandler(options, router.report(entity.DiscussionThread)))
+	}
+
+	postsGroup := options.Handler.Group("/posts", authMiddleware(options))
+	{
+		postsGroup.POST("/:id/upvote", wrapHandler(options, router.upvote(entity.DiscussionPost, true)))
+		postsGroup.DELETE("/:id/upvote", wrapHandler(options, router.upvote(entity.DiscussionPost, false)))
+		postsGroup.POST("/:id/report", wrapHandler(options, router.report(entity.DiscussionPost)))
+	}
+
+	moderationGroup := options.Handler.Group("/discussions/reports", authMiddleware(options))
+	{
+		moderationGroup.GET("", wrapHandler(options, router.getModerationQueue))
+		moderationGroup.POST("/:id/resolve", wrapHandler(options, router.resolveReport))
+	}
+}
+
+type discussionResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,lesson_not_found,thread_not_found,post_not_found,report_not_found,discussion_not_allowed,accept_not_allowed,discussion_moderation_not_allowed,invalid_cursor,own_content,already_reported,report_resolved,invalid_lesson_id,invalid_thread_title,invalid_body,invalid_parent_id,invalid_post_id,invalid_report_reason"`
+} // @name discussionResponseError
+
+func (e discussionResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type createThreadRequestBody struct {
+	*service.CreateThreadOptions
+} // @name createThreadRequestBody
+
+type threadResponseBody struct {
+	*entity.Thread
+} // @name threadResponseBody
+
+// @id           CreateThread
+// @Summary      Starts discussion thread in course or its lesson, available to enrolled students and course teacher.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body createThreadRequestBody true "data"
+// @Success      200 {object} threadResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /course/{id}/threads [POST]
+func (r *discussionRouter) createThread(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("createThread").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := createThreadRequestBody{&service.CreateThreadOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.CreateThreadOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	thread, err := r.services.DiscussionService.CreateThread(requestContext, body.CreateThreadOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to create thread", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to create thread", Details: err}
+	}
+
+	logger.Info("successfully created thread")
+	return &threadResponseBody{thread}, nil
+}
+
+type getThreadsResponseBody struct {
+	*service.GetThreadsOutput
+} // @name getThreadsResponseBody
+
+// @id           GetThreads
+// @Summary      Lists threads of course, most recently active first.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        lessonId query string false "lists threads of the lesson only"
+// @Param        cursor query string false "nextCursor of previous page"
+// @Param        limit query int false "page size, 20 by default and 100 at most"
+// @Success      200 {object} getThreadsResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /course/{id}/threads [GET]
+func (r *discussionRouter) getThreads(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getThreads").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	options := &service.GetThreadsOptions{}
+	err := requestContext.ShouldBindQuery(options)
+	if err != nil {
+		logger.Info("failed to parse request query", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request query", Details: err}
+	}
+	if options.LessonId != "" {
+		if _, err := uuid.Parse(options.LessonId); err != nil {
+			logger.Info("invalid lesson id parameter", "param", options.LessonId)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid lesson id parameter"}
+		}
+	}
+	logger.Debug("parsed request query")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	options.UserId = userId
+	options.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	output, err := r.services.DiscussionService.GetThreads(requestContext, options)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get threads", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get threads", Details: err}
+	}
+
+	logger.Info("successfully served threads")
+	return &getThreadsResponseBody{output}, nil
+}
+
+// @id           GetThread
+// @Summary      Gets thread with its opening post.
+// @Produce      application/json
+// @Param        id path string true "thread id"
+// @Success      200 {object} threadResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /threads/{id} [GET]
+func (r *discussionRouter) getThread(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getThread").WithContext(requestContext)
+
+	threadId := requestContext.Param("id")
+	if _, err := uuid.Parse(threadId); err != nil {
+		logger.Info("invalid thread id parameter", "param", threadId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid thread id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "threadId", threadId)
+
+	thread, err := r.services.DiscussionService.GetThread(requestContext, &service.GetThreadOptions{
+		UserId:   userId,
+		ThreadId: threadId,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get thread", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get thread", Details: err}
+	}
+
+	logger.Info("successfully served thread")
+	return &threadResponseBody{thread}, nil
+}
+
+type getPostsResponseBody struct {
+	*service.GetPostsOutput
+} // @name getPostsResponseBody
+
+// @id           GetPosts
+// @Summary      Lists replies of thread, oldest first.
+// @Produce      application/json
+// @Param        id path string true "thread id"
+// @Param        cursor query string false "nextCursor of previous page"
+// @Param        limit query int false "page size, 20 by default and 100 at most"
+// @Success      200 {object} getPostsResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /threads/{id}/posts [GET]
+func (r *discussionRouter) getPosts(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getPosts").WithContext(requestContext)
+
+	threadId := requestContext.Param("id")
+	if _, err := uuid.Parse(threadId); err != nil {
+		logger.Info("invalid thread id parameter", "param", threadId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid thread id parameter"}
+	}
+
+	options := &service.GetPostsOptions{}
+	err := requestContext.ShouldBindQuery(options)
+	if err != nil {
+		logger.Info("failed to parse request query", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request query", Details: err}
+	}
+	logger.Debug("parsed request query")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	options.UserId = userId
+	options.ThreadId = threadId
+	logger = logger.With("userId", userId, "threadId", threadId)
+
+	output, err := r.services.DiscussionService.GetPosts(requestContext, options)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get posts", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get posts", Details: err}
+	}
+
+	logger.Info("successfully served posts")
+	return &getPostsResponseBody{output}, nil
+}
+
+type createPostRequestBody struct {
+	*service.CreatePostOptions
+} // @name createPostRequestBody
+
+type postResponseBody struct {
+	*entity.Post
+} // @name postResponseBody
+
+// @id           CreatePost
+// @Summary      Replies to thread, parentId replies to one of its posts.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "thread id"
+// @Param        fields body createPostRequestBody true "data"
+// @Success      200 {object} postResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /threads/{id}/posts [POST]
+func (r *discussionRouter) createPost(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("createPost").WithContext(requestContext)
+
+	threadId := requestContext.Param("id")
+	if _, err := uuid.Parse(threadId); err != nil {
+		logger.Info("invalid thread id parameter", "param", threadId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid thread id parameter"}
+	}
+
+	body := createPostRequestBody{&service.CreatePostOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.CreatePostOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.ThreadId = threadId
+	logger = logger.With("userId", userId, "threadId", threadId)
+
+	post, err := r.services.DiscussionService.CreatePost(requestContext, body.CreatePostOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to create post", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to create post", Details: err}
+	}
+
+	logger.Info("successfully created post")
+	return &postResponseBody{post}, nil
+}
+
+type acceptPostRequestBody struct {
+	*service.AcceptPostOptions
+} // @name acceptPostRequestBody
+
+// @id           AcceptPost
+// @Summary      Marks reply as accepted answer of thread, only course teacher can accept answers.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "thread id"
+// @Param        fields body acceptPostRequestBody true "data"
+// @Success      200 {object} threadResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /threads/{id}/accept [POST]
+func (r *discussionRouter) acceptPost(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("acceptPost").WithContext(requestContext)
+
+	threadId := requestContext.Param("id")
+	if _, err := uuid.Parse(threadId); err != nil {
+		logger.Info("invalid thread id parameter", "param", threadId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid thread id parameter"}
+	}
+
+	body := acceptPostRequestBody{&service.AcceptPostOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AcceptPostOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.ThreadId = threadId
+	logger = logger.With("userId", userId, "threadId", threadId)
+
+	thread, err := r.services.DiscussionService.AcceptPost(requestContext, body.AcceptPostOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to accept post", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to accept post", Details: err}
+	}
+
+	logger.Info("successfully accepted post")
+	return &threadResponseBody{thread}, nil
+}
+
+type upvoteResponseBody struct {
+	Upvoted bool `json:"upvoted"`
+} // @name upvoteResponseBody
+
+// upvote returns handler adding or removing upvote of thread or post, repeated requests don't change counters.
+//
+// @id           Upvote
+// @Summary      Adds upvote with POST and removes it with DELETE, own threads and posts can't be upvoted.
+// @Produce      application/json
+// @Param        id path string true "thread or post id"
+// @Success      200 {object} upvoteResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /threads/{id}/upvote [POST]
+// @Router       /threads/{id}/upvote [DELETE]
+// @Router       /posts/{id}/upvote [POST]
+// @Router       /posts/{id}/upvote [DELETE]
+func (r *discussionRouter) upvote(targetType string, up bool) func(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	return func(requestContext *gin.Context) (interface{}, *httpResponseError) {
+		logger := r.logger.Named("upvote").WithContext(requestContext).With("targetType", targetType, "up", up)
+
+		targetId := requestContext.Param("id")
+		if _, err := uuid.Parse(targetId); err != nil {
+			logger.Info("invalid id parameter", "param", targetId)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid " + targetType + " id parameter"}
+		}
+
+		userId, ok := requestContext.Value("userId").(string)
+		if !ok || userId == "" {
+			logger.Info("user not found")
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+		}
+		logger = logger.With("userId", userId, "targetId", targetId)
+
+		err := r.services.DiscussionService.Upvote(requestContext, &service.UpvoteOptions{
+			UserId:     userId,
+			TargetType: targetType,
+			TargetId:   targetId,
+			Up:         up,
+		})
+		if err != nil {
+			if errs.IsExpected(err) {
+				logger.Info(err.Error())
+				return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+			}
+			logger.Error("failed to upvote", "err", err)
+			return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to upvote", Details: err}
+		}
+
+		logger.Info("successfully upvoted")
+		return &upvoteResponseBody{Upvoted: up}, nil
+	}
+}
+
+type reportRequestBody struct {
+	*service.ReportOptions
+} // @name reportRequestBody
+
+type reportResponseBody struct {
+	*entity.DiscussionReport
+} // @name reportResponseBody
+
+// report returns handler reporting thread or post to moderators.
+//
+// @id           Report
+// @Summary      Reports thread or post, reported content appears in moderation queue of admins.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "thread or post id"
+// @Param        fields body reportRequestBody true "data"
+// @Success      200 {object} reportResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /threads/{id}/report [POST]
+// @Router       /posts/{id}/report [POST]
+func (r *discussionRouter) report(targetType string) func(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	return func(requestContext *gin.Context) (interface{}, *httpResponseError) {
+		logger := r.logger.Named("report").WithContext(requestContext).With("targetType", targetType)
+
+		targetId := requestContext.Param("id")
+		if _, err := uuid.Parse(targetId); err != nil {
+			logger.Info("invalid id parameter", "param", targetId)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid " + targetType + " id parameter"}
+		}
+
+		body := reportRequestBody{&service.ReportOptions{}}
+		err := requestContext.ShouldBindJSON(&body)
+		if err != nil {
+			logger.Info("failed to parse request body", "err", err)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+		}
+		err = body.ReportOptions.Validate()
+		if err != nil {
+			logger.Info("invalid request body", "err", err)
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Debug("parsed request body")
+
+		userId, ok := requestContext.Value("userId").(string)
+		if !ok || userId == "" {
+			logger.Info("user not found")
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+		}
+		body.UserId = userId
+		body.TargetType = targetType
+		body.TargetId = targetId
+		logger = logger.With("userId", userId, "targetId", targetId)
+
+		report, err := r.services.DiscussionService.Report(requestContext, body.ReportOptions)
+		if err != nil {
+			if errs.IsExpected(err) {
+				logger.Info(err.Error())
+				return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+			}
+			logger.Error("failed to report", "err", err)
+			return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to report", Details: err}
+		}
+
+		logger.Info("successfully reported")
+		return &reportResponseBody{report}, nil
+	}
+}
+
+type getModerationQueueResponseBody struct {
+	Reports []*entity.ModerationQueueItem `json:"reports"`
+} // @name getModerationQueueResponseBody
+
+// @id           GetModerationQueue
+// @Summary      Lists reported threads and posts with open reports, oldest first, admins only.
+// @Produce      application/json
+// @Success      200 {object} getModerationQueueResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /discussions/reports [GET]
+func (r *discussionRouter) getModerationQueue(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getModerationQueue").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	queue, err := r.services.DiscussionService.GetModerationQueue(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get moderation queue", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get moderation queue", Details: err}
+	}
+
+	logger.Info("successfully served moderation queue")
+	return &getModerationQueueResponseBody{Reports: queue}, nil
+}
+
+type resolveReportRequestBody struct {
+	*service.ResolveReportOptions
+} // @name resolveReportRequestBody
+
+type resolveReportResponseBody struct {
+	Resolved bool `json:"resolved"`
+} // @name resolveReportResponseBody
+
+// @id           ResolveReport
+// @Summary      Hides reported content or dismisses its reports, all open reports of the content are resolved, admins only.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "report id"
+// @Param        fields body resolveReportRequestBody true "data"
+// @Success      200 {object} resolveReportResponseBody
+// @Failure      422,500 {object} discussionResponseError
+// @Router       /discussions/reports/{id}/resolve [POST]
+func (r *discussionRouter) resolveReport(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("resolveReport").WithContext(requestContext)
+
+	reportId := requestContext.Param("id")
+	if _, err := uuid.Parse(reportId); err != nil {
+		logger.Info("invalid report id parameter", "param", reportId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid report id parameter"}
+	}
+
+	body := resolveReportRequestBody{&service.ResolveReportOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.ReportId = reportId
+	logger = logger.With("userId", userId, "reportId", reportId)
+
+	err = r.services.DiscussionService.ResolveReport(requestContext, body.ResolveReportOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, discussionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to resolve report", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to resolve report", Details: err}
+	}
+
+	logger.Info("successfully resolved report")
+	return &resolveReportResponseBody{Resolved: true}, nil
+}
@@ -0,0 +1,347 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/ratelimit"
+	"mime"
+	"net/http"
+	"time"
+)
+
+type giftCodeRouter struct {
+	RouterContext
+}
+
+func setupGiftCodeRoutes(options RouterOptions) {
+	router := &giftCodeRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.POST("/course/:id/gift", authMiddleware(options), wrapHandler(options, router.purchaseGift))
+	options.Handler.GET("/me/gifts", authMiddleware(options), wrapHandler(options, router.getMyGifts))
+
+	routerGroup := options.Handler.Group("/course/:id/gift-codes", authMiddleware(options))
+	{
+		routerGroup.POST("", wrapHandler(options, router.generateGiftCodes))
+		routerGroup.GET("/export", wrapHandler(options, router.exportGiftCodes))
+	}
+
+	// codes are guessed with the same throttling as passwords
+	options.Handler.POST("/redeem", rateLimitMiddleware(options, "redeem", ratelimit.Limit{
+		Rate:  options.Config.RateLimit.AuthRate,
+		Burst: options.Config.RateLimit.AuthBurst,
+	}), authMiddleware(options), wrapHandler(options, router.redeemGiftCode))
+}
+
+type giftCodeResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,course_free,own_course,payment_declined,gift_codes_generate_not_allowed,gift_codes_export_not_allowed,code_not_found,code_redeemed,gift_course_owned,invalid_count,code_required,email_not_verified"`
+} // @name giftCodeResponseError
+
+func (e giftCodeResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type purchaseGiftRequestBody struct {
+	*service.PurchaseGiftOptions
+} // @name purchaseGiftRequestBody
+
+type purchaseGiftResponseBody struct {
+	Order *entity.Order    `json:"order"`
+	Code  *entity.GiftCode `json:"code"`
+} // @name purchaseGiftResponseBody
+
+// @id           PurchaseGift
+// @Summary      Buys paid course for someone else, returned code enrolls whoever redeems it.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body purchaseGiftRequestBody true "data"
+// @Success      200 {object} purchaseGiftResponseBody
+// @Failure      422,500 {object} giftCodeResponseError
+// @Router       /course/{id}/gift [POST]
+func (r *giftCodeRouter) purchaseGift(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("purchaseGift").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := purchaseGiftRequestBody{&service.PurchaseGiftOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	output, err := r.services.OrderService.PurchaseGift(requestContext, body.PurchaseGiftOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, giftCodeResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to purchase gift", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to purchase gift", Details: err}
+	}
+
+	logger.Info("successfully purchased gift")
+	return &purchaseGiftResponseBody{Order: output.Order, Code: output.Code}, nil
+}
+
+type giftCodesResponseBody struct {
+	Codes []*entity.GiftCode `json:"codes"`
+} // @name giftCodesResponseBody
+
+// @id           GetMyGifts
+// @Summary      Lists gift codes bought by current user with their redemption state, newest first.
+// @Produce      application/json
+// @Success      200 {object} giftCodesResponseBody
+// @Failure      422,500 {object} giftCodeResponseError
+// @Router       /me/gifts [GET]
+func (r *giftCodeRouter) getMyGifts(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyGifts").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	codes, err := r.services.GiftCodeService.GetMyGifts(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get gifts", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get gifts", Details: err}
+	}
+
+	logger.Info("successfully served gifts")
+	return &giftCodesResponseBody{Codes: codes}, nil
+}
+
+type generateGiftCodesRequestBody struct {
+	*service.GenerateGiftCodesOptions
+} // @name generateGiftCodesRequestBody
+
+// @id           GenerateGiftCodes
+// @Summary      Generates batch of free single-use codes enrolling in course, course teacher or admins only.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body generateGiftCodesRequestBody true "data"
+// @Success      200 {object} giftCodesResponseBody
+// @Failure      422,500 {object} giftCodeResponseError
+// @Router       /course/{id}/gift-codes [POST]
+func (r *giftCodeRouter) generateGiftCodes(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("generateGiftCodes").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := generateGiftCodesRequestBody{&service.GenerateGiftCodesOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.GenerateGiftCodesOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, giftCodeResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	codes, err := r.services.GiftCodeService.GenerateGiftCodes(requestContext, body.GenerateGiftCodesOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, giftCodeResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to generate gift codes", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to generate gift codes", Details: err}
+	}
+
+	logger.Info("successfully generated gift codes")
+	return &giftCodesResponseBody{Codes: codes}, nil
+}
+
+// @id           ExportGiftCodes
+// @Summary      Exports gift codes of course as CSV, optionally of one batch, course teacher or admins only.
+// @Produce      text/csv
+// @Param        id path string true "course id"
+// @Param        batchId query string false "batch id"
+// @Success      200 {file} file
+// @Failure      422,500 {object} giftCodeResponseError
+// @Router       /course/{id}/gift-codes/export [GET]
+func (r *giftCodeRouter) exportGiftCodes(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("exportGiftCodes").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	options := &service.GetCourseGiftCodesOptions{}
+	err := requestContext.ShouldBindQuery(options)
+	if err != nil {
+		logger.Info("failed to parse request query", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request query", Details: err}
+	}
+	if options.BatchId != "" {
+		if _, err := uuid.Parse(options.BatchId); err != nil {
+			logger.Info("invalid batch id parameter", "param", options.BatchId)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid batch id parameter"}
+		}
+	}
+	logger.Debug("parsed request query")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	options.UserId = userId
+	options.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	codes, err := r.services.GiftCodeService.GetCourseGiftCodes(requestContext, options)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, giftCodeResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get gift codes", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get gift codes", Details: err}
+	}
+
+	var buffer bytes.Buffer
+	err = writeGiftCodesCSV(&buffer, codes)
+	if err != nil {
+		logger.Error("failed to write csv", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to export gift codes", Details: err}
+	}
+
+	requestContext.Header("Content-Type", "text/csv; charset=utf-8")
+	requestContext.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "gift-codes-" + courseId + ".csv"}))
+	requestContext.Data(http.StatusOK, "text/csv; charset=utf-8", buffer.Bytes())
+
+	logger.Info("successfully exported gift codes", "count", len(codes))
+	return nil, nil
+}
+
+// writeGiftCodesCSV writes one row per code with header row first, empty cells mean code isn't redeemed.
+func writeGiftCodesCSV(buffer *bytes.Buffer, codes []*entity.GiftCode) error {
+	writer := csv.NewWriter(buffer)
+	err := writer.Write([]string{"code", "course_id", "batch_id", "created_at", "redeemed_by", "redeemed_at"})
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		row := []string{code.Code, code.CourseId, "", code.CreatedAt.UTC().Format(time.RFC3339), "", ""}
+		if code.BatchId != nil {
+			row[2] = *code.BatchId
+		}
+		if code.RedeemedBy != nil {
+			row[4] = *code.RedeemedBy
+		}
+		if code.RedeemedAt != nil {
+			row[5] = code.RedeemedAt.UTC().Format(time.RFC3339)
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type redeemGiftCodeRequestBody struct {
+	*service.RedeemGiftCodeOptions
+} // @name redeemGiftCodeRequestBody
+
+// @id           RedeemGiftCode
+// @Summary      Enrolls current user in course of gift code, every code can be redeemed once.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body redeemGiftCodeRequestBody true "data"
+// @Success      200 {object} enrollResponseBody
+// @Failure      422,500 {object} giftCodeResponseError
+// @Router       /redeem [POST]
+func (r *giftCodeRouter) redeemGiftCode(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("redeemGiftCode").WithContext(requestContext)
+
+	body := redeemGiftCodeRequestBody{&service.RedeemGiftCodeOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.RedeemGiftCodeOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, giftCodeResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	enrollment, err := r.services.GiftCodeService.RedeemGiftCode(requestContext, body.RedeemGiftCodeOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, giftCodeResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to redeem gift code", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to redeem gift code", Details: err}
+	}
+
+	logger.Info("successfully redeemed gift code")
+	return &enrollResponseBody{enrollment}, nil
+}
@@ -0,0 +1,260 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type ledgerRouter struct {
+	RouterContext
+}
+
+func setupLedgerRoutes(options RouterOptions) {
+	router := &ledgerRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	teacherGroup := options.Handler.Group("/teacher", authMiddleware(options))
+	{
+		teacherGroup.GET("/earnings", wrapHandler(options, router.getEarnings))
+		teacherGroup.GET("/payouts", wrapHandler(options, router.getMyPayouts))
+		teacherGroup.POST("/payouts", wrapHandler(options, router.requestPayout))
+	}
+
+	routerGroup := options.Handler.Group("/payouts", authMiddleware(options))
+	{
+		routerGroup.GET("", wrapHandler(options, router.getPayouts))
+		routerGroup.POST("/:id/resolve", wrapHandler(options, router.resolvePayout))
+	}
+}
+
+type ledgerResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"earnings_not_teacher,payout_not_teacher,payouts_list_not_allowed,payout_resolve_not_allowed,insufficient_balance,payout_not_found,payout_resolved,invalid_amount"`
+} // @name ledgerResponseError
+
+func (e ledgerResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getEarningsResponseBody struct {
+	*entity.Earnings
+} // @name getEarningsResponseBody
+
+// @id           GetEarnings
+// @Summary      Gets earnings of current teacher with per course and per month aggregates, amounts are in minor units.
+// @Produce      application/json
+// @Success      200 {object} getEarningsResponseBody
+// @Failure      422,500 {object} ledgerResponseError
+// @Router       /teacher/earnings [GET]
+func (r *ledgerRouter) getEarnings(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getEarnings").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	earnings, err := r.services.LedgerService.GetEarnings(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, ledgerResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get earnings", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get earnings", Details: err}
+	}
+
+	logger.Info("successfully served earnings")
+	return &getEarningsResponseBody{earnings}, nil
+}
+
+type requestPayoutRequestBody struct {
+	*service.RequestPayoutOptions
+} // @name requestPayoutRequestBody
+
+type payoutResponseBody struct {
+	*entity.Payout
+} // @name payoutResponseBody
+
+// @id           RequestPayout
+// @Summary      Requests payout of available earnings, it is paid once admin approves it.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body requestPayoutRequestBody true "data"
+// @Success      200 {object} payoutResponseBody
+// @Failure      422,500 {object} ledgerResponseError
+// @Router       /teacher/payouts [POST]
+func (r *ledgerRouter) requestPayout(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("requestPayout").WithContext(requestContext)
+
+	body := requestPayoutRequestBody{&service.RequestPayoutOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.RequestPayoutOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, ledgerResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	payout, err := r.services.LedgerService.RequestPayout(requestContext, body.RequestPayoutOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, ledgerResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to request payout", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to request payout", Details: err}
+	}
+
+	logger.Info("successfully requested payout")
+	return &payoutResponseBody{payout}, nil
+}
+
+type getPayoutsResponseBody struct {
+	Payouts []*entity.Payout `json:"payouts"`
+} // @name getPayoutsResponseBody
+
+// @id           GetMyPayouts
+// @Summary      Lists payouts of current teacher, newest first.
+// @Produce      application/json
+// @Success      200 {object} getPayoutsResponseBody
+// @Failure      422,500 {object} ledgerResponseError
+// @Router       /teacher/payouts [GET]
+func (r *ledgerRouter) getMyPayouts(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyPayouts").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	payouts, err := r.services.LedgerService.GetMyPayouts(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get payouts", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get payouts", Details: err}
+	}
+
+	logger.Info("successfully served payouts")
+	return &getPayoutsResponseBody{Payouts: payouts}, nil
+}
+
+// @id           GetPayouts
+// @Summary      Lists payouts of all teachers, newest first, admins only.
+// @Produce      application/json
+// @Param        status query string false "requested, approved or rejected"
+// @Success      200 {object} getPayoutsResponseBody
+// @Failure      422,500 {object} ledgerResponseError
+// @Router       /payouts [GET]
+func (r *ledgerRouter) getPayouts(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getPayouts").WithContext(requestContext)
+
+	options := &service.GetPayoutsOptions{}
+	err := requestContext.ShouldBindQuery(options)
+	if err != nil {
+		logger.Info("failed to parse request query", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request query", Details: err}
+	}
+	logger.Debug("parsed request query")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	options.UserId = userId
+	logger = logger.With("userId", userId)
+
+	payouts, err := r.services.LedgerService.GetPayouts(requestContext, options)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, ledgerResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get payouts", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get payouts", Details: err}
+	}
+
+	logger.Info("successfully served payouts")
+	return &getPayoutsResponseBody{Payouts: payouts}, nil
+}
+
+type resolvePayoutRequestBody struct {
+	*service.ResolvePayoutOptions
+} // @name resolvePayoutRequestBody
+
+// @id           ResolvePayout
+// @Summary      Approves or rejects requested payout, approved payout is posted to ledger, admins only.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "payout id"
+// @Param        fields body resolvePayoutRequestBody true "data"
+// @Success      200 {object} payoutResponseBody
+// @Failure      422,500 {object} ledgerResponseError
+// @Router       /payouts/{id}/resolve [POST]
+func (r *ledgerRouter) resolvePayout(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("resolvePayout").WithContext(requestContext)
+
+	payoutId := requestContext.Param("id")
+	if _, err := uuid.Parse(payoutId); err != nil {
+		logger.Info("invalid payout id parameter", "param", payoutId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid payout id parameter"}
+	}
+
+	body := resolvePayoutRequestBody{&service.ResolvePayoutOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.PayoutId = payoutId
+	logger = logger.With("userId", userId, "payoutId", payoutId)
+
+	payout, err := r.services.LedgerService.ResolvePayout(requestContext, body.ResolvePayoutOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, ledgerResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to resolve payout", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to resolve payout", Details: err}
+	}
+
+	logger.Info("successfully resolved payout")
+	return &payoutResponseBody{payout}, nil
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type oidcRouter struct {
+	RouterContext
+}
+
+// setupOIDCRoutes registers routes under auth group, so they share its rate limit.
+func setupOIDCRoutes(options RouterOptions) {
+	router := &oidcRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/oidc")
+	{
+		routerGroup.GET("/login", wrapHandler(options, router.login))
+		routerGroup.GET("/callback", wrapHandler(options, router.callback))
+	}
+}
+
+type oidcResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"oidc_not_configured,invalid_state,oidc_failed,provider_email_not_verified"`
+} // @name oidcResponseError
+
+func (e oidcResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type oidcLoginResponseBody struct {
+	*service.OIDCLoginOutput
+} // @name oidcLoginResponseBody
+
+// @id           OIDCLogin
+// @Summary      Starts social login, returns identity provider URL to redirect user to.
+// @Produce      application/json
+// @Success      200 {object} oidcLoginResponseBody
+// @Failure      422,500 {object} oidcResponseError
+// @Router       /auth/oidc/login [GET]
+func (o *oidcRouter) login(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := o.logger.Named("oidcLogin").WithContext(requestContext)
+
+	started, err := o.services.AuthService.OIDCLogin(requestContext)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, oidcResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to start social login", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to start social login", Details: err}
+	}
+
+	logger.Info("successfully started social login")
+	return &oidcLoginResponseBody{started}, nil
+}
+
+type oidcCallbackResponseBody struct {
+	*service.SignInOutput
+} // @name oidcCallbackResponseBody
+
+// @id           OIDCCallback
+// @Summary      Completes social login, identity provider redirects user here.
+// @Produce      application/json
+// @Param        code query string true "authorization code"
+// @Param        state query string true "state returned by identity provider"
+// @Success      200 {object} oidcCallbackResponseBody
+// @Failure      422,500 {object} oidcResponseError
+// @Router       /auth/oidc/callback [GET]
+func (o *oidcRouter) callback(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := o.logger.Named("oidcCallback").WithContext(requestContext)
+
+	options := &service.OIDCCallbackOptions{}
+	err := requestContext.ShouldBindQuery(options)
+	if err != nil {
+		logger.Info("failed to parse request query", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request query", Details: err}
+	}
+	// provider reports denied consent and other failures via error parameter instead of code
+	if providerErr := requestContext.Query("error"); providerErr != "" {
+		logger.Info("identity provider returned error", "error", providerErr)
+		return nil, oidcResponseError{Message: service.ErrOIDCCallbackFailed.Error(), Code: errs.GetCode(service.ErrOIDCCallbackFailed)}.Error()
+	}
+	logger.Debug("parsed request query")
+
+	signed, err := o.services.AuthService.OIDCCallback(requestContext, options)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, oidcResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to complete social login", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to complete social login", Details: err}
+	}
+
+	logger.Info("successfully signed in with identity provider")
+	return &oidcCallbackResponseBody{signed}, nil
+}
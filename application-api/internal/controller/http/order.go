@@ -0,0 +1,126 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type orderRouter struct {
+	RouterContext
+}
+
+func setupOrderRoutes(options RouterOptions) {
+	router := &orderRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.POST("/course/:id/purchase", authMiddleware(options), wrapHandler(options, router.purchaseCourse))
+	options.Handler.GET("/me/orders", authMiddleware(options), wrapHandler(options, router.getMyOrders))
+}
+
+type orderResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,course_free,own_course,already_owned,purchase_in_progress,payment_declined,email_not_verified"`
+} // @name orderResponseError
+
+func (e orderResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type purchaseCourseRequestBody struct {
+	*service.PurchaseCourseOptions
+} // @name purchaseCourseRequestBody
+
+type orderResponseBody struct {
+	*entity.Order
+} // @name orderResponseBody
+
+// @id           PurchaseCourse
+// @Summary      Buys paid course, paid order enrolls user to the course.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body purchaseCourseRequestBody true "data"
+// @Success      200 {object} orderResponseBody
+// @Failure      422,500 {object} orderResponseError
+// @Router       /course/{id}/purchase [POST]
+func (r *orderRouter) purchaseCourse(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("purchaseCourse").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := purchaseCourseRequestBody{&service.PurchaseCourseOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	order, err := r.services.OrderService.PurchaseCourse(requestContext, body.PurchaseCourseOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, orderResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to purchase course", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to purchase course", Details: err}
+	}
+
+	logger.Info("successfully purchased course")
+	return &orderResponseBody{order}, nil
+}
+
+type getMyOrdersResponseBody struct {
+	Orders []*entity.Order `json:"orders"`
+} // @name getMyOrdersResponseBody
+
+// @id           GetMyOrders
+// @Summary      Lists orders of current user with their items, newest first.
+// @Produce      application/json
+// @Success      200 {object} getMyOrdersResponseBody
+// @Failure      422,500 {object} orderResponseError
+// @Router       /me/orders [GET]
+func (r *orderRouter) getMyOrders(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyOrders").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	orders, err := r.services.OrderService.GetMyOrders(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get orders", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get orders", Details: err}
+	}
+
+	logger.Info("successfully served orders")
+	return &getMyOrdersResponseBody{Orders: orders}, nil
+}
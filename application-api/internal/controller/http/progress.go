@@ -0,0 +1,132 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type progressRouter struct {
+	RouterContext
+}
+
+func setupProgressRoutes(options RouterOptions) {
+	router := &progressRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.PUT("/lessons/:id/progress", authMiddleware(options), wrapHandler(options, router.recordProgress))
+	options.Handler.GET("/me/courses", authMiddleware(options, entity.ScopeEnrollmentsRead), wrapHandler(options, router.getMyCourses))
+}
+
+type progressResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"lesson_not_found,not_enrolled,invalid_progress"`
+} // @name progressResponseError
+
+func (e progressResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type recordProgressRequestBody struct {
+	*service.RecordProgressOptions
+} // @name recordProgressRequestBody
+
+type recordProgressResponseBody struct {
+	*entity.LessonProgress
+} // @name recordProgressResponseBody
+
+// @id           RecordProgress
+// @Summary      Heartbeat of lesson player, merges watched time and position into lesson progress.
+// @Description  Repeated heartbeats are safe, watched time never decreases and completed lesson stays completed.
+// @Description  Position of heartbeat sent before the last recorded one is ignored.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "lesson id"
+// @Param        fields body recordProgressRequestBody true "data"
+// @Success      200 {object} recordProgressResponseBody
+// @Failure      422,500 {object} progressResponseError
+// @Router       /lessons/{id}/progress [PUT]
+func (r *progressRouter) recordProgress(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("recordProgress").WithContext(requestContext)
+
+	lessonId := requestContext.Param("id")
+	if _, err := uuid.Parse(lessonId); err != nil {
+		logger.Info("invalid lesson id parameter", "param", lessonId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid lesson id parameter"}
+	}
+
+	body := recordProgressRequestBody{&service.RecordProgressOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.RecordProgressOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, progressResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.LessonId = lessonId
+	logger = logger.With("userId", userId, "lessonId", lessonId)
+
+	progress, err := r.services.ProgressService.RecordProgress(requestContext, body.RecordProgressOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, progressResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to record progress", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to record progress", Details: err}
+	}
+
+	logger.Debug("successfully recorded progress")
+	return &recordProgressResponseBody{progress}, nil
+}
+
+type getMyCoursesResponseBody struct {
+	Courses []*service.MyCourse `json:"courses"`
+} // @name getMyCoursesResponseBody
+
+// @id           GetMyCourses
+// @Summary      Lists courses current user is enrolled in with completion progress.
+// @Produce      application/json
+// @Success      200 {object} getMyCoursesResponseBody
+// @Failure      422,500 {object} progressResponseError
+// @Router       /me/courses [GET]
+func (r *progressRouter) getMyCourses(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyCourses").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	courses, err := r.services.ProgressService.GetMyCourses(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get courses", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get courses", Details: err}
+	}
+
+	logger.Info("successfully served courses")
+	return &getMyCoursesResponseBody{Courses: courses}, nil
+}
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type quizRouter struct {
+	RouterContext
+}
+
+func setupQuizRoutes(options RouterOptions) {
+	router := &quizRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.POST("/sections/:id/quizzes", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.addQuiz))
+	options.Handler.POST("/quizzes/:id/questions", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.addQuizQuestion))
+
+	routerGroup := options.Handler.Group("/quizzes", authMiddleware(options))
+	{
+		routerGroup.POST("/:id/attempts", wrapHandler(options, router.startQuizAttempt))
+		routerGroup.GET("/:id/attempts", wrapHandler(options, router.getQuizAttempts))
+	}
+
+	options.Handler.POST("/quiz-attempts/:id/submit", authMiddleware(options), wrapHandler(options, router.submitQuizAttempt))
+}
+
+type quizResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"section_not_found,quiz_not_found,attempt_not_found,not_allowed,not_enrolled,quiz_empty,no_attempts_left,attempt_submitted,attempt_expired,invalid_title,invalid_passing_score,invalid_limits,text_required,invalid_points,invalid_type,invalid_options,unexpected_options,invalid_answers,answers_required,invalid_correct_options"`
+} // @name quizResponseError
+
+func (e quizResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type addQuizRequestBody struct {
+	*service.AddQuizOptions
+} // @name addQuizRequestBody
+
+type quizResponseBody struct {
+	*entity.Quiz
+} // @name quizResponseBody
+
+// @id           AddQuiz
+// @Summary      Adds quiz to section, only course teacher can add quizzes.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "section id"
+// @Param        fields body addQuizRequestBody true "data"
+// @Success      200 {object} quizResponseBody
+// @Failure      422,500 {object} quizResponseError
+// @Router       /sections/{id}/quizzes [POST]
+func (r *quizRouter) addQuiz(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addQuiz").WithContext(requestContext)
+
+	sectionId := requestContext.Param("id")
+	if _, err := uuid.Parse(sectionId); err != nil {
+		logger.Info("invalid section id parameter", "param", sectionId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid section id parameter"}
+	}
+
+	body := addQuizRequestBody{&service.AddQuizOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddQuizOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.SectionId = sectionId
+	logger = logger.With("userId", userId, "sectionId", sectionId)
+
+	quiz, err := r.services.QuizService.AddQuiz(requestContext, body.AddQuizOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add quiz", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add quiz", Details: err}
+	}
+
+	logger.Info("successfully added quiz")
+	return &quizResponseBody{quiz}, nil
+}
+
+type addQuizQuestionRequestBody struct {
+	*service.AddQuizQuestionOptions
+} // @name addQuizQuestionRequestBody
+
+// addQuizQuestionResponseBody - includes answers, it is returned to course teacher only.
+type addQuizQuestionResponseBody struct {
+	*entity.QuizQuestion
+	Answers []string `json:"answers"`
+} // @name addQuizQuestionResponseBody
+
+// @id           AddQuizQuestion
+// @Summary      Adds question with correct answers to quiz, only course teacher can add questions.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "quiz id"
+// @Param        fields body addQuizQuestionRequestBody true "data"
+// @Success      200 {object} addQuizQuestionResponseBody
+// @Failure      422,500 {object} quizResponseError
+// @Router       /quizzes/{id}/questions [POST]
+func (r *quizRouter) addQuizQuestion(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addQuizQuestion").WithContext(requestContext)
+
+	quizId := requestContext.Param("id")
+	if _, err := uuid.Parse(quizId); err != nil {
+		logger.Info("invalid quiz id parameter", "param", quizId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid quiz id parameter"}
+	}
+
+	body := addQuizQuestionRequestBody{&service.AddQuizQuestionOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddQuizQuestionOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.QuizId = quizId
+	logger = logger.With("userId", userId, "quizId", quizId)
+
+	question, err := r.services.QuizService.AddQuizQuestion(requestContext, body.AddQuizQuestionOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add question", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add question", Details: err}
+	}
+
+	logger.Info("successfully added question")
+	return &addQuizQuestionResponseBody{QuizQuestion: question, Answers: question.Answers}, nil
+}
+
+type quizAttemptResponseBody struct {
+	*service.QuizAttemptOutput
+} // @name quizAttemptResponseBody
+
+// @id           StartQuizAttempt
+// @Summary      Starts attempt of enrolled student, returns open attempt instead if there is one.
+// @Description  Questions are returned without correct answers, time limit counts from start of the attempt.
+// @Produce      application/json
+// @Param        id path string true "quiz id"
+// @Success      200 {object} quizAttemptResponseBody
+// @Failure      422,500 {object} quizResponseError
+// @Router       /quizzes/{id}/attempts [POST]
+func (r *quizRouter) startQuizAttempt(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("startQuizAttempt").WithContext(requestContext)
+
+	quizId := requestContext.Param("id")
+	if _, err := uuid.Parse(quizId); err != nil {
+		logger.Info("invalid quiz id parameter", "param", quizId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid quiz id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "quizId", quizId)
+
+	output, err := r.services.QuizService.StartQuizAttempt(requestContext, &service.StartQuizAttemptOptions{
+		UserId: userId,
+		QuizId: quizId,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to start attempt", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to start attempt", Details: err}
+	}
+
+	logger.Info("successfully started attempt")
+	return &quizAttemptResponseBody{output}, nil
+}
+
+type getQuizAttemptsResponseBody struct {
+	Attempts []*entity.QuizAttempt `json:"attempts"`
+} // @name getQuizAttemptsResponseBody
+
+// @id           GetQuizAttempts
+// @Summary      Lists own attempts in quiz, newest first.
+// @Produce      application/json
+// @Param        id path string true "quiz id"
+// @Success      200 {object} getQuizAttemptsResponseBody
+// @Failure      422,500 {object} quizResponseError
+// @Router       /quizzes/{id}/attempts [GET]
+func (r *quizRouter) getQuizAttempts(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getQuizAttempts").WithContext(requestContext)
+
+	quizId := requestContext.Param("id")
+	if _, err := uuid.Parse(quizId); err != nil {
+		logger.Info("invalid quiz id parameter", "param", quizId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid quiz id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "quizId", quizId)
+
+	attempts, err := r.services.QuizService.GetQuizAttempts(requestContext, &service.GetQuizAttemptsOptions{
+		UserId: userId,
+		QuizId: quizId,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get attempts", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get attempts", Details: err}
+	}
+
+	logger.Info("successfully served attempts")
+	return &getQuizAttemptsResponseBody{Attempts: attempts}, nil
+}
+
+type submitQuizAttemptRequestBody struct {
+	*service.SubmitQuizAttemptOptions
+} // @name submitQuizAttemptRequestBody
+
+type submitQuizAttemptResponseBody struct {
+	*service.SubmitQuizAttemptOutput
+} // @name submitQuizAttemptResponseBody
+
+// @id           SubmitQuizAttempt
+// @Summary      Grades answers of open attempt, attempt can be submitted once.
+// @Description  Attempts submitted after time limit are graded without answers.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "attempt id"
+// @Param        fields body submitQuizAttemptRequestBody true "data"
+// @Success      200 {object} submitQuizAttemptResponseBody
+// @Failure      422,500 {object} quizResponseError
+// @Router       /quiz-attempts/{id}/submit [POST]
+func (r *quizRouter) submitQuizAttempt(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("submitQuizAttempt").WithContext(requestContext)
+
+	attemptId := requestContext.Param("id")
+	if _, err := uuid.Parse(attemptId); err != nil {
+		logger.Info("invalid attempt id parameter", "param", attemptId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid attempt id parameter"}
+	}
+
+	body := submitQuizAttemptRequestBody{&service.SubmitQuizAttemptOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.AttemptId = attemptId
+	logger = logger.With("userId", userId, "attemptId", attemptId)
+
+	output, err := r.services.QuizService.SubmitQuizAttempt(requestContext, body.SubmitQuizAttemptOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, quizResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to submit attempt", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to submit attempt", Details: err}
+	}
+
+	logger.Info("successfully submitted attempt")
+	return &submitQuizAttemptResponseBody{output}, nil
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type refundRouter struct {
+	RouterContext
+}
+
+func setupRefundRoutes(options RouterOptions) {
+	router := &refundRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.POST("/course/:id/refund", authMiddleware(options), wrapHandler(options, router.requestRefund))
+	options.Handler.GET("/me/refunds", authMiddleware(options), wrapHandler(options, router.getMyRefunds))
+}
+
+type refundResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"not_purchased,window_expired,progress_exceeded,course_completed,already_requested,invalid_reason"`
+} // @name refundResponseError
+
+func (e refundResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type requestRefundRequestBody struct {
+	*service.RequestRefundOptions
+} // @name requestRefundRequestBody
+
+type refundResponseBody struct {
+	*entity.Refund
+} // @name refundResponseBody
+
+// @id           RequestRefund
+// @Summary      Refunds purchased course within refund policy, access to the course is revoked.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body requestRefundRequestBody true "data"
+// @Success      200 {object} refundResponseBody
+// @Failure      422,500 {object} refundResponseError
+// @Router       /course/{id}/refund [POST]
+func (r *refundRouter) requestRefund(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("requestRefund").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := requestRefundRequestBody{&service.RequestRefundOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.RequestRefundOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, refundResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	refund, err := r.services.RefundService.RequestRefund(requestContext, body.RequestRefundOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, refundResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to request refund", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to request refund", Details: err}
+	}
+
+	logger.Info("successfully refunded course")
+	return &refundResponseBody{refund}, nil
+}
+
+type getMyRefundsResponseBody struct {
+	Refunds []*entity.Refund `json:"refunds"`
+} // @name getMyRefundsResponseBody
+
+// @id           GetMyRefunds
+// @Summary      Lists refunds of current user, newest first.
+// @Produce      application/json
+// @Success      200 {object} getMyRefundsResponseBody
+// @Failure      422,500 {object} refundResponseError
+// @Router       /me/refunds [GET]
+func (r *refundRouter) getMyRefunds(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyRefunds").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	refunds, err := r.services.RefundService.GetMyRefunds(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get refunds", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get refunds", Details: err}
+	}
+
+	logger.Info("successfully served refunds")
+	return &getMyRefundsResponseBody{Refunds: refunds}, nil
+}
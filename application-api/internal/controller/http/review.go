@@ -0,0 +1,311 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type reviewRouter struct {
+	RouterContext
+}
+
+func setupReviewRoutes(options RouterOptions) {
+	router := &reviewRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	courseGroup := options.Handler.Group("/course/:id/reviews")
+	{
+		courseGroup.GET("", wrapHandler(options, router.getReviews))
+		courseGroup.POST("", authMiddleware(options), wrapHandler(options, router.createReview))
+	}
+
+	routerGroup := options.Handler.Group("/reviews", authMiddleware(options))
+	{
+		routerGroup.PATCH("/:id", wrapHandler(options, router.updateReview))
+		routerGroup.POST("/:id/reply", wrapHandler(options, router.replyToReview))
+		routerGroup.POST("/:id/moderate", wrapHandler(options, router.moderateReview))
+	}
+}
+
+type reviewResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"course_not_found,review_not_enrolled,already_reviewed,review_not_found,reply_not_allowed,review_moderation_not_allowed,invalid_rating,invalid_text,reply_required,invalid_reply"`
+} // @name reviewResponseError
+
+func (e reviewResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type reviewResponseBody struct {
+	*entity.Review
+} // @name reviewResponseBody
+
+type getReviewsResponseBody struct {
+	Reviews []*entity.Review `json:"reviews"`
+} // @name getReviewsResponseBody
+
+// @id           GetReviews
+// @Summary      Lists visible reviews of course.
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Success      200 {object} getReviewsResponseBody
+// @Failure      422,500 {object} reviewResponseError
+// @Router       /course/{id}/reviews [GET]
+func (r *reviewRouter) getReviews(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getReviews").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+	logger = logger.With("courseId", courseId)
+
+	reviews, err := r.services.ReviewService.GetReviews(requestContext, courseId)
+	if err != nil {
+		logger.Error("failed to get reviews", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get reviews", Details: err}
+	}
+
+	logger.Info("successfully served reviews")
+	return &getReviewsResponseBody{Reviews: reviews}, nil
+}
+
+type createReviewRequestBody struct {
+	*service.CreateReviewOptions
+} // @name createReviewRequestBody
+
+// @id           CreateReview
+// @Summary      Rates course, only enrolled students can review and only once.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "course id"
+// @Param        fields body createReviewRequestBody true "data"
+// @Success      200 {object} reviewResponseBody
+// @Failure      422,500 {object} reviewResponseError
+// @Router       /course/{id}/reviews [POST]
+func (r *reviewRouter) createReview(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("createReview").WithContext(requestContext)
+
+	courseId := requestContext.Param("id")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	body := createReviewRequestBody{&service.CreateReviewOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.CreateReviewOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.CourseId = courseId
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	review, err := r.services.ReviewService.CreateReview(requestContext, body.CreateReviewOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to create review", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to create review", Details: err}
+	}
+
+	logger.Info("successfully created review")
+	return &reviewResponseBody{review}, nil
+}
+
+type updateReviewRequestBody struct {
+	*service.UpdateReviewOptions
+} // @name updateReviewRequestBody
+
+// @id           UpdateReview
+// @Summary      Changes rating and text of own review.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "review id"
+// @Param        fields body updateReviewRequestBody true "data"
+// @Success      200 {object} reviewResponseBody
+// @Failure      422,500 {object} reviewResponseError
+// @Router       /reviews/{id} [PATCH]
+func (r *reviewRouter) updateReview(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("updateReview").WithContext(requestContext)
+
+	reviewId := requestContext.Param("id")
+	if _, err := uuid.Parse(reviewId); err != nil {
+		logger.Info("invalid review id parameter", "param", reviewId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid review id parameter"}
+	}
+
+	body := updateReviewRequestBody{&service.UpdateReviewOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.UpdateReviewOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.ReviewId = reviewId
+	logger = logger.With("userId", userId, "reviewId", reviewId)
+
+	review, err := r.services.ReviewService.UpdateReview(requestContext, body.UpdateReviewOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to update review", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to update review", Details: err}
+	}
+
+	logger.Info("successfully updated review")
+	return &reviewResponseBody{review}, nil
+}
+
+type replyToReviewRequestBody struct {
+	*service.ReplyToReviewOptions
+} // @name replyToReviewRequestBody
+
+// @id           ReplyToReview
+// @Summary      Sets reply of course teacher to review, replying again overwrites the reply.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "review id"
+// @Param        fields body replyToReviewRequestBody true "data"
+// @Success      200 {object} reviewResponseBody
+// @Failure      422,500 {object} reviewResponseError
+// @Router       /reviews/{id}/reply [POST]
+func (r *reviewRouter) replyToReview(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("replyToReview").WithContext(requestContext)
+
+	reviewId := requestContext.Param("id")
+	if _, err := uuid.Parse(reviewId); err != nil {
+		logger.Info("invalid review id parameter", "param", reviewId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid review id parameter"}
+	}
+
+	body := replyToReviewRequestBody{&service.ReplyToReviewOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.ReplyToReviewOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.ReviewId = reviewId
+	logger = logger.With("userId", userId, "reviewId", reviewId)
+
+	review, err := r.services.ReviewService.ReplyToReview(requestContext, body.ReplyToReviewOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to reply to review", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to reply to review", Details: err}
+	}
+
+	logger.Info("successfully replied to review")
+	return &reviewResponseBody{review}, nil
+}
+
+type moderateReviewRequestBody struct {
+	*service.ModerateReviewOptions
+} // @name moderateReviewRequestBody
+
+// @id           ModerateReview
+// @Summary      Hides or restores review, hidden reviews don't count towards course rating.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "review id"
+// @Param        fields body moderateReviewRequestBody true "data"
+// @Success      200 {object} reviewResponseBody
+// @Failure      422,500 {object} reviewResponseError
+// @Router       /reviews/{id}/moderate [POST]
+func (r *reviewRouter) moderateReview(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("moderateReview").WithContext(requestContext)
+
+	reviewId := requestContext.Param("id")
+	if _, err := uuid.Parse(reviewId); err != nil {
+		logger.Info("invalid review id parameter", "param", reviewId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid review id parameter"}
+	}
+
+	body := moderateReviewRequestBody{&service.ModerateReviewOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	body.ReviewId = reviewId
+	logger = logger.With("userId", userId, "reviewId", reviewId)
+
+	review, err := r.services.ReviewService.ModerateReview(requestContext, body.ModerateReviewOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, reviewResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to moderate review", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to moderate review", Details: err}
+	}
+
+	logger.Info("successfully moderated review")
+	return &reviewResponseBody{review}, nil
+}
@@ -0,0 +1,286 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"net/http"
+)
+
+// _maxWebhookSize limits payload of payment provider webhooks.
+const _maxWebhookSize = 64 << 10
+
+type subscriptionRouter struct {
+	RouterContext
+}
+
+func setupSubscriptionRoutes(options RouterOptions) {
+	router := &subscriptionRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/subscription")
+	{
+		routerGroup.GET("/plans", wrapHandler(options, router.getPlans))
+		routerGroup.POST("/plans", authMiddleware(options), wrapHandler(options, router.createPlan))
+		routerGroup.POST("", authMiddleware(options), wrapHandler(options, router.subscribe))
+	}
+
+	meGroup := options.Handler.Group("/me/subscription", authMiddleware(options))
+	{
+		meGroup.GET("", wrapHandler(options, router.getMySubscription))
+		meGroup.POST("/cancel", wrapHandler(options, router.cancelMySubscription))
+	}
+
+	// webhooks are authenticated by provider signature instead of access token
+	options.Handler.POST("/payments/webhook", wrapHandler(options, router.handleWebhook))
+}
+
+type subscriptionResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"plan_not_allowed,plan_not_found,already_subscribed,payment_declined,subscription_not_found,invalid_signature,invalid_plan_name,invalid_price,invalid_trial_days,invalid_plan_id,email_not_verified"`
+} // @name subscriptionResponseError
+
+func (e subscriptionResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getPlansResponseBody struct {
+	Plans []*entity.SubscriptionPlan `json:"plans"`
+} // @name getPlansResponseBody
+
+// @id           GetPlans
+// @Summary      Lists monthly subscription plans available for subscribing, cheapest first.
+// @Produce      application/json
+// @Success      200 {object} getPlansResponseBody
+// @Failure      422,500 {object} subscriptionResponseError
+// @Router       /subscription/plans [GET]
+func (r *subscriptionRouter) getPlans(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getPlans").WithContext(requestContext)
+
+	plans, err := r.services.SubscriptionService.GetPlans(requestContext)
+	if err != nil {
+		logger.Error("failed to get plans", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get plans", Details: err}
+	}
+
+	logger.Info("successfully served plans")
+	return &getPlansResponseBody{Plans: plans}, nil
+}
+
+type createPlanRequestBody struct {
+	*service.CreatePlanOptions
+} // @name createPlanRequestBody
+
+type planResponseBody struct {
+	*entity.SubscriptionPlan
+} // @name planResponseBody
+
+// @id           CreatePlan
+// @Summary      Creates monthly all-access subscription plan, price is in minor units, admins only.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body createPlanRequestBody true "data"
+// @Success      200 {object} planResponseBody
+// @Failure      422,500 {object} subscriptionResponseError
+// @Router       /subscription/plans [POST]
+func (r *subscriptionRouter) createPlan(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("createPlan").WithContext(requestContext)
+
+	body := createPlanRequestBody{&service.CreatePlanOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.CreatePlanOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	plan, err := r.services.SubscriptionService.CreatePlan(requestContext, body.CreatePlanOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to create plan", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to create plan", Details: err}
+	}
+
+	logger.Info("successfully created plan")
+	return &planResponseBody{plan}, nil
+}
+
+type subscribeRequestBody struct {
+	*service.SubscribeOptions
+} // @name subscribeRequestBody
+
+type subscriptionResponseBody struct {
+	*entity.Subscription
+} // @name subscriptionResponseBody
+
+// @id           Subscribe
+// @Summary      Starts subscription giving access to every course while it is trialing or active.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body subscribeRequestBody true "data"
+// @Success      200 {object} subscriptionResponseBody
+// @Failure      422,500 {object} subscriptionResponseError
+// @Router       /subscription [POST]
+func (r *subscriptionRouter) subscribe(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("subscribe").WithContext(requestContext)
+
+	body := subscribeRequestBody{&service.SubscribeOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.SubscribeOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId, "planId", body.PlanId)
+
+	subscription, err := r.services.SubscriptionService.Subscribe(requestContext, body.SubscribeOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to subscribe", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to subscribe", Details: err}
+	}
+
+	logger.Info("successfully subscribed")
+	return &subscriptionResponseBody{subscription}, nil
+}
+
+// @id           GetMySubscription
+// @Summary      Gets subscription of current user which isn't canceled.
+// @Produce      application/json
+// @Success      200 {object} subscriptionResponseBody
+// @Failure      422,500 {object} subscriptionResponseError
+// @Router       /me/subscription [GET]
+func (r *subscriptionRouter) getMySubscription(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMySubscription").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	subscription, err := r.services.SubscriptionService.GetMySubscription(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get subscription", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get subscription", Details: err}
+	}
+
+	logger.Info("successfully served subscription")
+	return &subscriptionResponseBody{subscription}, nil
+}
+
+// @id           CancelMySubscription
+// @Summary      Stops renewal of subscription of current user, access lasts until current period ends.
+// @Produce      application/json
+// @Success      200 {object} subscriptionResponseBody
+// @Failure      422,500 {object} subscriptionResponseError
+// @Router       /me/subscription/cancel [POST]
+func (r *subscriptionRouter) cancelMySubscription(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("cancelMySubscription").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	subscription, err := r.services.SubscriptionService.CancelMySubscription(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to cancel subscription", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to cancel subscription", Details: err}
+	}
+
+	logger.Info("successfully canceled subscription")
+	return &subscriptionResponseBody{subscription}, nil
+}
+
+type handleWebhookResponseBody struct {
+	Received bool `json:"received"`
+} // @name handleWebhookResponseBody
+
+// @id           HandlePaymentWebhook
+// @Summary      Receives payment provider events changing subscriptions, payload must be signed in X-Payment-Signature or Stripe-Signature header.
+// @Accept       application/json
+// @Produce      application/json
+// @Success      200 {object} handleWebhookResponseBody
+// @Failure      422,500 {object} subscriptionResponseError
+// @Router       /payments/webhook [POST]
+func (r *subscriptionRouter) handleWebhook(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("handleWebhook").WithContext(requestContext)
+
+	requestContext.Request.Body = http.MaxBytesReader(requestContext.Writer, requestContext.Request.Body, _maxWebhookSize)
+	payload, err := requestContext.GetRawData()
+	if err != nil {
+		logger.Info("failed to read request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+
+	signature := requestContext.GetHeader("X-Payment-Signature")
+	if signature == "" {
+		signature = requestContext.GetHeader("Stripe-Signature")
+	}
+
+	err = r.services.SubscriptionService.HandleWebhook(requestContext, payload, signature)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subscriptionResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to handle webhook", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to handle webhook", Details: err}
+	}
+
+	logger.Info("successfully handled webhook")
+	return &handleWebhookResponseBody{Received: true}, nil
+}
@@ -0,0 +1,282 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"io"
+	"net/http"
+)
+
+type subtitleRouter struct {
+	RouterContext
+}
+
+func setupSubtitleRoutes(options RouterOptions) {
+	router := &subtitleRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/lessons/:id/subtitles")
+	{
+		routerGroup.PUT("/:language", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.uploadSubtitle))
+		routerGroup.GET("/:language", authMiddleware(options), wrapHandler(options, router.getSubtitle))
+		routerGroup.DELETE("/:language", authMiddleware(options, entity.ScopeCoursesWrite), wrapHandler(options, router.deleteSubtitle))
+	}
+
+	options.Handler.GET("/search/lessons", wrapHandler(options, router.searchTranscripts))
+}
+
+// subtitleResponseError has line and reason of the first error in details of invalid_subtitles.
+type subtitleResponseError struct {
+	Message string            `json:"message"`
+	Code    string            `json:"code" enums:"lesson_not_found,not_allowed,not_enrolled,subtitle_not_found,invalid_subtitles,subtitles_too_large,invalid_language,invalid_label,invalid_query"`
+	Details map[string]string `json:"details,omitempty"`
+} // @name subtitleResponseError
+
+func (e subtitleResponseError) Error() *httpResponseError {
+	err := &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+	if len(e.Details) > 0 {
+		err.Details = e.Details
+	}
+	return err
+}
+
+type subtitleResponseBody struct {
+	*entity.LessonSubtitle
+} // @name subtitleResponseBody
+
+// @id           UploadSubtitle
+// @Summary      Uploads WebVTT or SRT subtitles of lesson in language, only course teacher can upload them.
+// @Description  SRT is converted to WebVTT, subtitles in the same language are replaced.
+// @Accept       multipart/form-data
+// @Produce      application/json
+// @Param        id path string true "lesson id"
+// @Param        language path string true "language tag, e.g. uk or pt-BR"
+// @Param        file formData file true "subtitles file"
+// @Param        label formData string false "track name shown by player, language by default"
+// @Success      200 {object} subtitleResponseBody
+// @Failure      422,500 {object} subtitleResponseError
+// @Router       /lessons/{id}/subtitles/{language} [PUT]
+func (r *subtitleRouter) uploadSubtitle(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("uploadSubtitle").WithContext(requestContext)
+
+	lessonId := requestContext.Param("id")
+	if _, err := uuid.Parse(lessonId); err != nil {
+		logger.Info("invalid lesson id parameter", "param", lessonId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid lesson id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+
+	maxFileSize := r.config.Subtitle.MaxFileSize
+	requestContext.Request.Body = http.MaxBytesReader(requestContext.Writer, requestContext.Request.Body, maxFileSize+_multipartOverhead)
+	fileHeader, err := requestContext.FormFile("file")
+	if err != nil {
+		logger.Info("failed to parse subtitles file", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid subtitles file", Details: err.Error()}
+	}
+	if fileHeader.Size > maxFileSize {
+		logger.Info("file is too large", "size", fileHeader.Size)
+		return nil, subtitleResponseError{Message: service.ErrUploadSubtitleFileTooLarge.Error(), Code: errs.GetCode(service.ErrUploadSubtitleFileTooLarge)}.Error()
+	}
+
+	options := &service.UploadSubtitleOptions{
+		UserId:   userId,
+		LessonId: lessonId,
+		Language: requestContext.Param("language"),
+		Label:    requestContext.PostForm("label"),
+	}
+	err = options.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, subtitleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger = logger.With("userId", userId, "lessonId", lessonId, "language", options.Language)
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("failed to open subtitles file", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to open subtitles file", Details: err}
+	}
+	defer file.Close()
+
+	// subtitles are parsed as a whole, file size is checked above, so reading it into memory is bounded
+	options.Content, err = io.ReadAll(io.LimitReader(file, maxFileSize+1))
+	if err != nil {
+		logger.Error("failed to read subtitles file", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to read subtitles file", Details: err}
+	}
+
+	uploaded, err := r.services.SubtitleService.UploadSubtitle(requestContext, options)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subtitleResponseError{Message: err.Error(), Code: errs.GetCode(err), Details: errs.GetDetails(err)}.Error()
+		}
+		logger.Error("failed to upload subtitles", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to upload subtitles", Details: err}
+	}
+
+	logger.Info("successfully uploaded subtitles")
+	return &subtitleResponseBody{uploaded}, nil
+}
+
+// @id           GetSubtitle
+// @Summary      Downloads WebVTT subtitles of lesson in language, available to enrolled users and course teacher.
+// @Produce      text/vtt
+// @Param        id path string true "lesson id"
+// @Param        language path string true "language tag, e.g. uk or pt-BR"
+// @Success      200 {file} file
+// @Failure      422,500 {object} subtitleResponseError
+// @Router       /lessons/{id}/subtitles/{language} [GET]
+func (r *subtitleRouter) getSubtitle(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getSubtitle").WithContext(requestContext)
+
+	lessonId := requestContext.Param("id")
+	if _, err := uuid.Parse(lessonId); err != nil {
+		logger.Info("invalid lesson id parameter", "param", lessonId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid lesson id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	language := requestContext.Param("language")
+	logger = logger.With("userId", userId, "lessonId", lessonId, "language", language)
+
+	found, err := r.services.SubtitleService.GetSubtitle(requestContext, &service.GetSubtitleOptions{
+		UserId:   userId,
+		LessonId: lessonId,
+		Language: language,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subtitleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get subtitles", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get subtitles", Details: err}
+	}
+
+	// response is written here, content type set by cors middleware has to be replaced first
+	requestContext.Header("Content-Type", "text/vtt; charset=utf-8")
+	requestContext.Header("Content-Language", found.Language)
+	requestContext.Data(http.StatusOK, "text/vtt; charset=utf-8", []byte(found.Content))
+
+	logger.Info("successfully served subtitles")
+	return nil, nil
+}
+
+type deleteSubtitleResponseBody struct {
+	Deleted bool `json:"deleted"`
+} // @name deleteSubtitleResponseBody
+
+// @id           DeleteSubtitle
+// @Summary      Deletes subtitles of lesson in language with their transcript, only course teacher can delete them.
+// @Produce      application/json
+// @Param        id path string true "lesson id"
+// @Param        language path string true "language tag, e.g. uk or pt-BR"
+// @Success      200 {object} deleteSubtitleResponseBody
+// @Failure      422,500 {object} subtitleResponseError
+// @Router       /lessons/{id}/subtitles/{language} [DELETE]
+func (r *subtitleRouter) deleteSubtitle(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("deleteSubtitle").WithContext(requestContext)
+
+	lessonId := requestContext.Param("id")
+	if _, err := uuid.Parse(lessonId); err != nil {
+		logger.Info("invalid lesson id parameter", "param", lessonId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid lesson id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	language := requestContext.Param("language")
+	logger = logger.With("userId", userId, "lessonId", lessonId, "language", language)
+
+	err := r.services.SubtitleService.DeleteSubtitle(requestContext, &service.DeleteSubtitleOptions{
+		UserId:   userId,
+		LessonId: lessonId,
+		Language: language,
+	})
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, subtitleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to delete subtitles", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to delete subtitles", Details: err}
+	}
+
+	logger.Info("successfully deleted subtitles")
+	return &deleteSubtitleResponseBody{Deleted: true}, nil
+}
+
+type searchTranscriptsResponseBody struct {
+	Matches []*entity.TranscriptMatch `json:"matches"`
+} // @name searchTranscriptsResponseBody
+
+// @id           SearchTranscripts
+// @Summary      Finds lessons of published courses which transcripts mention query, best matches first.
+// @Description  Every lesson is returned once with its best matching cue, startMs is where the player should seek to.
+// @Description  Text of the cue is returned only for courses the user has access to, access token is optional.
+// @Produce      application/json
+// @Param        q query string true "words, \"quoted phrases\", or and -excluded words"
+// @Param        courseId query string false "searches the course only"
+// @Param        language query string false "searches transcripts in the language only"
+// @Param        limit query int false "number of lessons, 20 by default and 100 at most"
+// @Success      200 {object} searchTranscriptsResponseBody
+// @Failure      422,500 {object} subtitleResponseError
+// @Router       /search/lessons [GET]
+func (r *subtitleRouter) searchTranscripts(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("searchTranscripts").WithContext(requestContext)
+
+	options := &service.SearchTranscriptsOptions{}
+	err := requestContext.ShouldBindQuery(options)
+	if err != nil {
+		logger.Info("failed to parse request query", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request query", Details: err}
+	}
+	if options.CourseId != "" {
+		if _, err := uuid.Parse(options.CourseId); err != nil {
+			logger.Info("invalid course id parameter", "param", options.CourseId)
+			return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+		}
+	}
+	err = options.Validate()
+	if err != nil {
+		logger.Info("invalid request query", "err", err)
+		return nil, subtitleResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	options.UserId = optionalUserId(requestContext, r.services)
+	logger = logger.With("query", options.Query, "courseId", options.CourseId, "userId", options.UserId)
+	logger.Debug("parsed request query")
+
+	matches, err := r.services.SubtitleService.SearchTranscripts(requestContext, options)
+	if err != nil {
+		logger.Error("failed to search transcripts", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to search transcripts", Details: err}
+	}
+
+	logger.Info("successfully searched transcripts", "matches", len(matches))
+	return &searchTranscriptsResponseBody{Matches: matches}, nil
+}
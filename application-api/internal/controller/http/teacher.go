@@ -0,0 +1,165 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type teacherRouter struct {
+	RouterContext
+}
+
+func setupTeacherRoutes(options RouterOptions) {
+	router := &teacherRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.GET("/teachers/:id", wrapHandler(options, router.getTeacher))
+
+	routerGroup := options.Handler.Group("/me/teacher-profile", authMiddleware(options))
+	{
+		routerGroup.GET("", wrapHandler(options, router.getMyProfile))
+		routerGroup.PUT("", wrapHandler(options, router.updateMyProfile))
+	}
+}
+
+type teacherResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"teacher_not_found,not_teacher,invalid_display_name,display_name_taken,invalid_bio,invalid_avatar_url,invalid_social_links,too_many_social_links"`
+} // @name teacherResponseError
+
+func (e teacherResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type getTeacherResponseBody struct {
+	Profile *entity.TeacherProfile `json:"profile"`
+	Courses []*entity.Course       `json:"courses"`
+	Stats   *entity.TeacherStats   `json:"stats"`
+} // @name getTeacherResponseBody
+
+// @id           GetTeacher
+// @Summary      Gets public profile of teacher with published courses and their aggregate stats.
+// @Produce      application/json
+// @Param        id path string true "teacher user id"
+// @Success      200 {object} getTeacherResponseBody
+// @Failure      422,500 {object} teacherResponseError
+// @Router       /teachers/{id} [GET]
+func (r *teacherRouter) getTeacher(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getTeacher").WithContext(requestContext)
+
+	teacherId := requestContext.Param("id")
+	if _, err := uuid.Parse(teacherId); err != nil {
+		logger.Info("invalid teacher id parameter", "param", teacherId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid teacher id parameter"}
+	}
+	logger = logger.With("teacherId", teacherId)
+
+	teacher, err := r.services.TeacherService.GetTeacher(requestContext, teacherId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, teacherResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get teacher", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get teacher", Details: err}
+	}
+
+	logger.Info("successfully served teacher")
+	return &getTeacherResponseBody{Profile: teacher.Profile, Courses: teacher.Courses, Stats: teacher.Stats}, nil
+}
+
+type teacherProfileResponseBody struct {
+	*entity.TeacherProfile
+} // @name teacherProfileResponseBody
+
+// @id           GetMyTeacherProfile
+// @Summary      Gets profile of current teacher.
+// @Produce      application/json
+// @Success      200 {object} teacherProfileResponseBody
+// @Failure      422,500 {object} teacherResponseError
+// @Router       /me/teacher-profile [GET]
+func (r *teacherRouter) getMyProfile(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getMyProfile").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	profile, err := r.services.TeacherService.GetMyProfile(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, teacherResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to get teacher profile", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get teacher profile", Details: err}
+	}
+
+	logger.Info("successfully served teacher profile")
+	return &teacherProfileResponseBody{profile}, nil
+}
+
+type updateTeacherProfileRequestBody struct {
+	*service.UpdateTeacherProfileOptions
+} // @name updateTeacherProfileRequestBody
+
+// @id           UpdateMyTeacherProfile
+// @Summary      Overwrites profile of current teacher, display name becomes author of all teacher courses.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body updateTeacherProfileRequestBody true "data"
+// @Success      200 {object} teacherProfileResponseBody
+// @Failure      422,500 {object} teacherResponseError
+// @Router       /me/teacher-profile [PUT]
+func (r *teacherRouter) updateMyProfile(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("updateMyProfile").WithContext(requestContext)
+
+	body := updateTeacherProfileRequestBody{&service.UpdateTeacherProfileOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.UpdateTeacherProfileOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, teacherResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	profile, err := r.services.TeacherService.UpdateMyProfile(requestContext, body.UpdateTeacherProfileOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, teacherResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to update teacher profile", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to update teacher profile", Details: err}
+	}
+
+	logger.Info("successfully updated teacher profile")
+	return &teacherProfileResponseBody{profile}, nil
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type twoFactorRouter struct {
+	RouterContext
+}
+
+// setupTwoFactorRoutes registers routes under auth group, so they share its rate limit.
+func setupTwoFactorRoutes(options RouterOptions) {
+	router := &twoFactorRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/2fa")
+	{
+		routerGroup.POST("/enroll", authMiddleware(options), wrapHandler(options, router.enroll))
+		routerGroup.POST("/enable", authMiddleware(options), wrapHandler(options, router.enable))
+		routerGroup.POST("/disable", authMiddleware(options), wrapHandler(options, router.disable))
+		routerGroup.POST("/verify", wrapHandler(options, router.verify))
+	}
+}
+
+type twoFactorResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"two_factor_already_enabled,two_factor_not_enrolled,two_factor_not_enabled,invalid_code,invalid_challenge"`
+} // @name twoFactorResponseError
+
+func (e twoFactorResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type enrollTwoFactorResponseBody struct {
+	*service.EnrollTwoFactorOutput
+} // @name enrollTwoFactorResponseBody
+
+// @id           EnrollTwoFactor
+// @Summary      Generates TOTP secret and provisioning URI to be rendered as QR code.
+// @Produce      application/json
+// @Success      200 {object} enrollTwoFactorResponseBody
+// @Failure      422,500 {object} twoFactorResponseError
+// @Router       /auth/2fa/enroll [POST]
+func (t *twoFactorRouter) enroll(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := t.logger.Named("enrollTwoFactor").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	enrolled, err := t.services.TwoFactorService.Enroll(requestContext, userId)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, twoFactorResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to enroll two-factor authentication", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to enroll two-factor authentication", Details: err}
+	}
+
+	logger.Info("successfully enrolled two-factor authentication")
+	return &enrollTwoFactorResponseBody{enrolled}, nil
+}
+
+type enableTwoFactorRequestBody struct {
+	*service.EnableTwoFactorOptions
+} // @name enableTwoFactorRequestBody
+
+type enableTwoFactorResponseBody struct {
+	*service.EnableTwoFactorOutput
+} // @name enableTwoFactorResponseBody
+
+// @id           EnableTwoFactor
+// @Summary      Confirms enrollment with a code and returns single-use recovery codes.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body enableTwoFactorRequestBody true "data"
+// @Success      200 {object} enableTwoFactorResponseBody
+// @Failure      422,500 {object} twoFactorResponseError
+// @Router       /auth/2fa/enable [POST]
+func (t *twoFactorRouter) enable(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := t.logger.Named("enableTwoFactor").WithContext(requestContext)
+
+	body := enableTwoFactorRequestBody{&service.EnableTwoFactorOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	enabled, err := t.services.TwoFactorService.Enable(requestContext, body.EnableTwoFactorOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, twoFactorResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to enable two-factor authentication", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to enable two-factor authentication", Details: err}
+	}
+
+	logger.Info("successfully enabled two-factor authentication")
+	return &enableTwoFactorResponseBody{enabled}, nil
+}
+
+type disableTwoFactorRequestBody struct {
+	*service.DisableTwoFactorOptions
+} // @name disableTwoFactorRequestBody
+
+type disableTwoFactorResponseBody struct {
+	Disabled bool `json:"disabled"`
+} // @name disableTwoFactorResponseBody
+
+// @id           DisableTwoFactor
+// @Summary      Turns two-factor authentication off, requires a code or recovery code.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body disableTwoFactorRequestBody true "data"
+// @Success      200 {object} disableTwoFactorResponseBody
+// @Failure      422,500 {object} twoFactorResponseError
+// @Router       /auth/2fa/disable [POST]
+func (t *twoFactorRouter) disable(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := t.logger.Named("disableTwoFactor").WithContext(requestContext)
+
+	body := disableTwoFactorRequestBody{&service.DisableTwoFactorOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId)
+
+	err = t.services.TwoFactorService.Disable(requestContext, body.DisableTwoFactorOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, twoFactorResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to disable two-factor authentication", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to disable two-factor authentication", Details: err}
+	}
+
+	logger.Info("successfully disabled two-factor authentication")
+	return &disableTwoFactorResponseBody{Disabled: true}, nil
+}
+
+type verifyTwoFactorRequestBody struct {
+	*service.VerifyTwoFactorOptions
+} // @name verifyTwoFactorRequestBody
+
+type verifyTwoFactorResponseBody struct {
+	*service.SignInOutput
+} // @name verifyTwoFactorResponseBody
+
+// @id           VerifyTwoFactor
+// @Summary      Completes sign in with challenge token and a code or recovery code.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body verifyTwoFactorRequestBody true "data"
+// @Success      200 {object} verifyTwoFactorResponseBody
+// @Failure      422,500 {object} twoFactorResponseError
+// @Router       /auth/2fa/verify [POST]
+func (t *twoFactorRouter) verify(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := t.logger.Named("verifyTwoFactor").WithContext(requestContext)
+
+	body := verifyTwoFactorRequestBody{&service.VerifyTwoFactorOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body")
+
+	signed, err := t.services.TwoFactorService.Verify(requestContext, body.VerifyTwoFactorOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, twoFactorResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to verify two-factor authentication", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to verify two-factor authentication", Details: err}
+	}
+
+	logger.Info("successfully signed in with two-factor authentication")
+	return &verifyTwoFactorResponseBody{signed}, nil
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+)
+
+type wellKnownRouter struct {
+	RouterContext
+}
+
+// setupWellKnownRoutes registers public discovery documents, they are served outside of /api/v1 and rate limiting.
+func setupWellKnownRoutes(options RouterOptions) {
+	router := &wellKnownRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	options.Handler.GET("/jwks.json", wrapHandler(options, router.jwks))
+	options.Handler.GET("/certificate-jwks.json", wrapHandler(options, router.certificateJWKS))
+}
+
+type jwksResponseBody struct {
+	*jwks.Set
+} // @name jwksResponseBody
+
+// @id           JWKS
+// @Summary      Returns public keys access tokens are signed with.
+// @Produce      application/json
+// @Success      200 {object} jwksResponseBody
+// @Router       /.well-known/jwks.json [GET]
+func (w *wellKnownRouter) jwks(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	// verifiers cache keys, an hour is short enough to pick up a new key before it signs tokens
+	requestContext.Header("Cache-Control", "public, max-age=3600")
+	return &jwksResponseBody{w.services.AuthService.PublicKeys(requestContext)}, nil
+}
+
+// @id           CertificateJWKS
+// @Summary      Returns public keys certificates are signed with.
+// @Produce      application/json
+// @Success      200 {object} jwksResponseBody
+// @Router       /.well-known/certificate-jwks.json [GET]
+func (w *wellKnownRouter) certificateJWKS(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	requestContext.Header("Cache-Control", "public, max-age=3600")
+	return &jwksResponseBody{w.services.CertificateService.PublicKeys(requestContext)}, nil
+}
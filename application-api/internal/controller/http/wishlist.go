@@ -0,0 +1,156 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/service"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type wishlistRouter struct {
+	RouterContext
+}
+
+func setupWishlistRoutes(options RouterOptions) {
+	router := &wishlistRouter{
+		RouterContext{
+			logger:   options.Logger,
+			services: options.Services,
+			config:   options.Config,
+		},
+	}
+
+	routerGroup := options.Handler.Group("/me/wishlist", authMiddleware(options))
+	{
+		routerGroup.GET("", wrapHandler(options, router.getWishlist))
+		routerGroup.POST("", wrapHandler(options, router.addToWishlist))
+		routerGroup.DELETE("/:courseId", wrapHandler(options, router.removeFromWishlist))
+	}
+}
+
+type wishlistResponseError struct {
+	Message string `json:"message"`
+	Code    string `json:"code" enums:"invalid_course_id,course_not_found,wishlist_own_course,already_owned"`
+} // @name wishlistResponseError
+
+func (e wishlistResponseError) Error() *httpResponseError {
+	return &httpResponseError{
+		Type:    ErrorTypeClient,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+}
+
+type wishlistResponseBody struct {
+	Items []*entity.WishlistItem `json:"items"`
+} // @name wishlistResponseBody
+
+// @id           GetWishlist
+// @Summary      Lists courses current user saved for later with their current prices, newest first.
+// @Produce      application/json
+// @Success      200 {object} wishlistResponseBody
+// @Failure      422,500 {object} wishlistResponseError
+// @Router       /me/wishlist [GET]
+func (r *wishlistRouter) getWishlist(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("getWishlist").WithContext(requestContext)
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId)
+
+	items, err := r.services.WishlistService.GetWishlist(requestContext, userId)
+	if err != nil {
+		logger.Error("failed to get wishlist", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to get wishlist", Details: err}
+	}
+
+	logger.Info("successfully served wishlist")
+	return &wishlistResponseBody{Items: items}, nil
+}
+
+type addToWishlistRequestBody struct {
+	*service.AddToWishlistOptions
+} // @name addToWishlistRequestBody
+
+// @id           AddToWishlist
+// @Summary      Saves course to wishlist of current user, user is emailed when its price drops.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body addToWishlistRequestBody true "data"
+// @Success      200 {object} wishlistResponseBody
+// @Failure      422,500 {object} wishlistResponseError
+// @Router       /me/wishlist [POST]
+func (r *wishlistRouter) addToWishlist(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("addToWishlist").WithContext(requestContext)
+
+	body := addToWishlistRequestBody{&service.AddToWishlistOptions{}}
+	err := requestContext.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("failed to parse request body", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid request body", Details: err}
+	}
+	err = body.AddToWishlistOptions.Validate()
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, wishlistResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+	}
+	logger.Debug("parsed request body")
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	body.UserId = userId
+	logger = logger.With("userId", userId, "courseId", body.CourseId)
+
+	items, err := r.services.WishlistService.AddToWishlist(requestContext, body.AddToWishlistOptions)
+	if err != nil {
+		if errs.IsExpected(err) {
+			logger.Info(err.Error())
+			return nil, wishlistResponseError{Message: err.Error(), Code: errs.GetCode(err)}.Error()
+		}
+		logger.Error("failed to add course to wishlist", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to add course to wishlist", Details: err}
+	}
+
+	logger.Info("successfully added course to wishlist")
+	return &wishlistResponseBody{Items: items}, nil
+}
+
+// @id           RemoveFromWishlist
+// @Summary      Removes course from wishlist of current user.
+// @Produce      application/json
+// @Param        courseId path string true "course id"
+// @Success      200 {object} wishlistResponseBody
+// @Failure      422,500 {object} wishlistResponseError
+// @Router       /me/wishlist/{courseId} [DELETE]
+func (r *wishlistRouter) removeFromWishlist(requestContext *gin.Context) (interface{}, *httpResponseError) {
+	logger := r.logger.Named("removeFromWishlist").WithContext(requestContext)
+
+	courseId := requestContext.Param("courseId")
+	if _, err := uuid.Parse(courseId); err != nil {
+		logger.Info("invalid course id parameter", "param", courseId)
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "invalid course id parameter"}
+	}
+
+	userId, ok := requestContext.Value("userId").(string)
+	if !ok || userId == "" {
+		logger.Info("user not found")
+		return nil, &httpResponseError{Type: ErrorTypeClient, Message: "user not found"}
+	}
+	logger = logger.With("userId", userId, "courseId", courseId)
+
+	items, err := r.services.WishlistService.RemoveFromWishlist(requestContext, &service.RemoveFromWishlistOptions{UserId: userId, CourseId: courseId})
+	if err != nil {
+		logger.Error("failed to remove course from wishlist", "err", err)
+		return nil, &httpResponseError{Type: ErrorTypeServer, Message: "failed to remove course from wishlist", Details: err}
+	}
+
+	logger.Info("successfully removed course from wishlist")
+	return &wishlistResponseBody{Items: items}, nil
+}
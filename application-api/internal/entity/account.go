@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
 type Account struct {
 	Id              string           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	UserId          string           `json:"userId" gorm:"type:uuid;index"`
@@ -16,8 +21,75 @@ type AccountDevices struct {
 	Active     bool   `json:"active"`
 }
 
+// AccountSettings are preferences of user, see DefaultAccountSettings for values of new accounts.
 type AccountSettings struct {
 	Id        string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	AccountID string `json:"AccountID" gorm:"type:uuid;index"`
 	Language  string `json:"language"`
+	// Timezone is IANA time zone name, e.g. Europe/Kyiv.
+	Timezone      string                  `json:"timezone"`
+	Notifications NotificationPreferences `json:"notifications" gorm:"type:jsonb"`
+	Playback      PlaybackSettings        `json:"playback" gorm:"embedded;embeddedPrefix:playback_"`
+	Privacy       PrivacySettings         `json:"privacy" gorm:"embedded;embeddedPrefix:privacy_"`
+}
+
+// PlaybackSettings are defaults of lesson player, Speed is playback rate multiplier.
+type PlaybackSettings struct {
+	Speed    float64 `json:"speed"`
+	Autoplay bool    `json:"autoplay"`
+	Captions bool    `json:"captions"`
+}
+
+// PrivacySettings control what other users can see about user.
+type PrivacySettings struct {
+	PublicProfile bool `json:"publicProfile"`
+}
+
+// DefaultAccountSettings returns settings of new account, they are restored for fields removed by update as well.
+func DefaultAccountSettings() *AccountSettings {
+	return &AccountSettings{
+		Timezone:      "UTC",
+		Notifications: NotificationPreferences{},
+		Playback:      PlaybackSettings{Speed: 1, Autoplay: true},
+	}
+}
+
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelPush  = "push"
+)
+
+const (
+	NotificationPriceDrop        = "price_drop"
+	NotificationDiscussionReply  = "discussion_reply"
+	NotificationAssignmentGraded = "assignment_graded"
+	NotificationCourseUpdate     = "course_update"
+)
+
+// NotificationChannels and NotificationEvents list values NotificationPreferences can have.
+var (
+	NotificationChannels = []string{NotificationChannelEmail, NotificationChannelPush}
+	NotificationEvents   = []string{NotificationPriceDrop, NotificationDiscussionReply, NotificationAssignmentGraded, NotificationCourseUpdate}
+)
+
+// NotificationPreferences tell whether notifications of event type are sent through channel, by channel
+// and event type. Notifications missing here are sent.
+type NotificationPreferences map[string]map[string]bool
+
+// Enabled reports whether notifications of event type are sent through channel.
+func (p NotificationPreferences) Enabled(channel, event string) bool {
+	enabled, ok := p[channel][event]
+	return !ok || enabled
+}
+
+func (p NotificationPreferences) Value() (driver.Value, error) {
+	if p == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]map[string]bool(p))
+	return string(data), err
+}
+
+func (p *NotificationPreferences) Scan(value interface{}) error {
+	return scanJSON(value, p)
 }
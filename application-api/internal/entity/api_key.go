@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey is a personal key for scripts and integrations, only its hash is stored.
+// Prefix is the visible beginning of the key, so users can tell their keys apart.
+type APIKey struct {
+	Id         string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserId     string     `json:"userId" gorm:"type:uuid;index"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-" gorm:"uniqueIndex"`
+	Scopes     string     `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+const (
+	ScopeCoursesRead     = "courses:read"
+	ScopeCoursesWrite    = "courses:write"
+	ScopeEnrollmentsRead = "enrollments:read"
+)
+
+// APIKeyScopes lists all scopes API keys can be granted.
+var APIKeyScopes = []string{ScopeCoursesRead, ScopeCoursesWrite, ScopeEnrollmentsRead}
+
+// ScopeList returns scopes of the key, they are stored space separated.
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Assignment is a project task of course submitted as file. Submissions after DueAt are accepted
+// for LateDays days and lose LatePenaltyPercent of score for every started day of delay.
+type Assignment struct {
+	Id                 string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId           string    `json:"courseId" gorm:"type:uuid;index"`
+	Title              string    `json:"title"`
+	Description        string    `json:"description"`
+	MaxScore           int       `json:"maxScore"`
+	DueAt              time.Time `json:"dueAt"`
+	LateDays           int       `json:"lateDays"`
+	LatePenaltyPercent int       `json:"latePenaltyPercent"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+const (
+	SubmissionSubmitted = "submitted"
+	SubmissionGraded    = "graded"
+)
+
+// Submission is the latest file student submitted for assignment, resubmitting replaces it until graded.
+// Score is given by teacher, FinalScore is the score after late penalty.
+type Submission struct {
+	Id           string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	AssignmentId string     `json:"assignmentId" gorm:"type:uuid;uniqueIndex:idx_submissions_assignment_user"`
+	UserId       string     `json:"userId" gorm:"type:uuid;uniqueIndex:idx_submissions_assignment_user"`
+	CourseId     string     `json:"courseId" gorm:"type:uuid;index"`
+	FileName     string     `json:"fileName"`
+	ContentType  string     `json:"contentType"`
+	Size         int64      `json:"size"`
+	BlobKey      string     `json:"-"`
+	Comment      string     `json:"comment"`
+	SubmittedAt  time.Time  `json:"submittedAt"`
+	DaysLate     int        `json:"daysLate"`
+	Status       string     `json:"status"`
+	Score        *int       `json:"score"`
+	FinalScore   *int       `json:"finalScore"`
+	Feedback     string     `json:"feedback"`
+	GradedBy     *string    `json:"gradedBy" gorm:"type:uuid"`
+	GradedAt     *time.Time `json:"gradedAt"`
+}
+
+// GradingQueueItem is ungraded submission with context teacher needs to pick it up.
+type GradingQueueItem struct {
+	Submission      `gorm:"embedded"`
+	AssignmentTitle string    `json:"assignmentTitle"`
+	MaxScore        int       `json:"maxScore"`
+	CourseName      string    `json:"courseName"`
+	Username        string    `json:"username"`
+	DueAt           time.Time `json:"dueAt"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Bundle is set of teacher courses sold together, Price is in minor units.
+type Bundle struct {
+	Id          string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TeacherId   string    `json:"teacherId" gorm:"type:uuid;index"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       int64     `json:"price"`
+	Published   bool      `json:"published"`
+	Courses     []*Course `json:"courses" gorm:"many2many:bundle_courses"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
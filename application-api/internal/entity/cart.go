@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// CartItem is course user is going to buy, Price in minor units is fixed when course is added
+// and revalidated at checkout.
+type CartItem struct {
+	UserId     string    `json:"-" gorm:"type:uuid;primaryKey"`
+	CourseId   string    `json:"courseId" gorm:"type:uuid;primaryKey"`
+	CourseName string    `json:"courseName" gorm:"->;-:migration"`
+	Price      int64     `json:"price"`
+	AddedAt    time.Time `json:"addedAt"`
+}
+
+// Cart is persisted list of courses user is going to buy in one order.
+type Cart struct {
+	Items    []*CartItem `json:"items"`
+	Total    int64       `json:"total"`
+	Currency string      `json:"currency"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Certificate is issued to student who completed all lessons of course, one per student and course.
+// Username, course name and author are copied at issue time, Signature covers them together with the id,
+// so the certificate keeps proving what was issued even after the user or course changes.
+type Certificate struct {
+	Id            string     `json:"id" gorm:"type:uuid;primaryKey"`
+	UserId        string     `json:"userId" gorm:"type:uuid;uniqueIndex:idx_certificates_user_course"`
+	CourseId      string     `json:"courseId" gorm:"type:uuid;uniqueIndex:idx_certificates_user_course"`
+	Username      string     `json:"username"`
+	CourseName    string     `json:"courseName"`
+	Author        string     `json:"author"`
+	IssuedAt      time.Time  `json:"issuedAt"`
+	Signature     string     `json:"-"`
+	RevokedAt     *time.Time `json:"revokedAt,omitempty"`
+	RevokedReason string     `json:"revokedReason,omitempty"`
+}
+
+// CertificateRefundedReason is reason of certificates revoked by refund of their course.
+const CertificateRefundedReason = "course is refunded"
@@ -1,11 +1,39 @@
 package entity
 
+import "time"
+
 type Course struct {
 	Id             string  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	Name           string  `json:"name" gorm:"index"`
-	TeacherId      string  `json:"teacherId" gorm:"index"`
+	TeacherId      string  `json:"teacherId" gorm:"type:uuid;index"`
 	Author         string  `json:"author" gorm:"index"`
 	Description    string  `json:"description"`
 	Price          float32 `json:"price"`
 	CourseLanguage string  `json:"courseLanguage"`
+	Published      bool    `json:"published" gorm:"index"`
+	// rating aggregates are maintained by review storage, so they are read-only here
+	RatingCount   int     `json:"ratingCount" gorm:"->"`
+	RatingSum     int     `json:"-" gorm:"->"`
+	RatingAverage float64 `json:"ratingAverage" gorm:"->"`
+	// ContentLanguage is language of name and description when course is localized for client.
+	ContentLanguage string `json:"contentLanguage,omitempty" gorm:"-"`
+}
+
+// CoursePrice is entry of course price history in minor units, new entry is recorded once price changes.
+type CoursePrice struct {
+	Id         string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId   string    `json:"courseId" gorm:"type:uuid;index"`
+	Price      int64     `json:"price"`
+	RecordedAt time.Time `json:"recordedAt"`
+	// NotifiedAt is set once price drop notification of the record is taken for sending.
+	NotifiedAt *time.Time `json:"-"`
+}
+
+// CourseTranslation is name and description of course in another language, see CourseService.GetCourseById.
+type CourseTranslation struct {
+	CourseId    string    `json:"courseId" gorm:"type:uuid;primaryKey"`
+	Language    string    `json:"language" gorm:"primaryKey"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Section groups lessons of course, sections and lessons are ordered by Position.
+type Section struct {
+	Id        string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId  string    `json:"courseId" gorm:"type:uuid;index"`
+	Title     string    `json:"title"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Lesson is a single video of course.
+type Lesson struct {
+	Id              string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId        string    `json:"courseId" gorm:"type:uuid;index"`
+	SectionId       string    `json:"sectionId" gorm:"type:uuid;index"`
+	Title           string    `json:"title"`
+	Position        int       `json:"position"`
+	DurationSeconds int       `json:"durationSeconds"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
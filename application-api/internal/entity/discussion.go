@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+// Thread is a question or discussion topic of course, optionally scoped to one of its lessons.
+// Title and body of the thread are its opening post, replies are stored as posts.
+type Thread struct {
+	Id             string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId       string    `json:"courseId" gorm:"type:uuid;index"`
+	LessonId       *string   `json:"lessonId" gorm:"type:uuid"`
+	UserId         string    `json:"userId" gorm:"type:uuid"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	Upvotes        int       `json:"upvotes"`
+	PostCount      int       `json:"postCount"`
+	AcceptedPostId *string   `json:"acceptedPostId" gorm:"type:uuid"`
+	Hidden         bool      `json:"-"`
+	CreatedAt      time.Time `json:"createdAt"`
+	LastActivityAt time.Time `json:"lastActivityAt"`
+}
+
+func (Thread) TableName() string {
+	return "discussion_threads"
+}
+
+// Post is a reply in thread, ParentId is set for replies to other posts.
+type Post struct {
+	Id        string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ThreadId  string    `json:"threadId" gorm:"type:uuid;index"`
+	ParentId  *string   `json:"parentId" gorm:"type:uuid"`
+	UserId    string    `json:"userId" gorm:"type:uuid"`
+	Body      string    `json:"body"`
+	Upvotes   int       `json:"upvotes"`
+	Hidden    bool      `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (Post) TableName() string {
+	return "discussion_posts"
+}
+
+// Targets of discussion votes and reports.
+const (
+	DiscussionThread = "thread"
+	DiscussionPost   = "post"
+)
+
+const (
+	ReportOpen      = "open"
+	ReportHidden    = "hidden"
+	ReportDismissed = "dismissed"
+)
+
+// DiscussionReport is a complaint about thread or post, open reports form moderation queue of admins.
+type DiscussionReport struct {
+	Id         string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TargetType string     `json:"targetType"`
+	TargetId   string     `json:"targetId" gorm:"type:uuid"`
+	CourseId   string     `json:"courseId" gorm:"type:uuid"`
+	ReporterId string     `json:"reporterId" gorm:"type:uuid"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	ResolvedBy *string    `json:"resolvedBy" gorm:"type:uuid"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ModerationQueueItem is reported content with number of its open reports.
+type ModerationQueueItem struct {
+	DiscussionReport `gorm:"embedded"`
+	// Body is text of reported thread or post.
+	Body        string `json:"body"`
+	ReportCount int    `json:"reportCount"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Enrollment grants user access to course.
+type Enrollment struct {
+	Id        string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserId    string    `json:"userId" gorm:"type:uuid;uniqueIndex:idx_enrollments_user_course"`
+	CourseId  string    `json:"courseId" gorm:"type:uuid;uniqueIndex:idx_enrollments_user_course;index"`
+	CreatedAt time.Time `json:"createdAt"`
+}
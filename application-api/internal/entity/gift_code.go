@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// GiftCode enrolls user who redeems it in course, code can be redeemed once. Code bought as gift has OrderId
+// and can be redeemed only once order is paid, codes generated in bulk share BatchId.
+type GiftCode struct {
+	Id         string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Code       string     `json:"code" gorm:"uniqueIndex"`
+	CourseId   string     `json:"courseId" gorm:"type:uuid;index"`
+	OrderId    *string    `json:"orderId" gorm:"type:uuid"`
+	BatchId    *string    `json:"batchId" gorm:"type:uuid"`
+	CreatedBy  string     `json:"createdBy" gorm:"type:uuid"`
+	RedeemedBy *string    `json:"redeemedBy" gorm:"type:uuid"`
+	RedeemedAt *time.Time `json:"redeemedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
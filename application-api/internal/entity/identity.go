@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// UserIdentity links account of external OpenID provider to user.
+type UserIdentity struct {
+	Id        string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserId    string    `json:"userId" gorm:"type:uuid;index"`
+	Issuer    string    `json:"issuer" gorm:"uniqueIndex:idx_user_identities_issuer_subject"`
+	Subject   string    `json:"subject" gorm:"uniqueIndex:idx_user_identities_issuer_subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OIDCState keeps login attempt data between redirect to provider and callback, only state hash is stored.
+type OIDCState struct {
+	StateHash    string    `json:"-" gorm:"primaryKey"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+func (OIDCState) TableName() string {
+	return "oidc_states"
+}
@@ -0,0 +1,91 @@
+package entity
+
+import "time"
+
+// Ledger accounts, teacher accounts are named TeacherAccount(teacherId).
+const (
+	AccountPlatformCash    = "platform:cash"
+	AccountPlatformRevenue = "platform:revenue"
+)
+
+// TeacherAccount returns ledger account of teacher earnings.
+func TeacherAccount(teacherId string) string {
+	return "teacher:" + teacherId
+}
+
+const (
+	LedgerSale   = "sale"
+	LedgerFee    = "fee"
+	LedgerRefund = "refund"
+	LedgerPayout = "payout"
+	// LedgerSubscription is paid subscription period, it is platform revenue.
+	LedgerSubscription = "subscription"
+)
+
+// LedgerEntry is an append-only posting to account, entries of one transaction sum up to zero.
+// Positive Amount debits account and negative credits it, so teacher balance is negated sum of its entries.
+type LedgerEntry struct {
+	Id            string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TransactionId string    `json:"transactionId" gorm:"type:uuid"`
+	Account       string    `json:"account"`
+	Kind          string    `json:"kind"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CourseId      *string   `json:"courseId" gorm:"type:uuid"`
+	OrderItemId   *string   `json:"orderItemId" gorm:"type:uuid"`
+	PayoutId      *string   `json:"payoutId" gorm:"type:uuid"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+const (
+	PayoutRequested = "requested"
+	PayoutApproved  = "approved"
+	PayoutRejected  = "rejected"
+)
+
+// Payout is a withdrawal of teacher earnings, it is posted to ledger once admin approves it.
+type Payout struct {
+	Id          string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TeacherId   string     `json:"teacherId" gorm:"type:uuid;index"`
+	Amount      int64      `json:"amount"`
+	Currency    string     `json:"currency"`
+	Status      string     `json:"status"`
+	Note        string     `json:"note"`
+	ResolvedBy  *string    `json:"resolvedBy" gorm:"type:uuid"`
+	ResolvedAt  *time.Time `json:"resolvedAt"`
+	RequestedAt time.Time  `json:"requestedAt"`
+}
+
+// EarningsTotals are teacher earnings in minor units, Net is Gross minus Fees and Refunds.
+type EarningsTotals struct {
+	Sales   int   `json:"sales"`
+	Gross   int64 `json:"gross"`
+	Fees    int64 `json:"fees"`
+	Refunds int64 `json:"refunds"`
+	Net     int64 `json:"net"`
+}
+
+type CourseEarnings struct {
+	CourseId   string `json:"courseId"`
+	CourseName string `json:"courseName"`
+	EarningsTotals
+}
+
+type MonthEarnings struct {
+	// Month is formatted as YYYY-MM.
+	Month string `json:"month"`
+	EarningsTotals
+}
+
+// Earnings is a summary of teacher ledger account. Balance is not paid out yet and Available
+// is Balance without amount of requested payouts.
+type Earnings struct {
+	Currency       string            `json:"currency"`
+	Balance        int64             `json:"balance"`
+	Available      int64             `json:"available"`
+	PendingPayouts int64             `json:"pendingPayouts"`
+	PaidOut        int64             `json:"paidOut"`
+	Totals         EarningsTotals    `json:"totals"`
+	Courses        []*CourseEarnings `json:"courses"`
+	Months         []*MonthEarnings  `json:"months"`
+}
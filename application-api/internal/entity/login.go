@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// LoginFailure counts recent failed sign in attempts per key, e.g. account email or client IP.
+type LoginFailure struct {
+	Key           string     `json:"key" gorm:"primaryKey"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"lastFailureAt"`
+	LockedUntil   *time.Time `json:"lockedUntil"`
+}
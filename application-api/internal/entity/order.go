@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+const (
+	OrderPending = "pending"
+	OrderPaid    = "paid"
+	OrderFailed  = "failed"
+	// OrderRefunded is set once every item of order is refunded.
+	OrderRefunded = "refunded"
+)
+
+// Order is a purchase of courses by user, amounts are in minor units of Currency.
+// Paid order enrolls user to its courses and credits their teachers in ledger.
+type Order struct {
+	Id       string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserId   string `json:"userId" gorm:"type:uuid;index"`
+	Status   string `json:"status"`
+	Total    int64  `json:"total"`
+	Currency string `json:"currency"`
+	ChargeId string `json:"-"`
+	// Gift order doesn't enroll buyer, every its item gets gift code instead.
+	Gift      bool         `json:"gift"`
+	Items     []*OrderItem `json:"items" gorm:"foreignKey:OrderId"`
+	PaidAt    *time.Time   `json:"paidAt"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// OrderItem is a course of order with its teacher and price at purchase time.
+type OrderItem struct {
+	Id        string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderId   string `json:"orderId" gorm:"type:uuid;index"`
+	CourseId  string `json:"courseId" gorm:"type:uuid"`
+	TeacherId string `json:"teacherId" gorm:"type:uuid"`
+	Price     int64  `json:"price"`
+	// BundleId is set when course was bought as part of bundle, Price is its share of bundle price.
+	BundleId *string `json:"bundleId" gorm:"type:uuid"`
+}
+
+const (
+	RefundPending   = "pending"
+	RefundCompleted = "completed"
+	RefundFailed    = "failed"
+)
+
+// Refund returns price of order item to user, completed refund revokes access to the course.
+type Refund struct {
+	Id               string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderId          string `json:"orderId" gorm:"type:uuid"`
+	OrderItemId      string `json:"orderItemId" gorm:"type:uuid"`
+	UserId           string `json:"userId" gorm:"type:uuid;index"`
+	CourseId         string `json:"courseId" gorm:"type:uuid"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	Reason           string `json:"reason"`
+	Status           string `json:"status"`
+	ProviderRefundId string `json:"-"`
+	// Duplicate refund returns price of course owned already when order was paid, it keeps access to the course.
+	Duplicate   bool       `json:"duplicate"`
+	CompletedAt *time.Time `json:"completedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
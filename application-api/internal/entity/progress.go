@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// LessonProgress is a resume point of user in lesson, updated by player heartbeats.
+// PositionAt is when player reported LastPosition, older heartbeats don't move it back.
+type LessonProgress struct {
+	UserId         string    `json:"userId" gorm:"type:uuid;primaryKey"`
+	LessonId       string    `json:"lessonId" gorm:"type:uuid;primaryKey"`
+	CourseId       string    `json:"courseId" gorm:"type:uuid"`
+	WatchedSeconds int       `json:"watchedSeconds"`
+	LastPosition   int       `json:"lastPosition"`
+	Completed      bool      `json:"completed"`
+	PositionAt     time.Time `json:"-"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (LessonProgress) TableName() string {
+	return "lesson_progress"
+}
+
+// CourseProgress is completion of course by user, course is completed when all lessons are completed
+// and all quizzes are passed.
+type CourseProgress struct {
+	CourseId         string `json:"courseId"`
+	TotalLessons     int    `json:"totalLessons"`
+	CompletedLessons int    `json:"completedLessons"`
+	TotalQuizzes     int    `json:"totalQuizzes"`
+	PassedQuizzes    int    `json:"passedQuizzes"`
+	// Percent is rounded down, so 100 means every lesson is completed and every quiz is passed.
+	Percent int `json:"percent"`
+	// LastLessonId and LastPosition point to where user left off, empty when nothing was watched.
+	LastLessonId string `json:"lastLessonId"`
+	LastPosition int    `json:"lastPosition"`
+}
+
+// Completed reports whether user finished everything in course.
+func (p *CourseProgress) Completed() bool {
+	return p.TotalLessons+p.TotalQuizzes > 0 &&
+		p.CompletedLessons == p.TotalLessons &&
+		p.PassedQuizzes == p.TotalQuizzes
+}
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Quiz is an auto-graded assessment attached to section, passing it counts towards course completion.
+// Zero MaxAttempts allows unlimited attempts, zero TimeLimitSeconds doesn't limit attempt time.
+type Quiz struct {
+	Id               string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId         string    `json:"courseId" gorm:"type:uuid;index"`
+	SectionId        string    `json:"sectionId" gorm:"type:uuid;index"`
+	Title            string    `json:"title"`
+	Position         int       `json:"position"`
+	PassingScore     int       `json:"passingScore"`
+	MaxAttempts      int       `json:"maxAttempts"`
+	TimeLimitSeconds int       `json:"timeLimitSeconds"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+const (
+	QuestionSingleChoice   = "single_choice"
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionShortText      = "short_text"
+)
+
+// QuizQuestion is a single question of quiz. Answers are indexes of correct options for choice questions
+// and accepted texts for short text questions, they are never sent to students.
+type QuizQuestion struct {
+	Id       string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	QuizId   string     `json:"quizId" gorm:"type:uuid;index"`
+	Position int        `json:"position"`
+	Type     string     `json:"type"`
+	Text     string     `json:"text"`
+	Options  StringList `json:"options" gorm:"type:jsonb"`
+	Answers  StringList `json:"-" gorm:"type:jsonb"`
+	Points   int        `json:"points"`
+}
+
+// QuizAttempt is a try of student to pass quiz. Attempt is open until SubmittedAt is set,
+// open attempt past ExpiresAt is graded with no answers.
+type QuizAttempt struct {
+	Id          string      `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	QuizId      string      `json:"quizId" gorm:"type:uuid"`
+	UserId      string      `json:"userId" gorm:"type:uuid"`
+	CourseId    string      `json:"courseId" gorm:"type:uuid"`
+	StartedAt   time.Time   `json:"startedAt"`
+	ExpiresAt   *time.Time  `json:"expiresAt"`
+	SubmittedAt *time.Time  `json:"submittedAt"`
+	Answers     QuizAnswers `json:"answers" gorm:"type:jsonb"`
+	Score       int         `json:"score"`
+	MaxScore    int         `json:"maxScore"`
+	Percent     int         `json:"percent"`
+	Passed      bool        `json:"passed"`
+}
+
+// StringList is a list of strings stored as JSON.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	return string(data), err
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// QuizAnswers are answers of attempt by question id, see QuizQuestion for their format.
+type QuizAnswers map[string][]string
+
+func (a QuizAnswers) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string][]string(a))
+	return string(data), err
+}
+
+func (a *QuizAnswers) Scan(value interface{}) error {
+	return scanJSON(value, a)
+}
+
+func scanJSON(value interface{}, v interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(value, v)
+	case string:
+		return json.Unmarshal([]byte(value), v)
+	}
+	return fmt.Errorf("unsupported JSON column type %T", value)
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Review is a rating of course by enrolled student, one per student and course.
+// Hidden reviews are removed by moderation and don't count towards course rating.
+type Review struct {
+	Id        string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CourseId  string     `json:"courseId" gorm:"type:uuid;uniqueIndex:idx_reviews_course_user"`
+	UserId    string     `json:"userId" gorm:"type:uuid;uniqueIndex:idx_reviews_course_user"`
+	Rating    int        `json:"rating"`
+	Text      string     `json:"text"`
+	Reply     string     `json:"reply"`
+	RepliedAt *time.Time `json:"repliedAt"`
+	Hidden    bool       `json:"hidden"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// SubscriptionPlan is monthly all-access plan, Price is in minor units of Currency.
+type SubscriptionPlan struct {
+	Id        string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name      string    `json:"name"`
+	Price     int64     `json:"price"`
+	Currency  string    `json:"currency"`
+	TrialDays int       `json:"trialDays"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const (
+	SubscriptionTrialing = "trialing"
+	SubscriptionActive   = "active"
+	SubscriptionPastDue  = "past_due"
+	SubscriptionCanceled = "canceled"
+)
+
+// Subscription gives access to every course while it is trialing or active and its period isn't over,
+// status is changed by payment provider webhooks.
+type Subscription struct {
+	Id                     string     `json:"id" gorm:"type:uuid;primaryKey"`
+	UserId                 string     `json:"userId" gorm:"type:uuid;index"`
+	PlanId                 string     `json:"planId" gorm:"type:uuid"`
+	Status                 string     `json:"status"`
+	ProviderSubscriptionId string     `json:"-"`
+	CurrentPeriodEnd       time.Time  `json:"currentPeriodEnd"`
+	CancelAtPeriodEnd      bool       `json:"cancelAtPeriodEnd"`
+	CanceledAt             *time.Time `json:"canceledAt"`
+	CreatedAt              time.Time  `json:"createdAt"`
+	UpdatedAt              time.Time  `json:"updatedAt"`
+}
+
+// HasAccess reports whether subscription gives access to courses at the moment.
+func (s *Subscription) HasAccess(now time.Time) bool {
+	return (s.Status == SubscriptionTrialing || s.Status == SubscriptionActive) && now.Before(s.CurrentPeriodEnd)
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// LessonSubtitle is WebVTT subtitle track of lesson in language, SRT uploads are converted to WebVTT.
+type LessonSubtitle struct {
+	LessonId string `json:"lessonId" gorm:"type:uuid;primaryKey"`
+	Language string `json:"language" gorm:"primaryKey"`
+	CourseId string `json:"courseId" gorm:"type:uuid;index"`
+	// Label is name of track shown by player, e.g. English (CC).
+	Label     string    `json:"label"`
+	Content   string    `json:"-"`
+	CueCount  int       `json:"cueCount"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TranscriptCue is text of subtitle cue indexed for transcript search, times are in milliseconds.
+type TranscriptCue struct {
+	Id       string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	LessonId string `json:"lessonId" gorm:"type:uuid;index"`
+	Language string `json:"language"`
+	CourseId string `json:"courseId" gorm:"type:uuid;index"`
+	StartMs  int64  `json:"startMs"`
+	EndMs    int64  `json:"endMs"`
+	Text     string `json:"text"`
+}
+
+// TranscriptMatch is lesson which transcript matches search query, Text and StartMs are of its best matching cue.
+// Text is empty when user has no access to course.
+type TranscriptMatch struct {
+	LessonId    string `json:"lessonId"`
+	LessonTitle string `json:"lessonTitle"`
+	CourseId    string `json:"courseId"`
+	CourseName  string `json:"courseName"`
+	Language    string `json:"language"`
+	StartMs     int64  `json:"startMs"`
+	Text        string `json:"text,omitempty"`
+}
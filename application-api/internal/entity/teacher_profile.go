@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// TeacherProfile is public page of teacher, DisplayName is author name of all teacher courses.
+type TeacherProfile struct {
+	UserId      string      `json:"userId" gorm:"type:uuid;primaryKey"`
+	DisplayName string      `json:"displayName"`
+	Bio         string      `json:"bio"`
+	AvatarURL   string      `json:"avatarUrl"`
+	SocialLinks SocialLinks `json:"socialLinks" gorm:"type:jsonb"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+}
+
+// SocialLinks are URLs of teacher pages by network name, e.g. youtube.
+type SocialLinks map[string]string
+
+func (l SocialLinks) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]string(l))
+	return string(data), err
+}
+
+func (l *SocialLinks) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// TeacherStats aggregate published courses of teacher, Students counts distinct enrolled users.
+type TeacherStats struct {
+	Courses       int     `json:"courses"`
+	Students      int     `json:"students"`
+	RatingCount   int     `json:"ratingCount"`
+	RatingAverage float64 `json:"ratingAverage"`
+}
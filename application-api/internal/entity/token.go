@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// OneTimeToken is a single-use secret sent to user by email, only its hash is stored.
+type OneTimeToken struct {
+	Id        string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserId    string     `json:"userId" gorm:"type:uuid;index"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+const (
+	TokenPurposeVerifyEmail        = "verify_email"
+	TokenPurposeResetPassword      = "reset_password"
+	TokenPurposeTwoFactorChallenge = "two_factor_challenge"
+)
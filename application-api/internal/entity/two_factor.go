@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// TwoFactor holds TOTP secret of user, it becomes active once enrollment is confirmed with a code.
+type TwoFactor struct {
+	UserId       string     `json:"userId" gorm:"type:uuid;primaryKey"`
+	Secret       string     `json:"-"`
+	Enabled      bool       `json:"enabled"`
+	LastUsedStep int64      `json:"-"`
+	EnabledAt    *time.Time `json:"enabledAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// RecoveryCode is a single-use code to pass two-factor check without authenticator, only its hash is stored.
+type RecoveryCode struct {
+	Id       string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserId   string     `json:"userId" gorm:"type:uuid;index"`
+	CodeHash string     `json:"-" gorm:"uniqueIndex"`
+	UsedAt   *time.Time `json:"usedAt"`
+}
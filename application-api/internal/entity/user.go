@@ -1,14 +1,16 @@
 package entity
 
 type User struct {
-	Id       string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Type     int    `json:"type"`
-	Password string `json:"password"`
+	Id            string `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	Type          int    `json:"type"`
+	Password      string `json:"password"`
+	EmailVerified bool   `json:"emailVerified"`
 }
 
 const (
 	Student = 1
 	Teacher = 2
+	Admin   = 3
 )
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// WishlistItem is course user saved for later, Price is current course price and isn't stored with the item.
+type WishlistItem struct {
+	UserId     string    `json:"-" gorm:"type:uuid;primaryKey"`
+	CourseId   string    `json:"courseId" gorm:"type:uuid;primaryKey"`
+	CourseName string    `json:"courseName" gorm:"->;-:migration"`
+	Price      float32   `json:"price" gorm:"->;-:migration"`
+	AddedAt    time.Time `json:"addedAt"`
+}
@@ -1,12 +1,30 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/mergepatch"
+	"math"
+	"regexp"
+	"time"
+	// time zones are validated without relying on zone database of the host
+	_ "time/tzdata"
 )
 
+const (
+	_maxLanguageLength = 35
+	_minPlaybackSpeed  = 0.5
+	_maxPlaybackSpeed  = 2
+	_playbackSpeedStep = 0.25
+)
+
+var _languageTagRegexp = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
 type accountService struct {
 	serviceContext
 }
@@ -50,10 +68,9 @@ func (a accountService) CreateAccount(ctx context.Context, options *CreateAccoun
 				Active:     options.Active,
 			},
 		},
-		AccountSettings: &entity.AccountSettings{
-			Language: options.AccountLanguage,
-		},
+		AccountSettings: entity.DefaultAccountSettings(),
 	}
+	account.AccountSettings.Language = options.AccountLanguage
 	logger = logger.With("account", account)
 
 	createdAccount, err := a.storages.AccountStorage.CreateAccount(ctx, account)
@@ -88,19 +105,194 @@ func (a accountService) GetAccount(ctx context.Context, options *GetAccountOptio
 	return account, nil
 }
 
-func (a accountService) UpdateAccount(ctx context.Context, account *entity.Account) (*entity.Account, error) {
+func (a accountService) GetAccountLanguage(ctx context.Context, userId string) (string, error) {
+	account, err := a.storages.AccountStorage.GetAccount(ctx, &storage.GetAccountFilter{UserId: userId})
+	if err != nil {
+		return "", fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || account.AccountSettings == nil {
+		return "", nil
+	}
+
+	return account.AccountSettings.Language, nil
+}
+
+func (a accountService) UpdateAccount(ctx context.Context, options *UpdateAccountOptions) (*entity.Account, error) {
 	logger := a.logger.
 		Named("UpdateAccount").
 		WithContext(ctx).
-		With("account", account)
+		With("accountId", options.AccountId, "userId", options.UserId)
+
+	var patch map[string]json.RawMessage
+	err := json.Unmarshal(options.Patch, &patch)
+	if err != nil || patch == nil {
+		logger.Info("patch is not JSON object")
+		return nil, ErrUpdateAccountInvalidPatch
+	}
+	for name := range patch {
+		if name != "accountSettings" && name != "accountDevices" {
+			logger.Info("patch changes read-only member", "member", name)
+			return nil, ErrUpdateAccountInvalidPatch
+		}
+	}
 
-	updatedAccount, err := a.storages.AccountStorage.UpdateAccount(ctx, account)
+	account, err := a.storages.AccountStorage.GetAccount(ctx, &storage.GetAccountFilter{AccountId: options.AccountId, UserId: options.UserId})
 	if err != nil {
-		logger.Error("failed to update account: ", err)
-		return nil, fmt.Errorf("failed to update account: %w", err)
+		logger.Error("failed to get account: ", err)
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		logger.Info("account not found")
+		return nil, ErrUpdateAccountAccountNotFound
+	}
+
+	if len(patch) == 0 {
+		logger.Info("nothing to update")
+		return account, nil
+	}
+
+	update := &entity.Account{Id: account.Id, UserId: account.UserId}
+	if settingsPatch, ok := patch["accountSettings"]; ok {
+		update.AccountSettings, err = patchAccountSettings(account, settingsPatch)
+		if err != nil {
+			logger.Info("failed to apply patch", "err", err)
+			return nil, ErrUpdateAccountInvalidPatch
+		}
+		err = validateAccountSettings(update.AccountSettings)
+		if err != nil {
+			logger.Info("invalid settings", "err", err)
+			return nil, err
+		}
+	}
+	if devicesPatch, ok := patch["accountDevices"]; ok {
+		update.AccountDevices, err = patchAccountDevices(account, devicesPatch)
+		if err != nil {
+			logger.Info("invalid devices", "err", err)
+			return nil, ErrUpdateAccountInvalidDevices
+		}
+	}
+
+	err = a.storages.AccountStorage.SaveAccount(ctx, update)
+	if err != nil {
+		logger.Error("failed to save account: ", err)
+		return nil, fmt.Errorf("failed to save account: %w", err)
+	}
+
+	updatedAccount, err := a.storages.AccountStorage.GetAccount(ctx, &storage.GetAccountFilter{AccountId: account.Id})
+	if err != nil || updatedAccount == nil {
+		logger.Error("failed to get updated account: ", err)
+		return nil, fmt.Errorf("failed to get updated account: %w", err)
 	}
-	logger = logger.With("updatedAccount", updatedAccount)
 
 	logger.Info("successfully updated account")
 	return updatedAccount, nil
 }
+
+// patchAccountDevices returns devices replacing devices of account, merge patch replaces arrays as a whole
+// and null removes all devices. Devices without id are added, others have to be devices of account.
+func patchAccountDevices(account *entity.Account, patch json.RawMessage) ([]entity.AccountDevices, error) {
+	devices := []entity.AccountDevices{}
+	if string(bytes.TrimSpace(patch)) != "null" {
+		decoder := json.NewDecoder(bytes.NewReader(patch))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&devices)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	known := map[string]bool{}
+	for _, device := range account.AccountDevices {
+		known[device.Id] = true
+	}
+	for i := range devices {
+		if devices[i].Id != "" && !known[devices[i].Id] {
+			return nil, fmt.Errorf("device %s isn't device of account", devices[i].Id)
+		}
+		devices[i].AccountID = account.Id
+	}
+	return devices, nil
+}
+
+// patchAccountSettings applies JSON Merge Patch to settings of account. Fields removed by patch get
+// their default values, so null resets a preference.
+func patchAccountSettings(account *entity.Account, patch json.RawMessage) (*entity.AccountSettings, error) {
+	current := account.AccountSettings
+	if current == nil {
+		current = entity.DefaultAccountSettings()
+	}
+	document, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := mergepatch.Apply(document, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := entity.DefaultAccountSettings()
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	// settings stay attached to the same account
+	settings.Id = current.Id
+	settings.AccountID = account.Id
+	return settings, nil
+}
+
+func validateAccountSettings(settings *entity.AccountSettings) error {
+	if !isLanguageTag(settings.Language) {
+		return ErrUpdateAccountInvalidLanguage
+	}
+
+	if settings.Timezone == "" || settings.Timezone == "Local" {
+		return ErrUpdateAccountInvalidTimezone
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return ErrUpdateAccountInvalidTimezone
+	}
+
+	for channel, events := range settings.Notifications {
+		if !contains(entity.NotificationChannels, channel) {
+			return ErrUpdateAccountInvalidNotifications
+		}
+		for event := range events {
+			if !contains(entity.NotificationEvents, event) {
+				return ErrUpdateAccountInvalidNotifications
+			}
+		}
+	}
+
+	speed := settings.Playback.Speed
+	if speed < _minPlaybackSpeed || speed > _maxPlaybackSpeed || math.Mod(speed, _playbackSpeedStep) != 0 {
+		return ErrUpdateAccountInvalidPlaybackSpeed
+	}
+
+	return nil
+}
+
+// isLanguageTag reports whether language is empty or looks like BCP 47 tag, e.g. en or pt-BR.
+func isLanguageTag(language string) bool {
+	return language == "" || (len(language) <= _maxLanguageLength && _languageTagRegexp.MatchString(language))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CreateAccountOptions) Validate() error {
+	if !isLanguageTag(c.AccountLanguage) {
+		return errs.New("Language must be language tag, e.g. en or pt-BR.", "invalid_language")
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/hash"
+)
+
+type adminService struct {
+	serviceContext
+	hash hash.Hash
+}
+
+var _ AdminService = (*adminService)(nil)
+
+func NewAdminService(options *Options) AdminService {
+	return &adminService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("AdminService"),
+		},
+		hash: options.Hash,
+	}
+}
+
+func (a *adminService) CreateAdmin(ctx context.Context, options *CreateAdminOptions) (*entity.User, error) {
+	logger := a.logger.
+		Named("CreateAdmin").
+		WithContext(ctx).
+		With("email", options.Email)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{Email: options.Email})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user != nil {
+		logger.Info("user already created")
+		return nil, ErrCreateAdminUserAlreadyCreated
+	}
+
+	hashedPassword, err := a.hash.GenerateHash(options.Password)
+	if err != nil {
+		logger.Error("failed to hash user password: ", err)
+		return nil, fmt.Errorf("failed to hash user password: %w", err)
+	}
+
+	createdUser, err := a.storages.UserStorage.CreateUser(ctx, &entity.User{
+		Email:         options.Email,
+		Password:      hashedPassword,
+		Username:      options.Username,
+		Type:          entity.Admin,
+		EmailVerified: true,
+	})
+	if err != nil {
+		logger.Error("failed to create user: ", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	logger.Info("successfully created admin")
+	return createdUser, nil
+}
+
+func (a *adminService) ResetPassword(ctx context.Context, options *ResetPasswordOptions) error {
+	logger := a.logger.
+		Named("ResetPassword").
+		WithContext(ctx).
+		With("email", options.Email)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{Email: options.Email})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		logger.Info("user not found")
+		return ErrResetPasswordUserNotFound
+	}
+
+	user.Password, err = a.hash.GenerateHash(options.Password)
+	if err != nil {
+		logger.Error("failed to hash user password: ", err)
+		return fmt.Errorf("failed to hash user password: %w", err)
+	}
+
+	_, err = a.storages.UserStorage.UpdateUser(ctx, user)
+	if err != nil {
+		logger.Error("failed to update user: ", err)
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	logger.Info("successfully reset password")
+	return nil
+}
+
+func (a *adminService) PromoteToTeacher(ctx context.Context, email string) (*entity.User, error) {
+	logger := a.logger.
+		Named("PromoteToTeacher").
+		WithContext(ctx).
+		With("email", email)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{Email: email})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		logger.Info("user not found")
+		return nil, ErrPromoteToTeacherUserNotFound
+	}
+	if user.Type != entity.Student {
+		logger.Info("user is not a student", "type", user.Type)
+		return nil, ErrPromoteToTeacherWrongUserType
+	}
+
+	user.Type = entity.Teacher
+	updatedUser, err := a.storages.UserStorage.UpdateUser(ctx, user)
+	if err != nil {
+		logger.Error("failed to update user: ", err)
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	logger.Info("successfully promoted user to teacher")
+	return updatedUser, nil
+}
+
+func (a *adminService) ListCourses(ctx context.Context) ([]*entity.Course, error) {
+	courses, err := a.storages.CourseStorage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+
+	return courses, nil
+}
+
+func (a *adminService) UnpublishCourse(ctx context.Context, courseId string) (*entity.Course, error) {
+	logger := a.logger.
+		Named("UnpublishCourse").
+		WithContext(ctx).
+		With("courseId", courseId)
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: courseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrUnpublishCourseCourseNotFound
+	}
+
+	course.Published = false
+	updatedCourse, err := a.storages.CourseStorage.UpdateCourse(ctx, course)
+	if err != nil {
+		logger.Error("failed to update course: ", err)
+		return nil, fmt.Errorf("failed to update course: %w", err)
+	}
+
+	logger.Info("successfully unpublished course")
+	return updatedCourse, nil
+}
+
+func (a *adminService) Export(ctx context.Context, options *ExportOptions) (*ExportData, error) {
+	users, err := a.storages.UserStorage.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	if !options.WithPasswords {
+		for _, user := range users {
+			user.Password = ""
+		}
+	}
+
+	courses, err := a.storages.CourseStorage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+
+	return &ExportData{Users: users, Courses: courses}, nil
+}
+
+func (a *adminService) Import(ctx context.Context, data *ExportData) (*ImportOutput, error) {
+	logger := a.logger.
+		Named("Import").
+		WithContext(ctx).
+		With("users", len(data.Users), "courses", len(data.Courses))
+
+	err := a.storages.Transaction(ctx, func(storages *storage.Storages) error {
+		// users go first, courses reference them via teacher_id
+		err := storages.UserStorage.SaveUsers(ctx, data.Users)
+		if err != nil {
+			return fmt.Errorf("failed to save users: %w", err)
+		}
+
+		err = storages.CourseStorage.SaveCourses(ctx, data.Courses)
+		if err != nil {
+			return fmt.Errorf("failed to save courses: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to import data: ", err)
+		return nil, fmt.Errorf("failed to import data: %w", err)
+	}
+
+	logger.Info("successfully imported data")
+	return &ImportOutput{Users: len(data.Users), Courses: len(data.Courses)}, nil
+}
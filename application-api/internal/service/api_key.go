@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyPrefix - marks API keys, so they can be told apart from access tokens and found by secret scanners.
+	APIKeyPrefix = "cp_"
+	// _apiKeyVisibleLength - length of key beginning stored in plain text to identify the key.
+	_apiKeyVisibleLength = len(APIKeyPrefix) + 8
+)
+
+type apiKeyService struct {
+	serviceContext
+}
+
+var _ APIKeyService = (*apiKeyService)(nil)
+
+func NewAPIKeyService(options *Options) APIKeyService {
+	return &apiKeyService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("APIKeyService"),
+		},
+	}
+}
+
+func (a *apiKeyService) CreateAPIKey(ctx context.Context, options *CreateAPIKeyOptions) (*CreateAPIKeyOutput, error) {
+	logger := a.logger.
+		Named("CreateAPIKey").
+		WithContext(ctx).
+		With("userId", options.UserId, "name", options.Name, "scopes", options.Scopes)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Teacher && user.Type != entity.Admin {
+		logger.Info("user is not a teacher", "type", user.Type)
+		return nil, ErrCreateAPIKeyNotAllowed
+	}
+
+	keys, err := a.storages.APIKeyStorage.GetAPIKeys(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get api keys: ", err)
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+	active := 0
+	for _, key := range keys {
+		if key.RevokedAt == nil && (key.ExpiresAt == nil || key.ExpiresAt.After(time.Now())) {
+			active++
+		}
+	}
+	if active >= a.config.APIKey.MaxPerUser {
+		logger.Info("too many api keys", "active", active)
+		return nil, ErrCreateAPIKeyTooManyKeys
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		logger.Error("failed to generate api key: ", err)
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	plainKey := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	createdKey, err := a.storages.APIKeyStorage.CreateAPIKey(ctx, &entity.APIKey{
+		UserId:    options.UserId,
+		Name:      options.Name,
+		Prefix:    plainKey[:_apiKeyVisibleLength],
+		KeyHash:   hashOneTimeToken(plainKey),
+		Scopes:    strings.Join(options.Scopes, " "),
+		ExpiresAt: options.ExpiresAt,
+	})
+	if err != nil {
+		logger.Error("failed to create api key: ", err)
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	logger.Info("successfully created api key", "keyId", createdKey.Id)
+	return &CreateAPIKeyOutput{Key: plainKey, APIKey: createdKey}, nil
+}
+
+func (a *apiKeyService) GetAPIKeys(ctx context.Context, userId string) ([]*entity.APIKey, error) {
+	keys, err := a.storages.APIKeyStorage.GetAPIKeys(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (a *apiKeyService) RevokeAPIKey(ctx context.Context, options *RevokeAPIKeyOptions) error {
+	logger := a.logger.
+		Named("RevokeAPIKey").
+		WithContext(ctx).
+		With("userId", options.UserId, "keyId", options.KeyId)
+
+	revoked, err := a.storages.APIKeyStorage.RevokeAPIKey(ctx, options.UserId, options.KeyId)
+	if err != nil {
+		logger.Error("failed to revoke api key: ", err)
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if !revoked {
+		logger.Info("api key not found")
+		return ErrRevokeAPIKeyKeyNotFound
+	}
+
+	logger.Info("successfully revoked api key")
+	return nil
+}
+
+func (a *apiKeyService) VerifyAPIKey(ctx context.Context, options *VerifyAPIKeyOptions) (*VerifyAPIKeyOutput, error) {
+	logger := a.logger.
+		Named("VerifyAPIKey").
+		WithContext(ctx)
+
+	if !strings.HasPrefix(options.Key, APIKeyPrefix) {
+		logger.Info("malformed api key")
+		return nil, ErrVerifyAPIKeyInvalidAPIKey
+	}
+
+	key, err := a.storages.APIKeyStorage.GetAPIKey(ctx, hashOneTimeToken(options.Key))
+	if err != nil {
+		logger.Error("failed to get api key: ", err)
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key == nil || key.RevokedAt != nil || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		logger.Info("invalid api key")
+		return nil, ErrVerifyAPIKeyInvalidAPIKey
+	}
+	logger = logger.With("keyId", key.Id, "userId", key.UserId)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: key.UserId})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	// keys of users demoted from teacher stop working
+	if user == nil || (user.Type != entity.Teacher && user.Type != entity.Admin) {
+		logger.Info("api key owner is not allowed to use api keys")
+		return nil, ErrVerifyAPIKeyInvalidAPIKey
+	}
+
+	// usage tracking is best effort, failed update shouldn't fail the request
+	err = a.storages.APIKeyStorage.TouchAPIKey(ctx, key.Id, a.config.APIKey.UsageInterval)
+	if err != nil {
+		logger.Error("failed to record api key usage: ", err)
+	}
+
+	logger.Debug("successfully verified api key")
+	return &VerifyAPIKeyOutput{UserId: user.Id, Username: user.Username, Scopes: key.ScopeList()}, nil
+}
+
+func (c *CreateAPIKeyOptions) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errs.New("Name is required.", "name_required")
+	}
+	if len(c.Scopes) == 0 {
+		return errs.New("At least one scope is required.", "scopes_required")
+	}
+	for _, scope := range c.Scopes {
+		known := false
+		for _, allowed := range entity.APIKeyScopes {
+			if scope == allowed {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errs.New(fmt.Sprintf("Unknown scope %q, allowed scopes are %s.", scope, strings.Join(entity.APIKeyScopes, ", ")), "invalid_scopes")
+		}
+	}
+	if c.ExpiresAt != nil && !c.ExpiresAt.After(time.Now()) {
+		return errs.New("Expiration must be in the future.", "invalid_expires_at")
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/blob"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"io"
+	"strings"
+	"time"
+)
+
+type assignmentService struct {
+	serviceContext
+	blob blob.Storage
+}
+
+var _ AssignmentService = (*assignmentService)(nil)
+
+func NewAssignmentService(options *Options) AssignmentService {
+	return &assignmentService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("AssignmentService"),
+		},
+		blob: options.Blob,
+	}
+}
+
+func (a *assignmentService) AddAssignment(ctx context.Context, options *AddAssignmentOptions) (*entity.Assignment, error) {
+	logger := a.logger.
+		Named("AddAssignment").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrAddAssignmentCourseNotFound
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrAddAssignmentNotCourseTeacher
+	}
+
+	assignment, err := a.storages.AssignmentStorage.CreateAssignment(ctx, &entity.Assignment{
+		CourseId:           course.Id,
+		Title:              options.Title,
+		Description:        options.Description,
+		MaxScore:           options.MaxScore,
+		DueAt:              options.DueAt,
+		LateDays:           options.LateDays,
+		LatePenaltyPercent: options.LatePenaltyPercent,
+	})
+	if err != nil {
+		logger.Error("failed to create assignment: ", err)
+		return nil, fmt.Errorf("failed to create assignment: %w", err)
+	}
+
+	logger.Info("successfully added assignment", "assignmentId", assignment.Id)
+	return assignment, nil
+}
+
+func (a *assignmentService) GetAssignments(ctx context.Context, courseId string) ([]*entity.Assignment, error) {
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: courseId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, ErrGetAssignmentsCourseNotFound
+	}
+
+	assignments, err := a.storages.AssignmentStorage.GetAssignments(ctx, courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+func (a *assignmentService) SubmitAssignment(ctx context.Context, options *SubmitAssignmentOptions) (*entity.Submission, error) {
+	logger := a.logger.
+		Named("SubmitAssignment").
+		WithContext(ctx).
+		With("userId", options.UserId, "assignmentId", options.AssignmentId)
+
+	if options.Size > a.config.Assignment.MaxFileSize {
+		logger.Info("file is too large", "size", options.Size)
+		return nil, ErrSubmitAssignmentFileTooLarge
+	}
+
+	assignment, err := a.storages.AssignmentStorage.GetAssignment(ctx, options.AssignmentId)
+	if err != nil {
+		logger.Error("failed to get assignment: ", err)
+		return nil, fmt.Errorf("failed to get assignment: %w", err)
+	}
+	if assignment == nil {
+		logger.Info("assignment not found")
+		return nil, ErrSubmitAssignmentAssignmentNotFound
+	}
+
+	enrolled, err := hasCourseAccess(ctx, a.storages, options.UserId, assignment.CourseId)
+	if err != nil {
+		logger.Error("failed to check course access: ", err)
+		return nil, err
+	}
+	if !enrolled {
+		logger.Info("user is not enrolled")
+		return nil, ErrSubmitAssignmentNotEnrolled
+	}
+
+	now := time.Now()
+	daysLate := lateDays(assignment.DueAt, now)
+	if daysLate > assignment.LateDays {
+		logger.Info("deadline has passed", "daysLate", daysLate)
+		return nil, ErrSubmitAssignmentPastDeadline
+	}
+
+	previous, err := a.storages.AssignmentStorage.GetSubmission(ctx, &storage.GetSubmissionFilter{
+		AssignmentId: assignment.Id,
+		UserId:       options.UserId,
+	})
+	if err != nil {
+		logger.Error("failed to get submission: ", err)
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	}
+	if previous != nil && previous.Status == entity.SubmissionGraded {
+		logger.Info("submission is graded already")
+		return nil, ErrSubmitAssignmentAlreadyGraded
+	}
+
+	// every upload gets own key, so file of saved submission is never overwritten by failed upload
+	blobKey := fmt.Sprintf("submissions/%s/%s/%s", assignment.Id, options.UserId, uuid.NewString())
+	size, err := a.blob.Put(ctx, blobKey, io.LimitReader(options.File, a.config.Assignment.MaxFileSize+1))
+	if err != nil {
+		logger.Error("failed to store file: ", err)
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if size > a.config.Assignment.MaxFileSize {
+		a.deleteBlob(ctx, blobKey)
+		logger.Info("file is too large", "size", size)
+		return nil, ErrSubmitAssignmentFileTooLarge
+	}
+
+	contentType := options.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	submission, err := a.storages.AssignmentStorage.SaveSubmission(ctx, &entity.Submission{
+		AssignmentId: assignment.Id,
+		UserId:       options.UserId,
+		CourseId:     assignment.CourseId,
+		FileName:     options.FileName,
+		ContentType:  contentType,
+		Size:         size,
+		BlobKey:      blobKey,
+		Comment:      options.Comment,
+		SubmittedAt:  now,
+		DaysLate:     daysLate,
+	})
+	if err != nil {
+		a.deleteBlob(ctx, blobKey)
+		logger.Error("failed to save submission: ", err)
+		return nil, fmt.Errorf("failed to save submission: %w", err)
+	}
+	if submission == nil {
+		a.deleteBlob(ctx, blobKey)
+		logger.Info("submission was graded meanwhile")
+		return nil, ErrSubmitAssignmentAlreadyGraded
+	}
+	if previous != nil {
+		a.deleteBlob(ctx, previous.BlobKey)
+	}
+
+	logger.Info("successfully submitted assignment", "submissionId", submission.Id, "daysLate", daysLate)
+	return submission, nil
+}
+
+func (a *assignmentService) GetMySubmission(ctx context.Context, options *GetMySubmissionOptions) (*entity.Submission, error) {
+	submission, err := a.storages.AssignmentStorage.GetSubmission(ctx, &storage.GetSubmissionFilter{
+		AssignmentId: options.AssignmentId,
+		UserId:       options.UserId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	}
+	if submission == nil {
+		return nil, ErrGetMySubmissionNotSubmitted
+	}
+
+	return submission, nil
+}
+
+func (a *assignmentService) GetSubmissionFile(ctx context.Context, options *GetSubmissionFileOptions) (*SubmissionFileOutput, error) {
+	logger := a.logger.
+		Named("GetSubmissionFile").
+		WithContext(ctx).
+		With("userId", options.UserId, "submissionId", options.SubmissionId)
+
+	submission, err := a.storages.AssignmentStorage.GetSubmission(ctx, &storage.GetSubmissionFilter{Id: options.SubmissionId})
+	if err != nil {
+		logger.Error("failed to get submission: ", err)
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	}
+	if submission == nil {
+		logger.Info("submission not found")
+		return nil, ErrGetSubmissionFileNotFound
+	}
+
+	if submission.UserId != options.UserId {
+		course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: submission.CourseId})
+		if err != nil || course == nil {
+			logger.Error("failed to get course: ", err)
+			return nil, fmt.Errorf("failed to get course: %w", err)
+		}
+		// submissions of other students are reported as missing
+		if course.TeacherId != options.UserId {
+			logger.Info("user is neither student nor teacher")
+			return nil, ErrGetSubmissionFileNotFound
+		}
+	}
+
+	content, err := a.blob.Get(ctx, submission.BlobKey)
+	if err != nil {
+		logger.Error("failed to open file: ", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return &SubmissionFileOutput{Submission: submission, Content: content}, nil
+}
+
+func (a *assignmentService) GradeSubmission(ctx context.Context, options *GradeSubmissionOptions) (*entity.Submission, error) {
+	logger := a.logger.
+		Named("GradeSubmission").
+		WithContext(ctx).
+		With("userId", options.UserId, "submissionId", options.SubmissionId)
+
+	submission, err := a.storages.AssignmentStorage.GetSubmission(ctx, &storage.GetSubmissionFilter{Id: options.SubmissionId})
+	if err != nil {
+		logger.Error("failed to get submission: ", err)
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	}
+	if submission == nil {
+		logger.Info("submission not found")
+		return nil, ErrGradeSubmissionNotFound
+	}
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: submission.CourseId})
+	if err != nil || course == nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrGradeSubmissionNotCourseTeacher
+	}
+
+	assignment, err := a.storages.AssignmentStorage.GetAssignment(ctx, submission.AssignmentId)
+	if err != nil || assignment == nil {
+		logger.Error("failed to get assignment: ", err)
+		return nil, fmt.Errorf("failed to get assignment: %w", err)
+	}
+	if options.Score < 0 || options.Score > assignment.MaxScore {
+		logger.Info("invalid score", "score", options.Score)
+		return nil, ErrGradeSubmissionInvalidScore
+	}
+
+	// grading again overwrites the grade, penalty is always computed from the submission delay
+	penalty := submission.DaysLate * assignment.LatePenaltyPercent
+	if penalty > 100 {
+		penalty = 100
+	}
+	score := options.Score
+	finalScore := score * (100 - penalty) / 100
+	now := time.Now()
+	submission.Status = entity.SubmissionGraded
+	submission.Score = &score
+	submission.FinalScore = &finalScore
+	submission.Feedback = strings.TrimSpace(options.Feedback)
+	submission.GradedBy = &options.UserId
+	submission.GradedAt = &now
+
+	submission, err = a.storages.AssignmentStorage.GradeSubmission(ctx, submission)
+	if err != nil {
+		logger.Error("failed to grade submission: ", err)
+		return nil, fmt.Errorf("failed to grade submission: %w", err)
+	}
+
+	logger.Info("successfully graded submission", "score", score, "finalScore", finalScore)
+	return submission, nil
+}
+
+func (a *assignmentService) GetGradingQueue(ctx context.Context, teacherId string) ([]*entity.GradingQueueItem, error) {
+	queue, err := a.storages.AssignmentStorage.GetGradingQueue(ctx, teacherId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grading queue: %w", err)
+	}
+
+	return queue, nil
+}
+
+// deleteBlob removes file that is not referenced anymore, failure only leaves orphaned file behind.
+func (a *assignmentService) deleteBlob(ctx context.Context, key string) {
+	err := a.blob.Delete(ctx, key)
+	if err != nil {
+		a.logger.WithContext(ctx).Error("failed to delete file: ", err, "key", key)
+	}
+}
+
+// lateDays returns number of started days since due time, zero when submitted in time.
+func lateDays(dueAt, submittedAt time.Time) int {
+	if !submittedAt.After(dueAt) {
+		return 0
+	}
+	late := submittedAt.Sub(dueAt)
+	return int((late + 24*time.Hour - 1) / (24 * time.Hour))
+}
+
+func (a *AddAssignmentOptions) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return errs.New("Title is required.", "invalid_title")
+	}
+	if a.MaxScore <= 0 {
+		return errs.New("Max score must be positive.", "invalid_max_score")
+	}
+	if a.DueAt.IsZero() {
+		return errs.New("Due date is required.", "invalid_due_at")
+	}
+	if a.LateDays < 0 || a.LatePenaltyPercent < 0 || a.LatePenaltyPercent > 100 {
+		return errs.New("Late days can't be negative and penalty must be percent from 0 to 100.", "invalid_late_policy")
+	}
+	return nil
+}
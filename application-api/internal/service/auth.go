@@ -3,30 +3,45 @@ package service
 import (
 	"context"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/internal/storage"
 	"github.com/vovk404/course-platform/application-api/pkg/auth"
 	"github.com/vovk404/course-platform/application-api/pkg/errs"
 	"github.com/vovk404/course-platform/application-api/pkg/hash"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+	"github.com/vovk404/course-platform/application-api/pkg/mailer"
+	"github.com/vovk404/course-platform/application-api/pkg/oidc"
+	"net/mail"
 )
 
 type authService struct {
 	serviceContext
-	hash hash.Hash
-	auth auth.Authenticator
+	hash   hash.Hash
+	auth   auth.Authenticator
+	mailer mailer.Mailer
+	oidc   *oidc.Provider
+	// dummyHash is compared on sign in of unknown users to keep timing constant.
+	dummyHash string
 }
 
 var _ AuthService = (*authService)(nil)
 
 func NewAuthService(options *Options) AuthService {
+	// error leaves hash empty, comparing with it just fails faster
+	dummyHash, _ := options.Hash.GenerateHash(uuid.NewString())
+
 	return &authService{
 		serviceContext: serviceContext{
 			storages: options.Storages,
 			config:   options.Config,
 			logger:   options.Logger.Named("AuthService"),
 		},
-		hash: options.Hash,
-		auth: options.Auth,
+		hash:      options.Hash,
+		auth:      options.Auth,
+		mailer:    options.Mailer,
+		oidc:      options.OIDC,
+		dummyHash: dummyHash,
 	}
 }
 
@@ -34,23 +49,72 @@ func (a *authService) SignIn(ctx context.Context, options *SignInOptions) (*Sign
 	logger := a.logger.
 		Named("SignIn").
 		WithContext(ctx).
-		With("options", options)
+		With("email", options.Email, "ip", options.IP)
+
+	keys := a.loginKeys(options)
+	wait, err := a.checkLoginThrottle(ctx, keys)
+	if err != nil {
+		logger.Error("failed to check login throttle: ", err)
+		return nil, fmt.Errorf("failed to check login throttle: %w", err)
+	}
+	if wait > 0 {
+		logger.Info("sign in throttled", "wait", wait)
+		return nil, tooManyAttempts(wait)
+	}
 
 	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{Email: options.Email})
 	if err != nil {
 		logger.Error("failed to get user: ", err)
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	if user == nil {
-		logger.Info("user not found")
-		return nil, ErrSignInUserNotFound
+
+	// compare against dummy hash for unknown users, so response time doesn't reveal registered emails
+	hashedPassword := a.dummyHash
+	if user != nil {
+		hashedPassword = user.Password
+	}
+	err = a.hash.CompareHash([]byte(hashedPassword), []byte(options.Password))
+	if err != nil || user == nil {
+		logger.Info("invalid credentials", "userFound", user != nil)
+
+		err = a.registerLoginFailure(ctx, keys)
+		if err != nil {
+			logger.Error("failed to register login failure: ", err)
+		}
+		return nil, ErrSignInInvalidCredentials
+	}
+	logger = logger.With("user", user.Id)
+
+	// successful sign in proves account ownership, ip counter is kept to slow down credential stuffing
+	err = a.storages.LoginStorage.ResetLoginFailures(ctx, keys[0].key)
+	if err != nil {
+		logger.Error("failed to reset login failures: ", err)
 	}
-	logger = logger.With("user", user)
 
-	err = a.hash.CompareHash([]byte(user.Password), []byte(options.Password))
+	return a.completeSignIn(ctx, user)
+}
+
+// completeSignIn issues access token, or challenge token when user has two-factor authentication enabled.
+func (a *authService) completeSignIn(ctx context.Context, user *entity.User) (*SignInOutput, error) {
+	logger := a.logger.
+		Named("completeSignIn").
+		WithContext(ctx).
+		With("user", user.Id)
+
+	twoFactor, err := a.storages.TwoFactorStorage.GetTwoFactor(ctx, user.Id)
 	if err != nil {
-		logger.Info(err.Error())
-		return nil, ErrSignInWrongPassword
+		logger.Error("failed to get two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		challengeToken, err := a.issueOneTimeToken(ctx, user, entity.TokenPurposeTwoFactorChallenge, a.config.TwoFactor.ChallengeTTL)
+		if err != nil {
+			logger.Error("failed to issue challenge token: ", err)
+			return nil, fmt.Errorf("failed to issue challenge token: %w", err)
+		}
+
+		logger.Info("two-factor authentication required")
+		return &SignInOutput{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
 	}
 
 	accessToken, err := a.auth.GenerateToken(&auth.GenerateTokenClaimsOptions{UserName: user.Username, UserId: user.Id})
@@ -96,6 +160,12 @@ func (a *authService) SignUp(ctx context.Context, options *SignUpOptions) (*Sign
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// user is created already, so failed email is logged and can be requested again with ResendVerificationEmail
+	err = a.sendVerificationEmail(ctx, createdUser)
+	if err != nil {
+		logger.Error("failed to send verification email: ", err)
+	}
+
 	accessToken, err := a.auth.GenerateToken(&auth.GenerateTokenClaimsOptions{UserName: createdUser.Username, UserId: createdUser.Id})
 	if err != nil {
 		logger.Error("failed to generate token for user: ", err)
@@ -127,10 +197,196 @@ func (a *authService) VerifyToken(ctx context.Context, options *VerifyTokenOptio
 	return &VerifyTokenOutput{Username: claims.Username, UserId: claims.UserId}, nil
 }
 
+func (a *authService) PublicKeys(ctx context.Context) *jwks.Set {
+	return a.auth.PublicKeys()
+}
+
+func (a *authService) VerifyEmail(ctx context.Context, options *VerifyEmailOptions) error {
+	logger := a.logger.
+		Named("VerifyEmail").
+		WithContext(ctx)
+
+	token, err := a.useOneTimeToken(ctx, options.Token, entity.TokenPurposeVerifyEmail)
+	if err != nil {
+		logger.Error("failed to use token: ", err)
+		return fmt.Errorf("failed to use token: %w", err)
+	}
+	if token == nil {
+		logger.Info("invalid token")
+		return ErrVerifyEmailInvalidToken
+	}
+	logger = logger.With("userId", token.UserId)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: token.UserId})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		logger.Info("user not found")
+		return ErrVerifyEmailInvalidToken
+	}
+
+	user.EmailVerified = true
+	_, err = a.storages.UserStorage.UpdateUser(ctx, user)
+	if err != nil {
+		logger.Error("failed to update user: ", err)
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	logger.Info("successfully verified email")
+	return nil
+}
+
+func (a *authService) ResendVerificationEmail(ctx context.Context, userId string) error {
+	logger := a.logger.
+		Named("ResendVerificationEmail").
+		WithContext(ctx).
+		With("userId", userId)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.EmailVerified {
+		logger.Info("email is verified already")
+		return ErrResendVerificationVerified
+	}
+
+	err = a.sendVerificationEmail(ctx, user)
+	if err != nil {
+		logger.Error("failed to send verification email: ", err)
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	logger.Info("successfully resent verification email")
+	return nil
+}
+
+func (a *authService) ForgotPassword(ctx context.Context, options *ForgotPasswordOptions) error {
+	logger := a.logger.
+		Named("ForgotPassword").
+		WithContext(ctx).
+		With("email", options.Email)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{Email: options.Email})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		// same response as for existing user, so emails can't be enumerated
+		logger.Info("user not found")
+		return nil
+	}
+
+	token, err := a.issueOneTimeToken(ctx, user, entity.TokenPurposeResetPassword, a.config.Token.ResetPasswordTTL)
+	if err != nil {
+		logger.Error("failed to issue token: ", err)
+		return fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	err = a.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the token below to set a new password. It expires in %s.\n\n%s\n\nIf you didn't request a password reset, ignore this email.\n",
+			user.Username, a.config.Token.ResetPasswordTTL, token,
+		),
+	})
+	if err != nil {
+		// failure isn't returned, it would tell registered emails apart from unknown ones
+		logger.Error("failed to send reset password email: ", err)
+		return nil
+	}
+
+	logger.Info("successfully sent reset password email")
+	return nil
+}
+
+func (a *authService) ResetPassword(ctx context.Context, options *ResetPasswordWithTokenOptions) error {
+	logger := a.logger.
+		Named("ResetPassword").
+		WithContext(ctx)
+
+	token, err := a.useOneTimeToken(ctx, options.Token, entity.TokenPurposeResetPassword)
+	if err != nil {
+		logger.Error("failed to use token: ", err)
+		return fmt.Errorf("failed to use token: %w", err)
+	}
+	if token == nil {
+		logger.Info("invalid token")
+		return ErrResetPasswordInvalidToken
+	}
+	logger = logger.With("userId", token.UserId)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: token.UserId})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		logger.Info("user not found")
+		return ErrResetPasswordInvalidToken
+	}
+
+	user.Password, err = a.hash.GenerateHash(options.Password)
+	if err != nil {
+		logger.Error("failed to hash user password: ", err)
+		return fmt.Errorf("failed to hash user password: %w", err)
+	}
+	// receiving the token proves ownership of the email as well
+	user.EmailVerified = true
+
+	_, err = a.storages.UserStorage.UpdateUser(ctx, user)
+	if err != nil {
+		logger.Error("failed to update user: ", err)
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	err = a.storages.TokenStorage.DeleteUserTokens(ctx, user.Id, entity.TokenPurposeResetPassword)
+	if err != nil {
+		logger.Error("failed to delete reset password tokens: ", err)
+	}
+
+	logger.Info("successfully reset password")
+	return nil
+}
+
+func (a *authService) sendVerificationEmail(ctx context.Context, user *entity.User) error {
+	token, err := a.issueOneTimeToken(ctx, user, entity.TokenPurposeVerifyEmail, a.config.Token.VerifyEmailTTL)
+	if err != nil {
+		return fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	return a.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the token below to verify your email. It expires in %s.\n\n%s\n",
+			user.Username, a.config.Token.VerifyEmailTTL, token,
+		),
+	})
+}
+
 func (s *SignUpOptions) Validate() error {
 	fmt.Println("User type: ", s.Type)
 	if s.Type > 2 || s.Type < 1 {
 		return errs.New("Type must be either 1 or 2, which means student or teacher.", "wrong user type")
 	}
+	if _, err := mail.ParseAddress(s.Email); err != nil {
+		return errs.New("Email is not valid.", "invalid_email")
+	}
+	return nil
+}
+
+func (r *ResetPasswordWithTokenOptions) Validate() error {
+	if r.Token == "" {
+		return errs.New("Token is required.", "token_required")
+	}
+	if r.Password == "" {
+		return errs.New("Password is required.", "invalid_password")
+	}
 	return nil
 }
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	_maxBundleNameLength = 200
+	_maxBundleCourses    = 50
+)
+
+type bundleService struct {
+	serviceContext
+}
+
+var _ BundleService = (*bundleService)(nil)
+
+func NewBundleService(options *Options) BundleService {
+	return &bundleService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("BundleService"),
+		},
+	}
+}
+
+func (b *bundleService) CreateBundle(ctx context.Context, options *CreateBundleOptions) (*entity.Bundle, error) {
+	logger := b.logger.
+		Named("CreateBundle").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	user, err := b.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Teacher {
+		logger.Info("user is not teacher", "type", user.Type)
+		return nil, ErrCreateBundleNotTeacher
+	}
+	if options.Published && !user.EmailVerified {
+		logger.Info("user email is not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	courses, err := b.storages.CourseStorage.GetCourses(ctx, options.CourseIds)
+	if err != nil {
+		logger.Error("failed to get courses: ", err)
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+	if len(courses) != len(options.CourseIds) {
+		logger.Info("some courses not found")
+		return nil, ErrCreateBundleCourseNotFound
+	}
+	for _, course := range courses {
+		if course.TeacherId != user.Id || !course.Published {
+			logger.Info("course is not own published course", "courseId", course.Id)
+			return nil, ErrCreateBundleCourseNotFound
+		}
+	}
+
+	bundle, err := b.storages.BundleStorage.CreateBundle(ctx, &entity.Bundle{
+		TeacherId:   user.Id,
+		Name:        strings.TrimSpace(options.Name),
+		Description: strings.TrimSpace(options.Description),
+		Price:       options.Price,
+		Published:   options.Published,
+		Courses:     courses,
+	})
+	if err != nil {
+		logger.Error("failed to create bundle: ", err)
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	logger.Info("successfully created bundle", "bundleId", bundle.Id)
+	return bundle, nil
+}
+
+func (b *bundleService) GetBundles(ctx context.Context) ([]*entity.Bundle, error) {
+	bundles, err := b.storages.BundleStorage.GetBundles(ctx, &storage.GetBundlesFilter{Published: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundles: %w", err)
+	}
+
+	return bundles, nil
+}
+
+func (b *bundleService) GetBundle(ctx context.Context, bundleId string) (*entity.Bundle, error) {
+	bundle, err := b.storages.BundleStorage.GetBundle(ctx, bundleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle: %w", err)
+	}
+	if bundle == nil || !bundle.Published {
+		return nil, ErrGetBundleBundleNotFound
+	}
+
+	return bundle, nil
+}
+
+func (b *bundleService) GetMyBundles(ctx context.Context, userId string) ([]*entity.Bundle, error) {
+	bundles, err := b.storages.BundleStorage.GetBundles(ctx, &storage.GetBundlesFilter{TeacherId: userId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundles: %w", err)
+	}
+
+	return bundles, nil
+}
+
+// bundleItems splits bundle price between its courses in proportion to their prices, so every item
+// can be refunded and earned separately. Rounding remainder goes to the last course.
+func bundleItems(bundle *entity.Bundle) []*entity.OrderItem {
+	items := make([]*entity.OrderItem, 0, len(bundle.Courses))
+	var listTotal int64
+	for _, course := range bundle.Courses {
+		listTotal += minorUnits(course.Price)
+	}
+
+	var allocated int64
+	for i, course := range bundle.Courses {
+		var price int64
+		switch {
+		case i == len(bundle.Courses)-1:
+			price = bundle.Price - allocated
+		case listTotal == 0:
+			price = bundle.Price / int64(len(bundle.Courses))
+		default:
+			price = bundle.Price * minorUnits(course.Price) / listTotal
+		}
+		allocated += price
+
+		items = append(items, &entity.OrderItem{
+			CourseId:  course.Id,
+			TeacherId: course.TeacherId,
+			Price:     price,
+			BundleId:  &bundle.Id,
+		})
+	}
+	return items
+}
+
+func (c *CreateBundleOptions) Validate() error {
+	name := strings.TrimSpace(c.Name)
+	if name == "" || utf8.RuneCountInString(name) > _maxBundleNameLength {
+		return errs.New(fmt.Sprintf("Name is required and can't be longer than %d characters.", _maxBundleNameLength), "invalid_bundle_name")
+	}
+	if c.Price <= 0 {
+		return errs.New("Price must be positive.", "invalid_price")
+	}
+	if len(c.CourseIds) < 2 || len(c.CourseIds) > _maxBundleCourses {
+		return errs.New(fmt.Sprintf("Bundle must include from 2 to %d courses.", _maxBundleCourses), "invalid_courses_count")
+	}
+	seen := make(map[string]bool, len(c.CourseIds))
+	for _, courseId := range c.CourseIds {
+		if _, err := uuid.Parse(courseId); err != nil || seen[courseId] {
+			return errs.New("Course ids must be valid and unique.", "invalid_courses")
+		}
+		seen[courseId] = true
+	}
+	return nil
+}
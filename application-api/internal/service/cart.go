@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+)
+
+type cartService struct {
+	serviceContext
+}
+
+var _ CartService = (*cartService)(nil)
+
+func NewCartService(options *Options) CartService {
+	return &cartService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("CartService"),
+		},
+	}
+}
+
+func (c *cartService) GetCart(ctx context.Context, userId string) (*entity.Cart, error) {
+	items, err := c.storages.CartStorage.GetCartItems(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+
+	cart := &entity.Cart{Items: items, Currency: c.config.Payment.Currency}
+	for _, item := range items {
+		cart.Total += item.Price
+	}
+	return cart, nil
+}
+
+func (c *cartService) AddToCart(ctx context.Context, options *AddToCartOptions) (*entity.Cart, error) {
+	logger := c.logger.
+		Named("AddToCart").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := c.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil || !course.Published {
+		logger.Info("course not found")
+		return nil, ErrAddToCartCourseNotFound
+	}
+	price := minorUnits(course.Price)
+	if price <= 0 {
+		logger.Info("course is free")
+		return nil, ErrAddToCartFree
+	}
+	if course.TeacherId == options.UserId {
+		logger.Info("user is teacher of the course")
+		return nil, ErrAddToCartOwnCourse
+	}
+
+	enrollment, err := c.storages.EnrollmentStorage.GetEnrollment(ctx, options.UserId, course.Id)
+	if err != nil {
+		logger.Error("failed to get enrollment: ", err)
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	if enrollment != nil {
+		logger.Info("course is owned already")
+		return nil, ErrAddToCartAlreadyOwned
+	}
+
+	err = c.storages.CartStorage.AddCartItem(ctx, &entity.CartItem{
+		UserId:   options.UserId,
+		CourseId: course.Id,
+		Price:    price,
+	})
+	if err != nil {
+		logger.Error("failed to add cart item: ", err)
+		return nil, fmt.Errorf("failed to add cart item: %w", err)
+	}
+
+	logger.Info("successfully added course to cart")
+	return c.GetCart(ctx, options.UserId)
+}
+
+func (c *cartService) RemoveFromCart(ctx context.Context, options *RemoveFromCartOptions) (*entity.Cart, error) {
+	err := c.storages.CartStorage.RemoveCartItems(ctx, options.UserId, []string{options.CourseId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove cart item: %w", err)
+	}
+
+	return c.GetCart(ctx, options.UserId)
+}
+
+func (a *AddToCartOptions) Validate() error {
+	if _, err := uuid.Parse(a.CourseId); err != nil {
+		return errs.New("Course id is invalid.", "invalid_course_id")
+	}
+	return nil
+}
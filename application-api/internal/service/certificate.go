@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/auth"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+	"github.com/vovk404/course-platform/application-api/pkg/pdf"
+	"strings"
+	"time"
+)
+
+// _certificateAudience - keeps certificate signatures from being accepted as access tokens.
+const _certificateAudience = "certificate"
+
+// certificateClaims - represents signed content of certificate.
+type certificateClaims struct {
+	jwt.RegisteredClaims
+	CourseId   string `json:"courseId"`
+	Username   string `json:"username"`
+	CourseName string `json:"courseName"`
+	Author     string `json:"author"`
+}
+
+type certificateService struct {
+	serviceContext
+	signer auth.Signer
+	fonts  *CertificateFonts
+}
+
+var _ CertificateService = (*certificateService)(nil)
+
+func NewCertificateService(options *Options) CertificateService {
+	return &certificateService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("CertificateService"),
+		},
+		signer: options.Signer,
+		fonts:  options.CertificateFonts,
+	}
+}
+
+func (c *certificateService) IssueCertificate(ctx context.Context, userId, courseId string) (*entity.Certificate, error) {
+	logger := c.logger.
+		Named("IssueCertificate").
+		WithContext(ctx).
+		With("userId", userId, "courseId", courseId)
+
+	certificate, err := c.storages.CertificateStorage.GetCertificate(ctx, &storage.GetCertificateFilter{UserId: userId, CourseId: courseId})
+	if err != nil {
+		logger.Error("failed to get certificate: ", err)
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+	if certificate != nil {
+		return certificate, nil
+	}
+
+	progress, err := c.storages.ProgressStorage.GetCoursesProgress(ctx, userId, []string{courseId})
+	if err != nil {
+		logger.Error("failed to get progress: ", err)
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+	if len(progress) == 0 || !progress[0].Completed() {
+		return nil, nil
+	}
+
+	user, err := c.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	course, err := c.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: courseId})
+	if err != nil || course == nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	certificate = &entity.Certificate{
+		Id:         uuid.NewString(),
+		UserId:     user.Id,
+		CourseId:   course.Id,
+		Username:   user.Username,
+		CourseName: course.Name,
+		Author:     course.Author,
+		// signed time has second precision, so it is compared exactly after reading from storage
+		IssuedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	certificate.Signature, err = c.signer.SignClaims(newCertificateClaims(certificate))
+	if err != nil {
+		logger.Error("failed to sign certificate: ", err)
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+
+	certificate, err = c.storages.CertificateStorage.CreateCertificate(ctx, certificate)
+	if err != nil {
+		logger.Error("failed to create certificate: ", err)
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	logger.Info("successfully issued certificate", "certificateId", certificate.Id)
+	return certificate, nil
+}
+
+func (c *certificateService) GetMyCertificates(ctx context.Context, userId string) ([]*entity.Certificate, error) {
+	certificates, err := c.storages.CertificateStorage.GetUserCertificates(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+func (c *certificateService) GetCertificatePDF(ctx context.Context, options *GetCertificatePDFOptions) ([]byte, error) {
+	logger := c.logger.
+		Named("GetCertificatePDF").
+		WithContext(ctx).
+		With("userId", options.UserId, "certificateId", options.CertificateId)
+
+	certificate, err := c.storages.CertificateStorage.GetCertificate(ctx, &storage.GetCertificateFilter{Id: options.CertificateId})
+	if err != nil {
+		logger.Error("failed to get certificate: ", err)
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+	// certificates of other users are reported as missing, anyone can still verify them by id
+	if certificate == nil || certificate.UserId != options.UserId {
+		logger.Info("certificate not found")
+		return nil, ErrGetCertificatePDFCertificateNotFound
+	}
+	if certificate.RevokedAt != nil {
+		logger.Info("certificate is revoked")
+		return nil, ErrGetCertificatePDFRevoked
+	}
+
+	return c.renderCertificate(certificate), nil
+}
+
+func (c *certificateService) PublicKeys(ctx context.Context) *jwks.Set {
+	return c.signer.PublicKeys()
+}
+
+func (c *certificateService) VerifyCertificate(ctx context.Context, certificateId string) (*VerifyCertificateOutput, error) {
+	logger := c.logger.
+		Named("VerifyCertificate").
+		WithContext(ctx).
+		With("certificateId", certificateId)
+
+	certificate, err := c.storages.CertificateStorage.GetCertificate(ctx, &storage.GetCertificateFilter{Id: certificateId})
+	if err != nil {
+		logger.Error("failed to get certificate: ", err)
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+	if certificate == nil {
+		logger.Info("certificate not found")
+		return nil, ErrVerifyCertificateCertificateNotFound
+	}
+
+	// signature is checked against stored fields, so certificate changed in database isn't valid either
+	authentic := true
+	claims := &certificateClaims{}
+	err = c.signer.ParseClaims(certificate.Signature, claims)
+	if err != nil {
+		logger.Info("invalid certificate signature: ", err)
+		authentic = false
+	} else if !claims.matches(newCertificateClaims(certificate)) {
+		logger.Info("certificate doesn't match signature")
+		authentic = false
+	}
+
+	revoked := certificate.RevokedAt != nil
+	return &VerifyCertificateOutput{
+		Valid:       authentic && !revoked,
+		Revoked:     revoked,
+		Certificate: certificate,
+	}, nil
+}
+
+func (c *certificateService) RevokeCertificate(ctx context.Context, options *RevokeCertificateOptions) (*entity.Certificate, error) {
+	logger := c.logger.
+		Named("RevokeCertificate").
+		WithContext(ctx).
+		With("userId", options.UserId, "certificateId", options.CertificateId)
+
+	user, err := c.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return nil, ErrRevokeCertificateNotAdmin
+	}
+
+	certificate, err := c.storages.CertificateStorage.RevokeCertificate(ctx, options.CertificateId, strings.TrimSpace(options.Reason))
+	if err != nil {
+		logger.Error("failed to revoke certificate: ", err)
+		return nil, fmt.Errorf("failed to revoke certificate: %w", err)
+	}
+	if certificate == nil {
+		logger.Info("certificate not found")
+		return nil, ErrRevokeCertificateCertificateNotFound
+	}
+
+	logger.Info("successfully revoked certificate")
+	return certificate, nil
+}
+
+// renderCertificate draws landscape A4 certificate with link to verification endpoint.
+func (c *certificateService) renderCertificate(certificate *entity.Certificate) []byte {
+	document := pdf.New(pdf.A4Height, pdf.A4Width)
+	document.SetTitle("Certificate of Completion - " + certificate.CourseName)
+	width, height := document.Width(), document.Height()
+	maxTextWidth := width - 140
+
+	document.Rectangle(30, 30, width-60, height-60, 3)
+	document.Rectangle(40, 40, width-80, height-80, 1)
+
+	document.CenteredText(460, c.fonts.Bold, 34, "CERTIFICATE OF COMPLETION")
+	document.CenteredText(405, c.fonts.Regular, 16, "This is to certify that")
+	document.CenteredText(360, c.fonts.Bold, fitTextSize(c.fonts.Bold, 30, certificate.Username, maxTextWidth), certificate.Username)
+	document.CenteredText(320, c.fonts.Regular, 16, "has successfully completed the course")
+	document.CenteredText(275, c.fonts.Bold, fitTextSize(c.fonts.Bold, 26, certificate.CourseName, maxTextWidth), certificate.CourseName)
+	if certificate.Author != "" {
+		author := "by " + certificate.Author
+		document.CenteredText(240, c.fonts.Regular, fitTextSize(c.fonts.Regular, 16, author, maxTextWidth), author)
+	}
+
+	document.Line(width/2-120, 175, width/2+120, 175, 0.5)
+	document.CenteredText(155, c.fonts.Regular, 14, "Issued on "+certificate.IssuedAt.Format("January 2, 2006"))
+
+	document.CenteredText(90, c.fonts.Regular, 10, "Certificate ID: "+certificate.Id)
+	document.CenteredText(74, c.fonts.Regular, 10, "Verify at "+strings.TrimSuffix(c.config.App.BaseURL, "/")+"/api/v1/certificates/"+certificate.Id+"/verify")
+
+	return document.Bytes()
+}
+
+// fitTextSize shrinks font size below given one until text fits into width.
+func fitTextSize(font pdf.Font, size float64, text string, width float64) float64 {
+	textWidth := pdf.TextWidth(font, size, text)
+	if textWidth <= width {
+		return size
+	}
+	return size * width / textWidth
+}
+
+func newCertificateClaims(certificate *entity.Certificate) *certificateClaims {
+	return &certificateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       certificate.Id,
+			Subject:  certificate.UserId,
+			Audience: []string{_certificateAudience},
+			IssuedAt: jwt.NewNumericDate(certificate.IssuedAt),
+		},
+		CourseId:   certificate.CourseId,
+		Username:   certificate.Username,
+		CourseName: certificate.CourseName,
+		Author:     certificate.Author,
+	}
+}
+
+func (c *certificateClaims) matches(other *certificateClaims) bool {
+	return len(c.Audience) == 1 && c.Audience[0] == _certificateAudience &&
+		c.ID == other.ID &&
+		c.Subject == other.Subject &&
+		c.IssuedAt != nil && c.IssuedAt.Unix() == other.IssuedAt.Unix() &&
+		c.CourseId == other.CourseId &&
+		c.Username == other.Username &&
+		c.CourseName == other.CourseName &&
+		c.Author == other.Author
+}
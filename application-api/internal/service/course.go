@@ -5,6 +5,15 @@ import (
 	"fmt"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/i18n"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	_maxCourseNameLength        = 200
+	_maxCourseDescriptionLength = 20000
 )
 
 type courseService struct {
@@ -28,7 +37,21 @@ func (a *courseService) UploadCourse(ctx context.Context, options *UploadCourseO
 		Named("UploadCourse").
 		WithContext(ctx).
 		With("options", options)
-	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Name: options.Name, Author: options.Author})
+	//get user
+	userId := ctx.Value("userId").(string)
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		logger.Error("can`t find user with this id", err)
+		return nil, fmt.Errorf("can`t find user with this id: %s , error: %w", userId, err)
+	}
+	if user.Type != entity.Teacher {
+		return nil, fmt.Errorf("user`s type can`t allow creating a course")
+	}
+	if !user.EmailVerified {
+		logger.Info("user email is not verified")
+		return nil, ErrUploadCourseEmailNotVerified
+	}
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Name: options.Name, TeacherId: user.Id})
 	if err != nil {
 		logger.Error("failed to get course: ", course)
 		return nil, fmt.Errorf("failed to get course: %w", err)
@@ -37,24 +60,25 @@ func (a *courseService) UploadCourse(ctx context.Context, options *UploadCourseO
 		logger.Error("course with such name and author already created: ", course)
 		return nil, fmt.Errorf("course with such name and author already created")
 	}
-	//get user
-	userId := ctx.Value("userId").(string)
-	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
-	if err != nil || user == nil {
-		logger.Error("can`t find user with this id", err)
-		return nil, fmt.Errorf("can`t find user with this id: %w , error: %w", userId, err)
+	// author is unique display name of profile, usernames could be taken as display names by other teachers
+	profile, err := a.storages.TeacherStorage.GetProfile(ctx, user.Id)
+	if err != nil {
+		logger.Error("failed to get teacher profile: ", err)
+		return nil, fmt.Errorf("failed to get teacher profile: %w", err)
 	}
-	if user.Type != entity.Teacher {
-		return nil, fmt.Errorf("user`s type can`t allow creating a course")
+	if profile == nil {
+		logger.Info("teacher profile is not created")
+		return nil, ErrUploadCourseProfileRequired
 	}
 
 	insertCourse := entity.Course{
 		Name:           options.Name,
-		Author:         options.Author,
+		Author:         profile.DisplayName,
 		Description:    options.Description,
 		Price:          options.Price,
 		CourseLanguage: options.CourseLanguage,
 		TeacherId:      user.Id,
+		Published:      true,
 	}
 	//create course
 	createdCourse, err := a.storages.CourseStorage.CreateCourse(ctx, &insertCourse)
@@ -71,18 +95,164 @@ func (a *courseService) UploadCourse(ctx context.Context, options *UploadCourseO
 	}, nil
 }
 
-func (a *courseService) GetCourseById(ctx context.Context, id string) (*entity.Course, error) {
-	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: id})
+func (a *courseService) GetCourseById(ctx context.Context, options *GetCourseByIdOptions) (*entity.Course, error) {
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.Id})
 	if err != nil || course == nil {
 		return nil, fmt.Errorf("failed to get course: %w", err)
 	}
+	if len(options.Languages) == 0 {
+		return course, nil
+	}
+
+	translations, err := a.storages.CourseStorage.GetTranslations(ctx, course.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+	localizeCourse(course, translations, options.Languages)
+
 	return course, nil
 }
 
+func (a *courseService) GetCourseTranslations(ctx context.Context, courseId string) ([]*entity.CourseTranslation, error) {
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: courseId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, ErrGetCourseTranslationsCourseNotFound
+	}
+
+	translations, err := a.storages.CourseStorage.GetTranslations(ctx, course.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+func (a *courseService) SetCourseTranslation(ctx context.Context, options *SetCourseTranslationOptions) (*entity.CourseTranslation, error) {
+	logger := a.logger.
+		Named("SetCourseTranslation").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId, "language", options.Language)
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrSetCourseTranslationCourseNotFound
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrSetCourseTranslationNotCourseTeacher
+	}
+
+	translation, err := a.storages.CourseStorage.SaveTranslation(ctx, &entity.CourseTranslation{
+		CourseId:    course.Id,
+		Language:    canonicalLanguageTag(options.Language),
+		Name:        strings.TrimSpace(options.Name),
+		Description: strings.TrimSpace(options.Description),
+	})
+	if err != nil {
+		logger.Error("failed to save translation: ", err)
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+
+	logger.Info("successfully saved translation")
+	return translation, nil
+}
+
+func (a *courseService) DeleteCourseTranslation(ctx context.Context, options *DeleteCourseTranslationOptions) error {
+	logger := a.logger.
+		Named("DeleteCourseTranslation").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId, "language", options.Language)
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return ErrDeleteCourseTranslationCourseNotFound
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return ErrDeleteCourseTranslationNotCourseTeacher
+	}
+
+	deleted, err := a.storages.CourseStorage.DeleteTranslation(ctx, course.Id, canonicalLanguageTag(options.Language))
+	if err != nil {
+		logger.Error("failed to delete translation: ", err)
+		return fmt.Errorf("failed to delete translation: %w", err)
+	}
+	if !deleted {
+		logger.Info("translation not found")
+		return ErrDeleteCourseTranslationNotFound
+	}
+
+	logger.Info("successfully deleted translation")
+	return nil
+}
+
+// localizeCourse replaces name and description of course with translation to the first of languages
+// course is available in. Fallback chain follows languages in order, each one is tried exactly and then
+// by its base language, and ends with original course. Course stays original when its own language is matched.
+func localizeCourse(course *entity.Course, translations []*entity.CourseTranslation, languages []string) {
+	original := ""
+	if course.CourseLanguage != "" && isLanguageTag(course.CourseLanguage) {
+		original = course.CourseLanguage
+	}
+	course.ContentLanguage = original
+
+	available := make([]string, 0, len(translations)+1)
+	if original != "" {
+		available = append(available, original)
+	}
+	for _, translation := range translations {
+		available = append(available, translation.Language)
+	}
+
+	language, ok := i18n.Match(languages, available)
+	if !ok || language == original {
+		return
+	}
+	for _, translation := range translations {
+		if translation.Language == language {
+			course.Name = translation.Name
+			course.Description = translation.Description
+			course.ContentLanguage = translation.Language
+			return
+		}
+	}
+}
+
+// canonicalLanguageTag returns tag in its usual case, e.g. pt-BR for PT-br, so every language is stored once.
+func canonicalLanguageTag(tag string) string {
+	parts := strings.Split(tag, "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
 func (a *courseService) GetTeachersList(ctx context.Context, teacherId string) ([]*entity.Course, error) {
 	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: teacherId})
 	if err != nil || user == nil {
-		return nil, fmt.Errorf("can`t find user with this id: %w , error: %w", teacherId, err)
+		return nil, fmt.Errorf("can`t find user with this id: %s , error: %w", teacherId, err)
 	}
 	if user.Type != entity.Teacher {
 		return nil, fmt.Errorf("user`s type can`t allow getting a course list")
@@ -104,3 +274,199 @@ func (a *courseService) GetList() ([]*entity.Course, error) {
 
 	return courses, nil
 }
+
+func (a *courseService) Enroll(ctx context.Context, options *EnrollOptions) (*entity.Enrollment, error) {
+	logger := a.logger.
+		Named("Enroll").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil || !course.Published {
+		logger.Info("course not found")
+		return nil, ErrEnrollCourseNotFound
+	}
+	if course.Price > 0 {
+		logger.Info("course is paid")
+		return nil, ErrEnrollPaymentRequired
+	}
+
+	enrollment, err := a.storages.EnrollmentStorage.CreateEnrollment(ctx, &entity.Enrollment{
+		UserId:   options.UserId,
+		CourseId: options.CourseId,
+	})
+	if err != nil {
+		logger.Error("failed to create enrollment: ", err)
+		return nil, fmt.Errorf("failed to create enrollment: %w", err)
+	}
+
+	logger.Info("successfully enrolled user")
+	return enrollment, nil
+}
+
+func (a *courseService) AddSection(ctx context.Context, options *AddSectionOptions) (*entity.Section, error) {
+	logger := a.logger.
+		Named("AddSection").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrAddSectionCourseNotFound
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrAddSectionNotCourseTeacher
+	}
+
+	section, err := a.storages.CurriculumStorage.CreateSection(ctx, &entity.Section{
+		CourseId: course.Id,
+		Title:    options.Title,
+		Position: options.Position,
+	})
+	if err != nil {
+		logger.Error("failed to create section: ", err)
+		return nil, fmt.Errorf("failed to create section: %w", err)
+	}
+
+	logger.Info("successfully added section", "sectionId", section.Id)
+	return section, nil
+}
+
+func (a *courseService) AddLesson(ctx context.Context, options *AddLessonOptions) (*entity.Lesson, error) {
+	logger := a.logger.
+		Named("AddLesson").
+		WithContext(ctx).
+		With("userId", options.UserId, "sectionId", options.SectionId)
+
+	section, err := a.storages.CurriculumStorage.GetSection(ctx, options.SectionId)
+	if err != nil {
+		logger.Error("failed to get section: ", err)
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		logger.Info("section not found")
+		return nil, ErrAddLessonSectionNotFound
+	}
+
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: section.CourseId})
+	if err != nil || course == nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrAddLessonNotCourseTeacher
+	}
+
+	lesson, err := a.storages.CurriculumStorage.CreateLesson(ctx, &entity.Lesson{
+		CourseId:        course.Id,
+		SectionId:       section.Id,
+		Title:           options.Title,
+		Position:        options.Position,
+		DurationSeconds: options.DurationSeconds,
+	})
+	if err != nil {
+		logger.Error("failed to create lesson: ", err)
+		return nil, fmt.Errorf("failed to create lesson: %w", err)
+	}
+
+	logger.Info("successfully added lesson", "lessonId", lesson.Id)
+	return lesson, nil
+}
+
+func (a *courseService) GetCurriculum(ctx context.Context, courseId string) (*CurriculumOutput, error) {
+	course, err := a.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: courseId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, ErrGetCurriculumCourseNotFound
+	}
+
+	sections, err := a.storages.CurriculumStorage.GetSections(ctx, courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	lessons, err := a.storages.CurriculumStorage.GetLessons(ctx, courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lessons: %w", err)
+	}
+	quizzes, err := a.storages.QuizStorage.GetQuizzes(ctx, courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quizzes: %w", err)
+	}
+	subtitles, err := a.storages.SubtitleStorage.GetSubtitles(ctx, courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtitles: %w", err)
+	}
+	byLesson := make(map[string][]*entity.LessonSubtitle, len(lessons))
+	for _, subtitle := range subtitles {
+		byLesson[subtitle.LessonId] = append(byLesson[subtitle.LessonId], subtitle)
+	}
+
+	output := &CurriculumOutput{Sections: make([]*CurriculumSection, 0, len(sections))}
+	bySection := make(map[string]*CurriculumSection, len(sections))
+	for _, section := range sections {
+		curriculumSection := &CurriculumSection{Section: section, Lessons: []*CurriculumLesson{}, Quizzes: []*entity.Quiz{}}
+		output.Sections = append(output.Sections, curriculumSection)
+		bySection[section.Id] = curriculumSection
+	}
+	// lessons and quizzes are ordered already, so appending keeps the order within section
+	for _, lesson := range lessons {
+		if curriculumSection, ok := bySection[lesson.SectionId]; ok {
+			lessonSubtitles := byLesson[lesson.Id]
+			if lessonSubtitles == nil {
+				lessonSubtitles = []*entity.LessonSubtitle{}
+			}
+			curriculumSection.Lessons = append(curriculumSection.Lessons, &CurriculumLesson{Lesson: lesson, Subtitles: lessonSubtitles})
+		}
+	}
+	for _, quiz := range quizzes {
+		if curriculumSection, ok := bySection[quiz.SectionId]; ok {
+			curriculumSection.Quizzes = append(curriculumSection.Quizzes, quiz)
+		}
+	}
+
+	return output, nil
+}
+
+func (a *AddSectionOptions) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return errs.New("Title is required.", "invalid_title")
+	}
+	return nil
+}
+
+func (a *AddLessonOptions) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return errs.New("Title is required.", "invalid_title")
+	}
+	if a.DurationSeconds <= 0 {
+		return errs.New("Duration must be positive.", "invalid_duration")
+	}
+	return nil
+}
+
+func (s *SetCourseTranslationOptions) Validate() error {
+	if s.Language == "" || !isLanguageTag(s.Language) {
+		return errs.New("Language must be language tag, e.g. en or pt-BR.", "invalid_language")
+	}
+	name := strings.TrimSpace(s.Name)
+	if name == "" || utf8.RuneCountInString(name) > _maxCourseNameLength {
+		return errs.New(fmt.Sprintf("Name is required and can't be longer than %d characters.", _maxCourseNameLength), "invalid_name")
+	}
+	if utf8.RuneCountInString(s.Description) > _maxCourseDescriptionLength {
+		return errs.New(fmt.Sprintf("Description can't be longer than %d characters.", _maxCourseDescriptionLength), "invalid_description")
+	}
+	return nil
+}
@@ -0,0 +1,559 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// _maxThreadTitleLength - limit of thread title in characters.
+	_maxThreadTitleLength = 200
+	// _maxPostLength - limit of thread body and replies in characters.
+	_maxPostLength = 10000
+	// _maxReportReasonLength - limit of report reason in characters.
+	_maxReportReasonLength = 1000
+	// _defaultPageSize - page size of threads and posts when limit isn't requested.
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+)
+
+type discussionService struct {
+	serviceContext
+}
+
+var _ DiscussionService = (*discussionService)(nil)
+
+func NewDiscussionService(options *Options) DiscussionService {
+	return &discussionService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("DiscussionService"),
+		},
+	}
+}
+
+func (d *discussionService) CreateThread(ctx context.Context, options *CreateThreadOptions) (*entity.Thread, error) {
+	logger := d.logger.
+		Named("CreateThread").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId, "lessonId", options.LessonId)
+
+	course, err := d.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrCreateThreadCourseNotFound
+	}
+
+	err = d.authorize(ctx, options.UserId, course)
+	if err != nil {
+		logger.Info("user can't access discussions: ", err)
+		return nil, err
+	}
+
+	var lessonId *string
+	if options.LessonId != "" {
+		lesson, err := d.storages.CurriculumStorage.GetLesson(ctx, options.LessonId)
+		if err != nil {
+			logger.Error("failed to get lesson: ", err)
+			return nil, fmt.Errorf("failed to get lesson: %w", err)
+		}
+		if lesson == nil || lesson.CourseId != course.Id {
+			logger.Info("lesson not found")
+			return nil, ErrCreateThreadLessonNotFound
+		}
+		lessonId = &lesson.Id
+	}
+
+	now := time.Now()
+	thread, err := d.storages.DiscussionStorage.CreateThread(ctx, &entity.Thread{
+		CourseId:       course.Id,
+		LessonId:       lessonId,
+		UserId:         options.UserId,
+		Title:          strings.TrimSpace(options.Title),
+		Body:           strings.TrimSpace(options.Body),
+		CreatedAt:      now,
+		LastActivityAt: now,
+	})
+	if err != nil {
+		logger.Error("failed to create thread: ", err)
+		return nil, fmt.Errorf("failed to create thread: %w", err)
+	}
+
+	logger.Info("successfully created thread", "threadId", thread.Id)
+	return thread, nil
+}
+
+func (d *discussionService) GetThreads(ctx context.Context, options *GetThreadsOptions) (*GetThreadsOutput, error) {
+	logger := d.logger.
+		Named("GetThreads").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := d.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrGetThreadsCourseNotFound
+	}
+
+	err = d.authorize(ctx, options.UserId, course)
+	if err != nil {
+		logger.Info("user can't access discussions: ", err)
+		return nil, err
+	}
+
+	filter := &storage.GetThreadsFilter{
+		CourseId: course.Id,
+		LessonId: options.LessonId,
+		Limit:    pageSize(options.Limit) + 1,
+	}
+	if options.Cursor != "" {
+		filter.AfterActivityAt, filter.AfterId, err = decodeCursor(options.Cursor)
+		if err != nil {
+			logger.Info("invalid cursor: ", err)
+			return nil, ErrDiscussionInvalidCursor
+		}
+	}
+
+	threads, err := d.storages.DiscussionStorage.GetThreads(ctx, filter)
+	if err != nil {
+		logger.Error("failed to get threads: ", err)
+		return nil, fmt.Errorf("failed to get threads: %w", err)
+	}
+
+	// one extra thread is requested to find out whether there is next page
+	output := &GetThreadsOutput{Threads: threads}
+	if len(threads) == filter.Limit {
+		output.Threads = threads[:len(threads)-1]
+		last := output.Threads[len(output.Threads)-1]
+		output.NextCursor = encodeCursor(last.LastActivityAt, last.Id)
+	}
+
+	return output, nil
+}
+
+func (d *discussionService) GetThread(ctx context.Context, options *GetThreadOptions) (*entity.Thread, error) {
+	logger := d.logger.
+		Named("GetThread").
+		WithContext(ctx).
+		With("userId", options.UserId, "threadId", options.ThreadId)
+
+	thread, _, err := d.getThread(ctx, options.UserId, options.ThreadId)
+	if err != nil {
+		logger.Info("failed to get thread: ", err)
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+func (d *discussionService) GetPosts(ctx context.Context, options *GetPostsOptions) (*GetPostsOutput, error) {
+	logger := d.logger.
+		Named("GetPosts").
+		WithContext(ctx).
+		With("userId", options.UserId, "threadId", options.ThreadId)
+
+	thread, _, err := d.getThread(ctx, options.UserId, options.ThreadId)
+	if err != nil {
+		logger.Info("failed to get thread: ", err)
+		return nil, err
+	}
+
+	filter := &storage.GetPostsFilter{
+		ThreadId: thread.Id,
+		Limit:    pageSize(options.Limit) + 1,
+	}
+	if options.Cursor != "" {
+		filter.AfterCreatedAt, filter.AfterId, err = decodeCursor(options.Cursor)
+		if err != nil {
+			logger.Info("invalid cursor: ", err)
+			return nil, ErrDiscussionInvalidCursor
+		}
+	}
+
+	posts, err := d.storages.DiscussionStorage.GetPosts(ctx, filter)
+	if err != nil {
+		logger.Error("failed to get posts: ", err)
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+
+	output := &GetPostsOutput{Posts: posts}
+	if len(posts) == filter.Limit {
+		output.Posts = posts[:len(posts)-1]
+		last := output.Posts[len(output.Posts)-1]
+		output.NextCursor = encodeCursor(last.CreatedAt, last.Id)
+	}
+
+	return output, nil
+}
+
+func (d *discussionService) CreatePost(ctx context.Context, options *CreatePostOptions) (*entity.Post, error) {
+	logger := d.logger.
+		Named("CreatePost").
+		WithContext(ctx).
+		With("userId", options.UserId, "threadId", options.ThreadId)
+
+	thread, _, err := d.getThread(ctx, options.UserId, options.ThreadId)
+	if err != nil {
+		logger.Info("failed to get thread: ", err)
+		return nil, err
+	}
+
+	if options.ParentId != nil {
+		parent, err := d.storages.DiscussionStorage.GetPost(ctx, *options.ParentId)
+		if err != nil {
+			logger.Error("failed to get parent post: ", err)
+			return nil, fmt.Errorf("failed to get parent post: %w", err)
+		}
+		if parent == nil || parent.Hidden || parent.ThreadId != thread.Id {
+			logger.Info("parent post not found", "parentId", *options.ParentId)
+			return nil, ErrPostNotFound
+		}
+	}
+
+	post, err := d.storages.DiscussionStorage.CreatePost(ctx, &entity.Post{
+		ThreadId:  thread.Id,
+		ParentId:  options.ParentId,
+		UserId:    options.UserId,
+		Body:      strings.TrimSpace(options.Body),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger.Error("failed to create post: ", err)
+		return nil, fmt.Errorf("failed to create post: %w", err)
+	}
+
+	logger.Info("successfully created post", "postId", post.Id)
+	return post, nil
+}
+
+func (d *discussionService) AcceptPost(ctx context.Context, options *AcceptPostOptions) (*entity.Thread, error) {
+	logger := d.logger.
+		Named("AcceptPost").
+		WithContext(ctx).
+		With("userId", options.UserId, "threadId", options.ThreadId)
+
+	thread, course, err := d.getThread(ctx, options.UserId, options.ThreadId)
+	if err != nil {
+		logger.Info("failed to get thread: ", err)
+		return nil, err
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrAcceptPostNotCourseTeacher
+	}
+
+	if options.PostId != nil {
+		post, err := d.storages.DiscussionStorage.GetPost(ctx, *options.PostId)
+		if err != nil {
+			logger.Error("failed to get post: ", err)
+			return nil, fmt.Errorf("failed to get post: %w", err)
+		}
+		if post == nil || post.Hidden || post.ThreadId != thread.Id {
+			logger.Info("post not found", "postId", *options.PostId)
+			return nil, ErrPostNotFound
+		}
+	}
+
+	thread, err = d.storages.DiscussionStorage.AcceptPost(ctx, thread.Id, options.PostId)
+	if err != nil {
+		logger.Error("failed to accept post: ", err)
+		return nil, fmt.Errorf("failed to accept post: %w", err)
+	}
+
+	logger.Info("successfully accepted post", "postId", options.PostId)
+	return thread, nil
+}
+
+func (d *discussionService) Upvote(ctx context.Context, options *UpvoteOptions) error {
+	logger := d.logger.
+		Named("Upvote").
+		WithContext(ctx).
+		With("userId", options.UserId, "targetType", options.TargetType, "targetId", options.TargetId, "up", options.Up)
+
+	authorId, _, err := d.getTarget(ctx, options.UserId, options.TargetType, options.TargetId)
+	if err != nil {
+		logger.Info("failed to get target: ", err)
+		return err
+	}
+	if authorId == options.UserId {
+		logger.Info("user upvotes own content")
+		return ErrUpvoteOwnContent
+	}
+
+	changed, err := d.storages.DiscussionStorage.SetVote(ctx, options.UserId, options.TargetType, options.TargetId, options.Up)
+	if err != nil {
+		logger.Error("failed to set vote: ", err)
+		return fmt.Errorf("failed to set vote: %w", err)
+	}
+
+	logger.Info("successfully set vote", "changed", changed)
+	return nil
+}
+
+func (d *discussionService) Report(ctx context.Context, options *ReportOptions) (*entity.DiscussionReport, error) {
+	logger := d.logger.
+		Named("Report").
+		WithContext(ctx).
+		With("userId", options.UserId, "targetType", options.TargetType, "targetId", options.TargetId)
+
+	_, courseId, err := d.getTarget(ctx, options.UserId, options.TargetType, options.TargetId)
+	if err != nil {
+		logger.Info("failed to get target: ", err)
+		return nil, err
+	}
+
+	report, err := d.storages.DiscussionStorage.CreateReport(ctx, &entity.DiscussionReport{
+		TargetType: options.TargetType,
+		TargetId:   options.TargetId,
+		CourseId:   courseId,
+		ReporterId: options.UserId,
+		Reason:     strings.TrimSpace(options.Reason),
+		Status:     entity.ReportOpen,
+	})
+	if err != nil {
+		logger.Error("failed to create report: ", err)
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+	if report == nil {
+		logger.Info("content is reported already")
+		return nil, ErrReportAlreadyReported
+	}
+
+	logger.Info("successfully reported content", "reportId", report.Id)
+	return report, nil
+}
+
+func (d *discussionService) GetModerationQueue(ctx context.Context, userId string) ([]*entity.ModerationQueueItem, error) {
+	logger := d.logger.
+		Named("GetModerationQueue").
+		WithContext(ctx).
+		With("userId", userId)
+
+	user, err := d.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return nil, ErrGetModerationQueueNotAdmin
+	}
+
+	queue, err := d.storages.DiscussionStorage.GetModerationQueue(ctx)
+	if err != nil {
+		logger.Error("failed to get moderation queue: ", err)
+		return nil, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+
+	return queue, nil
+}
+
+func (d *discussionService) ResolveReport(ctx context.Context, options *ResolveReportOptions) error {
+	logger := d.logger.
+		Named("ResolveReport").
+		WithContext(ctx).
+		With("userId", options.UserId, "reportId", options.ReportId, "hide", options.Hide)
+
+	user, err := d.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return ErrResolveReportNotAdmin
+	}
+
+	report, err := d.storages.DiscussionStorage.GetReport(ctx, options.ReportId)
+	if err != nil {
+		logger.Error("failed to get report: ", err)
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+	if report == nil {
+		logger.Info("report not found")
+		return ErrResolveReportReportNotFound
+	}
+	if report.Status != entity.ReportOpen {
+		logger.Info("report is resolved already", "status", report.Status)
+		return ErrResolveReportAlreadyResolved
+	}
+
+	// all open reports of the same content are resolved together
+	status := entity.ReportDismissed
+	if options.Hide {
+		status = entity.ReportHidden
+	}
+	err = d.storages.DiscussionStorage.ResolveReports(ctx, report.TargetType, report.TargetId, status, user.Id)
+	if err != nil {
+		logger.Error("failed to resolve reports: ", err)
+		return fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	logger.Info("successfully resolved reports", "status", status)
+	return nil
+}
+
+// authorize allows discussions of course to enrolled users, subscribers and its teacher.
+func (d *discussionService) authorize(ctx context.Context, userId string, course *entity.Course) error {
+	if course.TeacherId == userId {
+		return nil
+	}
+
+	enrolled, err := hasCourseAccess(ctx, d.storages, userId, course.Id)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrDiscussionNotAllowed
+	}
+
+	return nil
+}
+
+// getThread returns visible thread with its course when user can access discussions of the course.
+func (d *discussionService) getThread(ctx context.Context, userId, threadId string) (*entity.Thread, *entity.Course, error) {
+	thread, err := d.storages.DiscussionStorage.GetThread(ctx, threadId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+	if thread == nil || thread.Hidden {
+		return nil, nil, ErrThreadNotFound
+	}
+
+	course, err := d.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: thread.CourseId})
+	if err != nil || course == nil {
+		return nil, nil, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	err = d.authorize(ctx, userId, course)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return thread, course, nil
+}
+
+// getTarget returns author and course of visible thread or post user can access.
+func (d *discussionService) getTarget(ctx context.Context, userId, targetType, targetId string) (string, string, error) {
+	if targetType == entity.DiscussionThread {
+		thread, _, err := d.getThread(ctx, userId, targetId)
+		if err != nil {
+			return "", "", err
+		}
+		return thread.UserId, thread.CourseId, nil
+	}
+
+	post, err := d.storages.DiscussionStorage.GetPost(ctx, targetId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get post: %w", err)
+	}
+	if post == nil || post.Hidden {
+		return "", "", ErrPostNotFound
+	}
+
+	thread, _, err := d.getThread(ctx, userId, post.ThreadId)
+	if err != nil {
+		return "", "", err
+	}
+	return post.UserId, thread.CourseId, nil
+}
+
+// pageSize returns requested page size within limits.
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return _defaultPageSize
+	}
+	if limit > _maxPageSize {
+		return _maxPageSize
+	}
+	return limit
+}
+
+// encodeCursor returns opaque cursor pointing after row with given sort time and id.
+func encodeCursor(at time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(at.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	at, id, found := strings.Cut(string(decoded), "|")
+	if !found {
+		return time.Time{}, "", fmt.Errorf("malformed cursor")
+	}
+	parsedAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", err
+	}
+
+	return parsedAt, id, nil
+}
+
+func (c *CreateThreadOptions) Validate() error {
+	if c.LessonId != "" {
+		if _, err := uuid.Parse(c.LessonId); err != nil {
+			return errs.New("Lesson id is invalid.", "invalid_lesson_id")
+		}
+	}
+	title := strings.TrimSpace(c.Title)
+	if title == "" || utf8.RuneCountInString(title) > _maxThreadTitleLength {
+		return errs.New(fmt.Sprintf("Title is required and can't be longer than %d characters.", _maxThreadTitleLength), "invalid_thread_title")
+	}
+	return validatePostBody(c.Body)
+}
+
+func (c *CreatePostOptions) Validate() error {
+	if c.ParentId != nil {
+		if _, err := uuid.Parse(*c.ParentId); err != nil {
+			return errs.New("Parent post id is invalid.", "invalid_parent_id")
+		}
+	}
+	return validatePostBody(c.Body)
+}
+
+func (a *AcceptPostOptions) Validate() error {
+	if a.PostId != nil {
+		if _, err := uuid.Parse(*a.PostId); err != nil {
+			return errs.New("Post id is invalid.", "invalid_post_id")
+		}
+	}
+	return nil
+}
+
+func (r *ReportOptions) Validate() error {
+	if utf8.RuneCountInString(strings.TrimSpace(r.Reason)) > _maxReportReasonLength {
+		return errs.New(fmt.Sprintf("Reason can't be longer than %d characters.", _maxReportReasonLength), "invalid_report_reason")
+	}
+	return nil
+}
+
+func validatePostBody(body string) error {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > _maxPostLength {
+		return errs.New(fmt.Sprintf("Text is required and can't be longer than %d characters.", _maxPostLength), "invalid_body")
+	}
+	return nil
+}
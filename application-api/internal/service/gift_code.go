@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"strings"
+)
+
+const _maxGiftCodesBatch = 1000
+
+type giftCodeService struct {
+	serviceContext
+}
+
+var _ GiftCodeService = (*giftCodeService)(nil)
+
+func NewGiftCodeService(options *Options) GiftCodeService {
+	return &giftCodeService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("GiftCodeService"),
+		},
+	}
+}
+
+func (g *giftCodeService) GenerateGiftCodes(ctx context.Context, options *GenerateGiftCodesOptions) ([]*entity.GiftCode, error) {
+	logger := g.logger.
+		Named("GenerateGiftCodes").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId, "count", options.Count)
+
+	course, err := g.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrGenerateGiftCodesCourseNotFound
+	}
+
+	allowed, err := g.canManageGiftCodes(ctx, options.UserId, course)
+	if err != nil {
+		logger.Error("failed to check permissions: ", err)
+		return nil, err
+	}
+	if !allowed {
+		logger.Info("user is not course teacher or admin")
+		return nil, ErrGenerateGiftCodesNotAllowed
+	}
+
+	batchId := uuid.NewString()
+	codes := make([]*entity.GiftCode, 0, options.Count)
+	for i := 0; i < options.Count; i++ {
+		code, err := generateGiftCode()
+		if err != nil {
+			logger.Error("failed to generate gift code: ", err)
+			return nil, fmt.Errorf("failed to generate gift code: %w", err)
+		}
+		codes = append(codes, &entity.GiftCode{
+			Code:      code,
+			CourseId:  course.Id,
+			BatchId:   &batchId,
+			CreatedBy: options.UserId,
+		})
+	}
+
+	codes, err = g.storages.GiftCodeStorage.CreateGiftCodes(ctx, codes)
+	if err != nil {
+		logger.Error("failed to create gift codes: ", err)
+		return nil, fmt.Errorf("failed to create gift codes: %w", err)
+	}
+
+	logger.Info("successfully generated gift codes", "batchId", batchId)
+	return codes, nil
+}
+
+func (g *giftCodeService) GetCourseGiftCodes(ctx context.Context, options *GetCourseGiftCodesOptions) ([]*entity.GiftCode, error) {
+	logger := g.logger.
+		Named("GetCourseGiftCodes").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId, "batchId", options.BatchId)
+
+	course, err := g.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrGetCourseGiftCodesCourseNotFound
+	}
+
+	allowed, err := g.canManageGiftCodes(ctx, options.UserId, course)
+	if err != nil {
+		logger.Error("failed to check permissions: ", err)
+		return nil, err
+	}
+	if !allowed {
+		logger.Info("user is not course teacher or admin")
+		return nil, ErrGetCourseGiftCodesNotAllowed
+	}
+
+	codes, err := g.storages.GiftCodeStorage.GetGiftCodes(ctx, &storage.GetGiftCodesFilter{
+		CourseId: course.Id,
+		BatchId:  options.BatchId,
+	})
+	if err != nil {
+		logger.Error("failed to get gift codes: ", err)
+		return nil, fmt.Errorf("failed to get gift codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+func (g *giftCodeService) GetMyGifts(ctx context.Context, userId string) ([]*entity.GiftCode, error) {
+	codes, err := g.storages.GiftCodeStorage.GetGiftCodes(ctx, &storage.GetGiftCodesFilter{CreatedBy: userId, Gifts: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gift codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+func (g *giftCodeService) RedeemGiftCode(ctx context.Context, options *RedeemGiftCodeOptions) (*entity.Enrollment, error) {
+	logger := g.logger.
+		Named("RedeemGiftCode").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	code, err := g.storages.GiftCodeStorage.GetGiftCode(ctx, normalizeGiftCode(options.Code))
+	if err != nil {
+		logger.Error("failed to get gift code: ", err)
+		return nil, fmt.Errorf("failed to get gift code: %w", err)
+	}
+	if code == nil {
+		logger.Info("gift code not found")
+		return nil, ErrRedeemGiftCodeNotFound
+	}
+	logger = logger.With("codeId", code.Id, "courseId", code.CourseId)
+	if code.RedeemedBy != nil {
+		logger.Info("gift code is redeemed already")
+		return nil, ErrRedeemGiftCodeRedeemed
+	}
+	if code.OrderId != nil {
+		order, err := g.storages.OrderStorage.GetOrder(ctx, *code.OrderId)
+		if err != nil {
+			logger.Error("failed to get order: ", err)
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		if order == nil || order.Status != entity.OrderPaid {
+			logger.Info("gift order isn't paid")
+			return nil, ErrRedeemGiftCodeNotFound
+		}
+	}
+
+	// code isn't spent on course user has already, so it can be passed to someone else
+	enrollment, err := g.storages.EnrollmentStorage.GetEnrollment(ctx, options.UserId, code.CourseId)
+	if err != nil {
+		logger.Error("failed to get enrollment: ", err)
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	if enrollment != nil {
+		logger.Info("course is owned already")
+		return nil, ErrRedeemGiftCodeAlreadyOwned
+	}
+
+	enrollment, err = g.storages.GiftCodeStorage.RedeemGiftCode(ctx, code.Id, options.UserId)
+	if err != nil {
+		logger.Error("failed to redeem gift code: ", err)
+		return nil, fmt.Errorf("failed to redeem gift code: %w", err)
+	}
+	if enrollment == nil {
+		// concurrent redemption won
+		logger.Info("gift code can't be redeemed")
+		return nil, ErrRedeemGiftCodeRedeemed
+	}
+
+	logger.Info("successfully redeemed gift code")
+	return enrollment, nil
+}
+
+// canManageGiftCodes allows gift codes of course to its teacher and admins.
+func (g *giftCodeService) canManageGiftCodes(ctx context.Context, userId string, course *entity.Course) (bool, error) {
+	if course.TeacherId == userId {
+		return true, nil
+	}
+
+	user, err := g.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user.Type == entity.Admin, nil
+}
+
+// generateGiftCode returns 16 random base32 characters in groups of four, e.g. ABCD-EFGH-IJKL-MNOP.
+func generateGiftCode() (string, error) {
+	raw := make([]byte, 10)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+
+	code := base32.StdEncoding.EncodeToString(raw)
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+// normalizeGiftCode brings code typed by user to the stored form.
+func normalizeGiftCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 16 {
+		return code
+	}
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+}
+
+func (g *GenerateGiftCodesOptions) Validate() error {
+	if g.Count < 1 || g.Count > _maxGiftCodesBatch {
+		return errs.New(fmt.Sprintf("Count must be from 1 to %d.", _maxGiftCodesBatch), "invalid_count")
+	}
+	return nil
+}
+
+func (r *RedeemGiftCodeOptions) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return errs.New("Code is required.", "code_required")
+	}
+	return nil
+}
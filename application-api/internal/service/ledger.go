@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"strings"
+)
+
+type ledgerService struct {
+	serviceContext
+}
+
+var _ LedgerService = (*ledgerService)(nil)
+
+func NewLedgerService(options *Options) LedgerService {
+	return &ledgerService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("LedgerService"),
+		},
+	}
+}
+
+func (l *ledgerService) GetEarnings(ctx context.Context, userId string) (*entity.Earnings, error) {
+	logger := l.logger.
+		Named("GetEarnings").
+		WithContext(ctx).
+		With("userId", userId)
+
+	user, err := l.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Teacher {
+		logger.Info("user is not teacher", "type", user.Type)
+		return nil, ErrGetEarningsNotTeacher
+	}
+
+	earnings, err := l.storages.LedgerStorage.GetEarnings(ctx, userId)
+	if err != nil {
+		logger.Error("failed to get earnings: ", err)
+		return nil, fmt.Errorf("failed to get earnings: %w", err)
+	}
+	earnings.Currency = l.config.Payment.Currency
+
+	return earnings, nil
+}
+
+func (l *ledgerService) RequestPayout(ctx context.Context, options *RequestPayoutOptions) (*entity.Payout, error) {
+	logger := l.logger.
+		Named("RequestPayout").
+		WithContext(ctx).
+		With("userId", options.UserId, "amount", options.Amount)
+
+	user, err := l.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Teacher {
+		logger.Info("user is not teacher", "type", user.Type)
+		return nil, ErrRequestPayoutNotTeacher
+	}
+
+	payout, err := l.storages.LedgerStorage.CreatePayout(ctx, &entity.Payout{
+		TeacherId: user.Id,
+		Amount:    options.Amount,
+		Currency:  l.config.Payment.Currency,
+		Status:    entity.PayoutRequested,
+	})
+	if err != nil {
+		logger.Error("failed to create payout: ", err)
+		return nil, fmt.Errorf("failed to create payout: %w", err)
+	}
+	if payout == nil {
+		logger.Info("amount exceeds available balance")
+		return nil, ErrRequestPayoutInsufficientBalance
+	}
+
+	logger.Info("successfully requested payout", "payoutId", payout.Id)
+	return payout, nil
+}
+
+func (l *ledgerService) GetMyPayouts(ctx context.Context, userId string) ([]*entity.Payout, error) {
+	payouts, err := l.storages.LedgerStorage.GetPayouts(ctx, &storage.GetPayoutsFilter{TeacherId: userId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payouts: %w", err)
+	}
+
+	return payouts, nil
+}
+
+func (l *ledgerService) GetPayouts(ctx context.Context, options *GetPayoutsOptions) ([]*entity.Payout, error) {
+	logger := l.logger.
+		Named("GetPayouts").
+		WithContext(ctx).
+		With("userId", options.UserId, "status", options.Status)
+
+	user, err := l.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return nil, ErrGetPayoutsNotAdmin
+	}
+
+	payouts, err := l.storages.LedgerStorage.GetPayouts(ctx, &storage.GetPayoutsFilter{Status: options.Status})
+	if err != nil {
+		logger.Error("failed to get payouts: ", err)
+		return nil, fmt.Errorf("failed to get payouts: %w", err)
+	}
+
+	return payouts, nil
+}
+
+func (l *ledgerService) ResolvePayout(ctx context.Context, options *ResolvePayoutOptions) (*entity.Payout, error) {
+	logger := l.logger.
+		Named("ResolvePayout").
+		WithContext(ctx).
+		With("userId", options.UserId, "payoutId", options.PayoutId, "approve", options.Approve)
+
+	user, err := l.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return nil, ErrResolvePayoutNotAdmin
+	}
+
+	payout, err := l.storages.LedgerStorage.GetPayout(ctx, options.PayoutId)
+	if err != nil {
+		logger.Error("failed to get payout: ", err)
+		return nil, fmt.Errorf("failed to get payout: %w", err)
+	}
+	if payout == nil {
+		logger.Info("payout not found")
+		return nil, ErrResolvePayoutPayoutNotFound
+	}
+
+	if options.Approve {
+		payout, err = l.storages.LedgerStorage.ApprovePayout(ctx, payout.Id, user.Id, payoutEntries(payout))
+	} else {
+		payout, err = l.storages.LedgerStorage.RejectPayout(ctx, payout.Id, user.Id, strings.TrimSpace(options.Note))
+	}
+	if err != nil {
+		logger.Error("failed to resolve payout: ", err)
+		return nil, fmt.Errorf("failed to resolve payout: %w", err)
+	}
+	if payout == nil {
+		logger.Info("payout is resolved already")
+		return nil, ErrResolvePayoutAlreadyResolved
+	}
+
+	logger.Info("successfully resolved payout", "status", payout.Status)
+	return payout, nil
+}
+
+// saleEntries returns transactions of course sale: teacher is credited with price and platform
+// commission is moved from teacher to platform revenue.
+func saleEntries(item *entity.OrderItem, currency string, commissionPercent int) []*entity.LedgerEntry {
+	fee := commission(item.Price, commissionPercent)
+	teacherAccount := entity.TeacherAccount(item.TeacherId)
+
+	sale := uuid.NewString()
+	entries := []*entity.LedgerEntry{
+		ledgerEntry(sale, entity.AccountPlatformCash, entity.LedgerSale, item.Price, currency, item),
+		ledgerEntry(sale, teacherAccount, entity.LedgerSale, -item.Price, currency, item),
+	}
+	if fee == 0 {
+		return entries
+	}
+
+	feeTransaction := uuid.NewString()
+	return append(entries,
+		ledgerEntry(feeTransaction, teacherAccount, entity.LedgerFee, fee, currency, item),
+		ledgerEntry(feeTransaction, entity.AccountPlatformRevenue, entity.LedgerFee, -fee, currency, item),
+	)
+}
+
+// payoutEntries returns transaction moving paid out amount from teacher to platform cash.
+func payoutEntries(payout *entity.Payout) []*entity.LedgerEntry {
+	transaction := uuid.NewString()
+	entries := []*entity.LedgerEntry{
+		{Account: entity.TeacherAccount(payout.TeacherId), Amount: payout.Amount},
+		{Account: entity.AccountPlatformCash, Amount: -payout.Amount},
+	}
+	for _, entry := range entries {
+		entry.TransactionId = transaction
+		entry.Kind = entity.LedgerPayout
+		entry.Currency = payout.Currency
+		entry.PayoutId = &payout.Id
+	}
+	return entries
+}
+
+func ledgerEntry(transaction, account, kind string, amount int64, currency string, item *entity.OrderItem) *entity.LedgerEntry {
+	return &entity.LedgerEntry{
+		TransactionId: transaction,
+		Account:       account,
+		Kind:          kind,
+		Amount:        amount,
+		Currency:      currency,
+		CourseId:      &item.CourseId,
+		OrderItemId:   &item.Id,
+	}
+}
+
+// commission returns platform share of amount rounded to the nearest minor unit.
+func commission(amount int64, percent int) int64 {
+	return (amount*int64(percent) + 50) / 100
+}
+
+func (r *RequestPayoutOptions) Validate() error {
+	if r.Amount <= 0 {
+		return errs.New("Amount must be positive.", "invalid_amount")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loginKey identifies what failed sign in attempts are counted against.
+type loginKey struct {
+	key         string
+	maxFailures int
+}
+
+func (a *authService) loginKeys(options *SignInOptions) []loginKey {
+	// keyed by email rather than user id, so unknown accounts are throttled the same way
+	keys := []loginKey{{
+		key:         "email:" + strings.ToLower(strings.TrimSpace(options.Email)),
+		maxFailures: a.config.Login.MaxAccountFailures,
+	}}
+	if options.IP != "" {
+		keys = append(keys, loginKey{
+			key:         "ip:" + options.IP,
+			maxFailures: a.config.Login.MaxIPFailures,
+		})
+	}
+
+	return keys
+}
+
+// checkLoginThrottle returns how long the client has to wait before next sign in attempt.
+func (a *authService) checkLoginThrottle(ctx context.Context, keys []loginKey) (time.Duration, error) {
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.key)
+	}
+
+	failures, err := a.storages.LoginStorage.GetLoginFailures(ctx, names)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get login failures: %w", err)
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, failure := range failures {
+		var allowedAt time.Time
+		if now.Sub(failure.LastFailureAt) <= a.config.Login.FailureWindow {
+			allowedAt = failure.LastFailureAt.Add(a.loginDelay(failure))
+		}
+		if failure.LockedUntil != nil && failure.LockedUntil.After(allowedAt) {
+			allowedAt = *failure.LockedUntil
+		}
+		if d := allowedAt.Sub(now); d > wait {
+			wait = d
+		}
+	}
+
+	return wait, nil
+}
+
+// registerLoginFailure counts failed attempt against every key and locks keys that reached their limit.
+func (a *authService) registerLoginFailure(ctx context.Context, keys []loginKey) error {
+	for _, key := range keys {
+		failure, err := a.storages.LoginStorage.RegisterLoginFailure(ctx, key.key, a.config.Login.FailureWindow)
+		if err != nil {
+			return fmt.Errorf("failed to register login failure: %w", err)
+		}
+
+		if failure.Failures >= key.maxFailures {
+			err = a.storages.LoginStorage.LockLogin(ctx, key.key, time.Now().Add(a.config.Login.LockoutDuration))
+			if err != nil {
+				return fmt.Errorf("failed to lock login: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loginDelay grows exponentially with number of failures, starting from BaseDelay up to MaxDelay.
+func (a *authService) loginDelay(failure *entity.LoginFailure) time.Duration {
+	if failure.Failures < 1 {
+		return 0
+	}
+
+	delay := a.config.Login.BaseDelay
+	for i := 1; i < failure.Failures && delay < a.config.Login.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > a.config.Login.MaxDelay {
+		delay = a.config.Login.MaxDelay
+	}
+
+	return delay
+}
+
+// tooManyAttempts returns throttling error with number of seconds to wait.
+func tooManyAttempts(wait time.Duration) error {
+	seconds := int(wait.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return ErrSignInTooManyAttempts.WithDetails(map[string]string{"retryAfter": strconv.Itoa(seconds)})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/oidc"
+	"strings"
+	"time"
+)
+
+func (a *authService) OIDCLogin(ctx context.Context) (*OIDCLoginOutput, error) {
+	logger := a.logger.
+		Named("OIDCLogin").
+		WithContext(ctx)
+
+	if a.oidc == nil {
+		logger.Info("social login is not configured")
+		return nil, ErrOIDCLoginNotConfigured
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		logger.Error("failed to generate state: ", err)
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		logger.Error("failed to generate nonce: ", err)
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	codeVerifier, err := oidc.RandomString()
+	if err != nil {
+		logger.Error("failed to generate code verifier: ", err)
+		return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	authorizationURL, err := a.oidc.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		logger.Error("failed to build authorization url: ", err)
+		return nil, fmt.Errorf("failed to build authorization url: %w", err)
+	}
+
+	err = a.storages.IdentityStorage.CreateOIDCState(ctx, &entity.OIDCState{
+		StateHash:    hashOneTimeToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(a.config.OIDC.StateTTL),
+	})
+	if err != nil {
+		logger.Error("failed to save state: ", err)
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	logger.Info("successfully started social login")
+	return &OIDCLoginOutput{AuthorizationURL: authorizationURL}, nil
+}
+
+func (a *authService) OIDCCallback(ctx context.Context, options *OIDCCallbackOptions) (*SignInOutput, error) {
+	logger := a.logger.
+		Named("OIDCCallback").
+		WithContext(ctx)
+
+	if a.oidc == nil {
+		logger.Info("social login is not configured")
+		return nil, ErrOIDCCallbackNotConfigured
+	}
+
+	if options.State == "" || options.Code == "" {
+		logger.Info("missing state or code")
+		return nil, ErrOIDCCallbackInvalidState
+	}
+
+	// state is single-use, replayed or forged callbacks are rejected here
+	state, err := a.storages.IdentityStorage.TakeOIDCState(ctx, hashOneTimeToken(options.State))
+	if err != nil {
+		logger.Error("failed to get state: ", err)
+		return nil, fmt.Errorf("failed to get state: %w", err)
+	}
+	if state == nil || time.Now().After(state.ExpiresAt) {
+		logger.Info("invalid state")
+		return nil, ErrOIDCCallbackInvalidState
+	}
+
+	// provider errors are caused by invalid codes as well, so they are reported as expected
+	rawIDToken, err := a.oidc.Exchange(ctx, options.Code, state.CodeVerifier)
+	if err != nil {
+		logger.Info("failed to exchange code: ", err)
+		return nil, ErrOIDCCallbackFailed
+	}
+	claims, err := a.oidc.VerifyIDToken(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		logger.Info("failed to verify id token: ", err)
+		return nil, ErrOIDCCallbackFailed
+	}
+	logger = logger.With("issuer", claims.Issuer, "subject", claims.Subject)
+
+	user, err := a.userByIdentity(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("successfully authenticated with identity provider", "user", user.Id)
+	return a.completeSignIn(ctx, user)
+}
+
+// userByIdentity returns user linked to external identity. Unknown identities are linked to user
+// with the same email, or new student is created, in both cases email must be verified by provider.
+func (a *authService) userByIdentity(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	logger := a.logger.
+		Named("userByIdentity").
+		WithContext(ctx).
+		With("issuer", claims.Issuer, "subject", claims.Subject)
+
+	identity, err := a.storages.IdentityStorage.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		logger.Error("failed to get identity: ", err)
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+	if identity != nil {
+		user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: identity.UserId})
+		if err != nil || user == nil {
+			logger.Error("failed to get user: ", err)
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return user, nil
+	}
+
+	// linking by unverified email would let anyone take over account registered with that email
+	if claims.Email == "" || !claims.EmailVerified {
+		logger.Info("email is not verified by provider")
+		return nil, ErrOIDCCallbackEmailNotVerified
+	}
+	logger = logger.With("email", claims.Email)
+
+	user, err := a.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{Email: claims.Email})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user == nil {
+		user, err = a.createIdentityUser(ctx, claims)
+		if err != nil {
+			logger.Error("failed to create user: ", err)
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+	} else if !user.EmailVerified {
+		user.EmailVerified = true
+		user, err = a.storages.UserStorage.UpdateUser(ctx, user)
+		if err != nil {
+			logger.Error("failed to update user: ", err)
+			return nil, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+
+	_, err = a.storages.IdentityStorage.CreateIdentity(ctx, &entity.UserIdentity{
+		UserId:  user.Id,
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	})
+	if err != nil {
+		logger.Error("failed to create identity: ", err)
+		return nil, fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	logger.Info("linked identity to user", "user", user.Id)
+	return user, nil
+}
+
+// createIdentityUser creates student with random password, it can be set later via ForgotPassword.
+func (a *authService) createIdentityUser(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	password, err := oidc.RandomString()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := a.hash.GenerateHash(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash user password: %w", err)
+	}
+
+	username := claims.Name
+	if username == "" {
+		username, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	return a.storages.UserStorage.CreateUser(ctx, &entity.User{
+		Email:         claims.Email,
+		Password:      hashedPassword,
+		Username:      username,
+		Type:          entity.Student,
+		EmailVerified: true,
+	})
+}
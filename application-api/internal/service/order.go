@@ -0,0 +1,548 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/payment"
+	"math"
+	"time"
+)
+
+type orderService struct {
+	serviceContext
+	payment payment.Provider
+}
+
+var _ OrderService = (*orderService)(nil)
+
+func NewOrderService(options *Options) OrderService {
+	return &orderService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("OrderService"),
+		},
+		payment: options.Payment,
+	}
+}
+
+func (o *orderService) PurchaseCourse(ctx context.Context, options *PurchaseCourseOptions) (*entity.Order, error) {
+	logger := o.logger.
+		Named("PurchaseCourse").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	verified, err := hasVerifiedEmail(ctx, o.storages, options.UserId)
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, err
+	}
+	if !verified {
+		logger.Info("user email is not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	course, err := o.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil || !course.Published {
+		logger.Info("course not found")
+		return nil, ErrPurchaseCourseCourseNotFound
+	}
+	price := minorUnits(course.Price)
+	if price <= 0 {
+		logger.Info("course is free")
+		return nil, ErrPurchaseCourseFree
+	}
+	if course.TeacherId == options.UserId {
+		logger.Info("user is teacher of the course")
+		return nil, ErrPurchaseCourseOwnCourse
+	}
+
+	enrollment, err := o.storages.EnrollmentStorage.GetEnrollment(ctx, options.UserId, course.Id)
+	if err != nil {
+		logger.Error("failed to get enrollment: ", err)
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	if enrollment != nil {
+		logger.Info("course is owned already")
+		return nil, ErrPurchaseCourseAlreadyOwned
+	}
+
+	order, err := o.storages.OrderStorage.CreateOrder(ctx, &entity.Order{
+		UserId:   options.UserId,
+		Status:   entity.OrderPending,
+		Total:    price,
+		Currency: o.config.Payment.Currency,
+		Items: []*entity.OrderItem{{
+			CourseId:  course.Id,
+			TeacherId: course.TeacherId,
+			Price:     price,
+		}},
+	})
+	if err != nil {
+		logger.Error("failed to create order: ", err)
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	if order == nil {
+		logger.Info("course is owned or being purchased already")
+		return nil, ErrPurchaseCourseInProgress
+	}
+	logger = logger.With("orderId", order.Id)
+
+	order, err = o.payOrder(ctx, order, options.PaymentToken)
+	if err != nil {
+		if errors.Is(err, ErrPurchaseCoursePaymentDeclined) {
+			logger.Info("payment is declined")
+			return nil, err
+		}
+		logger.Error("failed to pay order: ", err)
+		return nil, err
+	}
+
+	logger.Info("successfully purchased course")
+	return order, nil
+}
+
+func (o *orderService) Checkout(ctx context.Context, options *CheckoutOptions) (*entity.Order, error) {
+	logger := o.logger.
+		Named("Checkout").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	verified, err := hasVerifiedEmail(ctx, o.storages, options.UserId)
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, err
+	}
+	if !verified {
+		logger.Info("user email is not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	cartItems, err := o.storages.CartStorage.GetCartItems(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get cart items: ", err)
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+	if len(cartItems) == 0 {
+		logger.Info("cart is empty")
+		return nil, ErrCheckoutCartEmpty
+	}
+
+	courseIds := make([]string, 0, len(cartItems))
+	for _, item := range cartItems {
+		courseIds = append(courseIds, item.CourseId)
+	}
+	courses, err := o.storages.CourseStorage.GetCourses(ctx, courseIds)
+	if err != nil {
+		logger.Error("failed to get courses: ", err)
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+	coursesById := make(map[string]*entity.Course, len(courses))
+	for _, course := range courses {
+		coursesById[course.Id] = course
+	}
+
+	enrollments, err := o.storages.EnrollmentStorage.GetUserEnrollments(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get enrollments: ", err)
+		return nil, fmt.Errorf("failed to get enrollments: %w", err)
+	}
+	owned := make(map[string]bool, len(enrollments))
+	for _, enrollment := range enrollments {
+		owned[enrollment.CourseId] = true
+	}
+
+	// cart is revalidated as a whole, so user reviews every change before paying
+	var ownedIds, unavailableIds []string
+	repriced := false
+	order := &entity.Order{
+		UserId:   options.UserId,
+		Status:   entity.OrderPending,
+		Currency: o.config.Payment.Currency,
+	}
+	for _, item := range cartItems {
+		course := coursesById[item.CourseId]
+		if owned[item.CourseId] {
+			ownedIds = append(ownedIds, item.CourseId)
+			continue
+		}
+		if course == nil || !course.Published || course.TeacherId == options.UserId || minorUnits(course.Price) <= 0 {
+			unavailableIds = append(unavailableIds, item.CourseId)
+			continue
+		}
+
+		price := minorUnits(course.Price)
+		if price != item.Price {
+			err = o.storages.CartStorage.UpdateCartItemPrice(ctx, options.UserId, item.CourseId, price)
+			if err != nil {
+				logger.Error("failed to update cart item price: ", err)
+				return nil, fmt.Errorf("failed to update cart item price: %w", err)
+			}
+			repriced = true
+			continue
+		}
+
+		order.Total += price
+		order.Items = append(order.Items, &entity.OrderItem{
+			CourseId:  course.Id,
+			TeacherId: course.TeacherId,
+			Price:     price,
+		})
+	}
+
+	err = o.storages.CartStorage.RemoveCartItems(ctx, options.UserId, append(ownedIds, unavailableIds...))
+	if err != nil {
+		logger.Error("failed to remove cart items: ", err)
+		return nil, fmt.Errorf("failed to remove cart items: %w", err)
+	}
+	switch {
+	case len(ownedIds) > 0:
+		logger.Info("cart has owned courses", "courseIds", ownedIds)
+		return nil, ErrCheckoutAlreadyOwned
+	case len(unavailableIds) > 0:
+		logger.Info("cart has unavailable courses", "courseIds", unavailableIds)
+		return nil, ErrCheckoutCourseUnavailable
+	case repriced:
+		logger.Info("cart prices have changed")
+		return nil, ErrCheckoutPriceChanged
+	}
+
+	order, err = o.storages.OrderStorage.CreateOrder(ctx, order)
+	if err != nil {
+		logger.Error("failed to create order: ", err)
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	if order == nil {
+		logger.Info("course is owned or being purchased already")
+		return nil, ErrCheckoutInProgress
+	}
+	logger = logger.With("orderId", order.Id)
+
+	order, err = o.payOrder(ctx, order, options.PaymentToken)
+	if err != nil {
+		if errors.Is(err, ErrPurchaseCoursePaymentDeclined) {
+			logger.Info("payment is declined")
+			return nil, err
+		}
+		logger.Error("failed to pay order: ", err)
+		return nil, err
+	}
+
+	// order is paid already, so failing to clear cart is only logged
+	err = o.storages.CartStorage.RemoveCartItems(ctx, options.UserId, courseIds)
+	if err != nil {
+		logger.Error("failed to clear cart: ", err)
+	}
+
+	logger.Info("successfully checked out cart")
+	return order, nil
+}
+
+func (o *orderService) PurchaseBundle(ctx context.Context, options *PurchaseBundleOptions) (*entity.Order, error) {
+	logger := o.logger.
+		Named("PurchaseBundle").
+		WithContext(ctx).
+		With("userId", options.UserId, "bundleId", options.BundleId)
+
+	verified, err := hasVerifiedEmail(ctx, o.storages, options.UserId)
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, err
+	}
+	if !verified {
+		logger.Info("user email is not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	bundle, err := o.storages.BundleStorage.GetBundle(ctx, options.BundleId)
+	if err != nil {
+		logger.Error("failed to get bundle: ", err)
+		return nil, fmt.Errorf("failed to get bundle: %w", err)
+	}
+	if bundle == nil || !bundle.Published {
+		logger.Info("bundle not found")
+		return nil, ErrPurchaseBundleBundleNotFound
+	}
+	if bundle.TeacherId == options.UserId {
+		logger.Info("user is teacher of the bundle")
+		return nil, ErrPurchaseBundleOwnBundle
+	}
+
+	enrollments, err := o.storages.EnrollmentStorage.GetUserEnrollments(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get enrollments: ", err)
+		return nil, fmt.Errorf("failed to get enrollments: %w", err)
+	}
+	owned := make(map[string]bool, len(enrollments))
+	for _, enrollment := range enrollments {
+		owned[enrollment.CourseId] = true
+	}
+
+	// owned courses are skipped together with their share of bundle price
+	order := &entity.Order{
+		UserId:   options.UserId,
+		Status:   entity.OrderPending,
+		Currency: o.config.Payment.Currency,
+	}
+	for _, item := range bundleItems(bundle) {
+		if owned[item.CourseId] || item.Price <= 0 {
+			continue
+		}
+		order.Total += item.Price
+		order.Items = append(order.Items, item)
+	}
+	if len(order.Items) == 0 {
+		logger.Info("all courses of bundle are owned already")
+		return nil, ErrPurchaseBundleAlreadyOwned
+	}
+
+	order, err = o.storages.OrderStorage.CreateOrder(ctx, order)
+	if err != nil {
+		logger.Error("failed to create order: ", err)
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	if order == nil {
+		logger.Info("course is owned or being purchased already")
+		return nil, ErrPurchaseBundleInProgress
+	}
+	logger = logger.With("orderId", order.Id)
+
+	order, err = o.payOrder(ctx, order, options.PaymentToken)
+	if err != nil {
+		if errors.Is(err, ErrPurchaseCoursePaymentDeclined) {
+			logger.Info("payment is declined")
+			return nil, err
+		}
+		logger.Error("failed to pay order: ", err)
+		return nil, err
+	}
+
+	logger.Info("successfully purchased bundle")
+	return order, nil
+}
+
+func (o *orderService) PurchaseGift(ctx context.Context, options *PurchaseGiftOptions) (*PurchaseGiftOutput, error) {
+	logger := o.logger.
+		Named("PurchaseGift").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	verified, err := hasVerifiedEmail(ctx, o.storages, options.UserId)
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, err
+	}
+	if !verified {
+		logger.Info("user email is not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	course, err := o.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil || !course.Published {
+		logger.Info("course not found")
+		return nil, ErrPurchaseGiftCourseNotFound
+	}
+	price := minorUnits(course.Price)
+	if price <= 0 {
+		logger.Info("course is free")
+		return nil, ErrPurchaseGiftFree
+	}
+	if course.TeacherId == options.UserId {
+		logger.Info("user is teacher of the course")
+		return nil, ErrPurchaseGiftOwnCourse
+	}
+
+	order, err := o.storages.OrderStorage.CreateOrder(ctx, &entity.Order{
+		UserId:   options.UserId,
+		Status:   entity.OrderPending,
+		Total:    price,
+		Currency: o.config.Payment.Currency,
+		Gift:     true,
+		Items: []*entity.OrderItem{{
+			CourseId:  course.Id,
+			TeacherId: course.TeacherId,
+			Price:     price,
+		}},
+	})
+	if err != nil {
+		logger.Error("failed to create order: ", err)
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	logger = logger.With("orderId", order.Id)
+
+	// code is created with order, it can't be redeemed until order is paid
+	code, err := generateGiftCode()
+	if err != nil {
+		logger.Error("failed to generate gift code: ", err)
+		return nil, fmt.Errorf("failed to generate gift code: %w", err)
+	}
+	codes, err := o.storages.GiftCodeStorage.CreateGiftCodes(ctx, []*entity.GiftCode{{
+		Code:      code,
+		CourseId:  course.Id,
+		OrderId:   &order.Id,
+		CreatedBy: options.UserId,
+	}})
+	if err != nil {
+		logger.Error("failed to create gift code: ", err)
+		return nil, fmt.Errorf("failed to create gift code: %w", err)
+	}
+
+	order, err = o.payOrder(ctx, order, options.PaymentToken)
+	if err != nil {
+		if errors.Is(err, ErrPurchaseCoursePaymentDeclined) {
+			logger.Info("payment is declined")
+			return nil, ErrPurchaseGiftPaymentDeclined
+		}
+		logger.Error("failed to pay order: ", err)
+		return nil, err
+	}
+
+	logger.Info("successfully purchased gift")
+	return &PurchaseGiftOutput{Order: order, Code: codes[0]}, nil
+}
+
+func (o *orderService) GetMyOrders(ctx context.Context, userId string) ([]*entity.Order, error) {
+	orders, err := o.storages.OrderStorage.GetUserOrders(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+func (o *orderService) ReconcilePendingOrders(ctx context.Context) error {
+	logger := o.logger.
+		Named("ReconcilePendingOrders").
+		WithContext(ctx)
+
+	orders, err := o.storages.OrderStorage.GetPendingOrders(ctx, time.Now().Add(-o.config.Payment.PendingOrderTimeout))
+	if err != nil {
+		logger.Error("failed to get pending orders: ", err)
+		return fmt.Errorf("failed to get pending orders: %w", err)
+	}
+
+	fulfilled, failed := 0, 0
+	for _, order := range orders {
+		orderLogger := logger.With("orderId", order.Id)
+
+		charge, err := o.payment.GetCharge(ctx, order.Id)
+		if err != nil {
+			// provider is asked again on the next run
+			orderLogger.Error("failed to get charge: ", err)
+			continue
+		}
+		if charge == nil {
+			// purchase was interrupted before customer was charged, payment token isn't kept to retry it
+			err = o.storages.OrderStorage.FailOrder(ctx, order.Id)
+			if err != nil {
+				orderLogger.Error("failed to fail order: ", err)
+				continue
+			}
+			failed++
+			continue
+		}
+
+		_, err = o.fulfillOrder(ctx, order, charge)
+		if err != nil {
+			orderLogger.Error("failed to fulfill order: ", err)
+			continue
+		}
+		fulfilled++
+	}
+
+	if len(orders) > 0 {
+		logger.Info("successfully reconciled pending orders", "orders", len(orders), "fulfilled", fulfilled, "failed", failed)
+	}
+	return nil
+}
+
+// payOrder charges pending order and fulfills it. Order stays pending when provider fails without
+// answer or order can't be fulfilled after charge, ReconcilePendingOrders finishes it later by charge
+// made with order id, so customer who paid gets courses without being charged twice.
+func (o *orderService) payOrder(ctx context.Context, order *entity.Order, token string) (*entity.Order, error) {
+	charge, err := o.payment.Charge(ctx, &payment.ChargeRequest{
+		Reference: order.Id,
+		Token:     token,
+		Amount:    order.Total,
+		Currency:  order.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, payment.ErrDeclined) {
+			failErr := o.storages.OrderStorage.FailOrder(ctx, order.Id)
+			if failErr != nil {
+				return nil, fmt.Errorf("failed to fail order: %w", failErr)
+			}
+			return nil, ErrPurchaseCoursePaymentDeclined
+		}
+		return nil, fmt.Errorf("failed to charge order: %w", err)
+	}
+
+	paid, err := o.fulfillOrder(ctx, order, charge)
+	if err != nil {
+		return nil, err
+	}
+	if paid == nil {
+		return nil, fmt.Errorf("order %s isn't pending anymore", order.Id)
+	}
+
+	return paid, nil
+}
+
+// fulfillOrder marks charged order paid, nil is returned when order isn't pending anymore. Items whose
+// courses user got another way while order was pending are refunded, refund which fails stays pending
+// and RetryPendingRefunds completes it later.
+func (o *orderService) fulfillOrder(ctx context.Context, order *entity.Order, charge *payment.Charge) (*entity.Order, error) {
+	logger := o.logger.
+		Named("fulfillOrder").
+		WithContext(ctx).
+		With("orderId", order.Id)
+
+	var entries []*entity.LedgerEntry
+	for _, item := range order.Items {
+		entries = append(entries, saleEntries(item, order.Currency, o.config.Payment.CommissionPercent)...)
+	}
+
+	paid, duplicates, err := o.storages.OrderStorage.PayOrder(ctx, order.Id, charge.Id, entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pay order: %w", err)
+	}
+
+	for _, duplicate := range duplicates {
+		refundLogger := logger.With("refundId", duplicate.Id, "courseId", duplicate.CourseId)
+
+		_, err = completeRefund(ctx, o.storages, o.payment, duplicate, paid)
+		if err != nil {
+			refundLogger.Error("failed to refund owned course: ", err)
+			continue
+		}
+		refundLogger.Info("successfully refunded owned course")
+	}
+
+	return paid, nil
+}
+
+// hasVerifiedEmail reports whether user has verified email, payments and payouts are bound to
+// reachable users only.
+func hasVerifiedEmail(ctx context.Context, storages *storage.Storages, userId string) (bool, error) {
+	user, err := storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user != nil && user.EmailVerified, nil
+}
+
+// minorUnits converts course price to minor units of currency.
+func minorUnits(price float32) int64 {
+	return int64(math.Round(float64(price) * 100))
+}
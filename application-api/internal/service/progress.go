@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"math"
+	"time"
+)
+
+type progressService struct {
+	serviceContext
+	certificates CertificateService
+}
+
+var _ ProgressService = (*progressService)(nil)
+
+func NewProgressService(options *Options, certificates CertificateService) ProgressService {
+	return &progressService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("ProgressService"),
+		},
+		certificates: certificates,
+	}
+}
+
+func (p *progressService) RecordProgress(ctx context.Context, options *RecordProgressOptions) (*entity.LessonProgress, error) {
+	logger := p.logger.
+		Named("RecordProgress").
+		WithContext(ctx).
+		With("userId", options.UserId, "lessonId", options.LessonId)
+
+	lesson, err := p.storages.CurriculumStorage.GetLesson(ctx, options.LessonId)
+	if err != nil {
+		logger.Error("failed to get lesson: ", err)
+		return nil, fmt.Errorf("failed to get lesson: %w", err)
+	}
+	if lesson == nil {
+		logger.Info("lesson not found")
+		return nil, ErrRecordProgressLessonNotFound
+	}
+
+	enrolled, err := hasCourseAccess(ctx, p.storages, options.UserId, lesson.CourseId)
+	if err != nil {
+		logger.Error("failed to check course access: ", err)
+		return nil, err
+	}
+	if !enrolled {
+		logger.Info("user is not enrolled")
+		return nil, ErrRecordProgressNotEnrolled
+	}
+
+	// players may report slightly past the end, values are capped by lesson duration
+	watched, position := options.WatchedSeconds, options.Position
+	if watched > lesson.DurationSeconds {
+		watched = lesson.DurationSeconds
+	}
+	if position > lesson.DurationSeconds {
+		position = lesson.DurationSeconds
+	}
+	sentAt := time.Now()
+	if options.SentAt != nil && options.SentAt.Before(sentAt) {
+		sentAt = *options.SentAt
+	}
+
+	progress, justCompleted, err := p.storages.ProgressStorage.SaveLessonProgress(ctx, &entity.LessonProgress{
+		UserId:         options.UserId,
+		LessonId:       lesson.Id,
+		CourseId:       lesson.CourseId,
+		WatchedSeconds: watched,
+		LastPosition:   position,
+		PositionAt:     sentAt,
+	}, &storage.WatchLimits{
+		FirstHeartbeat:    p.config.Progress.FirstHeartbeat,
+		MaxPlaybackSpeed:  p.config.Progress.MaxPlaybackSpeed,
+		CompletionSeconds: int(math.Ceil(p.config.Progress.CompletionRatio * float64(lesson.DurationSeconds))),
+	})
+	if err != nil {
+		logger.Error("failed to save progress: ", err)
+		return nil, fmt.Errorf("failed to save progress: %w", err)
+	}
+
+	// course can be completed only by heartbeat completing a lesson, so other heartbeats stay cheap
+	if justCompleted {
+		// certificate missed here is issued when user lists own courses, so failure shouldn't lose the progress
+		_, err = p.certificates.IssueCertificate(ctx, options.UserId, lesson.CourseId)
+		if err != nil {
+			logger.Error("failed to issue certificate: ", err)
+		}
+	}
+
+	logger.Debug("successfully recorded progress")
+	return progress, nil
+}
+
+func (p *progressService) GetMyCourses(ctx context.Context, userId string) ([]*MyCourse, error) {
+	logger := p.logger.
+		Named("GetMyCourses").
+		WithContext(ctx).
+		With("userId", userId)
+
+	enrollments, err := p.storages.EnrollmentStorage.GetUserEnrollments(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enrollments: %w", err)
+	}
+	courseIds := make([]string, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		courseIds = append(courseIds, enrollment.CourseId)
+	}
+
+	courses, err := p.storages.CourseStorage.GetCourses(ctx, courseIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+	coursesById := make(map[string]*entity.Course, len(courses))
+	for _, course := range courses {
+		coursesById[course.Id] = course
+	}
+
+	progress, err := p.storages.ProgressStorage.GetCoursesProgress(ctx, userId, courseIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+	progressById := make(map[string]*entity.CourseProgress, len(progress))
+	for _, courseProgress := range progress {
+		progressById[courseProgress.CourseId] = courseProgress
+	}
+
+	myCourses := make([]*MyCourse, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		course, ok := coursesById[enrollment.CourseId]
+		if !ok {
+			continue
+		}
+		courseProgress, ok := progressById[course.Id]
+		if !ok {
+			// course without lessons has nothing to complete yet
+			courseProgress = &entity.CourseProgress{CourseId: course.Id}
+		}
+		if courseProgress.Completed() {
+			// issues certificate missed when issuing failed on completion, existing one is returned as is
+			_, err = p.certificates.IssueCertificate(ctx, userId, course.Id)
+			if err != nil {
+				logger.Error("failed to issue certificate: ", err)
+			}
+		}
+		myCourses = append(myCourses, &MyCourse{Course: course, EnrolledAt: enrollment.CreatedAt, Progress: courseProgress})
+	}
+
+	return myCourses, nil
+}
+
+func (r *RecordProgressOptions) Validate() error {
+	if r.Position < 0 || r.WatchedSeconds < 0 {
+		return errs.New("Position and watched seconds can't be negative.", "invalid_progress")
+	}
+	return nil
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type quizService struct {
+	serviceContext
+	certificates CertificateService
+}
+
+var _ QuizService = (*quizService)(nil)
+
+func NewQuizService(options *Options, certificates CertificateService) QuizService {
+	return &quizService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("QuizService"),
+		},
+		certificates: certificates,
+	}
+}
+
+func (q *quizService) AddQuiz(ctx context.Context, options *AddQuizOptions) (*entity.Quiz, error) {
+	logger := q.logger.
+		Named("AddQuiz").
+		WithContext(ctx).
+		With("userId", options.UserId, "sectionId", options.SectionId)
+
+	section, err := q.storages.CurriculumStorage.GetSection(ctx, options.SectionId)
+	if err != nil {
+		logger.Error("failed to get section: ", err)
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		logger.Info("section not found")
+		return nil, ErrAddQuizSectionNotFound
+	}
+
+	course, err := q.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: section.CourseId})
+	if err != nil || course == nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrAddQuizNotCourseTeacher
+	}
+
+	quiz, err := q.storages.QuizStorage.CreateQuiz(ctx, &entity.Quiz{
+		CourseId:         course.Id,
+		SectionId:        section.Id,
+		Title:            options.Title,
+		Position:         options.Position,
+		PassingScore:     options.PassingScore,
+		MaxAttempts:      options.MaxAttempts,
+		TimeLimitSeconds: options.TimeLimitSeconds,
+	})
+	if err != nil {
+		logger.Error("failed to create quiz: ", err)
+		return nil, fmt.Errorf("failed to create quiz: %w", err)
+	}
+
+	logger.Info("successfully added quiz", "quizId", quiz.Id)
+	return quiz, nil
+}
+
+func (q *quizService) AddQuizQuestion(ctx context.Context, options *AddQuizQuestionOptions) (*entity.QuizQuestion, error) {
+	logger := q.logger.
+		Named("AddQuizQuestion").
+		WithContext(ctx).
+		With("userId", options.UserId, "quizId", options.QuizId)
+
+	quiz, err := q.storages.QuizStorage.GetQuiz(ctx, options.QuizId)
+	if err != nil {
+		logger.Error("failed to get quiz: ", err)
+		return nil, fmt.Errorf("failed to get quiz: %w", err)
+	}
+	if quiz == nil {
+		logger.Info("quiz not found")
+		return nil, ErrAddQuizQuestionQuizNotFound
+	}
+
+	course, err := q.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: quiz.CourseId})
+	if err != nil || course == nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrAddQuizQuestionNotCourseTeacher
+	}
+
+	points := options.Points
+	if points == 0 {
+		points = 1
+	}
+	question, err := q.storages.QuizStorage.CreateQuestion(ctx, &entity.QuizQuestion{
+		QuizId:   quiz.Id,
+		Position: options.Position,
+		Type:     options.Type,
+		Text:     options.Text,
+		Options:  options.Options,
+		Answers:  options.Answers,
+		Points:   points,
+	})
+	if err != nil {
+		logger.Error("failed to create question: ", err)
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	logger.Info("successfully added question", "questionId", question.Id)
+	return question, nil
+}
+
+func (q *quizService) StartQuizAttempt(ctx context.Context, options *StartQuizAttemptOptions) (*QuizAttemptOutput, error) {
+	logger := q.logger.
+		Named("StartQuizAttempt").
+		WithContext(ctx).
+		With("userId", options.UserId, "quizId", options.QuizId)
+
+	quiz, err := q.storages.QuizStorage.GetQuiz(ctx, options.QuizId)
+	if err != nil {
+		logger.Error("failed to get quiz: ", err)
+		return nil, fmt.Errorf("failed to get quiz: %w", err)
+	}
+	if quiz == nil {
+		logger.Info("quiz not found")
+		return nil, ErrStartQuizAttemptQuizNotFound
+	}
+
+	enrolled, err := hasCourseAccess(ctx, q.storages, options.UserId, quiz.CourseId)
+	if err != nil {
+		logger.Error("failed to check course access: ", err)
+		return nil, err
+	}
+	if !enrolled {
+		logger.Info("user is not enrolled")
+		return nil, ErrStartQuizAttemptNotEnrolled
+	}
+
+	questions, err := q.storages.QuizStorage.GetQuestions(ctx, quiz.Id)
+	if err != nil {
+		logger.Error("failed to get questions: ", err)
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	if len(questions) == 0 {
+		logger.Info("quiz has no questions")
+		return nil, ErrStartQuizAttemptNoQuestions
+	}
+
+	attempts, err := q.storages.QuizStorage.GetAttempts(ctx, options.UserId, quiz.Id)
+	if err != nil {
+		logger.Error("failed to get attempts: ", err)
+		return nil, fmt.Errorf("failed to get attempts: %w", err)
+	}
+	for _, attempt := range attempts {
+		if attempt.SubmittedAt != nil {
+			continue
+		}
+		// reloading the page resumes open attempt instead of spending another one
+		if !q.expired(attempt) {
+			logger.Info("resumed open attempt", "attemptId", attempt.Id)
+			return &QuizAttemptOutput{Attempt: attempt, Questions: questions}, nil
+		}
+		_, err = q.grade(ctx, attempt, questions, quiz, nil)
+		if err != nil {
+			logger.Error("failed to grade expired attempt: ", err)
+			return nil, fmt.Errorf("failed to grade expired attempt: %w", err)
+		}
+	}
+
+	now := time.Now()
+	attempt := &entity.QuizAttempt{
+		QuizId:    quiz.Id,
+		UserId:    options.UserId,
+		CourseId:  quiz.CourseId,
+		StartedAt: now,
+	}
+	if quiz.TimeLimitSeconds > 0 {
+		expiresAt := now.Add(time.Duration(quiz.TimeLimitSeconds) * time.Second)
+		attempt.ExpiresAt = &expiresAt
+	}
+	attempt, err = q.storages.QuizStorage.CreateAttempt(ctx, attempt, quiz.MaxAttempts)
+	if err != nil {
+		logger.Error("failed to create attempt: ", err)
+		return nil, fmt.Errorf("failed to create attempt: %w", err)
+	}
+	if attempt == nil {
+		logger.Info("no attempts left")
+		return nil, ErrStartQuizAttemptNoAttemptsLeft
+	}
+
+	logger.Info("successfully started attempt", "attemptId", attempt.Id)
+	return &QuizAttemptOutput{Attempt: attempt, Questions: questions}, nil
+}
+
+func (q *quizService) SubmitQuizAttempt(ctx context.Context, options *SubmitQuizAttemptOptions) (*SubmitQuizAttemptOutput, error) {
+	logger := q.logger.
+		Named("SubmitQuizAttempt").
+		WithContext(ctx).
+		With("userId", options.UserId, "attemptId", options.AttemptId)
+
+	attempt, err := q.storages.QuizStorage.GetAttempt(ctx, options.AttemptId)
+	if err != nil {
+		logger.Error("failed to get attempt: ", err)
+		return nil, fmt.Errorf("failed to get attempt: %w", err)
+	}
+	if attempt == nil || attempt.UserId != options.UserId {
+		logger.Info("attempt not found")
+		return nil, ErrSubmitQuizAttemptAttemptNotFound
+	}
+	if attempt.SubmittedAt != nil {
+		logger.Info("attempt is submitted already")
+		return nil, ErrSubmitQuizAttemptAlreadySubmitted
+	}
+
+	quiz, err := q.storages.QuizStorage.GetQuiz(ctx, attempt.QuizId)
+	if err != nil || quiz == nil {
+		logger.Error("failed to get quiz: ", err)
+		return nil, fmt.Errorf("failed to get quiz: %w", err)
+	}
+	questions, err := q.storages.QuizStorage.GetQuestions(ctx, quiz.Id)
+	if err != nil {
+		logger.Error("failed to get questions: ", err)
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+
+	expired := q.expired(attempt)
+	answers := options.Answers
+	if expired {
+		answers = nil
+	}
+	output, err := q.grade(ctx, attempt, questions, quiz, answers)
+	if err != nil {
+		logger.Error("failed to grade attempt: ", err)
+		return nil, fmt.Errorf("failed to grade attempt: %w", err)
+	}
+	if output == nil {
+		logger.Info("attempt is submitted already")
+		return nil, ErrSubmitQuizAttemptAlreadySubmitted
+	}
+	if expired {
+		logger.Info("attempt is expired")
+		return nil, ErrSubmitQuizAttemptTimeExpired
+	}
+
+	if output.Attempt.Passed {
+		// certificate is issued again on next passed attempt or when user lists own courses, so failure shouldn't lose the result
+		_, err = q.certificates.IssueCertificate(ctx, options.UserId, quiz.CourseId)
+		if err != nil {
+			logger.Error("failed to issue certificate: ", err)
+		}
+	}
+
+	logger.Info("successfully graded attempt", "percent", output.Attempt.Percent, "passed", output.Attempt.Passed)
+	return output, nil
+}
+
+func (q *quizService) GetQuizAttempts(ctx context.Context, options *GetQuizAttemptsOptions) ([]*entity.QuizAttempt, error) {
+	quiz, err := q.storages.QuizStorage.GetQuiz(ctx, options.QuizId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz: %w", err)
+	}
+	if quiz == nil {
+		return nil, ErrGetQuizAttemptsQuizNotFound
+	}
+
+	attempts, err := q.storages.QuizStorage.GetAttempts(ctx, options.UserId, quiz.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// expired reports whether time limit of attempt including grace period is over.
+func (q *quizService) expired(attempt *entity.QuizAttempt) bool {
+	return attempt.ExpiresAt != nil && time.Now().After(attempt.ExpiresAt.Add(q.config.Quiz.SubmitGrace))
+}
+
+// grade scores answers and submits attempt, nil is returned when attempt was submitted meanwhile.
+// Questions score all or nothing, only answers to questions of the quiz are kept.
+func (q *quizService) grade(ctx context.Context, attempt *entity.QuizAttempt, questions []*entity.QuizQuestion, quiz *entity.Quiz, answers map[string][]string) (*SubmitQuizAttemptOutput, error) {
+	attempt.Answers = entity.QuizAnswers{}
+	attempt.Score, attempt.MaxScore = 0, 0
+	results := make([]*QuestionResult, 0, len(questions))
+	for _, question := range questions {
+		answer, ok := answers[question.Id]
+		if ok {
+			attempt.Answers[question.Id] = answer
+		}
+
+		result := &QuestionResult{QuestionId: question.Id, Correct: ok && answerCorrect(question, answer)}
+		if result.Correct {
+			result.Points = question.Points
+			attempt.Score += question.Points
+		}
+		attempt.MaxScore += question.Points
+		results = append(results, result)
+	}
+
+	if attempt.MaxScore > 0 {
+		attempt.Percent = attempt.Score * 100 / attempt.MaxScore
+	}
+	attempt.Passed = attempt.Percent >= quiz.PassingScore
+	now := time.Now()
+	attempt.SubmittedAt = &now
+
+	submitted, err := q.storages.QuizStorage.SubmitAttempt(ctx, attempt)
+	if err != nil || submitted == nil {
+		return nil, err
+	}
+
+	return &SubmitQuizAttemptOutput{Attempt: submitted, Results: results}, nil
+}
+
+// answerCorrect checks answer against question. Choice answers must select exactly the correct options,
+// short text answers must match one of accepted texts after trimming spaces.
+func answerCorrect(question *entity.QuizQuestion, answer []string) bool {
+	switch question.Type {
+	case entity.QuestionShortText:
+		if len(answer) != 1 {
+			return false
+		}
+		for _, accepted := range question.Answers {
+			if strings.TrimSpace(answer[0]) == strings.TrimSpace(accepted) {
+				return true
+			}
+		}
+		return false
+	case entity.QuestionSingleChoice, entity.QuestionMultipleChoice:
+		return sameOptions(answer, question.Answers)
+	}
+	return false
+}
+
+func sameOptions(answer, correct []string) bool {
+	normalize := func(options []string) []string {
+		normalized := make([]string, 0, len(options))
+		seen := make(map[string]bool, len(options))
+		for _, option := range options {
+			option = strings.TrimSpace(option)
+			if index, err := strconv.Atoi(option); err == nil {
+				option = strconv.Itoa(index)
+			}
+			if !seen[option] {
+				seen[option] = true
+				normalized = append(normalized, option)
+			}
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+
+	a, c := normalize(answer), normalize(correct)
+	if len(a) != len(c) {
+		return false
+	}
+	for i := range a {
+		if a[i] != c[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *AddQuizOptions) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return errs.New("Title is required.", "invalid_title")
+	}
+	if a.PassingScore < 0 || a.PassingScore > 100 {
+		return errs.New("Passing score must be percent from 0 to 100.", "invalid_passing_score")
+	}
+	if a.MaxAttempts < 0 || a.TimeLimitSeconds < 0 {
+		return errs.New("Attempts and time limit can't be negative.", "invalid_limits")
+	}
+	return nil
+}
+
+func (a *AddQuizQuestionOptions) Validate() error {
+	if strings.TrimSpace(a.Text) == "" {
+		return errs.New("Text is required.", "text_required")
+	}
+	if a.Points < 0 {
+		return errs.New("Points can't be negative.", "invalid_points")
+	}
+
+	switch a.Type {
+	case entity.QuestionShortText:
+		if len(a.Options) > 0 {
+			return errs.New("Short text question has no options.", "unexpected_options")
+		}
+		if len(a.Answers) == 0 {
+			return errs.New("At least one accepted answer is required.", "answers_required")
+		}
+	case entity.QuestionSingleChoice, entity.QuestionMultipleChoice:
+		if len(a.Options) < 2 {
+			return errs.New("Choice question needs at least two options.", "invalid_options")
+		}
+		if len(a.Answers) == 0 || (a.Type == entity.QuestionSingleChoice && len(a.Answers) != 1) {
+			return errs.New("Single choice question needs one correct option, multiple choice at least one.", "invalid_correct_options")
+		}
+		for _, answer := range a.Answers {
+			index, err := strconv.Atoi(answer)
+			if err != nil || index < 0 || index >= len(a.Options) {
+				return errs.New("Answers must be indexes of options.", "invalid_answers")
+			}
+		}
+	default:
+		return errs.New("Type must be single_choice, multiple_choice or short_text.", "invalid_type")
+	}
+	return nil
+}
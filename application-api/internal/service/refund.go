@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/payment"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const _maxRefundReasonLength = 1000
+
+type refundService struct {
+	serviceContext
+	payment payment.Provider
+}
+
+var _ RefundService = (*refundService)(nil)
+
+func NewRefundService(options *Options) RefundService {
+	return &refundService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("RefundService"),
+		},
+		payment: options.Payment,
+	}
+}
+
+func (r *refundService) RequestRefund(ctx context.Context, options *RequestRefundOptions) (*entity.Refund, error) {
+	logger := r.logger.
+		Named("RequestRefund").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	item, err := r.storages.OrderStorage.GetPaidOrderItem(ctx, options.UserId, options.CourseId)
+	if err != nil {
+		logger.Error("failed to get order item: ", err)
+		return nil, fmt.Errorf("failed to get order item: %w", err)
+	}
+	if item == nil {
+		logger.Info("course wasn't purchased")
+		return nil, ErrRequestRefundNotPurchased
+	}
+	logger = logger.With("orderId", item.OrderId, "orderItemId", item.Id)
+
+	order, err := r.storages.OrderStorage.GetOrder(ctx, item.OrderId)
+	if err != nil || order == nil {
+		logger.Error("failed to get order: ", err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.PaidAt == nil || time.Since(*order.PaidAt) > r.config.Refund.Window {
+		logger.Info("refund window is over", "paidAt", order.PaidAt)
+		return nil, ErrRequestRefundWindowExpired
+	}
+
+	watched, err := r.storages.ProgressStorage.GetWatchedPercent(ctx, options.UserId, options.CourseId)
+	if err != nil {
+		logger.Error("failed to get watched percent: ", err)
+		return nil, fmt.Errorf("failed to get watched percent: %w", err)
+	}
+	if watched > r.config.Refund.MaxProgressPercent {
+		logger.Info("too much of the course is watched", "percent", watched)
+		return nil, ErrRequestRefundProgressExceeded
+	}
+
+	certificate, err := r.storages.CertificateStorage.GetCertificate(ctx, &storage.GetCertificateFilter{UserId: options.UserId, CourseId: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get certificate: ", err)
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+	if certificate != nil {
+		logger.Info("course is certified", "certificateId", certificate.Id)
+		return nil, ErrRequestRefundCourseCompleted
+	}
+
+	progress, err := r.storages.ProgressStorage.GetCoursesProgress(ctx, options.UserId, []string{options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course progress: ", err)
+		return nil, fmt.Errorf("failed to get course progress: %w", err)
+	}
+	if len(progress) > 0 && progress[0].Completed() {
+		logger.Info("course is completed")
+		return nil, ErrRequestRefundCourseCompleted
+	}
+
+	refund, err := r.storages.RefundStorage.CreateRefund(ctx, &entity.Refund{
+		OrderId:     order.Id,
+		OrderItemId: item.Id,
+		UserId:      options.UserId,
+		CourseId:    item.CourseId,
+		Amount:      item.Price,
+		Currency:    order.Currency,
+		Reason:      strings.TrimSpace(options.Reason),
+		Status:      entity.RefundPending,
+	})
+	if err != nil {
+		logger.Error("failed to create refund: ", err)
+		return nil, fmt.Errorf("failed to create refund: %w", err)
+	}
+	if refund == nil {
+		logger.Info("refund is requested already")
+		return nil, ErrRequestRefundAlreadyRequested
+	}
+	logger = logger.With("refundId", refund.Id)
+
+	// refund stays pending when it fails, RetryPendingRefunds completes it later
+	completed, err := completeRefund(ctx, r.storages, r.payment, refund, order)
+	if err != nil {
+		logger.Error("failed to complete refund: ", err)
+		return nil, err
+	}
+	if completed == nil {
+		logger.Info("refund is completed already")
+		return nil, ErrRequestRefundAlreadyRequested
+	}
+
+	logger.Info("successfully refunded course")
+	return completed, nil
+}
+
+func (r *refundService) RetryPendingRefunds(ctx context.Context) error {
+	logger := r.logger.
+		Named("RetryPendingRefunds").
+		WithContext(ctx)
+
+	refunds, err := r.storages.RefundStorage.GetPendingRefunds(ctx, time.Now().Add(-r.config.Refund.RetryInterval))
+	if err != nil {
+		logger.Error("failed to get pending refunds: ", err)
+		return fmt.Errorf("failed to get pending refunds: %w", err)
+	}
+
+	completed := 0
+	for _, refund := range refunds {
+		refundLogger := logger.With("refundId", refund.Id)
+
+		order, err := r.storages.OrderStorage.GetOrder(ctx, refund.OrderId)
+		if err != nil || order == nil {
+			refundLogger.Error("failed to get order: ", err)
+			continue
+		}
+
+		// one failed refund shouldn't keep the rest from being completed, it is retried on the next run
+		_, err = completeRefund(ctx, r.storages, r.payment, refund, order)
+		if err != nil {
+			refundLogger.Error("failed to complete refund: ", err)
+			continue
+		}
+		completed++
+	}
+
+	if len(refunds) > 0 {
+		logger.Info("successfully retried pending refunds", "refunds", len(refunds), "completed", completed)
+	}
+	return nil
+}
+
+// completeRefund returns money of pending refund at provider and reverses its ledger entries, nil is returned
+// when refund isn't pending anymore. Refund id is idempotency key, so provider doesn't return money twice
+// when completing is retried.
+func completeRefund(ctx context.Context, storages *storage.Storages, provider payment.Provider, refund *entity.Refund, order *entity.Order) (*entity.Refund, error) {
+	entries, err := storages.RefundStorage.GetOrderItemEntries(ctx, refund.OrderItemId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+
+	providerRefund, err := provider.Refund(ctx, &payment.RefundRequest{
+		Reference: refund.Id,
+		ChargeId:  order.ChargeId,
+		Amount:    refund.Amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refund charge: %w", err)
+	}
+	refund.ProviderRefundId = providerRefund.Id
+
+	completed, err := storages.RefundStorage.CompleteRefund(ctx, refund, reversalEntries(entries))
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete refund: %w", err)
+	}
+
+	return completed, nil
+}
+
+func (r *refundService) GetMyRefunds(ctx context.Context, userId string) ([]*entity.Refund, error) {
+	refunds, err := r.storages.RefundStorage.GetUserRefunds(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+// reversalEntries returns transactions cancelling sale and fee transactions of order item,
+// every original transaction gets its own balanced reversal.
+func reversalEntries(entries []*entity.LedgerEntry) []*entity.LedgerEntry {
+	transactions := map[string]string{}
+	var reversed []*entity.LedgerEntry
+	for _, entry := range entries {
+		if entry.Kind != entity.LedgerSale && entry.Kind != entity.LedgerFee {
+			continue
+		}
+		transaction, ok := transactions[entry.TransactionId]
+		if !ok {
+			transaction = uuid.NewString()
+			transactions[entry.TransactionId] = transaction
+		}
+		reversed = append(reversed, &entity.LedgerEntry{
+			TransactionId: transaction,
+			Account:       entry.Account,
+			Kind:          entity.LedgerRefund,
+			Amount:        -entry.Amount,
+			Currency:      entry.Currency,
+			CourseId:      entry.CourseId,
+			OrderItemId:   entry.OrderItemId,
+		})
+	}
+	return reversed
+}
+
+func (r *RequestRefundOptions) Validate() error {
+	if utf8.RuneCountInString(strings.TrimSpace(r.Reason)) > _maxRefundReasonLength {
+		return errs.New(fmt.Sprintf("Reason can't be longer than %d characters.", _maxRefundReasonLength), "invalid_reason")
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// _maxReviewLength - limit of review text and reply in characters.
+	_maxReviewLength = 5000
+)
+
+type reviewService struct {
+	serviceContext
+}
+
+var _ ReviewService = (*reviewService)(nil)
+
+func NewReviewService(options *Options) ReviewService {
+	return &reviewService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("ReviewService"),
+		},
+	}
+}
+
+func (r *reviewService) CreateReview(ctx context.Context, options *CreateReviewOptions) (*entity.Review, error) {
+	logger := r.logger.
+		Named("CreateReview").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := r.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		logger.Info("course not found")
+		return nil, ErrCreateReviewCourseNotFound
+	}
+
+	user, err := r.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	enrollment, err := r.storages.EnrollmentStorage.GetEnrollment(ctx, options.UserId, options.CourseId)
+	if err != nil {
+		logger.Error("failed to get enrollment: ", err)
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	if enrollment == nil || user.Type != entity.Student {
+		logger.Info("user is not enrolled student", "type", user.Type)
+		return nil, ErrCreateReviewNotEnrolled
+	}
+
+	review, err := r.storages.ReviewStorage.CreateReview(ctx, &entity.Review{
+		CourseId: options.CourseId,
+		UserId:   options.UserId,
+		Rating:   options.Rating,
+		Text:     strings.TrimSpace(options.Text),
+	})
+	if err != nil {
+		logger.Error("failed to create review: ", err)
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+	if review == nil {
+		logger.Info("course is reviewed already")
+		return nil, ErrCreateReviewAlreadyReviewed
+	}
+
+	logger.Info("successfully created review", "reviewId", review.Id)
+	return review, nil
+}
+
+func (r *reviewService) GetReviews(ctx context.Context, courseId string) ([]*entity.Review, error) {
+	reviews, err := r.storages.ReviewStorage.GetReviews(ctx, courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+func (r *reviewService) UpdateReview(ctx context.Context, options *UpdateReviewOptions) (*entity.Review, error) {
+	logger := r.logger.
+		Named("UpdateReview").
+		WithContext(ctx).
+		With("userId", options.UserId, "reviewId", options.ReviewId)
+
+	review, err := r.storages.ReviewStorage.GetReview(ctx, &storage.GetReviewFilter{Id: options.ReviewId, UserId: options.UserId})
+	if err != nil {
+		logger.Error("failed to get review: ", err)
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		logger.Info("review not found")
+		return nil, ErrUpdateReviewReviewNotFound
+	}
+
+	updatedReview, err := r.storages.ReviewStorage.UpdateReview(ctx, review.Id, options.Rating, strings.TrimSpace(options.Text))
+	if err != nil {
+		logger.Error("failed to update review: ", err)
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	logger.Info("successfully updated review")
+	return updatedReview, nil
+}
+
+func (r *reviewService) ReplyToReview(ctx context.Context, options *ReplyToReviewOptions) (*entity.Review, error) {
+	logger := r.logger.
+		Named("ReplyToReview").
+		WithContext(ctx).
+		With("userId", options.UserId, "reviewId", options.ReviewId)
+
+	review, err := r.storages.ReviewStorage.GetReview(ctx, &storage.GetReviewFilter{Id: options.ReviewId})
+	if err != nil {
+		logger.Error("failed to get review: ", err)
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		logger.Info("review not found")
+		return nil, ErrReplyToReviewReviewNotFound
+	}
+
+	course, err := r.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: review.CourseId})
+	if err != nil || course == nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.TeacherId != options.UserId {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrReplyToReviewNotCourseTeacher
+	}
+
+	repliedReview, err := r.storages.ReviewStorage.ReplyToReview(ctx, review.Id, strings.TrimSpace(options.Reply))
+	if err != nil {
+		logger.Error("failed to reply to review: ", err)
+		return nil, fmt.Errorf("failed to reply to review: %w", err)
+	}
+
+	logger.Info("successfully replied to review")
+	return repliedReview, nil
+}
+
+func (r *reviewService) ModerateReview(ctx context.Context, options *ModerateReviewOptions) (*entity.Review, error) {
+	logger := r.logger.
+		Named("ModerateReview").
+		WithContext(ctx).
+		With("userId", options.UserId, "reviewId", options.ReviewId, "hidden", options.Hidden)
+
+	user, err := r.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return nil, ErrModerateReviewNotAdmin
+	}
+
+	review, err := r.storages.ReviewStorage.GetReview(ctx, &storage.GetReviewFilter{Id: options.ReviewId})
+	if err != nil {
+		logger.Error("failed to get review: ", err)
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		logger.Info("review not found")
+		return nil, ErrModerateReviewReviewNotFound
+	}
+
+	moderatedReview, err := r.storages.ReviewStorage.SetReviewHidden(ctx, review.Id, options.Hidden)
+	if err != nil {
+		logger.Error("failed to moderate review: ", err)
+		return nil, fmt.Errorf("failed to moderate review: %w", err)
+	}
+
+	logger.Info("successfully moderated review")
+	return moderatedReview, nil
+}
+
+func (c *CreateReviewOptions) Validate() error {
+	return validateReview(c.Rating, c.Text)
+}
+
+func (u *UpdateReviewOptions) Validate() error {
+	return validateReview(u.Rating, u.Text)
+}
+
+func (r *ReplyToReviewOptions) Validate() error {
+	if strings.TrimSpace(r.Reply) == "" {
+		return errs.New("Reply is required.", "reply_required")
+	}
+	if utf8.RuneCountInString(r.Reply) > _maxReviewLength {
+		return errs.New(fmt.Sprintf("Reply must be at most %d characters.", _maxReviewLength), "invalid_reply")
+	}
+	return nil
+}
+
+func validateReview(rating int, text string) error {
+	if rating < entity.MinRating || rating > entity.MaxRating {
+		return errs.New(fmt.Sprintf("Rating must be from %d to %d.", entity.MinRating, entity.MaxRating), "invalid_rating")
+	}
+	if utf8.RuneCountInString(text) > _maxReviewLength {
+		return errs.New(fmt.Sprintf("Text must be at most %d characters.", _maxReviewLength), "invalid_text")
+	}
+	return nil
+}
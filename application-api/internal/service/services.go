@@ -6,16 +6,74 @@ import (
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/internal/storage"
 	"github.com/vovk404/course-platform/application-api/pkg/auth"
+	"github.com/vovk404/course-platform/application-api/pkg/blob"
 	"github.com/vovk404/course-platform/application-api/pkg/errs"
 	"github.com/vovk404/course-platform/application-api/pkg/hash"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
 	"github.com/vovk404/course-platform/application-api/pkg/logger"
+	"github.com/vovk404/course-platform/application-api/pkg/mailer"
+	"github.com/vovk404/course-platform/application-api/pkg/oidc"
+	"github.com/vovk404/course-platform/application-api/pkg/payment"
+	"github.com/vovk404/course-platform/application-api/pkg/pdf"
+	"io"
+	"time"
 )
 
 type Services struct {
-	AuthService    AuthService
-	AccountService AccountService
-	NodeService    NodeService
-	CourseService  CourseService
+	AuthService         AuthService
+	AccountService      AccountService
+	NodeService         NodeService
+	CourseService       CourseService
+	AdminService        AdminService
+	TwoFactorService    TwoFactorService
+	APIKeyService       APIKeyService
+	ReviewService       ReviewService
+	ProgressService     ProgressService
+	CertificateService  CertificateService
+	QuizService         QuizService
+	AssignmentService   AssignmentService
+	DiscussionService   DiscussionService
+	OrderService        OrderService
+	LedgerService       LedgerService
+	RefundService       RefundService
+	CartService         CartService
+	BundleService       BundleService
+	SubscriptionService SubscriptionService
+	GiftCodeService     GiftCodeService
+	WishlistService     WishlistService
+	TeacherService      TeacherService
+	SubtitleService     SubtitleService
+}
+
+// NewServices creates all services with given options.
+func NewServices(options *Options) Services {
+	certificateService := NewCertificateService(options)
+
+	return Services{
+		AuthService:         NewAuthService(options),
+		AccountService:      NewAccountService(options),
+		NodeService:         NewNodeService(options),
+		CourseService:       NewCourseService(options),
+		AdminService:        NewAdminService(options),
+		TwoFactorService:    NewTwoFactorService(options),
+		APIKeyService:       NewAPIKeyService(options),
+		ReviewService:       NewReviewService(options),
+		ProgressService:     NewProgressService(options, certificateService),
+		CertificateService:  certificateService,
+		QuizService:         NewQuizService(options, certificateService),
+		AssignmentService:   NewAssignmentService(options),
+		DiscussionService:   NewDiscussionService(options),
+		OrderService:        NewOrderService(options),
+		LedgerService:       NewLedgerService(options),
+		RefundService:       NewRefundService(options),
+		CartService:         NewCartService(options),
+		BundleService:       NewBundleService(options),
+		SubscriptionService: NewSubscriptionService(options),
+		GiftCodeService:     NewGiftCodeService(options),
+		WishlistService:     NewWishlistService(options),
+		TeacherService:      NewTeacherService(options),
+		SubtitleService:     NewSubtitleService(options),
+	}
 }
 
 type Options struct {
@@ -24,6 +82,21 @@ type Options struct {
 	Logger   logger.Logger
 	Hash     hash.Hash
 	Auth     auth.Authenticator
+	// Signer signs certificates, its keys aren't rotated out like access token ones.
+	Signer auth.Signer
+	Mailer mailer.Mailer
+	// OIDC is nil when social login is not configured.
+	OIDC    *oidc.Provider
+	Blob    blob.Storage
+	Payment payment.Provider
+	// CertificateFonts draw certificates, they have to cover names of users and courses in any language.
+	CertificateFonts *CertificateFonts
+}
+
+// CertificateFonts - represents regular and bold fonts of certificates.
+type CertificateFonts struct {
+	Regular pdf.Font
+	Bold    pdf.Font
 }
 
 type serviceContext struct {
@@ -39,15 +112,35 @@ type AuthService interface {
 	SignUp(ctx context.Context, options *SignUpOptions) (*SignUpOutput, error)
 	// VerifyToken provides logic of validating provided authorization token.
 	VerifyToken(ctx context.Context, options *VerifyTokenOptions) (*VerifyTokenOutput, error)
+	// VerifyEmail provides confirming user email via one-time token sent on sign up.
+	VerifyEmail(ctx context.Context, options *VerifyEmailOptions) error
+	// ResendVerificationEmail provides sending new verification token to email of user who hasn't verified it yet.
+	ResendVerificationEmail(ctx context.Context, userId string) error
+	// ForgotPassword provides sending password reset token to user email.
+	ForgotPassword(ctx context.Context, options *ForgotPasswordOptions) error
+	// ResetPassword provides setting new password via one-time token sent by ForgotPassword.
+	ResetPassword(ctx context.Context, options *ResetPasswordWithTokenOptions) error
+	// OIDCLogin provides starting sign in with external OpenID provider, returns URL to redirect user to.
+	OIDCLogin(ctx context.Context) (*OIDCLoginOutput, error)
+	// OIDCCallback provides completing sign in with external OpenID provider, users are linked by verified email.
+	OIDCCallback(ctx context.Context, options *OIDCCallbackOptions) (*SignInOutput, error)
+	// PublicKeys provides keys access tokens are verified with, so other services can verify them.
+	PublicKeys(ctx context.Context) *jwks.Set
 }
 
 type SignInOptions struct {
 	Email    string
 	Password string
+	// IP of the client, used to throttle failed attempts.
+	IP string `json:"-"`
 }
 
+// SignInOutput contains either access token or, for users with two-factor authentication,
+// challenge token to be exchanged for access token via TwoFactorService.Verify.
 type SignInOutput struct {
-	AccessToken string
+	AccessToken       string
+	TwoFactorRequired bool
+	ChallengeToken    string
 }
 
 type SignUpOptions struct {
@@ -75,10 +168,40 @@ type VerifyTokenOutput struct {
 	UserId   string
 }
 
+type VerifyEmailOptions struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordOptions struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordWithTokenOptions struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type OIDCLoginOutput struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+}
+
+type OIDCCallbackOptions struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+}
+
 var (
-	ErrSignUpUserAlreadyCreated = errs.New("user already created", "user_already_created")
-	ErrSignInUserNotFound       = errs.New("user not found", "user_not_found")
-	ErrSignInWrongPassword      = errs.New("wrong password", "wrong_password")
+	ErrSignUpUserAlreadyCreated     = errs.New("user already created", "user_already_created")
+	ErrSignInInvalidCredentials     = errs.New("invalid credentials", "invalid_credentials")
+	ErrSignInTooManyAttempts        = errs.New("too many sign in attempts, try again later", "too_many_attempts")
+	ErrVerifyEmailInvalidToken      = errs.New("invalid or expired token", "invalid_token")
+	ErrResendVerificationVerified   = errs.New("email is verified already", "already_verified")
+	ErrResetPasswordInvalidToken    = errs.New("invalid or expired token", "invalid_token")
+	ErrOIDCLoginNotConfigured       = errs.New("social login is not configured", "oidc_not_configured")
+	ErrOIDCCallbackNotConfigured    = errs.New("social login is not configured", "oidc_not_configured")
+	ErrOIDCCallbackInvalidState     = errs.New("invalid or expired login state", "invalid_state")
+	ErrOIDCCallbackFailed           = errs.New("failed to authenticate with identity provider", "oidc_failed")
+	ErrOIDCCallbackEmailNotVerified = errs.New("identity provider has not verified email", "provider_email_not_verified")
 )
 
 type AccountService interface {
@@ -86,8 +209,10 @@ type AccountService interface {
 	CreateAccount(ctx context.Context, options *CreateAccountOptions) (*CreateAccountOutput, error)
 	// GetAccount provides logic of getting account via accountId.
 	GetAccount(ctx context.Context, options *GetAccountOptions) (*entity.Account, error)
-	// UpdateAccount provides logic of updating existing account.
-	UpdateAccount(ctx context.Context, account *entity.Account) (*entity.Account, error)
+	// UpdateAccount provides changing account settings with JSON Merge Patch, settings are validated.
+	UpdateAccount(ctx context.Context, options *UpdateAccountOptions) (*entity.Account, error)
+	// GetAccountLanguage provides language chosen in account settings of user, empty when it isn't chosen.
+	GetAccountLanguage(ctx context.Context, userId string) (string, error)
 }
 
 type CreateAccountOptions struct {
@@ -109,9 +234,25 @@ type GetAccountOptions struct {
 	UserId    string `json:"userId"`
 }
 
+// UpdateAccountOptions contain JSON Merge Patch (RFC 7396) of account, only accountSettings and accountDevices
+// can be changed.
+type UpdateAccountOptions struct {
+	AccountId string
+	UserId    string
+	Patch     []byte
+}
+
 var (
 	ErrCreateAccountUserNotFound = errs.New("user not found", "user_not_found")
 	ErrGetAccountAccountNotFound = errs.New("account not found", "account_not_found")
+
+	ErrUpdateAccountAccountNotFound      = errs.New("account not found", "account_not_found")
+	ErrUpdateAccountInvalidPatch         = errs.New("patch must be JSON object changing known fields of accountSettings or accountDevices only", "invalid_patch")
+	ErrUpdateAccountInvalidDevices       = errs.New("devices must be list of devices of the account, devices without id are added", "invalid_devices")
+	ErrUpdateAccountInvalidLanguage      = errs.New("Language must be language tag, e.g. en or pt-BR.", "invalid_language")
+	ErrUpdateAccountInvalidTimezone      = errs.New("timezone must be IANA time zone name, e.g. Europe/Kyiv", "invalid_timezone")
+	ErrUpdateAccountInvalidNotifications = errs.New("notifications have unknown channel or event type", "invalid_notifications")
+	ErrUpdateAccountInvalidPlaybackSpeed = errs.New("playback speed must be from 0.5 to 2 in steps of 0.25", "invalid_playback_speed")
 )
 
 type NodeService interface {
@@ -133,17 +274,53 @@ type CourseService interface {
 	UploadCourse(ctx context.Context, options *UploadCourseOptions) (*CreateCourseOutput, error)
 	GetTeachersList(ctx context.Context, teacherId string) ([]*entity.Course, error)
 	GetList() ([]*entity.Course, error)
-	GetCourseById(ctx context.Context, id string) (*entity.Course, error)
+	// GetCourseById provides getting course with name and description translated to the first of languages
+	// available, original course is the last fallback.
+	GetCourseById(ctx context.Context, options *GetCourseByIdOptions) (*entity.Course, error)
+	// GetCourseTranslations provides listing all translations of course.
+	GetCourseTranslations(ctx context.Context, courseId string) ([]*entity.CourseTranslation, error)
+	// SetCourseTranslation provides creating or overwriting translation of course by its teacher.
+	SetCourseTranslation(ctx context.Context, options *SetCourseTranslationOptions) (*entity.CourseTranslation, error)
+	// DeleteCourseTranslation provides deleting translation of course by its teacher.
+	DeleteCourseTranslation(ctx context.Context, options *DeleteCourseTranslationOptions) error
+	// Enroll provides enrolling user in free published course, paid courses are enrolled on purchase.
+	Enroll(ctx context.Context, options *EnrollOptions) (*entity.Enrollment, error)
+	// AddSection provides adding section to course by its teacher.
+	AddSection(ctx context.Context, options *AddSectionOptions) (*entity.Section, error)
+	// AddLesson provides adding lesson to section by course teacher.
+	AddLesson(ctx context.Context, options *AddLessonOptions) (*entity.Lesson, error)
+	// GetCurriculum provides getting ordered sections of course with their lessons.
+	GetCurriculum(ctx context.Context, courseId string) (*CurriculumOutput, error)
 }
 
+// UploadCourseOptions don't have author, it is display name of teacher profile.
 type UploadCourseOptions struct {
-	Author         string  `json:"author"`
 	Name           string  `json:"name"`
 	Description    string  `json:"description"`
 	Price          float32 `json:"price"`
 	CourseLanguage string  `json:"courseLanguage"`
 }
 
+type GetCourseByIdOptions struct {
+	Id string
+	// Languages preferred by client from the most preferred one, course isn't localized when empty.
+	Languages []string
+}
+
+type SetCourseTranslationOptions struct {
+	UserId      string `json:"-"`
+	CourseId    string `json:"-"`
+	Language    string `json:"-"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type DeleteCourseTranslationOptions struct {
+	UserId   string
+	CourseId string
+	Language string
+}
+
 type CreateCourseOutput struct {
 	Id     string `json:"id"`
 	Name   string `json:"name"`
@@ -153,3 +330,1007 @@ type CreateCourseOutput struct {
 type CreateGetListOutput struct {
 	Courses []*entity.Course `json:"courses"`
 }
+
+type EnrollOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+}
+
+type AddSectionOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	Title    string `json:"title"`
+	Position int    `json:"position"`
+}
+
+type AddLessonOptions struct {
+	UserId          string `json:"-"`
+	SectionId       string `json:"-"`
+	Title           string `json:"title"`
+	Position        int    `json:"position"`
+	DurationSeconds int    `json:"durationSeconds"`
+}
+
+type CurriculumOutput struct {
+	Sections []*CurriculumSection `json:"sections"`
+}
+
+type CurriculumSection struct {
+	*entity.Section
+	Lessons []*CurriculumLesson `json:"lessons"`
+	Quizzes []*entity.Quiz      `json:"quizzes"`
+}
+
+// CurriculumLesson is lesson with subtitle tracks player can offer, their content is served separately.
+type CurriculumLesson struct {
+	*entity.Lesson
+	Subtitles []*entity.LessonSubtitle `json:"subtitles"`
+}
+
+var (
+	ErrUploadCourseEmailNotVerified = errs.New("email is not verified", "email_not_verified")
+	ErrUploadCourseProfileRequired  = errs.New("teacher profile must be created before uploading courses", "profile_required")
+	ErrEnrollCourseNotFound         = errs.New("course not found", "course_not_found")
+	ErrEnrollPaymentRequired        = errs.New("course is paid, purchase it to enroll", "payment_required")
+	ErrAddSectionCourseNotFound     = errs.New("course not found", "course_not_found")
+	ErrAddSectionNotCourseTeacher   = errs.New("only teacher of the course can change it", "not_allowed")
+
+	ErrAddLessonSectionNotFound    = errs.New("section not found", "section_not_found")
+	ErrAddLessonNotCourseTeacher   = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrGetCurriculumCourseNotFound = errs.New("course not found", "course_not_found")
+
+	ErrGetCourseTranslationsCourseNotFound     = errs.New("course not found", "course_not_found")
+	ErrSetCourseTranslationCourseNotFound      = errs.New("course not found", "course_not_found")
+	ErrSetCourseTranslationNotCourseTeacher    = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrDeleteCourseTranslationCourseNotFound   = errs.New("course not found", "course_not_found")
+	ErrDeleteCourseTranslationNotCourseTeacher = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrDeleteCourseTranslationNotFound         = errs.New("translation not found", "translation_not_found")
+)
+
+type AdminService interface {
+	// CreateAdmin provides creating user with admin privileges.
+	CreateAdmin(ctx context.Context, options *CreateAdminOptions) (*entity.User, error)
+	// ResetPassword provides setting new password for user found by email.
+	ResetPassword(ctx context.Context, options *ResetPasswordOptions) error
+	// PromoteToTeacher provides changing type of user found by email to teacher.
+	PromoteToTeacher(ctx context.Context, email string) (*entity.User, error)
+	// ListCourses provides getting all courses including unpublished ones.
+	ListCourses(ctx context.Context) ([]*entity.Course, error)
+	// UnpublishCourse provides hiding course from public course list.
+	UnpublishCourse(ctx context.Context, courseId string) (*entity.Course, error)
+	// Export provides dumping users and courses, password hashes are left out unless requested.
+	Export(ctx context.Context, options *ExportOptions) (*ExportData, error)
+	// Import provides creating or overwriting users and courses from dump at once, nothing is saved on failure.
+	// Users without password keep their current one, authors of courses are derived from their teachers.
+	Import(ctx context.Context, data *ExportData) (*ImportOutput, error)
+}
+
+type CreateAdminOptions struct {
+	Username string
+	Email    string
+	Password string
+}
+
+type ResetPasswordOptions struct {
+	Email    string
+	Password string
+}
+
+type ExportOptions struct {
+	// WithPasswords includes password hashes, so dump can restore sign in of users.
+	WithPasswords bool
+}
+
+type ExportData struct {
+	Users   []*entity.User   `json:"users"`
+	Courses []*entity.Course `json:"courses"`
+}
+
+type ImportOutput struct {
+	Users   int `json:"users"`
+	Courses int `json:"courses"`
+}
+
+var (
+	ErrCreateAdminUserAlreadyCreated = errs.New("user already created", "user_already_created")
+	ErrResetPasswordUserNotFound     = errs.New("user not found", "user_not_found")
+	ErrPromoteToTeacherUserNotFound  = errs.New("user not found", "user_not_found")
+	ErrUnpublishCourseCourseNotFound = errs.New("course not found", "course_not_found")
+	ErrPromoteToTeacherWrongUserType = errs.New("only students can be promoted to teacher", "wrong_user_type")
+)
+
+type TwoFactorService interface {
+	// Enroll provides generating new TOTP secret for user, it takes effect once confirmed via Enable.
+	Enroll(ctx context.Context, userId string) (*EnrollTwoFactorOutput, error)
+	// Enable provides confirming enrollment with a code, returns recovery codes shown only once.
+	Enable(ctx context.Context, options *EnableTwoFactorOptions) (*EnableTwoFactorOutput, error)
+	// Disable provides turning two-factor authentication off after checking a code.
+	Disable(ctx context.Context, options *DisableTwoFactorOptions) error
+	// Verify provides completing sign in started by AuthService.SignIn with a code or recovery code.
+	Verify(ctx context.Context, options *VerifyTwoFactorOptions) (*SignInOutput, error)
+}
+
+type EnrollTwoFactorOutput struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type EnableTwoFactorOptions struct {
+	UserId string `json:"-"`
+	Code   string `json:"code"`
+}
+
+type EnableTwoFactorOutput struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type DisableTwoFactorOptions struct {
+	UserId       string `json:"-"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+type VerifyTwoFactorOptions struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recoveryCode"`
+}
+
+var (
+	ErrEnrollTwoFactorAlreadyEnabled   = errs.New("two-factor authentication already enabled", "two_factor_already_enabled")
+	ErrEnableTwoFactorNotEnrolled      = errs.New("two-factor authentication is not enrolled", "two_factor_not_enrolled")
+	ErrEnableTwoFactorAlreadyEnabled   = errs.New("two-factor authentication already enabled", "two_factor_already_enabled")
+	ErrEnableTwoFactorInvalidCode      = errs.New("invalid code", "invalid_code")
+	ErrDisableTwoFactorNotEnabled      = errs.New("two-factor authentication is not enabled", "two_factor_not_enabled")
+	ErrDisableTwoFactorInvalidCode     = errs.New("invalid code", "invalid_code")
+	ErrVerifyTwoFactorInvalidChallenge = errs.New("invalid or expired challenge token", "invalid_challenge")
+	ErrVerifyTwoFactorInvalidCode      = errs.New("invalid code", "invalid_code")
+)
+
+type APIKeyService interface {
+	// CreateAPIKey provides issuing personal API key for teachers, plain key is returned only once.
+	CreateAPIKey(ctx context.Context, options *CreateAPIKeyOptions) (*CreateAPIKeyOutput, error)
+	// GetAPIKeys provides listing API keys of user including revoked and expired ones.
+	GetAPIKeys(ctx context.Context, userId string) ([]*entity.APIKey, error)
+	// RevokeAPIKey provides disabling API key of user.
+	RevokeAPIKey(ctx context.Context, options *RevokeAPIKeyOptions) error
+	// VerifyAPIKey provides authenticating request with API key and records its usage.
+	VerifyAPIKey(ctx context.Context, options *VerifyAPIKeyOptions) (*VerifyAPIKeyOutput, error)
+}
+
+type CreateAPIKeyOptions struct {
+	UserId    string     `json:"-"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type CreateAPIKeyOutput struct {
+	// Key is shown only once, it can't be recovered later.
+	Key    string         `json:"key"`
+	APIKey *entity.APIKey `json:"apiKey"`
+}
+
+type RevokeAPIKeyOptions struct {
+	UserId string `json:"-"`
+	KeyId  string `json:"-"`
+}
+
+type VerifyAPIKeyOptions struct {
+	Key string
+}
+
+type VerifyAPIKeyOutput struct {
+	UserId   string
+	Username string
+	Scopes   []string
+}
+
+var (
+	ErrCreateAPIKeyNotAllowed    = errs.New("only teachers can create API keys", "api_key_not_allowed")
+	ErrCreateAPIKeyTooManyKeys   = errs.New("too many API keys, revoke unused ones first", "too_many_keys")
+	ErrRevokeAPIKeyKeyNotFound   = errs.New("API key not found", "api_key_not_found")
+	ErrVerifyAPIKeyInvalidAPIKey = errs.New("invalid, expired or revoked API key", "invalid_api_key")
+)
+
+type ReviewService interface {
+	// CreateReview provides rating course by enrolled student, one review per student and course.
+	CreateReview(ctx context.Context, options *CreateReviewOptions) (*entity.Review, error)
+	// GetReviews provides listing visible reviews of course.
+	GetReviews(ctx context.Context, courseId string) ([]*entity.Review, error)
+	// UpdateReview provides changing rating and text of own review.
+	UpdateReview(ctx context.Context, options *UpdateReviewOptions) (*entity.Review, error)
+	// ReplyToReview provides answering review by teacher of the course.
+	ReplyToReview(ctx context.Context, options *ReplyToReviewOptions) (*entity.Review, error)
+	// ModerateReview provides hiding or restoring review by admin.
+	ModerateReview(ctx context.Context, options *ModerateReviewOptions) (*entity.Review, error)
+}
+
+type CreateReviewOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	Rating   int    `json:"rating"`
+	Text     string `json:"text"`
+}
+
+type UpdateReviewOptions struct {
+	UserId   string `json:"-"`
+	ReviewId string `json:"-"`
+	Rating   int    `json:"rating"`
+	Text     string `json:"text"`
+}
+
+type ReplyToReviewOptions struct {
+	UserId   string `json:"-"`
+	ReviewId string `json:"-"`
+	Reply    string `json:"reply"`
+}
+
+type ModerateReviewOptions struct {
+	UserId   string `json:"-"`
+	ReviewId string `json:"-"`
+	Hidden   bool   `json:"hidden"`
+}
+
+var (
+	ErrCreateReviewCourseNotFound    = errs.New("course not found", "course_not_found")
+	ErrCreateReviewNotEnrolled       = errs.New("only enrolled students can review course", "review_not_enrolled")
+	ErrCreateReviewAlreadyReviewed   = errs.New("course is reviewed already, update existing review", "already_reviewed")
+	ErrUpdateReviewReviewNotFound    = errs.New("review not found", "review_not_found")
+	ErrReplyToReviewReviewNotFound   = errs.New("review not found", "review_not_found")
+	ErrReplyToReviewNotCourseTeacher = errs.New("only teacher of the course can reply", "reply_not_allowed")
+	ErrModerateReviewReviewNotFound  = errs.New("review not found", "review_not_found")
+	ErrModerateReviewNotAdmin        = errs.New("only admins can moderate reviews", "review_moderation_not_allowed")
+)
+
+type ProgressService interface {
+	// RecordProgress provides merging player heartbeat into lesson progress, repeated heartbeats are harmless.
+	RecordProgress(ctx context.Context, options *RecordProgressOptions) (*entity.LessonProgress, error)
+	// GetMyCourses provides listing courses user is enrolled in with completion of each.
+	GetMyCourses(ctx context.Context, userId string) ([]*MyCourse, error)
+}
+
+type RecordProgressOptions struct {
+	UserId   string `json:"-"`
+	LessonId string `json:"-"`
+	// Position is current playback position in seconds.
+	Position int `json:"position"`
+	// WatchedSeconds is total time watched by the player, it never decreases on server
+	// and can't grow faster than playback between heartbeats.
+	WatchedSeconds int `json:"watchedSeconds"`
+	// SentAt is when player sent heartbeat, position of heartbeat arriving after a later one is ignored.
+	// Time of receiving is used when it is omitted, future time is capped by it.
+	SentAt *time.Time `json:"sentAt"`
+}
+
+type MyCourse struct {
+	Course     *entity.Course         `json:"course"`
+	EnrolledAt time.Time              `json:"enrolledAt"`
+	Progress   *entity.CourseProgress `json:"progress"`
+}
+
+var (
+	ErrRecordProgressLessonNotFound = errs.New("lesson not found", "lesson_not_found")
+	ErrRecordProgressNotEnrolled    = errs.New("user is not enrolled in course", "not_enrolled")
+)
+
+type CertificateService interface {
+	// IssueCertificate provides issuing certificate once user completed all lessons and passed all quizzes of course,
+	// nil is returned while course isn't completed and existing certificate is returned when issued already.
+	IssueCertificate(ctx context.Context, userId, courseId string) (*entity.Certificate, error)
+	// GetMyCertificates provides listing certificates of user.
+	GetMyCertificates(ctx context.Context, userId string) ([]*entity.Certificate, error)
+	// GetCertificatePDF provides rendering own certificate to PDF document.
+	GetCertificatePDF(ctx context.Context, options *GetCertificatePDFOptions) ([]byte, error)
+	// VerifyCertificate provides checking that certificate was issued by the platform and isn't revoked.
+	VerifyCertificate(ctx context.Context, certificateId string) (*VerifyCertificateOutput, error)
+	// RevokeCertificate provides revoking certificate by admin.
+	RevokeCertificate(ctx context.Context, options *RevokeCertificateOptions) (*entity.Certificate, error)
+	// PublicKeys provides keys certificates are verified with, so they can be verified offline.
+	PublicKeys(ctx context.Context) *jwks.Set
+}
+
+type GetCertificatePDFOptions struct {
+	UserId        string
+	CertificateId string
+}
+
+type VerifyCertificateOutput struct {
+	// Valid is true when signature matches certificate and it isn't revoked.
+	Valid       bool                `json:"valid"`
+	Revoked     bool                `json:"revoked"`
+	Certificate *entity.Certificate `json:"certificate"`
+}
+
+type RevokeCertificateOptions struct {
+	UserId        string `json:"-"`
+	CertificateId string `json:"-"`
+	Reason        string `json:"reason"`
+}
+
+var (
+	ErrGetCertificatePDFCertificateNotFound = errs.New("certificate not found", "certificate_not_found")
+	ErrGetCertificatePDFRevoked             = errs.New("certificate is revoked", "certificate_revoked")
+	ErrVerifyCertificateCertificateNotFound = errs.New("certificate not found", "certificate_not_found")
+	ErrRevokeCertificateCertificateNotFound = errs.New("certificate not found", "certificate_not_found")
+	ErrRevokeCertificateNotAdmin            = errs.New("only admins can revoke certificates", "revoke_not_allowed")
+)
+
+type QuizService interface {
+	// AddQuiz provides adding quiz to section by course teacher.
+	AddQuiz(ctx context.Context, options *AddQuizOptions) (*entity.Quiz, error)
+	// AddQuizQuestion provides adding question with correct answers to quiz by course teacher.
+	AddQuizQuestion(ctx context.Context, options *AddQuizQuestionOptions) (*entity.QuizQuestion, error)
+	// StartQuizAttempt provides starting attempt by enrolled student, open attempt is returned instead if there is one.
+	// Questions are returned without answers.
+	StartQuizAttempt(ctx context.Context, options *StartQuizAttemptOptions) (*QuizAttemptOutput, error)
+	// SubmitQuizAttempt provides grading answers of open attempt.
+	SubmitQuizAttempt(ctx context.Context, options *SubmitQuizAttemptOptions) (*SubmitQuizAttemptOutput, error)
+	// GetQuizAttempts provides listing own attempts in quiz.
+	GetQuizAttempts(ctx context.Context, options *GetQuizAttemptsOptions) ([]*entity.QuizAttempt, error)
+}
+
+type AddQuizOptions struct {
+	UserId    string `json:"-"`
+	SectionId string `json:"-"`
+	Title     string `json:"title"`
+	Position  int    `json:"position"`
+	// PassingScore is percent of points needed to pass.
+	PassingScore int `json:"passingScore"`
+	// MaxAttempts limits attempts of each student, zero is unlimited.
+	MaxAttempts int `json:"maxAttempts"`
+	// TimeLimitSeconds limits duration of attempt, zero is unlimited.
+	TimeLimitSeconds int `json:"timeLimitSeconds"`
+}
+
+type AddQuizQuestionOptions struct {
+	UserId   string `json:"-"`
+	QuizId   string `json:"-"`
+	Position int    `json:"position"`
+	Type     string `json:"type" enums:"single_choice,multiple_choice,short_text"`
+	Text     string `json:"text"`
+	// Options are choices of choice questions, short text questions have none.
+	Options []string `json:"options"`
+	// Answers are indexes of correct options starting from 0, or accepted texts of short text question.
+	Answers []string `json:"answers"`
+	// Points question is worth, defaults to 1.
+	Points int `json:"points"`
+}
+
+type StartQuizAttemptOptions struct {
+	UserId string
+	QuizId string
+}
+
+type QuizAttemptOutput struct {
+	Attempt   *entity.QuizAttempt    `json:"attempt"`
+	Questions []*entity.QuizQuestion `json:"questions"`
+}
+
+type SubmitQuizAttemptOptions struct {
+	UserId    string `json:"-"`
+	AttemptId string `json:"-"`
+	// Answers by question id, indexes of chosen options for choice questions or single text.
+	Answers map[string][]string `json:"answers"`
+}
+
+type SubmitQuizAttemptOutput struct {
+	Attempt *entity.QuizAttempt `json:"attempt"`
+	// Results tell which questions were answered correctly, correct answers themselves aren't revealed.
+	Results []*QuestionResult `json:"results"`
+}
+
+type QuestionResult struct {
+	QuestionId string `json:"questionId"`
+	Correct    bool   `json:"correct"`
+	Points     int    `json:"points"`
+}
+
+type GetQuizAttemptsOptions struct {
+	UserId string
+	QuizId string
+}
+
+var (
+	ErrAddQuizSectionNotFound            = errs.New("section not found", "section_not_found")
+	ErrAddQuizNotCourseTeacher           = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrAddQuizQuestionQuizNotFound       = errs.New("quiz not found", "quiz_not_found")
+	ErrAddQuizQuestionNotCourseTeacher   = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrStartQuizAttemptQuizNotFound      = errs.New("quiz not found", "quiz_not_found")
+	ErrStartQuizAttemptNotEnrolled       = errs.New("user is not enrolled in course", "not_enrolled")
+	ErrStartQuizAttemptNoQuestions       = errs.New("quiz has no questions yet", "quiz_empty")
+	ErrStartQuizAttemptNoAttemptsLeft    = errs.New("no attempts left", "no_attempts_left")
+	ErrSubmitQuizAttemptAttemptNotFound  = errs.New("attempt not found", "attempt_not_found")
+	ErrSubmitQuizAttemptAlreadySubmitted = errs.New("attempt is submitted already", "attempt_submitted")
+	ErrSubmitQuizAttemptTimeExpired      = errs.New("time limit of attempt is exceeded, it is graded without answers", "attempt_expired")
+	ErrGetQuizAttemptsQuizNotFound       = errs.New("quiz not found", "quiz_not_found")
+)
+
+type AssignmentService interface {
+	// AddAssignment provides adding assignment to course by its teacher.
+	AddAssignment(ctx context.Context, options *AddAssignmentOptions) (*entity.Assignment, error)
+	// GetAssignments provides listing assignments of course.
+	GetAssignments(ctx context.Context, courseId string) ([]*entity.Assignment, error)
+	// SubmitAssignment provides uploading submission file by enrolled student, replacing ungraded submission.
+	SubmitAssignment(ctx context.Context, options *SubmitAssignmentOptions) (*entity.Submission, error)
+	// GetMySubmission provides getting own submission of assignment.
+	GetMySubmission(ctx context.Context, options *GetMySubmissionOptions) (*entity.Submission, error)
+	// GetSubmissionFile provides opening submitted file for its student or course teacher, content must be closed.
+	GetSubmissionFile(ctx context.Context, options *GetSubmissionFileOptions) (*SubmissionFileOutput, error)
+	// GradeSubmission provides scoring submission by course teacher, late penalty is applied to the score.
+	GradeSubmission(ctx context.Context, options *GradeSubmissionOptions) (*entity.Submission, error)
+	// GetGradingQueue provides listing ungraded submissions across courses of teacher.
+	GetGradingQueue(ctx context.Context, teacherId string) ([]*entity.GradingQueueItem, error)
+}
+
+type AddAssignmentOptions struct {
+	UserId      string    `json:"-"`
+	CourseId    string    `json:"-"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	MaxScore    int       `json:"maxScore"`
+	DueAt       time.Time `json:"dueAt"`
+	// LateDays is how many days after DueAt submissions are still accepted.
+	LateDays int `json:"lateDays"`
+	// LatePenaltyPercent is deducted from score for every started day of delay.
+	LatePenaltyPercent int `json:"latePenaltyPercent"`
+}
+
+type SubmitAssignmentOptions struct {
+	UserId       string
+	AssignmentId string
+	FileName     string
+	ContentType  string
+	Size         int64
+	File         io.Reader
+	Comment      string
+}
+
+type GetMySubmissionOptions struct {
+	UserId       string
+	AssignmentId string
+}
+
+type GetSubmissionFileOptions struct {
+	UserId       string
+	SubmissionId string
+}
+
+type SubmissionFileOutput struct {
+	Submission *entity.Submission
+	Content    io.ReadCloser
+}
+
+type GradeSubmissionOptions struct {
+	UserId       string `json:"-"`
+	SubmissionId string `json:"-"`
+	Score        int    `json:"score"`
+	Feedback     string `json:"feedback"`
+}
+
+var (
+	ErrAddAssignmentCourseNotFound        = errs.New("course not found", "course_not_found")
+	ErrAddAssignmentNotCourseTeacher      = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrGetAssignmentsCourseNotFound       = errs.New("course not found", "course_not_found")
+	ErrSubmitAssignmentAssignmentNotFound = errs.New("assignment not found", "assignment_not_found")
+	ErrSubmitAssignmentNotEnrolled        = errs.New("user is not enrolled in course", "not_enrolled")
+	ErrSubmitAssignmentFileTooLarge       = errs.New("file is too large", "file_too_large")
+	ErrSubmitAssignmentPastDeadline       = errs.New("deadline for late submissions has passed", "past_deadline")
+	ErrSubmitAssignmentAlreadyGraded      = errs.New("submission is graded already", "already_graded")
+	ErrGetMySubmissionNotSubmitted        = errs.New("assignment is not submitted yet", "not_submitted")
+	ErrGetSubmissionFileNotFound          = errs.New("submission not found", "submission_not_found")
+	ErrGradeSubmissionNotFound            = errs.New("submission not found", "submission_not_found")
+	ErrGradeSubmissionNotCourseTeacher    = errs.New("only teacher of the course can grade", "grade_not_allowed")
+	ErrGradeSubmissionInvalidScore        = errs.New("score must be from 0 to max score of assignment", "invalid_score")
+)
+
+type DiscussionService interface {
+	// CreateThread provides starting thread in course or its lesson by enrolled student or teacher.
+	CreateThread(ctx context.Context, options *CreateThreadOptions) (*entity.Thread, error)
+	// GetThreads provides listing threads of course page by page, most recently active first.
+	GetThreads(ctx context.Context, options *GetThreadsOptions) (*GetThreadsOutput, error)
+	// GetThread provides getting thread with its opening post.
+	GetThread(ctx context.Context, options *GetThreadOptions) (*entity.Thread, error)
+	// GetPosts provides listing replies of thread page by page, oldest first.
+	GetPosts(ctx context.Context, options *GetPostsOptions) (*GetPostsOutput, error)
+	// CreatePost provides replying to thread or to its post.
+	CreatePost(ctx context.Context, options *CreatePostOptions) (*entity.Post, error)
+	// AcceptPost provides marking reply as accepted answer by course teacher.
+	AcceptPost(ctx context.Context, options *AcceptPostOptions) (*entity.Thread, error)
+	// Upvote provides adding or removing upvote of thread or post.
+	Upvote(ctx context.Context, options *UpvoteOptions) error
+	// Report provides reporting thread or post to moderators.
+	Report(ctx context.Context, options *ReportOptions) (*entity.DiscussionReport, error)
+	// GetModerationQueue provides listing reported threads and posts to admin.
+	GetModerationQueue(ctx context.Context, userId string) ([]*entity.ModerationQueueItem, error)
+	// ResolveReport provides hiding reported content or dismissing its reports by admin.
+	ResolveReport(ctx context.Context, options *ResolveReportOptions) error
+}
+
+type CreateThreadOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	// LessonId scopes thread to lesson of the course, empty for course wide threads.
+	LessonId string `json:"lessonId"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+}
+
+type GetThreadsOptions struct {
+	UserId   string `form:"-"`
+	CourseId string `form:"-"`
+	LessonId string `form:"lessonId"`
+	// Cursor is NextCursor of previous page, empty for the first page.
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type GetThreadsOutput struct {
+	Threads []*entity.Thread `json:"threads"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"nextCursor"`
+}
+
+type GetThreadOptions struct {
+	UserId   string
+	ThreadId string
+}
+
+type GetPostsOptions struct {
+	UserId   string `form:"-"`
+	ThreadId string `form:"-"`
+	Cursor   string `form:"cursor"`
+	Limit    int    `form:"limit"`
+}
+
+type GetPostsOutput struct {
+	Posts      []*entity.Post `json:"posts"`
+	NextCursor string         `json:"nextCursor"`
+}
+
+type CreatePostOptions struct {
+	UserId   string  `json:"-"`
+	ThreadId string  `json:"-"`
+	ParentId *string `json:"parentId"`
+	Body     string  `json:"body"`
+}
+
+type AcceptPostOptions struct {
+	UserId   string `json:"-"`
+	ThreadId string `json:"-"`
+	// PostId is reply to accept, null clears accepted answer.
+	PostId *string `json:"postId"`
+}
+
+type UpvoteOptions struct {
+	UserId     string
+	TargetType string
+	TargetId   string
+	// Up adds upvote, otherwise it is removed.
+	Up bool
+}
+
+type ReportOptions struct {
+	UserId     string `json:"-"`
+	TargetType string `json:"-"`
+	TargetId   string `json:"-"`
+	Reason     string `json:"reason"`
+}
+
+type ResolveReportOptions struct {
+	UserId   string `json:"-"`
+	ReportId string `json:"-"`
+	// Hide hides reported content, otherwise reports are dismissed.
+	Hide bool `json:"hide"`
+}
+
+var (
+	ErrCreateThreadCourseNotFound   = errs.New("course not found", "course_not_found")
+	ErrCreateThreadLessonNotFound   = errs.New("lesson not found", "lesson_not_found")
+	ErrDiscussionNotAllowed         = errs.New("discussions are available to enrolled students and teacher of the course", "discussion_not_allowed")
+	ErrDiscussionInvalidCursor      = errs.New("invalid cursor", "invalid_cursor")
+	ErrGetThreadsCourseNotFound     = errs.New("course not found", "course_not_found")
+	ErrThreadNotFound               = errs.New("thread not found", "thread_not_found")
+	ErrPostNotFound                 = errs.New("post not found", "post_not_found")
+	ErrAcceptPostNotCourseTeacher   = errs.New("only teacher of the course can accept answers", "accept_not_allowed")
+	ErrUpvoteOwnContent             = errs.New("own threads and posts can't be upvoted", "own_content")
+	ErrReportAlreadyReported        = errs.New("content is reported already", "already_reported")
+	ErrGetModerationQueueNotAdmin   = errs.New("only admins can moderate discussions", "discussion_moderation_not_allowed")
+	ErrResolveReportNotAdmin        = errs.New("only admins can moderate discussions", "discussion_moderation_not_allowed")
+	ErrResolveReportReportNotFound  = errs.New("report not found", "report_not_found")
+	ErrResolveReportAlreadyResolved = errs.New("report is resolved already", "report_resolved")
+)
+
+type OrderService interface {
+	// PurchaseCourse provides buying paid course, paid order enrolls user and credits course teacher.
+	PurchaseCourse(ctx context.Context, options *PurchaseCourseOptions) (*entity.Order, error)
+	// GetMyOrders provides listing orders of user.
+	GetMyOrders(ctx context.Context, userId string) ([]*entity.Order, error)
+	// Checkout provides buying all courses in cart of user with one order, cart is revalidated first.
+	Checkout(ctx context.Context, options *CheckoutOptions) (*entity.Order, error)
+	// PurchaseBundle provides buying courses of bundle not owned yet with one order, bundle price is split between them.
+	PurchaseBundle(ctx context.Context, options *PurchaseBundleOptions) (*entity.Order, error)
+	// PurchaseGift provides buying paid course for someone else, paid order gives gift code instead of enrollment.
+	PurchaseGift(ctx context.Context, options *PurchaseGiftOptions) (*PurchaseGiftOutput, error)
+	// ReconcilePendingOrders provides finishing orders left pending by interrupted purchases, charged orders
+	// are fulfilled and the rest are failed.
+	ReconcilePendingOrders(ctx context.Context) error
+}
+
+type PurchaseCourseOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	// PaymentToken identifies payment method at payment provider.
+	PaymentToken string `json:"paymentToken"`
+}
+
+var (
+	ErrPurchaseCourseCourseNotFound  = errs.New("course not found", "course_not_found")
+	ErrPurchaseCourseFree            = errs.New("course is free, enroll to it instead", "course_free")
+	ErrPurchaseCourseOwnCourse       = errs.New("teacher can't purchase own course", "own_course")
+	ErrPurchaseCourseAlreadyOwned    = errs.New("course is owned already", "already_owned")
+	ErrPurchaseCourseInProgress      = errs.New("course is being purchased already, wait for the purchase to finish", "purchase_in_progress")
+	ErrPurchaseCoursePaymentDeclined = errs.New("payment is declined", "payment_declined")
+)
+
+// ErrEmailNotVerified is returned when user who hasn't verified email pays or sells through platform.
+var ErrEmailNotVerified = errs.New("email is not verified", "email_not_verified")
+
+type CheckoutOptions struct {
+	UserId string `json:"-"`
+	// PaymentToken identifies payment method at payment provider.
+	PaymentToken string `json:"paymentToken"`
+}
+
+var (
+	ErrCheckoutCartEmpty         = errs.New("cart is empty", "cart_empty")
+	ErrCheckoutAlreadyOwned      = errs.New("some courses in cart are owned already, they were removed from cart", "cart_courses_owned")
+	ErrCheckoutCourseUnavailable = errs.New("some courses in cart aren't available anymore, they were removed from cart", "course_unavailable")
+	ErrCheckoutPriceChanged      = errs.New("prices of some courses in cart have changed, review cart and checkout again", "price_changed")
+	ErrCheckoutInProgress        = errs.New("course is being purchased already, wait for the purchase to finish", "purchase_in_progress")
+)
+
+type LedgerService interface {
+	// GetEarnings provides summary of teacher earnings with per course and per month aggregates.
+	GetEarnings(ctx context.Context, userId string) (*entity.Earnings, error)
+	// RequestPayout provides requesting withdrawal of available earnings by teacher.
+	RequestPayout(ctx context.Context, options *RequestPayoutOptions) (*entity.Payout, error)
+	// GetMyPayouts provides listing payouts of teacher.
+	GetMyPayouts(ctx context.Context, userId string) ([]*entity.Payout, error)
+	// GetPayouts provides listing payouts of all teachers to admin.
+	GetPayouts(ctx context.Context, options *GetPayoutsOptions) ([]*entity.Payout, error)
+	// ResolvePayout provides approving or rejecting requested payout by admin, approved payout is posted to ledger.
+	ResolvePayout(ctx context.Context, options *ResolvePayoutOptions) (*entity.Payout, error)
+}
+
+type RequestPayoutOptions struct {
+	UserId string `json:"-"`
+	// Amount is in minor units of currency.
+	Amount int64 `json:"amount"`
+}
+
+type GetPayoutsOptions struct {
+	UserId string `form:"-"`
+	Status string `form:"status"`
+}
+
+type ResolvePayoutOptions struct {
+	UserId   string `json:"-"`
+	PayoutId string `json:"-"`
+	Approve  bool   `json:"approve"`
+	Note     string `json:"note"`
+}
+
+var (
+	ErrGetEarningsNotTeacher            = errs.New("only teachers have earnings", "earnings_not_teacher")
+	ErrRequestPayoutNotTeacher          = errs.New("only teachers can request payouts", "payout_not_teacher")
+	ErrRequestPayoutInsufficientBalance = errs.New("amount exceeds available balance", "insufficient_balance")
+	ErrGetPayoutsNotAdmin               = errs.New("only admins can list payouts", "payouts_list_not_allowed")
+	ErrResolvePayoutNotAdmin            = errs.New("only admins can resolve payouts", "payout_resolve_not_allowed")
+	ErrResolvePayoutPayoutNotFound      = errs.New("payout not found", "payout_not_found")
+	ErrResolvePayoutAlreadyResolved     = errs.New("payout is resolved already", "payout_resolved")
+)
+
+type RefundService interface {
+	// RequestRefund provides refunding purchased course within refund policy, access to the course is revoked.
+	RequestRefund(ctx context.Context, options *RequestRefundOptions) (*entity.Refund, error)
+	// GetMyRefunds provides listing refunds of user.
+	GetMyRefunds(ctx context.Context, userId string) ([]*entity.Refund, error)
+	// RetryPendingRefunds provides completing refunds left pending by provider or storage failures.
+	RetryPendingRefunds(ctx context.Context) error
+}
+
+type RequestRefundOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	Reason   string `json:"reason"`
+}
+
+var (
+	ErrRequestRefundNotPurchased     = errs.New("course wasn't purchased", "not_purchased")
+	ErrRequestRefundWindowExpired    = errs.New("refund window is over", "window_expired")
+	ErrRequestRefundProgressExceeded = errs.New("too much of the course is watched to refund it", "progress_exceeded")
+	ErrRequestRefundAlreadyRequested = errs.New("refund is requested already", "already_requested")
+	ErrRequestRefundCourseCompleted  = errs.New("course is completed, it can't be refunded", "course_completed")
+)
+
+type CartService interface {
+	// GetCart provides getting cart of user.
+	GetCart(ctx context.Context, userId string) (*entity.Cart, error)
+	// AddToCart provides adding paid course to cart of user at its current price.
+	AddToCart(ctx context.Context, options *AddToCartOptions) (*entity.Cart, error)
+	// RemoveFromCart provides removing course from cart of user.
+	RemoveFromCart(ctx context.Context, options *RemoveFromCartOptions) (*entity.Cart, error)
+}
+
+type AddToCartOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"courseId"`
+}
+
+type RemoveFromCartOptions struct {
+	UserId   string
+	CourseId string
+}
+
+var (
+	ErrAddToCartCourseNotFound = errs.New("course not found", "course_not_found")
+	ErrAddToCartFree           = errs.New("course is free, enroll to it instead", "course_free")
+	ErrAddToCartOwnCourse      = errs.New("teacher can't purchase own course", "own_course")
+	ErrAddToCartAlreadyOwned   = errs.New("course is owned already", "already_owned")
+)
+
+type BundleService interface {
+	// CreateBundle provides creating bundle of own published courses by teacher.
+	CreateBundle(ctx context.Context, options *CreateBundleOptions) (*entity.Bundle, error)
+	// GetBundles provides listing published bundles.
+	GetBundles(ctx context.Context) ([]*entity.Bundle, error)
+	// GetBundle provides getting published bundle.
+	GetBundle(ctx context.Context, bundleId string) (*entity.Bundle, error)
+	// GetMyBundles provides listing bundles of teacher, unpublished ones included.
+	GetMyBundles(ctx context.Context, userId string) ([]*entity.Bundle, error)
+}
+
+type CreateBundleOptions struct {
+	UserId      string `json:"-"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Price is in minor units of currency.
+	Price     int64    `json:"price"`
+	CourseIds []string `json:"courseIds"`
+	Published bool     `json:"published"`
+}
+
+var (
+	ErrCreateBundleNotTeacher     = errs.New("only teachers can create bundles", "bundle_not_teacher")
+	ErrCreateBundleCourseNotFound = errs.New("bundle can include only own published courses", "bundle_course_not_allowed")
+	ErrGetBundleBundleNotFound    = errs.New("bundle not found", "bundle_not_found")
+)
+
+type PurchaseBundleOptions struct {
+	UserId   string `json:"-"`
+	BundleId string `json:"-"`
+	// PaymentToken identifies payment method at payment provider.
+	PaymentToken string `json:"paymentToken"`
+}
+
+var (
+	ErrPurchaseBundleBundleNotFound = errs.New("bundle not found", "bundle_not_found")
+	ErrPurchaseBundleOwnBundle      = errs.New("teacher can't purchase own bundle", "own_bundle")
+	ErrPurchaseBundleAlreadyOwned   = errs.New("all courses of bundle are owned already", "bundle_courses_owned")
+	ErrPurchaseBundleInProgress     = errs.New("course is being purchased already, wait for the purchase to finish", "purchase_in_progress")
+)
+
+type SubscriptionService interface {
+	// GetPlans provides listing subscription plans available for subscribing.
+	GetPlans(ctx context.Context) ([]*entity.SubscriptionPlan, error)
+	// CreatePlan provides creating subscription plan by admin.
+	CreatePlan(ctx context.Context, options *CreatePlanOptions) (*entity.SubscriptionPlan, error)
+	// Subscribe provides starting subscription of user at payment provider.
+	Subscribe(ctx context.Context, options *SubscribeOptions) (*entity.Subscription, error)
+	// GetMySubscription provides getting subscription of user which isn't canceled.
+	GetMySubscription(ctx context.Context, userId string) (*entity.Subscription, error)
+	// CancelMySubscription provides stopping renewal of user subscription, access lasts until period end.
+	CancelMySubscription(ctx context.Context, userId string) (*entity.Subscription, error)
+	// HandleWebhook provides applying signed payment provider event to subscription.
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+type CreatePlanOptions struct {
+	UserId string `json:"-"`
+	Name   string `json:"name"`
+	// Price is monthly price in minor units of currency.
+	Price     int64 `json:"price"`
+	TrialDays int   `json:"trialDays"`
+}
+
+type SubscribeOptions struct {
+	UserId string `json:"-"`
+	PlanId string `json:"planId"`
+	// PaymentToken identifies payment method at payment provider.
+	PaymentToken string `json:"paymentToken"`
+}
+
+var (
+	ErrCreatePlanNotAdmin            = errs.New("only admins can create subscription plans", "plan_not_allowed")
+	ErrSubscribePlanNotFound         = errs.New("subscription plan not found", "plan_not_found")
+	ErrSubscribeAlreadySubscribed    = errs.New("user is subscribed already", "already_subscribed")
+	ErrSubscribePaymentDeclined      = errs.New("payment is declined", "payment_declined")
+	ErrGetMySubscriptionNotFound     = errs.New("subscription not found", "subscription_not_found")
+	ErrCancelMySubscriptionNotFound  = errs.New("subscription not found", "subscription_not_found")
+	ErrHandleWebhookInvalidSignature = errs.New("webhook signature is invalid", "invalid_signature")
+)
+
+type PurchaseGiftOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	// PaymentToken identifies payment method at payment provider.
+	PaymentToken string `json:"paymentToken"`
+}
+
+// PurchaseGiftOutput contains paid gift order and code to be handed to gift recipient.
+type PurchaseGiftOutput struct {
+	Order *entity.Order
+	Code  *entity.GiftCode
+}
+
+var (
+	ErrPurchaseGiftCourseNotFound  = errs.New("course not found", "course_not_found")
+	ErrPurchaseGiftFree            = errs.New("course is free, enroll to it instead", "course_free")
+	ErrPurchaseGiftOwnCourse       = errs.New("teacher can't purchase own course", "own_course")
+	ErrPurchaseGiftPaymentDeclined = errs.New("payment is declined", "payment_declined")
+)
+
+type GiftCodeService interface {
+	// GenerateGiftCodes provides generating batch of free gift codes for course by its teacher or admin.
+	GenerateGiftCodes(ctx context.Context, options *GenerateGiftCodesOptions) ([]*entity.GiftCode, error)
+	// GetCourseGiftCodes provides listing gift codes of course to its teacher or admin for export.
+	GetCourseGiftCodes(ctx context.Context, options *GetCourseGiftCodesOptions) ([]*entity.GiftCode, error)
+	// GetMyGifts provides listing gift codes bought by user.
+	GetMyGifts(ctx context.Context, userId string) ([]*entity.GiftCode, error)
+	// RedeemGiftCode provides enrolling user in course of gift code, code can be redeemed once.
+	RedeemGiftCode(ctx context.Context, options *RedeemGiftCodeOptions) (*entity.Enrollment, error)
+}
+
+type GenerateGiftCodesOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"-"`
+	Count    int    `json:"count"`
+}
+
+type GetCourseGiftCodesOptions struct {
+	UserId   string `form:"-"`
+	CourseId string `form:"-"`
+	BatchId  string `form:"batchId"`
+}
+
+type RedeemGiftCodeOptions struct {
+	UserId string `json:"-"`
+	Code   string `json:"code"`
+}
+
+var (
+	ErrGenerateGiftCodesCourseNotFound  = errs.New("course not found", "course_not_found")
+	ErrGenerateGiftCodesNotAllowed      = errs.New("only course teacher or admins can generate gift codes", "gift_codes_generate_not_allowed")
+	ErrGetCourseGiftCodesCourseNotFound = errs.New("course not found", "course_not_found")
+	ErrGetCourseGiftCodesNotAllowed     = errs.New("only course teacher or admins can export gift codes", "gift_codes_export_not_allowed")
+	ErrRedeemGiftCodeNotFound           = errs.New("gift code not found", "code_not_found")
+	ErrRedeemGiftCodeRedeemed           = errs.New("gift code is redeemed already", "code_redeemed")
+	ErrRedeemGiftCodeAlreadyOwned       = errs.New("course is owned already, gift code isn't used", "gift_course_owned")
+)
+
+type WishlistService interface {
+	// GetWishlist provides listing courses user saved for later with their current prices.
+	GetWishlist(ctx context.Context, userId string) ([]*entity.WishlistItem, error)
+	// AddToWishlist provides saving published course user doesn't own to wishlist.
+	AddToWishlist(ctx context.Context, options *AddToWishlistOptions) ([]*entity.WishlistItem, error)
+	// RemoveFromWishlist provides removing course from wishlist.
+	RemoveFromWishlist(ctx context.Context, options *RemoveFromWishlistOptions) ([]*entity.WishlistItem, error)
+	// NotifyPriceDrops provides recording course price changes to price history and emailing users
+	// who wishlisted courses which became cheaper, it is run periodically in background.
+	NotifyPriceDrops(ctx context.Context) error
+}
+
+type AddToWishlistOptions struct {
+	UserId   string `json:"-"`
+	CourseId string `json:"courseId"`
+}
+
+type RemoveFromWishlistOptions struct {
+	UserId   string
+	CourseId string
+}
+
+var (
+	ErrAddToWishlistCourseNotFound = errs.New("course not found", "course_not_found")
+	ErrAddToWishlistOwnCourse      = errs.New("teacher can't wishlist own course", "wishlist_own_course")
+	ErrAddToWishlistAlreadyOwned   = errs.New("course is owned already", "already_owned")
+)
+
+type TeacherService interface {
+	// GetTeacher provides public profile of teacher with published courses and their aggregate stats.
+	GetTeacher(ctx context.Context, teacherId string) (*GetTeacherOutput, error)
+	// GetMyProfile provides getting profile of current teacher.
+	GetMyProfile(ctx context.Context, userId string) (*entity.TeacherProfile, error)
+	// UpdateMyProfile provides overwriting profile of current teacher, courses are renamed to new display name.
+	// Display names are unique regardless of case, so course authors can't be impersonated.
+	UpdateMyProfile(ctx context.Context, options *UpdateTeacherProfileOptions) (*entity.TeacherProfile, error)
+}
+
+type GetTeacherOutput struct {
+	Profile *entity.TeacherProfile
+	Courses []*entity.Course
+	Stats   *entity.TeacherStats
+}
+
+type UpdateTeacherProfileOptions struct {
+	UserId      string             `json:"-"`
+	DisplayName string             `json:"displayName"`
+	Bio         string             `json:"bio"`
+	AvatarURL   string             `json:"avatarUrl"`
+	SocialLinks entity.SocialLinks `json:"socialLinks"`
+}
+
+var (
+	ErrGetTeacherNotFound                   = errs.New("teacher not found", "teacher_not_found")
+	ErrGetMyTeacherProfileNotTeacher        = errs.New("only teachers have profiles", "not_teacher")
+	ErrUpdateTeacherProfileNotTeacher       = errs.New("only teachers have profiles", "not_teacher")
+	ErrUpdateTeacherProfileDisplayNameTaken = errs.New("display name is taken by another teacher", "display_name_taken")
+)
+
+type SubtitleService interface {
+	// UploadSubtitle provides validating and saving subtitles of lesson in language by course teacher,
+	// SRT is converted to WebVTT and transcript is indexed for search. Subtitles in the same language are replaced.
+	UploadSubtitle(ctx context.Context, options *UploadSubtitleOptions) (*entity.LessonSubtitle, error)
+	// GetSubtitle provides WebVTT subtitles of lesson to course teacher and users with access to course.
+	GetSubtitle(ctx context.Context, options *GetSubtitleOptions) (*entity.LessonSubtitle, error)
+	// DeleteSubtitle provides deleting subtitles of lesson in language by course teacher.
+	DeleteSubtitle(ctx context.Context, options *DeleteSubtitleOptions) error
+	// SearchTranscripts provides lessons of published courses which transcripts match query, best matches first.
+	// Text of matching cue is hidden from users without access to course, so paid transcripts don't leak.
+	SearchTranscripts(ctx context.Context, options *SearchTranscriptsOptions) ([]*entity.TranscriptMatch, error)
+}
+
+// UploadSubtitleOptions contain subtitles in WebVTT or SRT format, Label defaults to language.
+type UploadSubtitleOptions struct {
+	UserId   string
+	LessonId string
+	Language string
+	Label    string
+	Content  []byte
+}
+
+type GetSubtitleOptions struct {
+	UserId   string
+	LessonId string
+	Language string
+}
+
+type DeleteSubtitleOptions struct {
+	UserId   string
+	LessonId string
+	Language string
+}
+
+type SearchTranscriptsOptions struct {
+	// UserId is empty for anonymous search, text of cues is returned only for courses the user has access to.
+	UserId string `form:"-"`
+	// Query supports "quoted phrases", or and -excluded words.
+	Query    string `form:"q"`
+	CourseId string `form:"courseId"`
+	Language string `form:"language"`
+	Limit    int    `form:"limit"`
+}
+
+var (
+	ErrUploadSubtitleLessonNotFound   = errs.New("lesson not found", "lesson_not_found")
+	ErrUploadSubtitleNotCourseTeacher = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrUploadSubtitleInvalid          = errs.New("subtitles must be valid WebVTT or SRT", "invalid_subtitles")
+	ErrUploadSubtitleFileTooLarge     = errs.New("subtitles file is too large", "subtitles_too_large")
+	ErrGetSubtitleLessonNotFound      = errs.New("lesson not found", "lesson_not_found")
+	ErrGetSubtitleNotEnrolled         = errs.New("user is not enrolled in course", "not_enrolled")
+	ErrGetSubtitleNotFound            = errs.New("subtitles not found", "subtitle_not_found")
+	ErrDeleteSubtitleLessonNotFound   = errs.New("lesson not found", "lesson_not_found")
+	ErrDeleteSubtitleNotCourseTeacher = errs.New("only teacher of the course can change it", "not_allowed")
+	ErrDeleteSubtitleNotFound         = errs.New("subtitles not found", "subtitle_not_found")
+)
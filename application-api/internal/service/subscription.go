@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/payment"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	_maxPlanNameLength = 200
+	_maxPlanTrialDays  = 90
+)
+
+type subscriptionService struct {
+	serviceContext
+	payment payment.Provider
+}
+
+var _ SubscriptionService = (*subscriptionService)(nil)
+
+func NewSubscriptionService(options *Options) SubscriptionService {
+	return &subscriptionService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("SubscriptionService"),
+		},
+		payment: options.Payment,
+	}
+}
+
+func (s *subscriptionService) GetPlans(ctx context.Context) ([]*entity.SubscriptionPlan, error) {
+	plans, err := s.storages.SubscriptionStorage.GetActivePlans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+func (s *subscriptionService) CreatePlan(ctx context.Context, options *CreatePlanOptions) (*entity.SubscriptionPlan, error) {
+	logger := s.logger.
+		Named("CreatePlan").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	user, err := s.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Admin {
+		logger.Info("user is not admin", "type", user.Type)
+		return nil, ErrCreatePlanNotAdmin
+	}
+
+	plan, err := s.storages.SubscriptionStorage.CreatePlan(ctx, &entity.SubscriptionPlan{
+		Name:      strings.TrimSpace(options.Name),
+		Price:     options.Price,
+		Currency:  s.config.Payment.Currency,
+		TrialDays: options.TrialDays,
+		Active:    true,
+	})
+	if err != nil {
+		logger.Error("failed to create plan: ", err)
+		return nil, fmt.Errorf("failed to create plan: %w", err)
+	}
+
+	logger.Info("successfully created plan", "planId", plan.Id)
+	return plan, nil
+}
+
+func (s *subscriptionService) Subscribe(ctx context.Context, options *SubscribeOptions) (*entity.Subscription, error) {
+	logger := s.logger.
+		Named("Subscribe").
+		WithContext(ctx).
+		With("userId", options.UserId, "planId", options.PlanId)
+
+	verified, err := hasVerifiedEmail(ctx, s.storages, options.UserId)
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, err
+	}
+	if !verified {
+		logger.Info("user email is not verified")
+		return nil, ErrEmailNotVerified
+	}
+
+	plan, err := s.storages.SubscriptionStorage.GetPlan(ctx, options.PlanId)
+	if err != nil {
+		logger.Error("failed to get plan: ", err)
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	if plan == nil || !plan.Active {
+		logger.Info("plan not found")
+		return nil, ErrSubscribePlanNotFound
+	}
+
+	live, err := s.storages.SubscriptionStorage.GetLiveSubscription(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get subscription: ", err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if live != nil {
+		logger.Info("user is subscribed already")
+		return nil, ErrSubscribeAlreadySubscribed
+	}
+
+	// subscription id is generated upfront to be idempotency key of provider request
+	subscriptionId := uuid.NewString()
+	started, err := s.payment.Subscribe(ctx, &payment.SubscribeRequest{
+		Reference: subscriptionId,
+		Token:     options.PaymentToken,
+		Amount:    plan.Price,
+		Currency:  plan.Currency,
+		TrialDays: plan.TrialDays,
+	})
+	if err != nil {
+		if errors.Is(err, payment.ErrDeclined) {
+			logger.Info("payment is declined")
+			return nil, ErrSubscribePaymentDeclined
+		}
+		logger.Error("failed to start subscription: ", err)
+		return nil, fmt.Errorf("failed to start subscription: %w", err)
+	}
+
+	subscription, err := s.storages.SubscriptionStorage.CreateSubscription(ctx, &entity.Subscription{
+		Id:                     subscriptionId,
+		UserId:                 options.UserId,
+		PlanId:                 plan.Id,
+		Status:                 started.Status,
+		ProviderSubscriptionId: started.Id,
+		CurrentPeriodEnd:       started.CurrentPeriodEnd,
+	})
+	if err != nil {
+		logger.Error("failed to create subscription: ", err)
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	if subscription == nil {
+		// concurrent request subscribed user first, subscription started at provider isn't needed
+		cancelErr := s.payment.CancelSubscription(ctx, started.Id)
+		if cancelErr != nil {
+			logger.Error("failed to cancel duplicate subscription: ", cancelErr)
+		}
+		logger.Info("user is subscribed already")
+		return nil, ErrSubscribeAlreadySubscribed
+	}
+
+	logger.Info("successfully subscribed", "subscriptionId", subscription.Id, "status", subscription.Status)
+	return subscription, nil
+}
+
+func (s *subscriptionService) GetMySubscription(ctx context.Context, userId string) (*entity.Subscription, error) {
+	subscription, err := s.storages.SubscriptionStorage.GetLiveSubscription(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if subscription == nil {
+		return nil, ErrGetMySubscriptionNotFound
+	}
+
+	return subscription, nil
+}
+
+func (s *subscriptionService) CancelMySubscription(ctx context.Context, userId string) (*entity.Subscription, error) {
+	logger := s.logger.
+		Named("CancelMySubscription").
+		WithContext(ctx).
+		With("userId", userId)
+
+	subscription, err := s.storages.SubscriptionStorage.GetLiveSubscription(ctx, userId)
+	if err != nil {
+		logger.Error("failed to get subscription: ", err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if subscription == nil {
+		logger.Info("subscription not found")
+		return nil, ErrCancelMySubscriptionNotFound
+	}
+	logger = logger.With("subscriptionId", subscription.Id)
+
+	err = s.payment.CancelSubscription(ctx, subscription.ProviderSubscriptionId)
+	if err != nil {
+		logger.Error("failed to cancel subscription at provider: ", err)
+		return nil, fmt.Errorf("failed to cancel subscription at provider: %w", err)
+	}
+
+	// status becomes canceled once provider reports end of subscription with webhook
+	subscription, err = s.storages.SubscriptionStorage.CancelAtPeriodEnd(ctx, subscription.Id)
+	if err != nil {
+		logger.Error("failed to cancel subscription: ", err)
+		return nil, fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+	if subscription == nil {
+		logger.Info("subscription is canceled already")
+		return nil, ErrCancelMySubscriptionNotFound
+	}
+
+	logger.Info("successfully canceled subscription")
+	return subscription, nil
+}
+
+func (s *subscriptionService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	logger := s.logger.
+		Named("HandleWebhook").
+		WithContext(ctx)
+
+	event, err := s.payment.ParseEvent(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			logger.Info("webhook signature is invalid")
+			return ErrHandleWebhookInvalidSignature
+		}
+		logger.Error("failed to parse event: ", err)
+		return fmt.Errorf("failed to parse event: %w", err)
+	}
+	logger = logger.With("eventId", event.Id, "type", event.Type, "providerSubscriptionId", event.SubscriptionId)
+
+	update := &storage.SubscriptionEvent{
+		Id:                     event.Id,
+		Type:                   event.Type,
+		ProviderSubscriptionId: event.SubscriptionId,
+	}
+	if event.SubscriptionId == "" {
+		logger.Info("event isn't about subscription")
+		return nil
+	}
+
+	var entries []*entity.LedgerEntry
+	switch event.Type {
+	case payment.EventInvoicePaid:
+		update.Status = entity.SubscriptionActive
+		update.CurrentPeriodEnd = &event.CurrentPeriodEnd
+		entries = subscriptionEntries(event.Amount, s.config.Payment.Currency)
+	case payment.EventInvoicePaymentFailed:
+		update.Status = entity.SubscriptionPastDue
+	case payment.EventSubscriptionCanceled:
+		update.Status = entity.SubscriptionCanceled
+	default:
+		logger.Info("event type is ignored")
+		return nil
+	}
+
+	// provider may report subscription before Subscribe stores it, failing the webhook makes provider
+	// redeliver event instead of losing it
+	known, err := s.storages.SubscriptionStorage.GetProviderSubscription(ctx, event.SubscriptionId)
+	if err != nil {
+		logger.Error("failed to get subscription: ", err)
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if known == nil {
+		logger.Warn("subscription is unknown yet")
+		return fmt.Errorf("subscription %s is unknown", event.SubscriptionId)
+	}
+
+	subscription, err := s.storages.SubscriptionStorage.ApplyEvent(ctx, update, entries)
+	if err != nil {
+		logger.Error("failed to apply event: ", err)
+		return fmt.Errorf("failed to apply event: %w", err)
+	}
+	if subscription == nil {
+		logger.Info("event is processed already or subscription is canceled")
+		return nil
+	}
+
+	logger.Info("successfully applied event", "subscriptionId", subscription.Id, "status", subscription.Status)
+	return nil
+}
+
+// subscriptionEntries returns transaction of paid subscription period, all of it is platform revenue.
+func subscriptionEntries(amount int64, currency string) []*entity.LedgerEntry {
+	if amount <= 0 {
+		return nil
+	}
+
+	transaction := uuid.NewString()
+	entries := []*entity.LedgerEntry{
+		{Account: entity.AccountPlatformCash, Amount: amount},
+		{Account: entity.AccountPlatformRevenue, Amount: -amount},
+	}
+	for _, entry := range entries {
+		entry.TransactionId = transaction
+		entry.Kind = entity.LedgerSubscription
+		entry.Currency = currency
+	}
+	return entries
+}
+
+// hasCourseAccess reports whether user is enrolled in course or has subscription giving access to every course.
+func hasCourseAccess(ctx context.Context, storages *storage.Storages, userId, courseId string) (bool, error) {
+	enrollment, err := storages.EnrollmentStorage.GetEnrollment(ctx, userId, courseId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	if enrollment != nil {
+		return true, nil
+	}
+
+	subscription, err := storages.SubscriptionStorage.GetLiveSubscription(ctx, userId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return subscription != nil && subscription.HasAccess(time.Now()), nil
+}
+
+func (c *CreatePlanOptions) Validate() error {
+	name := strings.TrimSpace(c.Name)
+	if name == "" || utf8.RuneCountInString(name) > _maxPlanNameLength {
+		return errs.New(fmt.Sprintf("Name is required and can't be longer than %d characters.", _maxPlanNameLength), "invalid_plan_name")
+	}
+	if c.Price <= 0 {
+		return errs.New("Price must be positive.", "invalid_price")
+	}
+	if c.TrialDays < 0 || c.TrialDays > _maxPlanTrialDays {
+		return errs.New(fmt.Sprintf("Trial days must be from 0 to %d.", _maxPlanTrialDays), "invalid_trial_days")
+	}
+	return nil
+}
+
+func (s *SubscribeOptions) Validate() error {
+	if _, err := uuid.Parse(s.PlanId); err != nil {
+		return errs.New("Plan id is invalid.", "invalid_plan_id")
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/subtitle"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// _maxSubtitleLabelLength - limit of subtitle track label in characters.
+	_maxSubtitleLabelLength = 100
+	// _maxSearchQueryLength - limit of transcript search query in characters.
+	_maxSearchQueryLength = 200
+)
+
+type subtitleService struct {
+	serviceContext
+}
+
+var _ SubtitleService = (*subtitleService)(nil)
+
+func NewSubtitleService(options *Options) SubtitleService {
+	return &subtitleService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("SubtitleService"),
+		},
+	}
+}
+
+func (s *subtitleService) UploadSubtitle(ctx context.Context, options *UploadSubtitleOptions) (*entity.LessonSubtitle, error) {
+	logger := s.logger.
+		Named("UploadSubtitle").
+		WithContext(ctx).
+		With("userId", options.UserId, "lessonId", options.LessonId, "language", options.Language)
+
+	if int64(len(options.Content)) > s.config.Subtitle.MaxFileSize {
+		logger.Info("file is too large", "size", len(options.Content))
+		return nil, ErrUploadSubtitleFileTooLarge
+	}
+
+	lesson, err := s.storages.CurriculumStorage.GetLesson(ctx, options.LessonId)
+	if err != nil {
+		logger.Error("failed to get lesson: ", err)
+		return nil, fmt.Errorf("failed to get lesson: %w", err)
+	}
+	if lesson == nil {
+		logger.Info("lesson not found")
+		return nil, ErrUploadSubtitleLessonNotFound
+	}
+	isTeacher, err := s.isCourseTeacher(ctx, options.UserId, lesson.CourseId)
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, err
+	}
+	if !isTeacher {
+		logger.Info("user is not teacher of the course")
+		return nil, ErrUploadSubtitleNotCourseTeacher
+	}
+
+	parsed, err := subtitle.Parse(options.Content)
+	if err != nil {
+		logger.Info("invalid subtitles", "err", err)
+		if syntaxErr, ok := err.(*subtitle.SyntaxError); ok {
+			return nil, ErrUploadSubtitleInvalid.WithDetails(map[string]string{
+				"line":   strconv.Itoa(syntaxErr.Line),
+				"reason": syntaxErr.Reason,
+			})
+		}
+		return nil, ErrUploadSubtitleInvalid
+	}
+
+	language := canonicalLanguageTag(options.Language)
+	label := strings.TrimSpace(options.Label)
+	if label == "" {
+		label = language
+	}
+	cues := make([]*entity.TranscriptCue, 0, len(parsed.Cues))
+	for _, cue := range parsed.Cues {
+		text := cue.PlainText()
+		if text == "" {
+			continue
+		}
+		cues = append(cues, &entity.TranscriptCue{
+			LessonId: lesson.Id,
+			Language: language,
+			CourseId: lesson.CourseId,
+			StartMs:  cue.Start.Milliseconds(),
+			EndMs:    cue.End.Milliseconds(),
+			Text:     text,
+		})
+	}
+
+	saved, err := s.storages.SubtitleStorage.SaveSubtitle(ctx, &entity.LessonSubtitle{
+		LessonId: lesson.Id,
+		Language: language,
+		CourseId: lesson.CourseId,
+		Label:    label,
+		Content:  parsed.VTT(),
+		CueCount: len(parsed.Cues),
+	}, cues)
+	if err != nil {
+		logger.Error("failed to save subtitles: ", err)
+		return nil, fmt.Errorf("failed to save subtitles: %w", err)
+	}
+
+	logger.Info("successfully uploaded subtitles", "cues", len(parsed.Cues))
+	return saved, nil
+}
+
+func (s *subtitleService) GetSubtitle(ctx context.Context, options *GetSubtitleOptions) (*entity.LessonSubtitle, error) {
+	logger := s.logger.
+		Named("GetSubtitle").
+		WithContext(ctx).
+		With("userId", options.UserId, "lessonId", options.LessonId, "language", options.Language)
+
+	lesson, err := s.storages.CurriculumStorage.GetLesson(ctx, options.LessonId)
+	if err != nil {
+		logger.Error("failed to get lesson: ", err)
+		return nil, fmt.Errorf("failed to get lesson: %w", err)
+	}
+	if lesson == nil {
+		logger.Info("lesson not found")
+		return nil, ErrGetSubtitleLessonNotFound
+	}
+
+	isTeacher, err := s.isCourseTeacher(ctx, options.UserId, lesson.CourseId)
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, err
+	}
+	if !isTeacher {
+		enrolled, err := hasCourseAccess(ctx, s.storages, options.UserId, lesson.CourseId)
+		if err != nil {
+			logger.Error("failed to check course access: ", err)
+			return nil, err
+		}
+		if !enrolled {
+			logger.Info("user is not enrolled")
+			return nil, ErrGetSubtitleNotEnrolled
+		}
+	}
+
+	found, err := s.storages.SubtitleStorage.GetSubtitle(ctx, lesson.Id, canonicalLanguageTag(options.Language))
+	if err != nil {
+		logger.Error("failed to get subtitles: ", err)
+		return nil, fmt.Errorf("failed to get subtitles: %w", err)
+	}
+	if found == nil {
+		logger.Info("subtitles not found")
+		return nil, ErrGetSubtitleNotFound
+	}
+
+	return found, nil
+}
+
+func (s *subtitleService) DeleteSubtitle(ctx context.Context, options *DeleteSubtitleOptions) error {
+	logger := s.logger.
+		Named("DeleteSubtitle").
+		WithContext(ctx).
+		With("userId", options.UserId, "lessonId", options.LessonId, "language", options.Language)
+
+	lesson, err := s.storages.CurriculumStorage.GetLesson(ctx, options.LessonId)
+	if err != nil {
+		logger.Error("failed to get lesson: ", err)
+		return fmt.Errorf("failed to get lesson: %w", err)
+	}
+	if lesson == nil {
+		logger.Info("lesson not found")
+		return ErrDeleteSubtitleLessonNotFound
+	}
+	isTeacher, err := s.isCourseTeacher(ctx, options.UserId, lesson.CourseId)
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return err
+	}
+	if !isTeacher {
+		logger.Info("user is not teacher of the course")
+		return ErrDeleteSubtitleNotCourseTeacher
+	}
+
+	deleted, err := s.storages.SubtitleStorage.DeleteSubtitle(ctx, lesson.Id, canonicalLanguageTag(options.Language))
+	if err != nil {
+		logger.Error("failed to delete subtitles: ", err)
+		return fmt.Errorf("failed to delete subtitles: %w", err)
+	}
+	if !deleted {
+		logger.Info("subtitles not found")
+		return ErrDeleteSubtitleNotFound
+	}
+
+	logger.Info("successfully deleted subtitles")
+	return nil
+}
+
+func (s *subtitleService) SearchTranscripts(ctx context.Context, options *SearchTranscriptsOptions) ([]*entity.TranscriptMatch, error) {
+	language := ""
+	if options.Language != "" {
+		language = canonicalLanguageTag(options.Language)
+	}
+
+	matches, err := s.storages.SubtitleStorage.SearchTranscripts(ctx, &storage.SearchTranscriptsFilter{
+		Query:    strings.TrimSpace(options.Query),
+		CourseId: options.CourseId,
+		Language: language,
+		Limit:    pageSize(options.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+
+	access := make(map[string]bool)
+	for _, match := range matches {
+		allowed, ok := access[match.CourseId]
+		if !ok {
+			allowed, err = s.canReadTranscript(ctx, options.UserId, match.CourseId)
+			if err != nil {
+				return nil, err
+			}
+			access[match.CourseId] = allowed
+		}
+		if !allowed {
+			match.Text = ""
+		}
+	}
+
+	return matches, nil
+}
+
+// canReadTranscript reports whether user has access to course or teaches it, anonymous users have no access.
+func (s *subtitleService) canReadTranscript(ctx context.Context, userId, courseId string) (bool, error) {
+	if userId == "" {
+		return false, nil
+	}
+	teacher, err := s.isCourseTeacher(ctx, userId, courseId)
+	if err != nil {
+		return false, err
+	}
+	if teacher {
+		return true, nil
+	}
+	return hasCourseAccess(ctx, s.storages, userId, courseId)
+}
+
+// isCourseTeacher reports whether user teaches course, missing course is an error as lessons can't outlive it.
+func (s *subtitleService) isCourseTeacher(ctx context.Context, userId, courseId string) (bool, error) {
+	course, err := s.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: courseId})
+	if err != nil || course == nil {
+		return false, fmt.Errorf("failed to get course: %w", err)
+	}
+	return course.TeacherId == userId, nil
+}
+
+func (u *UploadSubtitleOptions) Validate() error {
+	if u.Language == "" || !isLanguageTag(u.Language) {
+		return errs.New("Language must be language tag, e.g. en or pt-BR.", "invalid_language")
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(u.Label)) > _maxSubtitleLabelLength {
+		return errs.New(fmt.Sprintf("Label can't be longer than %d characters.", _maxSubtitleLabelLength), "invalid_label")
+	}
+	return nil
+}
+
+func (s *SearchTranscriptsOptions) Validate() error {
+	query := strings.TrimSpace(s.Query)
+	if query == "" || utf8.RuneCountInString(query) > _maxSearchQueryLength {
+		return errs.New(fmt.Sprintf("Query is required and can't be longer than %d characters.", _maxSearchQueryLength), "invalid_query")
+	}
+	if s.Language != "" && !isLanguageTag(s.Language) {
+		return errs.New("Language must be language tag, e.g. en or pt-BR.", "invalid_language")
+	}
+	return nil
+}
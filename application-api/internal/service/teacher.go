@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	_maxDisplayNameLength = 100
+	_maxBioLength         = 5000
+	_maxProfileURLLength  = 2048
+	_maxSocialLinks       = 10
+)
+
+var _socialNetworkRegexp = regexp.MustCompile(`^[a-z0-9_]{1,30}$`)
+
+type teacherService struct {
+	serviceContext
+}
+
+var _ TeacherService = (*teacherService)(nil)
+
+func NewTeacherService(options *Options) TeacherService {
+	return &teacherService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("TeacherService"),
+		},
+	}
+}
+
+func (t *teacherService) GetTeacher(ctx context.Context, teacherId string) (*GetTeacherOutput, error) {
+	logger := t.logger.
+		Named("GetTeacher").
+		WithContext(ctx).
+		With("teacherId", teacherId)
+
+	user, err := t.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: teacherId})
+	if err != nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Type != entity.Teacher {
+		logger.Info("teacher not found")
+		return nil, ErrGetTeacherNotFound
+	}
+
+	profile, err := t.getProfile(ctx, user)
+	if err != nil {
+		logger.Error("failed to get profile: ", err)
+		return nil, err
+	}
+
+	courses, err := t.storages.CourseStorage.GetListByTeacherId(ctx, user.Id)
+	if err != nil {
+		logger.Error("failed to get courses: ", err)
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+	published := make([]*entity.Course, 0, len(courses))
+	for _, course := range courses {
+		if course.Published {
+			published = append(published, course)
+		}
+	}
+
+	stats, err := t.storages.TeacherStorage.GetStats(ctx, user.Id)
+	if err != nil {
+		logger.Error("failed to get stats: ", err)
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	return &GetTeacherOutput{Profile: profile, Courses: published, Stats: stats}, nil
+}
+
+func (t *teacherService) GetMyProfile(ctx context.Context, userId string) (*entity.TeacherProfile, error) {
+	user, err := t.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Teacher {
+		return nil, ErrGetMyTeacherProfileNotTeacher
+	}
+
+	return t.getProfile(ctx, user)
+}
+
+func (t *teacherService) UpdateMyProfile(ctx context.Context, options *UpdateTeacherProfileOptions) (*entity.TeacherProfile, error) {
+	logger := t.logger.
+		Named("UpdateMyProfile").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	user, err := t.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: options.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Type != entity.Teacher {
+		logger.Info("user is not teacher", "type", user.Type)
+		return nil, ErrUpdateTeacherProfileNotTeacher
+	}
+
+	profile, err := t.storages.TeacherStorage.SaveProfile(ctx, &entity.TeacherProfile{
+		UserId:      user.Id,
+		DisplayName: strings.TrimSpace(options.DisplayName),
+		Bio:         strings.TrimSpace(options.Bio),
+		AvatarURL:   options.AvatarURL,
+		SocialLinks: options.SocialLinks,
+	})
+	if err != nil {
+		logger.Error("failed to save profile: ", err)
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	if profile == nil {
+		logger.Info("display name is taken", "displayName", options.DisplayName)
+		return nil, ErrUpdateTeacherProfileDisplayNameTaken
+	}
+
+	logger.Info("successfully updated profile")
+	return profile, nil
+}
+
+// getProfile returns profile of teacher, teachers who haven't created one are shown by username.
+func (t *teacherService) getProfile(ctx context.Context, user *entity.User) (*entity.TeacherProfile, error) {
+	profile, err := t.storages.TeacherStorage.GetProfile(ctx, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if profile == nil {
+		profile = &entity.TeacherProfile{UserId: user.Id, DisplayName: user.Username, SocialLinks: entity.SocialLinks{}}
+	}
+
+	return profile, nil
+}
+
+// isProfileURL reports whether value is absolute http or https URL.
+func isProfileURL(value string) bool {
+	if len(value) > _maxProfileURLLength {
+		return false
+	}
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
+
+func (u *UpdateTeacherProfileOptions) Validate() error {
+	name := strings.TrimSpace(u.DisplayName)
+	if name == "" || utf8.RuneCountInString(name) > _maxDisplayNameLength {
+		return errs.New(fmt.Sprintf("Display name is required and can't be longer than %d characters.", _maxDisplayNameLength), "invalid_display_name")
+	}
+	if utf8.RuneCountInString(u.Bio) > _maxBioLength {
+		return errs.New(fmt.Sprintf("Bio can't be longer than %d characters.", _maxBioLength), "invalid_bio")
+	}
+	if u.AvatarURL != "" && !isProfileURL(u.AvatarURL) {
+		return errs.New("Avatar URL must be http or https URL.", "invalid_avatar_url")
+	}
+	if len(u.SocialLinks) > _maxSocialLinks {
+		return errs.New(fmt.Sprintf("No more than %d social links are allowed.", _maxSocialLinks), "too_many_social_links")
+	}
+	for network, link := range u.SocialLinks {
+		if !_socialNetworkRegexp.MatchString(network) || !isProfileURL(link) {
+			return errs.New("Social links must be http or https URLs by lowercase network name, e.g. youtube.", "invalid_social_links")
+		}
+	}
+	return nil
+}
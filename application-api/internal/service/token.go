@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"time"
+)
+
+// issueOneTimeToken replaces previous user tokens of the same purpose with a new one.
+// Plain token is returned to be sent to user, only its hash is stored.
+func (s serviceContext) issueOneTimeToken(ctx context.Context, user *entity.User, purpose string, ttl time.Duration) (string, error) {
+	err := s.storages.TokenStorage.DeleteUserTokens(ctx, user.Id, purpose)
+	if err != nil {
+		return "", fmt.Errorf("failed to delete previous tokens: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	_, err = s.storages.TokenStorage.CreateToken(ctx, &entity.OneTimeToken{
+		UserId:    user.Id,
+		Purpose:   purpose,
+		TokenHash: hashOneTimeToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return token, nil
+}
+
+// useOneTimeToken marks token as used and returns it, or nil if token is unknown, expired or used.
+func (s serviceContext) useOneTimeToken(ctx context.Context, token, purpose string) (*entity.OneTimeToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	stored, err := s.storages.TokenStorage.GetToken(ctx, &storage.GetTokenFilter{TokenHash: hashOneTimeToken(token), Purpose: purpose})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if stored == nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, nil
+	}
+
+	used, err := s.storages.TokenStorage.UseToken(ctx, stored.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark token as used: %w", err)
+	}
+	if !used {
+		return nil, nil
+	}
+
+	return stored, nil
+}
+
+func hashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
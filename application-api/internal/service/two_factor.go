@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/auth"
+	"github.com/vovk404/course-platform/application-api/pkg/totp"
+	"strings"
+	"time"
+)
+
+const (
+	// _totpSkew - number of periods before and after current one accepted to tolerate clock drift.
+	_totpSkew = 1
+)
+
+type twoFactorService struct {
+	serviceContext
+	auth auth.Authenticator
+}
+
+var _ TwoFactorService = (*twoFactorService)(nil)
+
+func NewTwoFactorService(options *Options) TwoFactorService {
+	return &twoFactorService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("TwoFactorService"),
+		},
+		auth: options.Auth,
+	}
+}
+
+func (t *twoFactorService) Enroll(ctx context.Context, userId string) (*EnrollTwoFactorOutput, error) {
+	logger := t.logger.
+		Named("Enroll").
+		WithContext(ctx).
+		With("userId", userId)
+
+	user, err := t.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: userId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	twoFactor, err := t.storages.TwoFactorStorage.GetTwoFactor(ctx, userId)
+	if err != nil {
+		logger.Error("failed to get two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		logger.Info("two-factor authentication already enabled")
+		return nil, ErrEnrollTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Error("failed to generate secret: ", err)
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	_, err = t.storages.TwoFactorStorage.SaveTwoFactor(ctx, &entity.TwoFactor{UserId: userId, Secret: secret})
+	if err != nil {
+		logger.Error("failed to save two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	logger.Info("successfully enrolled two-factor authentication")
+	return &EnrollTwoFactorOutput{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, t.config.TwoFactor.Issuer, user.Email),
+	}, nil
+}
+
+func (t *twoFactorService) Enable(ctx context.Context, options *EnableTwoFactorOptions) (*EnableTwoFactorOutput, error) {
+	logger := t.logger.
+		Named("Enable").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	twoFactor, err := t.storages.TwoFactorStorage.GetTwoFactor(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if twoFactor == nil {
+		logger.Info("two-factor authentication is not enrolled")
+		return nil, ErrEnableTwoFactorNotEnrolled
+	}
+	if twoFactor.Enabled {
+		logger.Info("two-factor authentication already enabled")
+		return nil, ErrEnableTwoFactorAlreadyEnabled
+	}
+
+	ok, err := t.checkSecondFactor(ctx, twoFactor, options.Code, "")
+	if err != nil {
+		logger.Error("failed to check code: ", err)
+		return nil, fmt.Errorf("failed to check code: %w", err)
+	}
+	if !ok {
+		logger.Info("invalid code")
+		return nil, ErrEnableTwoFactorInvalidCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(t.config.TwoFactor.RecoveryCodes)
+	if err != nil {
+		logger.Error("failed to generate recovery codes: ", err)
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	err = t.storages.TwoFactorStorage.ReplaceRecoveryCodes(ctx, options.UserId, hashes)
+	if err != nil {
+		logger.Error("failed to save recovery codes: ", err)
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	// re-read, matched step was stored by checkSecondFactor
+	twoFactor, err = t.storages.TwoFactorStorage.GetTwoFactor(ctx, options.UserId)
+	if err != nil || twoFactor == nil {
+		logger.Error("failed to get two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+
+	now := time.Now()
+	twoFactor.Enabled = true
+	twoFactor.EnabledAt = &now
+	_, err = t.storages.TwoFactorStorage.SaveTwoFactor(ctx, twoFactor)
+	if err != nil {
+		logger.Error("failed to save two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	logger.Info("successfully enabled two-factor authentication")
+	return &EnableTwoFactorOutput{RecoveryCodes: codes}, nil
+}
+
+func (t *twoFactorService) Disable(ctx context.Context, options *DisableTwoFactorOptions) error {
+	logger := t.logger.
+		Named("Disable").
+		WithContext(ctx).
+		With("userId", options.UserId)
+
+	twoFactor, err := t.storages.TwoFactorStorage.GetTwoFactor(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to get two-factor settings: ", err)
+		return fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		logger.Info("two-factor authentication is not enabled")
+		return ErrDisableTwoFactorNotEnabled
+	}
+
+	ok, err := t.checkSecondFactor(ctx, twoFactor, options.Code, options.RecoveryCode)
+	if err != nil {
+		logger.Error("failed to check code: ", err)
+		return fmt.Errorf("failed to check code: %w", err)
+	}
+	if !ok {
+		logger.Info("invalid code")
+		return ErrDisableTwoFactorInvalidCode
+	}
+
+	err = t.storages.TwoFactorStorage.DeleteTwoFactor(ctx, options.UserId)
+	if err != nil {
+		logger.Error("failed to delete two-factor settings: ", err)
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+
+	logger.Info("successfully disabled two-factor authentication")
+	return nil
+}
+
+func (t *twoFactorService) Verify(ctx context.Context, options *VerifyTwoFactorOptions) (*SignInOutput, error) {
+	logger := t.logger.
+		Named("Verify").
+		WithContext(ctx)
+
+	// challenge is single-use, wrong code requires signing in with password again
+	challenge, err := t.useOneTimeToken(ctx, options.ChallengeToken, entity.TokenPurposeTwoFactorChallenge)
+	if err != nil {
+		logger.Error("failed to use challenge token: ", err)
+		return nil, fmt.Errorf("failed to use challenge token: %w", err)
+	}
+	if challenge == nil {
+		logger.Info("invalid challenge token")
+		return nil, ErrVerifyTwoFactorInvalidChallenge
+	}
+	logger = logger.With("userId", challenge.UserId)
+
+	twoFactor, err := t.storages.TwoFactorStorage.GetTwoFactor(ctx, challenge.UserId)
+	if err != nil {
+		logger.Error("failed to get two-factor settings: ", err)
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		logger.Info("two-factor authentication is not enabled")
+		return nil, ErrVerifyTwoFactorInvalidChallenge
+	}
+
+	ok, err := t.checkSecondFactor(ctx, twoFactor, options.Code, options.RecoveryCode)
+	if err != nil {
+		logger.Error("failed to check code: ", err)
+		return nil, fmt.Errorf("failed to check code: %w", err)
+	}
+	if !ok {
+		logger.Info("invalid code")
+		return nil, ErrVerifyTwoFactorInvalidCode
+	}
+
+	user, err := t.storages.UserStorage.GetUser(ctx, &storage.GetUserFilter{UserId: challenge.UserId})
+	if err != nil || user == nil {
+		logger.Error("failed to get user: ", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	accessToken, err := t.auth.GenerateToken(&auth.GenerateTokenClaimsOptions{UserName: user.Username, UserId: user.Id})
+	if err != nil {
+		logger.Error("failed to generate token for user: ", err)
+		return nil, fmt.Errorf("failed to generate token for user: %w", err)
+	}
+
+	logger.Info("successfully verified two-factor authentication")
+	return &SignInOutput{AccessToken: accessToken}, nil
+}
+
+// checkSecondFactor validates TOTP code, or recovery code when code is empty.
+// Both are single-use: matched TOTP step is remembered and recovery code is marked as used.
+func (t *twoFactorService) checkSecondFactor(ctx context.Context, twoFactor *entity.TwoFactor, code, recoveryCode string) (bool, error) {
+	if code != "" {
+		step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), _totpSkew)
+		if !ok {
+			return false, nil
+		}
+		return t.storages.TwoFactorStorage.UseTwoFactorStep(ctx, twoFactor.UserId, step)
+	}
+
+	if recoveryCode != "" {
+		return t.storages.TwoFactorStorage.UseRecoveryCode(ctx, twoFactor.UserId, hashOneTimeToken(normalizeRecoveryCode(recoveryCode)))
+	}
+
+	return false, nil
+}
+
+// generateRecoveryCodes returns codes to be shown to user and their hashes to be stored.
+func generateRecoveryCodes(count int) ([]string, []string, error) {
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		raw := make([]byte, 10)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// 16 base32 characters shown in groups of four, e.g. abcd-efgh-ijkl-mnop
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))
+		codes = append(codes, code[0:4]+"-"+code[4:8]+"-"+code[8:12]+"-"+code[12:16])
+		hashes = append(hashes, hashOneTimeToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/internal/storage"
+	"github.com/vovk404/course-platform/application-api/pkg/errs"
+	"github.com/vovk404/course-platform/application-api/pkg/mailer"
+	"strings"
+)
+
+type wishlistService struct {
+	serviceContext
+	mailer mailer.Mailer
+}
+
+var _ WishlistService = (*wishlistService)(nil)
+
+func NewWishlistService(options *Options) WishlistService {
+	return &wishlistService{
+		serviceContext: serviceContext{
+			storages: options.Storages,
+			config:   options.Config,
+			logger:   options.Logger.Named("WishlistService"),
+		},
+		mailer: options.Mailer,
+	}
+}
+
+func (w *wishlistService) GetWishlist(ctx context.Context, userId string) ([]*entity.WishlistItem, error) {
+	items, err := w.storages.WishlistStorage.GetWishlistItems(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (w *wishlistService) AddToWishlist(ctx context.Context, options *AddToWishlistOptions) ([]*entity.WishlistItem, error) {
+	logger := w.logger.
+		Named("AddToWishlist").
+		WithContext(ctx).
+		With("userId", options.UserId, "courseId", options.CourseId)
+
+	course, err := w.storages.CourseStorage.GetCourse(ctx, &storage.GetCourseFilter{Id: options.CourseId})
+	if err != nil {
+		logger.Error("failed to get course: ", err)
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil || !course.Published {
+		logger.Info("course not found")
+		return nil, ErrAddToWishlistCourseNotFound
+	}
+	if course.TeacherId == options.UserId {
+		logger.Info("user is teacher of the course")
+		return nil, ErrAddToWishlistOwnCourse
+	}
+
+	enrollment, err := w.storages.EnrollmentStorage.GetEnrollment(ctx, options.UserId, course.Id)
+	if err != nil {
+		logger.Error("failed to get enrollment: ", err)
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	if enrollment != nil {
+		logger.Info("course is owned already")
+		return nil, ErrAddToWishlistAlreadyOwned
+	}
+
+	err = w.storages.WishlistStorage.AddWishlistItem(ctx, &entity.WishlistItem{
+		UserId:   options.UserId,
+		CourseId: course.Id,
+	})
+	if err != nil {
+		logger.Error("failed to add wishlist item: ", err)
+		return nil, fmt.Errorf("failed to add wishlist item: %w", err)
+	}
+
+	logger.Info("successfully added course to wishlist")
+	return w.GetWishlist(ctx, options.UserId)
+}
+
+func (w *wishlistService) RemoveFromWishlist(ctx context.Context, options *RemoveFromWishlistOptions) ([]*entity.WishlistItem, error) {
+	err := w.storages.WishlistStorage.RemoveWishlistItem(ctx, options.UserId, options.CourseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove wishlist item: %w", err)
+	}
+
+	return w.GetWishlist(ctx, options.UserId)
+}
+
+func (w *wishlistService) NotifyPriceDrops(ctx context.Context) error {
+	logger := w.logger.
+		Named("NotifyPriceDrops").
+		WithContext(ctx)
+
+	changes, err := w.storages.CourseStorage.RecordPriceChanges(ctx)
+	if err != nil {
+		logger.Error("failed to record price changes: ", err)
+		return fmt.Errorf("failed to record price changes: %w", err)
+	}
+
+	for _, change := range changes {
+		// the first record of course has nothing to compare with
+		if change.OldPrice == nil || change.NewPrice >= *change.OldPrice || !change.Published {
+			continue
+		}
+		courseLogger := logger.With("courseId", change.CourseId, "oldPrice", *change.OldPrice, "newPrice", change.NewPrice)
+
+		users, err := w.storages.WishlistStorage.GetPriceDropRecipients(ctx, change.CourseId)
+		if err != nil {
+			// change is notified by the next check, other courses are notified meanwhile
+			courseLogger.Error("failed to get recipients: ", err)
+			err = w.storages.CourseStorage.ReleasePriceChange(ctx, change.Id)
+			if err != nil {
+				courseLogger.Error("failed to release price change: ", err)
+			}
+			continue
+		}
+
+		sent := 0
+		for _, user := range users {
+			// one failed email shouldn't keep the rest of users from being notified
+			err = w.sendPriceDropEmail(ctx, user, change)
+			if err != nil {
+				courseLogger.With("userId", user.Id).Error("failed to send price drop email: ", err)
+				continue
+			}
+			sent++
+		}
+
+		courseLogger.Info("successfully notified about price drop", "recipients", len(users), "sent", sent)
+	}
+
+	return nil
+}
+
+func (w *wishlistService) sendPriceDropEmail(ctx context.Context, user *entity.User, change *storage.PriceChange) error {
+	return w.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: fmt.Sprintf("%s is on sale", change.CourseName),
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n%s from your wishlist now costs %s instead of %s.\n\nYou can turn these emails off in your account settings.\n",
+			user.Username, change.CourseName,
+			formatPrice(change.NewPrice, w.config.Payment.Currency), formatPrice(*change.OldPrice, w.config.Payment.Currency),
+		),
+	})
+}
+
+// formatPrice formats price in minor units for humans, e.g. 1999 as 19.99 USD.
+func formatPrice(amount int64, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", amount/100, amount%100, strings.ToUpper(currency))
+}
+
+func (a *AddToWishlistOptions) Validate() error {
+	if _, err := uuid.Parse(a.CourseId); err != nil {
+		return errs.New("Course id is invalid.", "invalid_course_id")
+	}
+	return nil
+}
@@ -2,7 +2,6 @@ package storage
 
 import (
 	"context"
-	"github.com/a631807682/zerofield"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/pkg/database"
 	"gorm.io/gorm"
@@ -54,26 +53,44 @@ func (u *accountStorage) GetAccount(ctx context.Context, filter *GetAccountFilte
 	return &account, nil
 }
 
-func (u *accountStorage) UpdateAccount(ctx context.Context, account *entity.Account) (*entity.Account, error) {
-	err := u.DB.
-		Session(&gorm.Session{FullSaveAssociations: true}).
-		Scopes(zerofield.UpdateScopes()).
-		Where(&entity.Account{Id: account.Id}).
-		Updates(account).
-		Error
-	if err != nil {
-		return nil, err
-	}
+func (u *accountStorage) SaveAccount(ctx context.Context, account *entity.Account) error {
+	return u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if account.AccountSettings != nil {
+			err := tx.Save(account.AccountSettings).Error
+			if err != nil {
+				return err
+			}
+		}
+		if account.AccountDevices == nil {
+			return nil
+		}
 
-	var updatedAccount entity.Account
-	err = u.DB.
-		Preload(clause.Associations).
-		WithContext(ctx).
-		Take(&updatedAccount, "id = ?", account.Id).
-		Error
-	if err != nil {
-		return nil, err
-	}
+		var kept []string
+		for _, device := range account.AccountDevices {
+			if device.Id != "" {
+				kept = append(kept, device.Id)
+			}
+		}
+		stmt := tx.Where("account_id = ?", account.Id)
+		if len(kept) > 0 {
+			stmt = stmt.Where("id NOT IN ?", kept)
+		}
+		err := stmt.Delete(&entity.AccountDevices{}).Error
+		if err != nil {
+			return err
+		}
 
-	return &updatedAccount, nil
+		for i := range account.AccountDevices {
+			device := &account.AccountDevices[i]
+			if device.Id == "" {
+				err = tx.Create(device).Error
+			} else {
+				err = tx.Save(device).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+type apiKeyStorage struct {
+	*database.PostgreSQL
+}
+
+var _ APIKeyStorage = (*apiKeyStorage)(nil)
+
+func NewAPIKeyStorage(postgresql *database.PostgreSQL) APIKeyStorage {
+	return &apiKeyStorage{postgresql}
+}
+
+func (a *apiKeyStorage) CreateAPIKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	err := a.DB.WithContext(ctx).Create(key).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (a *apiKeyStorage) GetAPIKey(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := a.DB.
+		WithContext(ctx).
+		Where(entity.APIKey{KeyHash: keyHash}).
+		First(&key).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (a *apiKeyStorage) GetAPIKeys(ctx context.Context, userId string) ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	err := a.DB.
+		WithContext(ctx).
+		Where(entity.APIKey{UserId: userId}).
+		Order("created_at DESC").
+		Find(&keys).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (a *apiKeyStorage) RevokeAPIKey(ctx context.Context, userId, keyId string) (bool, error) {
+	result := a.DB.
+		WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyId, userId).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (a *apiKeyStorage) TouchAPIKey(ctx context.Context, keyId string, interval time.Duration) error {
+	// skipping recent updates keeps frequent requests from writing on every call
+	now := time.Now()
+	return a.DB.
+		WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyId, now.Add(-interval)).
+		Update("last_used_at", now).
+		Error
+}
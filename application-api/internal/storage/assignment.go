@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type assignmentStorage struct {
+	*database.PostgreSQL
+}
+
+var _ AssignmentStorage = (*assignmentStorage)(nil)
+
+func NewAssignmentStorage(postgresql *database.PostgreSQL) AssignmentStorage {
+	return &assignmentStorage{postgresql}
+}
+
+func (a *assignmentStorage) CreateAssignment(ctx context.Context, assignment *entity.Assignment) (*entity.Assignment, error) {
+	err := a.DB.WithContext(ctx).Create(assignment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+func (a *assignmentStorage) GetAssignment(ctx context.Context, assignmentId string) (*entity.Assignment, error) {
+	var assignment entity.Assignment
+	err := a.DB.
+		WithContext(ctx).
+		Where(entity.Assignment{Id: assignmentId}).
+		First(&assignment).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+func (a *assignmentStorage) GetAssignments(ctx context.Context, courseId string) ([]*entity.Assignment, error) {
+	var assignments []*entity.Assignment
+	err := a.DB.
+		WithContext(ctx).
+		Where(entity.Assignment{CourseId: courseId}).
+		Order("due_at, created_at").
+		Find(&assignments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+func (a *assignmentStorage) SaveSubmission(ctx context.Context, submission *entity.Submission) (*entity.Submission, error) {
+	// graded submission is final, the condition keeps it when student uploads again
+	var saved []*entity.Submission
+	err := a.DB.
+		WithContext(ctx).
+		Raw(`INSERT INTO submissions (assignment_id, user_id, course_id, file_name, content_type, size, blob_key, comment, submitted_at, days_late)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (assignment_id, user_id) DO UPDATE SET
+				file_name = excluded.file_name,
+				content_type = excluded.content_type,
+				size = excluded.size,
+				blob_key = excluded.blob_key,
+				comment = excluded.comment,
+				submitted_at = excluded.submitted_at,
+				days_late = excluded.days_late
+			WHERE submissions.status = ?
+			RETURNING *`,
+			submission.AssignmentId, submission.UserId, submission.CourseId, submission.FileName, submission.ContentType,
+			submission.Size, submission.BlobKey, submission.Comment, submission.SubmittedAt, submission.DaysLate,
+			entity.SubmissionSubmitted,
+		).
+		Scan(&saved).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if len(saved) == 0 {
+		return nil, nil
+	}
+
+	return saved[0], nil
+}
+
+func (a *assignmentStorage) GetSubmission(ctx context.Context, filter *GetSubmissionFilter) (*entity.Submission, error) {
+	stmt := a.DB.WithContext(ctx)
+
+	if filter.Id != "" {
+		stmt = stmt.Where(entity.Submission{Id: filter.Id})
+	}
+
+	if filter.AssignmentId != "" {
+		stmt = stmt.Where(entity.Submission{AssignmentId: filter.AssignmentId})
+	}
+
+	if filter.UserId != "" {
+		stmt = stmt.Where(entity.Submission{UserId: filter.UserId})
+	}
+
+	var submission entity.Submission
+	err := stmt.First(&submission).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &submission, nil
+}
+
+func (a *assignmentStorage) GradeSubmission(ctx context.Context, submission *entity.Submission) (*entity.Submission, error) {
+	err := a.DB.
+		WithContext(ctx).
+		Model(submission).
+		Select("status", "score", "final_score", "feedback", "graded_by", "graded_at").
+		Updates(submission).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return submission, nil
+}
+
+func (a *assignmentStorage) GetGradingQueue(ctx context.Context, teacherId string) ([]*entity.GradingQueueItem, error) {
+	var queue []*entity.GradingQueueItem
+	err := a.DB.
+		WithContext(ctx).
+		Raw(`SELECT s.*, a.title AS assignment_title, a.max_score, a.due_at, c.name AS course_name, u.username
+			FROM submissions s
+			JOIN courses c ON c.id = s.course_id
+			JOIN assignments a ON a.id = s.assignment_id
+			JOIN users u ON u.id = s.user_id
+			WHERE c.teacher_id = ? AND s.status = ?
+			ORDER BY s.submitted_at`,
+			teacherId, entity.SubmissionSubmitted,
+		).
+		Scan(&queue).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type bundleStorage struct {
+	*database.PostgreSQL
+}
+
+var _ BundleStorage = (*bundleStorage)(nil)
+
+func NewBundleStorage(postgresql *database.PostgreSQL) BundleStorage {
+	return &bundleStorage{postgresql}
+}
+
+func (b *bundleStorage) CreateBundle(ctx context.Context, bundle *entity.Bundle) (*entity.Bundle, error) {
+	// courses exist already, only bundle and its links are inserted
+	err := b.DB.
+		WithContext(ctx).
+		Omit("Courses.*").
+		Create(bundle).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (b *bundleStorage) GetBundle(ctx context.Context, bundleId string) (*entity.Bundle, error) {
+	var bundle entity.Bundle
+	err := b.DB.
+		WithContext(ctx).
+		Preload("Courses").
+		Where(entity.Bundle{Id: bundleId}).
+		First(&bundle).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+func (b *bundleStorage) GetBundles(ctx context.Context, filter *GetBundlesFilter) ([]*entity.Bundle, error) {
+	stmt := b.DB.WithContext(ctx).Preload("Courses")
+
+	if filter.TeacherId != "" {
+		stmt = stmt.Where(entity.Bundle{TeacherId: filter.TeacherId})
+	}
+
+	if filter.Published {
+		stmt = stmt.Where("published")
+	}
+
+	var bundles []*entity.Bundle
+	err := stmt.Order("created_at DESC").Find(&bundles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return bundles, nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm/clause"
+)
+
+type cartStorage struct {
+	*database.PostgreSQL
+}
+
+var _ CartStorage = (*cartStorage)(nil)
+
+func NewCartStorage(postgresql *database.PostgreSQL) CartStorage {
+	return &cartStorage{postgresql}
+}
+
+func (c *cartStorage) AddCartItem(ctx context.Context, item *entity.CartItem) error {
+	// adding course twice keeps price it was added with
+	return c.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(item).
+		Error
+}
+
+func (c *cartStorage) GetCartItems(ctx context.Context, userId string) ([]*entity.CartItem, error) {
+	var items []*entity.CartItem
+	err := c.DB.
+		WithContext(ctx).
+		Select("cart_items.*, c.name AS course_name").
+		Joins("JOIN courses c ON c.id = cart_items.course_id").
+		Where("cart_items.user_id = ?", userId).
+		Order("cart_items.added_at").
+		Find(&items).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *cartStorage) UpdateCartItemPrice(ctx context.Context, userId, courseId string, price int64) error {
+	return c.DB.
+		WithContext(ctx).
+		Model(&entity.CartItem{}).
+		Where("user_id = ? AND course_id = ?", userId, courseId).
+		Update("price", price).
+		Error
+}
+
+func (c *cartStorage) RemoveCartItems(ctx context.Context, userId string, courseIds []string) error {
+	if len(courseIds) == 0 {
+		return nil
+	}
+
+	return c.DB.
+		WithContext(ctx).
+		Where("user_id = ? AND course_id IN ?", userId, courseIds).
+		Delete(&entity.CartItem{}).
+		Error
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type certificateStorage struct {
+	*database.PostgreSQL
+}
+
+var _ CertificateStorage = (*certificateStorage)(nil)
+
+func NewCertificateStorage(postgresql *database.PostgreSQL) CertificateStorage {
+	return &certificateStorage{postgresql}
+}
+
+func (c *certificateStorage) CreateCertificate(ctx context.Context, certificate *entity.Certificate) (*entity.Certificate, error) {
+	// concurrent heartbeats may complete course twice, the first certificate is kept
+	err := c.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(certificate).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCertificate(ctx, &GetCertificateFilter{UserId: certificate.UserId, CourseId: certificate.CourseId})
+}
+
+func (c *certificateStorage) GetCertificate(ctx context.Context, filter *GetCertificateFilter) (*entity.Certificate, error) {
+	stmt := c.DB.WithContext(ctx)
+
+	if filter.Id != "" {
+		stmt = stmt.Where(entity.Certificate{Id: filter.Id})
+	}
+
+	if filter.UserId != "" {
+		stmt = stmt.Where(entity.Certificate{UserId: filter.UserId})
+	}
+
+	if filter.CourseId != "" {
+		stmt = stmt.Where(entity.Certificate{CourseId: filter.CourseId})
+	}
+
+	var certificate entity.Certificate
+	err := stmt.First(&certificate).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+func (c *certificateStorage) GetUserCertificates(ctx context.Context, userId string) ([]*entity.Certificate, error) {
+	var certificates []*entity.Certificate
+	err := c.DB.
+		WithContext(ctx).
+		Where(entity.Certificate{UserId: userId}).
+		Order("issued_at DESC").
+		Find(&certificates).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return certificates, nil
+}
+
+func (c *certificateStorage) RevokeCertificate(ctx context.Context, certificateId, reason string) (*entity.Certificate, error) {
+	// revoking again keeps the original revocation time and reason
+	err := c.DB.
+		WithContext(ctx).
+		Model(&entity.Certificate{}).
+		Where("id = ? AND revoked_at IS NULL", certificateId).
+		Updates(map[string]interface{}{"revoked_at": gorm.Expr("now()"), "revoked_reason": reason}).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCertificate(ctx, &GetCertificateFilter{Id: certificateId})
+}
@@ -2,10 +2,12 @@ package storage
 
 import (
 	"context"
+	"github.com/a631807682/zerofield"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/pkg/database"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"time"
 )
 
 type courseStorage struct {
@@ -43,6 +45,10 @@ func (u *courseStorage) GetCourse(ctx context.Context, filter *GetCourseFilter)
 		stmt = stmt.Where(entity.Course{Id: filter.Id})
 	}
 
+	if filter.TeacherId != "" {
+		stmt = stmt.Where(entity.Course{TeacherId: filter.TeacherId})
+	}
+
 	var course entity.Course
 	err := stmt.
 		WithContext(ctx).
@@ -81,7 +87,7 @@ func (u *courseStorage) GetList() ([]*entity.Course, error) {
 	stmt := u.DB.Preload(clause.Associations)
 	var courses []*entity.Course
 
-	stmt = stmt.Where(entity.Course{})
+	stmt = stmt.Where(entity.Course{Published: true})
 
 	err := stmt.Find(&courses).Error
 
@@ -94,3 +100,188 @@ func (u *courseStorage) GetList() ([]*entity.Course, error) {
 
 	return courses, nil
 }
+
+func (u *courseStorage) GetAll(ctx context.Context) ([]*entity.Course, error) {
+	var courses []*entity.Course
+	err := u.DB.
+		WithContext(ctx).
+		Order("name").
+		Find(&courses).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+func (u *courseStorage) UpdateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error) {
+	err := u.DB.
+		WithContext(ctx).
+		Scopes(zerofield.UpdateScopes()).
+		Where(&entity.Course{Id: course.Id}).
+		Updates(course).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (u *courseStorage) SaveCourses(ctx context.Context, courses []*entity.Course) error {
+	if len(courses) == 0 {
+		return nil
+	}
+
+	return u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(courses).
+			Error
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(courses))
+		for _, course := range courses {
+			ids = append(ids, course.Id)
+		}
+
+		// author follows display name of teacher, or username until teacher creates profile
+		return tx.
+			Exec(`UPDATE courses c
+				SET author = COALESCE(p.display_name, t.username)
+				FROM users t
+				LEFT JOIN teacher_profiles p ON p.user_id = t.id
+				WHERE t.id = c.teacher_id AND c.id IN ?`, ids).
+			Error
+	})
+}
+
+func (u *courseStorage) GetCourses(ctx context.Context, ids []string) ([]*entity.Course, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var courses []*entity.Course
+	err := u.DB.
+		WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&courses).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+func (u *courseStorage) RecordPriceChanges(ctx context.Context) ([]*PriceChange, error) {
+	var changes []*PriceChange
+	err := u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// replicas checking prices at once would record and report every change twice
+		err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('course_prices'))").Error
+		if err != nil {
+			return err
+		}
+
+		var current []*PriceChange
+		err = tx.
+			Raw(`SELECT c.id AS course_id, round(c.price::numeric * 100)::bigint AS new_price
+				FROM courses c
+				LEFT JOIN LATERAL (
+					SELECT p.price FROM course_prices p WHERE p.course_id = c.id ORDER BY p.recorded_at DESC LIMIT 1
+				) last ON true
+				WHERE last.price IS DISTINCT FROM round(c.price::numeric * 100)::bigint`).
+			Scan(&current).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(current) > 0 {
+			now := time.Now()
+			prices := make([]*entity.CoursePrice, 0, len(current))
+			for _, change := range current {
+				prices = append(prices, &entity.CoursePrice{CourseId: change.CourseId, Price: change.NewPrice, RecordedAt: now})
+			}
+			err = tx.Create(prices).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// changes recorded now and ones released by failed checks are taken for notification at once
+		return tx.
+			Raw(`WITH taken AS (
+					UPDATE course_prices SET notified_at = now() WHERE notified_at IS NULL
+					RETURNING id, course_id, price, recorded_at
+				)
+				SELECT t.id, t.course_id, c.name AS course_name, c.published, previous.price AS old_price, t.price AS new_price
+				FROM taken t
+				JOIN courses c ON c.id = t.course_id
+				LEFT JOIN LATERAL (
+					SELECT p.price FROM course_prices p
+					WHERE p.course_id = t.course_id AND p.recorded_at < t.recorded_at
+					ORDER BY p.recorded_at DESC LIMIT 1
+				) previous ON true
+				ORDER BY t.recorded_at`).
+			Scan(&changes).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (u *courseStorage) ReleasePriceChange(ctx context.Context, id string) error {
+	return u.DB.
+		WithContext(ctx).
+		Model(&entity.CoursePrice{}).
+		Where("id = ?", id).
+		Update("notified_at", nil).
+		Error
+}
+
+func (u *courseStorage) SaveTranslation(ctx context.Context, translation *entity.CourseTranslation) (*entity.CourseTranslation, error) {
+	err := u.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(translation).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+func (u *courseStorage) GetTranslations(ctx context.Context, courseId string) ([]*entity.CourseTranslation, error) {
+	var translations []*entity.CourseTranslation
+	err := u.DB.
+		WithContext(ctx).
+		Where(entity.CourseTranslation{CourseId: courseId}).
+		Order("language").
+		Find(&translations).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func (u *courseStorage) DeleteTranslation(ctx context.Context, courseId, language string) (bool, error) {
+	result := u.DB.
+		WithContext(ctx).
+		Where("course_id = ? AND language = ?", courseId, language).
+		Delete(&entity.CourseTranslation{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type curriculumStorage struct {
+	*database.PostgreSQL
+}
+
+var _ CurriculumStorage = (*curriculumStorage)(nil)
+
+func NewCurriculumStorage(postgresql *database.PostgreSQL) CurriculumStorage {
+	return &curriculumStorage{postgresql}
+}
+
+func (c *curriculumStorage) CreateSection(ctx context.Context, section *entity.Section) (*entity.Section, error) {
+	err := c.DB.WithContext(ctx).Create(section).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return section, nil
+}
+
+func (c *curriculumStorage) GetSection(ctx context.Context, sectionId string) (*entity.Section, error) {
+	var section entity.Section
+	err := c.DB.
+		WithContext(ctx).
+		Where(entity.Section{Id: sectionId}).
+		First(&section).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &section, nil
+}
+
+func (c *curriculumStorage) GetSections(ctx context.Context, courseId string) ([]*entity.Section, error) {
+	var sections []*entity.Section
+	err := c.DB.
+		WithContext(ctx).
+		Where(entity.Section{CourseId: courseId}).
+		Order("position, created_at").
+		Find(&sections).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+func (c *curriculumStorage) CreateLesson(ctx context.Context, lesson *entity.Lesson) (*entity.Lesson, error) {
+	err := c.DB.WithContext(ctx).Create(lesson).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return lesson, nil
+}
+
+func (c *curriculumStorage) GetLesson(ctx context.Context, lessonId string) (*entity.Lesson, error) {
+	var lesson entity.Lesson
+	err := c.DB.
+		WithContext(ctx).
+		Where(entity.Lesson{Id: lessonId}).
+		First(&lesson).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &lesson, nil
+}
+
+func (c *curriculumStorage) GetLessons(ctx context.Context, courseId string) ([]*entity.Lesson, error) {
+	var lessons []*entity.Lesson
+	err := c.DB.
+		WithContext(ctx).
+		Where(entity.Lesson{CourseId: courseId}).
+		Order("position, created_at").
+		Find(&lessons).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return lessons, nil
+}
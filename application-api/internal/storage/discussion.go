@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type discussionStorage struct {
+	*database.PostgreSQL
+}
+
+var _ DiscussionStorage = (*discussionStorage)(nil)
+
+func NewDiscussionStorage(postgresql *database.PostgreSQL) DiscussionStorage {
+	return &discussionStorage{postgresql}
+}
+
+// _discussionTables - tables of vote and report targets.
+var _discussionTables = map[string]string{
+	entity.DiscussionThread: "discussion_threads",
+	entity.DiscussionPost:   "discussion_posts",
+}
+
+func (d *discussionStorage) CreateThread(ctx context.Context, thread *entity.Thread) (*entity.Thread, error) {
+	err := d.DB.WithContext(ctx).Create(thread).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+func (d *discussionStorage) GetThread(ctx context.Context, threadId string) (*entity.Thread, error) {
+	var thread entity.Thread
+	err := d.DB.
+		WithContext(ctx).
+		Where(entity.Thread{Id: threadId}).
+		First(&thread).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
+}
+
+func (d *discussionStorage) GetThreads(ctx context.Context, filter *GetThreadsFilter) ([]*entity.Thread, error) {
+	stmt := d.DB.WithContext(ctx).Where("course_id = ? AND hidden = false", filter.CourseId)
+
+	if filter.LessonId != "" {
+		stmt = stmt.Where("lesson_id = ?", filter.LessonId)
+	}
+
+	if filter.AfterId != "" {
+		stmt = stmt.Where("(last_activity_at, id) < (?, ?)", filter.AfterActivityAt, filter.AfterId)
+	}
+
+	var threads []*entity.Thread
+	err := stmt.
+		Order("last_activity_at DESC, id DESC").
+		Limit(filter.Limit).
+		Find(&threads).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return threads, nil
+}
+
+func (d *discussionStorage) CreatePost(ctx context.Context, post *entity.Post) (*entity.Post, error) {
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(post).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Thread{}).
+			Where(entity.Thread{Id: post.ThreadId}).
+			Updates(map[string]interface{}{
+				"post_count":       gorm.Expr("post_count + 1"),
+				"last_activity_at": post.CreatedAt,
+			}).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+func (d *discussionStorage) GetPost(ctx context.Context, postId string) (*entity.Post, error) {
+	var post entity.Post
+	err := d.DB.
+		WithContext(ctx).
+		Where(entity.Post{Id: postId}).
+		First(&post).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (d *discussionStorage) GetPosts(ctx context.Context, filter *GetPostsFilter) ([]*entity.Post, error) {
+	stmt := d.DB.WithContext(ctx).Where("thread_id = ? AND hidden = false", filter.ThreadId)
+
+	if filter.AfterId != "" {
+		stmt = stmt.Where("(created_at, id) > (?, ?)", filter.AfterCreatedAt, filter.AfterId)
+	}
+
+	var posts []*entity.Post
+	err := stmt.
+		Order("created_at, id").
+		Limit(filter.Limit).
+		Find(&posts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (d *discussionStorage) AcceptPost(ctx context.Context, threadId string, postId *string) (*entity.Thread, error) {
+	var thread entity.Thread
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Thread{}).
+			Where(entity.Thread{Id: threadId}).
+			Update("accepted_post_id", postId).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where(entity.Thread{Id: threadId}).First(&thread).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
+}
+
+func (d *discussionStorage) SetVote(ctx context.Context, userId, targetType, targetId string, up bool) (bool, error) {
+	changed := false
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		delta := 1
+		if up {
+			result = tx.Exec("INSERT INTO discussion_votes (user_id, target_id) VALUES (?, ?) ON CONFLICT DO NOTHING", userId, targetId)
+		} else {
+			result = tx.Exec("DELETE FROM discussion_votes WHERE user_id = ? AND target_id = ?", userId, targetId)
+			delta = -1
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		// counter is changed only by the request that changed the vote, so repeated clicks don't skew it
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		changed = true
+
+		return tx.Table(_discussionTables[targetType]).
+			Where("id = ?", targetId).
+			Update("upvotes", gorm.Expr("upvotes + ?", delta)).
+			Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return changed, nil
+}
+
+func (d *discussionStorage) CreateReport(ctx context.Context, report *entity.DiscussionReport) (*entity.DiscussionReport, error) {
+	result := d.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return report, nil
+}
+
+func (d *discussionStorage) GetReport(ctx context.Context, reportId string) (*entity.DiscussionReport, error) {
+	var report entity.DiscussionReport
+	err := d.DB.
+		WithContext(ctx).
+		Where(entity.DiscussionReport{Id: reportId}).
+		First(&report).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (d *discussionStorage) GetModerationQueue(ctx context.Context) ([]*entity.ModerationQueueItem, error) {
+	// one row per target, represented by its first open report
+	var queue []*entity.ModerationQueueItem
+	err := d.DB.
+		WithContext(ctx).
+		Raw(`SELECT * FROM (
+				SELECT DISTINCT ON (r.target_id) r.*,
+					COALESCE(t.title || E'\n\n' || t.body, p.body, '') AS body,
+					count(*) OVER (PARTITION BY r.target_id) AS report_count
+				FROM discussion_reports r
+				LEFT JOIN discussion_threads t ON r.target_type = ? AND t.id = r.target_id
+				LEFT JOIN discussion_posts p ON r.target_type = ? AND p.id = r.target_id
+				WHERE r.status = ?
+				ORDER BY r.target_id, r.created_at
+			) queue
+			ORDER BY created_at`,
+			entity.DiscussionThread, entity.DiscussionPost, entity.ReportOpen,
+		).
+		Scan(&queue).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+func (d *discussionStorage) ResolveReports(ctx context.Context, targetType, targetId, status, adminId string) error {
+	return d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if status == entity.ReportHidden {
+			err := hideDiscussionTarget(tx, targetType, targetId)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.DiscussionReport{}).
+			Where("target_id = ? AND status = ?", targetId, entity.ReportOpen).
+			Updates(map[string]interface{}{"status": status, "resolved_by": adminId, "resolved_at": time.Now()}).
+			Error
+	})
+}
+
+// hideDiscussionTarget hides thread or post, hidden posts don't count in thread replies.
+func hideDiscussionTarget(tx *gorm.DB, targetType, targetId string) error {
+	if targetType == entity.DiscussionThread {
+		return tx.Model(&entity.Thread{}).Where(entity.Thread{Id: targetId}).Update("hidden", true).Error
+	}
+
+	var post entity.Post
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(entity.Post{Id: targetId}).First(&post).Error
+	if err != nil {
+		return err
+	}
+	if post.Hidden {
+		return nil
+	}
+
+	err = tx.Model(&post).Update("hidden", true).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&entity.Thread{}).
+		Where(entity.Thread{Id: post.ThreadId}).
+		Update("post_count", gorm.Expr("post_count - 1")).
+		Error
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type enrollmentStorage struct {
+	*database.PostgreSQL
+}
+
+var _ EnrollmentStorage = (*enrollmentStorage)(nil)
+
+func NewEnrollmentStorage(postgresql *database.PostgreSQL) EnrollmentStorage {
+	return &enrollmentStorage{postgresql}
+}
+
+func (e *enrollmentStorage) CreateEnrollment(ctx context.Context, enrollment *entity.Enrollment) (*entity.Enrollment, error) {
+	// enrolling twice is not an error, existing enrollment is kept
+	err := e.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(enrollment).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return e.GetEnrollment(ctx, enrollment.UserId, enrollment.CourseId)
+}
+
+func (e *enrollmentStorage) GetEnrollment(ctx context.Context, userId, courseId string) (*entity.Enrollment, error) {
+	var enrollment entity.Enrollment
+	err := e.DB.
+		WithContext(ctx).
+		Where(entity.Enrollment{UserId: userId, CourseId: courseId}).
+		First(&enrollment).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
+
+func (e *enrollmentStorage) GetUserEnrollments(ctx context.Context, userId string) ([]*entity.Enrollment, error) {
+	var enrollments []*entity.Enrollment
+	err := e.DB.
+		WithContext(ctx).
+		Where(entity.Enrollment{UserId: userId}).
+		Order("created_at DESC").
+		Find(&enrollments).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollments, nil
+}
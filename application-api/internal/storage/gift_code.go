@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type giftCodeStorage struct {
+	*database.PostgreSQL
+}
+
+var _ GiftCodeStorage = (*giftCodeStorage)(nil)
+
+func NewGiftCodeStorage(postgresql *database.PostgreSQL) GiftCodeStorage {
+	return &giftCodeStorage{postgresql}
+}
+
+func (g *giftCodeStorage) CreateGiftCodes(ctx context.Context, codes []*entity.GiftCode) ([]*entity.GiftCode, error) {
+	err := g.DB.WithContext(ctx).Create(codes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (g *giftCodeStorage) GetGiftCode(ctx context.Context, code string) (*entity.GiftCode, error) {
+	var giftCode entity.GiftCode
+	err := g.DB.
+		WithContext(ctx).
+		Where(entity.GiftCode{Code: code}).
+		First(&giftCode).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &giftCode, nil
+}
+
+func (g *giftCodeStorage) GetGiftCodes(ctx context.Context, filter *GetGiftCodesFilter) ([]*entity.GiftCode, error) {
+	stmt := g.DB.WithContext(ctx)
+
+	if filter.CourseId != "" {
+		stmt = stmt.Where("course_id = ?", filter.CourseId)
+	}
+
+	if filter.BatchId != "" {
+		stmt = stmt.Where("batch_id = ?", filter.BatchId)
+	}
+
+	if filter.CreatedBy != "" {
+		stmt = stmt.Where("created_by = ?", filter.CreatedBy)
+	}
+
+	if filter.Gifts {
+		stmt = stmt.Where("order_id IS NOT NULL")
+	}
+
+	var codes []*entity.GiftCode
+	err := stmt.Order("created_at DESC, code").Find(&codes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (g *giftCodeStorage) RedeemGiftCode(ctx context.Context, codeId, userId string) (*entity.Enrollment, error) {
+	var enrollment *entity.Enrollment
+	err := g.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// conditional update takes row lock, so of concurrent redemptions only the first one changes the row
+		result := tx.Model(&entity.GiftCode{}).
+			Where("id = ? AND redeemed_by IS NULL", codeId).
+			Where("order_id IS NULL OR EXISTS (SELECT 1 FROM orders o WHERE o.id = gift_codes.order_id AND o.status = ?)", entity.OrderPaid).
+			Updates(map[string]interface{}{"redeemed_by": userId, "redeemed_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var code entity.GiftCode
+		err := tx.Where(entity.GiftCode{Id: codeId}).First(&code).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.Enrollment{UserId: userId, CourseId: code.CourseId}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		enrollment = &entity.Enrollment{}
+		return tx.Where(entity.Enrollment{UserId: userId, CourseId: code.CourseId}).First(enrollment).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
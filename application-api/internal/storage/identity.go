@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type identityStorage struct {
+	*database.PostgreSQL
+}
+
+var _ IdentityStorage = (*identityStorage)(nil)
+
+func NewIdentityStorage(postgresql *database.PostgreSQL) IdentityStorage {
+	return &identityStorage{postgresql}
+}
+
+func (i *identityStorage) GetIdentity(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	err := i.DB.
+		WithContext(ctx).
+		Where(entity.UserIdentity{Issuer: issuer, Subject: subject}).
+		First(&identity).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (i *identityStorage) CreateIdentity(ctx context.Context, identity *entity.UserIdentity) (*entity.UserIdentity, error) {
+	err := i.DB.WithContext(ctx).Create(identity).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (i *identityStorage) CreateOIDCState(ctx context.Context, state *entity.OIDCState) error {
+	return i.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// abandoned login attempts are cleaned up here instead of a background job
+		err := tx.Where("expires_at < ?", time.Now()).Delete(&entity.OIDCState{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(state).Error
+	})
+}
+
+func (i *identityStorage) TakeOIDCState(ctx context.Context, stateHash string) (*entity.OIDCState, error) {
+	// delete returning, so concurrent callbacks can't use the same state twice
+	var states []entity.OIDCState
+	err := i.DB.
+		WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where(entity.OIDCState{StateHash: stateHash}).
+		Delete(&states).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	return &states[0], nil
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ledgerStorage struct {
+	*database.PostgreSQL
+}
+
+var _ LedgerStorage = (*ledgerStorage)(nil)
+
+func NewLedgerStorage(postgresql *database.PostgreSQL) LedgerStorage {
+	return &ledgerStorage{postgresql}
+}
+
+// _earningsColumns - aggregates of teacher account entries, credits are negative, so sales are negated.
+const _earningsColumns = `
+	count(*) FILTER (WHERE e.kind = 'sale') AS sales,
+	COALESCE(-sum(e.amount) FILTER (WHERE e.kind = 'sale'), 0) AS gross,
+	COALESCE(sum(e.amount) FILTER (WHERE e.kind = 'fee'), 0) AS fees,
+	COALESCE(sum(e.amount) FILTER (WHERE e.kind = 'refund'), 0) AS refunds,
+	COALESCE(-sum(e.amount) FILTER (WHERE e.kind IN ('sale', 'fee', 'refund')), 0) AS net`
+
+func (l *ledgerStorage) GetEarnings(ctx context.Context, teacherId string) (*entity.Earnings, error) {
+	account := entity.TeacherAccount(teacherId)
+	earnings := &entity.Earnings{}
+	db := l.DB.WithContext(ctx)
+
+	err := db.
+		Raw(`SELECT `+_earningsColumns+` FROM ledger_entries e WHERE e.account = ?`, account).
+		Scan(&earnings.Totals).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.
+		Raw(`SELECT COALESCE(-sum(amount), 0) AS balance,
+				COALESCE(sum(amount) FILTER (WHERE kind = ?), 0) AS paid_out
+			FROM ledger_entries WHERE account = ?`,
+			entity.LedgerPayout, account,
+		).
+		Row().
+		Scan(&earnings.Balance, &earnings.PaidOut)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.
+		Model(&entity.Payout{}).
+		Select("COALESCE(sum(amount), 0)").
+		Where("teacher_id = ? AND status = ?", teacherId, entity.PayoutRequested).
+		Row().
+		Scan(&earnings.PendingPayouts)
+	if err != nil {
+		return nil, err
+	}
+	earnings.Available = earnings.Balance - earnings.PendingPayouts
+
+	err = db.
+		Raw(`SELECT e.course_id, c.name AS course_name,`+_earningsColumns+`
+			FROM ledger_entries e
+			JOIN courses c ON c.id = e.course_id
+			WHERE e.account = ?
+			GROUP BY e.course_id, c.name
+			ORDER BY gross DESC, c.name`,
+			account,
+		).
+		Scan(&earnings.Courses).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.
+		Raw(`SELECT to_char(date_trunc('month', e.created_at AT TIME ZONE 'UTC'), 'YYYY-MM') AS month,`+_earningsColumns+`
+			FROM ledger_entries e
+			WHERE e.account = ? AND e.kind <> ?
+			GROUP BY month
+			ORDER BY month DESC`,
+			account, entity.LedgerPayout,
+		).
+		Scan(&earnings.Months).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return earnings, nil
+}
+
+func (l *ledgerStorage) CreatePayout(ctx context.Context, payout *entity.Payout) (*entity.Payout, error) {
+	created := false
+	err := l.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// requests of the same teacher are serialized, so together they can't exceed the balance
+		err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", entity.TeacherAccount(payout.TeacherId)).Error
+		if err != nil {
+			return err
+		}
+
+		var available int64
+		err = tx.
+			Raw(`SELECT COALESCE((SELECT -sum(amount) FROM ledger_entries WHERE account = ?), 0)
+				- COALESCE((SELECT sum(amount) FROM payouts WHERE teacher_id = ? AND status = ?), 0)`,
+				entity.TeacherAccount(payout.TeacherId), payout.TeacherId, entity.PayoutRequested,
+			).
+			Row().
+			Scan(&available)
+		if err != nil {
+			return err
+		}
+		if payout.Amount > available {
+			return nil
+		}
+
+		created = true
+		return tx.Create(payout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+
+	return payout, nil
+}
+
+func (l *ledgerStorage) GetPayout(ctx context.Context, payoutId string) (*entity.Payout, error) {
+	var payout entity.Payout
+	err := l.DB.
+		WithContext(ctx).
+		Where(entity.Payout{Id: payoutId}).
+		First(&payout).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &payout, nil
+}
+
+func (l *ledgerStorage) GetPayouts(ctx context.Context, filter *GetPayoutsFilter) ([]*entity.Payout, error) {
+	stmt := l.DB.WithContext(ctx)
+
+	if filter.TeacherId != "" {
+		stmt = stmt.Where(entity.Payout{TeacherId: filter.TeacherId})
+	}
+
+	if filter.Status != "" {
+		stmt = stmt.Where(entity.Payout{Status: filter.Status})
+	}
+
+	var payouts []*entity.Payout
+	err := stmt.Order("requested_at DESC").Find(&payouts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
+
+func (l *ledgerStorage) ApprovePayout(ctx context.Context, payoutId, adminId string, entries []*entity.LedgerEntry) (*entity.Payout, error) {
+	var payout *entity.Payout
+	err := l.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		payout, err = resolvePayout(tx, payoutId, entity.PayoutApproved, adminId, "")
+		if err != nil || payout == nil {
+			return err
+		}
+
+		return tx.Create(entries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+func (l *ledgerStorage) RejectPayout(ctx context.Context, payoutId, adminId, note string) (*entity.Payout, error) {
+	var payout *entity.Payout
+	err := l.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		payout, err = resolvePayout(tx, payoutId, entity.PayoutRejected, adminId, note)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+// resolvePayout changes status of requested payout, nil is returned when it is resolved already.
+func resolvePayout(tx *gorm.DB, payoutId, status, adminId, note string) (*entity.Payout, error) {
+	result := tx.Model(&entity.Payout{}).
+		Where("id = ? AND status = ?", payoutId, entity.PayoutRequested).
+		Updates(map[string]interface{}{"status": status, "note": note, "resolved_by": adminId, "resolved_at": time.Now()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var payout entity.Payout
+	err := tx.Where(entity.Payout{Id: payoutId}).First(&payout).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &payout, nil
+}
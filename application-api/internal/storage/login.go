@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"time"
+)
+
+type loginStorage struct {
+	*database.PostgreSQL
+}
+
+var _ LoginStorage = (*loginStorage)(nil)
+
+func NewLoginStorage(postgresql *database.PostgreSQL) LoginStorage {
+	return &loginStorage{postgresql}
+}
+
+func (l *loginStorage) GetLoginFailures(ctx context.Context, keys []string) ([]*entity.LoginFailure, error) {
+	var failures []*entity.LoginFailure
+	err := l.DB.
+		WithContext(ctx).
+		Where("key IN ?", keys).
+		Find(&failures).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+func (l *loginStorage) RegisterLoginFailure(ctx context.Context, key string, window time.Duration) (*entity.LoginFailure, error) {
+	// single upsert, so concurrent attempts from several replicas are all counted
+	var failure entity.LoginFailure
+	err := l.DB.
+		WithContext(ctx).
+		Raw(`INSERT INTO login_failures (key, failures, last_failure_at)
+			VALUES (?, 1, now())
+			ON CONFLICT (key) DO UPDATE SET
+				failures = CASE
+					WHEN login_failures.last_failure_at < now() - make_interval(secs => ?) THEN 1
+					ELSE login_failures.failures + 1
+				END,
+				last_failure_at = now()
+			RETURNING *`, key, window.Seconds()).
+		Scan(&failure).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &failure, nil
+}
+
+func (l *loginStorage) LockLogin(ctx context.Context, key string, until time.Time) error {
+	return l.DB.
+		WithContext(ctx).
+		Model(&entity.LoginFailure{}).
+		Where("key = ?", key).
+		Update("locked_until", until).
+		Error
+}
+
+func (l *loginStorage) ResetLoginFailures(ctx context.Context, key string) error {
+	return l.DB.
+		WithContext(ctx).
+		Where("key = ?", key).
+		Delete(&entity.LoginFailure{}).
+		Error
+}
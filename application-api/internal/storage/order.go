@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type orderStorage struct {
+	*database.PostgreSQL
+}
+
+var _ OrderStorage = (*orderStorage)(nil)
+
+func NewOrderStorage(postgresql *database.PostgreSQL) OrderStorage {
+	return &orderStorage{postgresql}
+}
+
+func (o *orderStorage) CreateOrder(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	created := false
+	err := o.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// pending order claims its courses until it is paid or failed, user row lock serializes claims of the user,
+		// so concurrent purchases of the same course can't both reach the charge
+		if !order.Gift {
+			err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", order.UserId).Error
+			if err != nil {
+				return err
+			}
+
+			courseIds := make([]string, 0, len(order.Items))
+			for _, item := range order.Items {
+				courseIds = append(courseIds, item.CourseId)
+			}
+			var claimed bool
+			err = tx.
+				Raw(`SELECT EXISTS (SELECT 1 FROM enrollments WHERE user_id = ? AND course_id IN ?)
+					OR EXISTS (SELECT 1 FROM order_items i JOIN orders o ON o.id = i.order_id
+						WHERE o.user_id = ? AND o.status = ? AND NOT o.gift AND i.course_id IN ?)`,
+					order.UserId, courseIds, order.UserId, entity.OrderPending, courseIds).
+				Scan(&claimed).
+				Error
+			if err != nil {
+				return err
+			}
+			if claimed {
+				return nil
+			}
+		}
+
+		created = true
+		return tx.Create(order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+
+	return order, nil
+}
+
+func (o *orderStorage) GetOrder(ctx context.Context, orderId string) (*entity.Order, error) {
+	var order entity.Order
+	err := o.DB.
+		WithContext(ctx).
+		Preload("Items").
+		Where(entity.Order{Id: orderId}).
+		First(&order).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (o *orderStorage) GetUserOrders(ctx context.Context, userId string) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	err := o.DB.
+		WithContext(ctx).
+		Preload("Items").
+		Where(entity.Order{UserId: userId}).
+		Order("created_at DESC").
+		Find(&orders).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (o *orderStorage) PayOrder(ctx context.Context, orderId, chargeId string, entries []*entity.LedgerEntry) (*entity.Order, []*entity.Refund, error) {
+	var order *entity.Order
+	var duplicates []*entity.Refund
+	err := o.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// status condition makes sure that order is fulfilled once, even when payment is confirmed twice
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ?", orderId, entity.OrderPending).
+			Updates(map[string]interface{}{"status": entity.OrderPaid, "charge_id": chargeId, "paid_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		order = &entity.Order{}
+		err := tx.Preload("Items").Where(entity.Order{Id: orderId}).First(order).Error
+		if err != nil {
+			return err
+		}
+
+		// gift order is fulfilled by its gift codes, which become redeemable once order is paid
+		if !order.Gift {
+			for _, item := range order.Items {
+				result = tx.Clauses(clause.OnConflict{DoNothing: true}).
+					Create(&entity.Enrollment{UserId: order.UserId, CourseId: item.CourseId})
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected > 0 {
+					continue
+				}
+
+				// course was enrolled another way, e.g. by gift code, after order was created, its price is returned
+				duplicate := &entity.Refund{
+					OrderId:     order.Id,
+					OrderItemId: item.Id,
+					UserId:      order.UserId,
+					CourseId:    item.CourseId,
+					Amount:      item.Price,
+					Currency:    order.Currency,
+					Reason:      "course is owned already",
+					Status:      entity.RefundPending,
+					Duplicate:   true,
+				}
+				err = tx.Create(duplicate).Error
+				if err != nil {
+					return err
+				}
+				duplicates = append(duplicates, duplicate)
+			}
+		}
+
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Create(entries).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return order, duplicates, nil
+}
+
+func (o *orderStorage) FailOrder(ctx context.Context, orderId string) error {
+	return o.DB.
+		WithContext(ctx).
+		Model(&entity.Order{}).
+		Where("id = ? AND status = ?", orderId, entity.OrderPending).
+		Update("status", entity.OrderFailed).
+		Error
+}
+
+func (o *orderStorage) GetPendingOrders(ctx context.Context, createdBefore time.Time) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	err := o.DB.
+		WithContext(ctx).
+		Preload("Items").
+		Where("status = ? AND created_at < ?", entity.OrderPending, createdBefore).
+		Order("created_at").
+		Find(&orders).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (o *orderStorage) GetPaidOrderItem(ctx context.Context, userId, courseId string) (*entity.OrderItem, error) {
+	var item entity.OrderItem
+	err := o.DB.
+		WithContext(ctx).
+		Joins("JOIN orders o ON o.id = order_items.order_id").
+		Where("o.user_id = ? AND o.status = ? AND NOT o.gift AND order_items.course_id = ?", userId, entity.OrderPaid, courseId).
+		Where("NOT EXISTS (SELECT 1 FROM refunds r WHERE r.order_item_id = order_items.id AND r.status = ?)", entity.RefundCompleted).
+		Order("o.paid_at DESC").
+		First(&item).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
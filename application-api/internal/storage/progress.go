@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+)
+
+type progressStorage struct {
+	*database.PostgreSQL
+}
+
+var _ ProgressStorage = (*progressStorage)(nil)
+
+func NewProgressStorage(postgresql *database.PostgreSQL) ProgressStorage {
+	return &progressStorage{postgresql}
+}
+
+func (p *progressStorage) SaveLessonProgress(ctx context.Context, progress *entity.LessonProgress, limits *WatchLimits) (*entity.LessonProgress, bool, error) {
+	// single upsert per heartbeat, watched time never decreases, completed lessons stay completed and position
+	// is taken from the latest sent heartbeat, so repeated or reordered heartbeats give the same result.
+	// CTE reads progress as it was before the upsert, so completing heartbeat is told apart from the rest.
+	// Watched time grows by at most playback of time since the previous heartbeat, rounded down,
+	// so sending heartbeats often doesn't speed it up.
+	var saved struct {
+		entity.LessonProgress
+		JustCompleted bool
+	}
+	err := p.DB.
+		WithContext(ctx).
+		Raw(`WITH previous AS (
+				SELECT watched_seconds, completed, updated_at FROM lesson_progress WHERE user_id = ? AND lesson_id = ?
+			), watched AS (
+				SELECT CASE WHEN previous.updated_at IS NULL THEN LEAST(?::int, ?::int)
+					ELSE GREATEST(previous.watched_seconds, LEAST(?::int, previous.watched_seconds +
+						floor(?::float8 * extract(epoch FROM now() - previous.updated_at))::int)) END AS seconds
+				FROM (SELECT 1) one
+				LEFT JOIN previous ON true
+			)
+			INSERT INTO lesson_progress (user_id, lesson_id, course_id, watched_seconds, last_position, position_at, completed, updated_at)
+			SELECT ?, ?, ?, watched.seconds, ?, ?, watched.seconds >= ?, now()
+			FROM watched
+			ON CONFLICT (user_id, lesson_id) DO UPDATE SET
+				watched_seconds = excluded.watched_seconds,
+				last_position = CASE WHEN excluded.position_at >= lesson_progress.position_at
+					THEN excluded.last_position ELSE lesson_progress.last_position END,
+				position_at = GREATEST(lesson_progress.position_at, excluded.position_at),
+				completed = lesson_progress.completed OR excluded.completed,
+				updated_at = now()
+			RETURNING *, completed AND NOT coalesce((SELECT completed FROM previous), false) AS just_completed`,
+			progress.UserId, progress.LessonId,
+			progress.WatchedSeconds, int(limits.FirstHeartbeat.Seconds()),
+			progress.WatchedSeconds, limits.MaxPlaybackSpeed,
+			progress.UserId, progress.LessonId, progress.CourseId, progress.LastPosition, progress.PositionAt,
+			limits.CompletionSeconds,
+		).
+		Scan(&saved).
+		Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &saved.LessonProgress, saved.JustCompleted, nil
+}
+
+func (p *progressStorage) GetLessonProgress(ctx context.Context, userId, courseId string) ([]*entity.LessonProgress, error) {
+	var progress []*entity.LessonProgress
+	err := p.DB.
+		WithContext(ctx).
+		Where(entity.LessonProgress{UserId: userId, CourseId: courseId}).
+		Find(&progress).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func (p *progressStorage) GetCoursesProgress(ctx context.Context, userId string, courseIds []string) ([]*entity.CourseProgress, error) {
+	if len(courseIds) == 0 {
+		return nil, nil
+	}
+
+	var progress []*entity.CourseProgress
+	err := p.DB.
+		WithContext(ctx).
+		Raw(`SELECT totals.*,
+				((totals.completed_lessons + totals.passed_quizzes) * 100 / (totals.total_lessons + totals.total_quizzes))::int AS percent,
+				coalesce(last.lesson_id::text, '') AS last_lesson_id,
+				coalesce(last.last_position, 0) AS last_position
+			FROM (
+				SELECT items.course_id,
+					sum(items.lessons) AS total_lessons,
+					sum(items.completed_lessons) AS completed_lessons,
+					sum(items.quizzes) AS total_quizzes,
+					sum(items.passed_quizzes) AS passed_quizzes
+				FROM (
+					SELECT l.course_id, 1 AS lessons, coalesce(lp.completed, false)::int AS completed_lessons, 0 AS quizzes, 0 AS passed_quizzes
+					FROM lessons l
+					LEFT JOIN lesson_progress lp ON lp.lesson_id = l.id AND lp.user_id = ?
+					WHERE l.course_id IN ?
+					UNION ALL
+					SELECT q.course_id, 0, 0, 1, EXISTS (
+						SELECT 1 FROM quiz_attempts a WHERE a.quiz_id = q.id AND a.user_id = ? AND a.passed
+					)::int
+					FROM quizzes q
+					-- quiz without questions can't be passed, so it doesn't count until teacher adds them
+					WHERE q.course_id IN ? AND EXISTS (SELECT 1 FROM quiz_questions qq WHERE qq.quiz_id = q.id)
+				) items
+				GROUP BY items.course_id
+			) totals
+			LEFT JOIN LATERAL (
+				SELECT lesson_id, last_position FROM lesson_progress
+				WHERE user_id = ? AND course_id = totals.course_id
+				ORDER BY updated_at DESC LIMIT 1
+			) last ON true`,
+			userId, courseIds, userId, courseIds, userId,
+		).
+		Scan(&progress).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func (p *progressStorage) GetWatchedPercent(ctx context.Context, userId, courseId string) (int, error) {
+	// watched time of lesson is capped by its duration, so rewatching doesn't count twice
+	var percent int
+	err := p.DB.
+		WithContext(ctx).
+		Raw(`SELECT COALESCE(
+				sum(LEAST(coalesce(lp.watched_seconds, 0), l.duration_seconds)) * 100 / NULLIF(sum(l.duration_seconds), 0),
+				0)::int
+			FROM lessons l
+			LEFT JOIN lesson_progress lp ON lp.lesson_id = l.id AND lp.user_id = ?
+			WHERE l.course_id = ?`,
+			userId, courseId,
+		).
+		Row().
+		Scan(&percent)
+	if err != nil {
+		return 0, err
+	}
+
+	return percent, nil
+}
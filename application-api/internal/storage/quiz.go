@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type quizStorage struct {
+	*database.PostgreSQL
+}
+
+var _ QuizStorage = (*quizStorage)(nil)
+
+func NewQuizStorage(postgresql *database.PostgreSQL) QuizStorage {
+	return &quizStorage{postgresql}
+}
+
+func (q *quizStorage) CreateQuiz(ctx context.Context, quiz *entity.Quiz) (*entity.Quiz, error) {
+	err := q.DB.WithContext(ctx).Create(quiz).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+func (q *quizStorage) GetQuiz(ctx context.Context, quizId string) (*entity.Quiz, error) {
+	var quiz entity.Quiz
+	err := q.DB.
+		WithContext(ctx).
+		Where(entity.Quiz{Id: quizId}).
+		First(&quiz).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &quiz, nil
+}
+
+func (q *quizStorage) GetQuizzes(ctx context.Context, courseId string) ([]*entity.Quiz, error) {
+	var quizzes []*entity.Quiz
+	err := q.DB.
+		WithContext(ctx).
+		Where(entity.Quiz{CourseId: courseId}).
+		Order("position, created_at").
+		Find(&quizzes).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return quizzes, nil
+}
+
+func (q *quizStorage) CreateQuestion(ctx context.Context, question *entity.QuizQuestion) (*entity.QuizQuestion, error) {
+	err := q.DB.WithContext(ctx).Create(question).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+func (q *quizStorage) GetQuestions(ctx context.Context, quizId string) ([]*entity.QuizQuestion, error) {
+	var questions []*entity.QuizQuestion
+	err := q.DB.
+		WithContext(ctx).
+		Where(entity.QuizQuestion{QuizId: quizId}).
+		Order("position, id").
+		Find(&questions).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+func (q *quizStorage) CreateAttempt(ctx context.Context, attempt *entity.QuizAttempt, maxAttempts int) (*entity.QuizAttempt, error) {
+	// limit is checked by the insert itself, so parallel starts can't easily exceed it
+	var created []*entity.QuizAttempt
+	err := q.DB.
+		WithContext(ctx).
+		Raw(`INSERT INTO quiz_attempts (quiz_id, user_id, course_id, started_at, expires_at)
+			SELECT ?, ?, ?, ?, ?
+			WHERE ? = 0 OR (SELECT count(*) FROM quiz_attempts WHERE quiz_id = ? AND user_id = ?) < ?
+			RETURNING *`,
+			attempt.QuizId, attempt.UserId, attempt.CourseId, attempt.StartedAt, attempt.ExpiresAt,
+			maxAttempts, attempt.QuizId, attempt.UserId, maxAttempts,
+		).
+		Scan(&created).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		return nil, nil
+	}
+
+	return created[0], nil
+}
+
+func (q *quizStorage) GetAttempt(ctx context.Context, attemptId string) (*entity.QuizAttempt, error) {
+	var attempt entity.QuizAttempt
+	err := q.DB.
+		WithContext(ctx).
+		Where(entity.QuizAttempt{Id: attemptId}).
+		First(&attempt).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (q *quizStorage) GetAttempts(ctx context.Context, userId, quizId string) ([]*entity.QuizAttempt, error) {
+	var attempts []*entity.QuizAttempt
+	err := q.DB.
+		WithContext(ctx).
+		Where(entity.QuizAttempt{UserId: userId, QuizId: quizId}).
+		Order("started_at DESC").
+		Find(&attempts).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (q *quizStorage) SubmitAttempt(ctx context.Context, attempt *entity.QuizAttempt) (*entity.QuizAttempt, error) {
+	// only the first submission is graded, repeated or parallel ones don't change the result
+	result := q.DB.
+		WithContext(ctx).
+		Model(attempt).
+		Where("submitted_at IS NULL").
+		Select("submitted_at", "answers", "score", "max_score", "percent", "passed").
+		Updates(attempt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return attempt, nil
+}
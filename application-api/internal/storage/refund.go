@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type refundStorage struct {
+	*database.PostgreSQL
+}
+
+var _ RefundStorage = (*refundStorage)(nil)
+
+func NewRefundStorage(postgresql *database.PostgreSQL) RefundStorage {
+	return &refundStorage{postgresql}
+}
+
+func (r *refundStorage) CreateRefund(ctx context.Context, refund *entity.Refund) (*entity.Refund, error) {
+	// partial unique index allows one pending or completed refund per order item
+	result := r.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(refund)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return refund, nil
+}
+
+func (r *refundStorage) GetUserRefunds(ctx context.Context, userId string) ([]*entity.Refund, error) {
+	var refunds []*entity.Refund
+	err := r.DB.
+		WithContext(ctx).
+		Where(entity.Refund{UserId: userId}).
+		Order("created_at DESC").
+		Find(&refunds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+func (r *refundStorage) GetOrderItemEntries(ctx context.Context, orderItemId string) ([]*entity.LedgerEntry, error) {
+	var entries []*entity.LedgerEntry
+	err := r.DB.
+		WithContext(ctx).
+		Where("order_item_id = ?", orderItemId).
+		Order("created_at, transaction_id").
+		Find(&entries).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *refundStorage) CompleteRefund(ctx context.Context, refund *entity.Refund, entries []*entity.LedgerEntry) (*entity.Refund, error) {
+	var completed *entity.Refund
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// status condition makes sure that ledger is reversed once, even when refund is completed twice
+		result := tx.Model(&entity.Refund{}).
+			Where("id = ? AND status = ?", refund.Id, entity.RefundPending).
+			Updates(map[string]interface{}{
+				"status":             entity.RefundCompleted,
+				"provider_refund_id": refund.ProviderRefundId,
+				"completed_at":       time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var err error
+		// duplicate refund returns second payment only, user keeps course owned another way
+		if !refund.Duplicate {
+			err = tx.Where("user_id = ? AND course_id = ?", refund.UserId, refund.CourseId).
+				Delete(&entity.Enrollment{}).
+				Error
+			if err != nil {
+				return err
+			}
+
+			// certificate issued while refund was pending isn't valid for refunded course
+			err = tx.Model(&entity.Certificate{}).
+				Where("user_id = ? AND course_id = ? AND revoked_at IS NULL", refund.UserId, refund.CourseId).
+				Updates(map[string]interface{}{"revoked_at": gorm.Expr("now()"), "revoked_reason": entity.CertificateRefundedReason}).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(entries) > 0 {
+			err = tx.Create(entries).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ?", refund.OrderId, entity.OrderPaid).
+			Where(`NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id AND NOT EXISTS (
+				SELECT 1 FROM refunds r WHERE r.order_item_id = i.id AND r.status = ?))`, entity.RefundCompleted).
+			Update("status", entity.OrderRefunded).
+			Error
+		if err != nil {
+			return err
+		}
+
+		completed = &entity.Refund{}
+		return tx.Where(entity.Refund{Id: refund.Id}).First(completed).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return completed, nil
+}
+
+func (r *refundStorage) GetPendingRefunds(ctx context.Context, createdBefore time.Time) ([]*entity.Refund, error) {
+	var refunds []*entity.Refund
+	err := r.DB.
+		WithContext(ctx).
+		Where("status = ? AND created_at < ?", entity.RefundPending, createdBefore).
+		Order("created_at").
+		Find(&refunds).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type reviewStorage struct {
+	*database.PostgreSQL
+}
+
+var _ ReviewStorage = (*reviewStorage)(nil)
+
+func NewReviewStorage(postgresql *database.PostgreSQL) ReviewStorage {
+	return &reviewStorage{postgresql}
+}
+
+func (r *reviewStorage) CreateReview(ctx context.Context, review *entity.Review) (*entity.Review, error) {
+	created := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+
+		return updateRating(tx, review.CourseId, review.Rating, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+
+	return review, nil
+}
+
+func (r *reviewStorage) GetReview(ctx context.Context, filter *GetReviewFilter) (*entity.Review, error) {
+	stmt := r.DB.WithContext(ctx)
+
+	if filter.Id != "" {
+		stmt = stmt.Where(entity.Review{Id: filter.Id})
+	}
+
+	if filter.CourseId != "" {
+		stmt = stmt.Where(entity.Review{CourseId: filter.CourseId})
+	}
+
+	if filter.UserId != "" {
+		stmt = stmt.Where(entity.Review{UserId: filter.UserId})
+	}
+
+	var review entity.Review
+	err := stmt.First(&review).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *reviewStorage) GetReviews(ctx context.Context, courseId string) ([]*entity.Review, error) {
+	var reviews []*entity.Review
+	err := r.DB.
+		WithContext(ctx).
+		Where("course_id = ? AND hidden = false", courseId).
+		Order("created_at DESC").
+		Find(&reviews).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (r *reviewStorage) UpdateReview(ctx context.Context, reviewId string, rating int, text string) (*entity.Review, error) {
+	var review entity.Review
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// row lock keeps rating delta right when the same review is edited concurrently
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(entity.Review{Id: reviewId}).First(&review).Error
+		if err != nil {
+			return err
+		}
+		previousRating := review.Rating
+
+		review.Rating = rating
+		review.Text = text
+		err = tx.Model(&review).Select("rating", "text", "updated_at").Updates(&review).Error
+		if err != nil {
+			return err
+		}
+
+		if review.Hidden {
+			return nil
+		}
+		return updateRating(tx, review.CourseId, rating-previousRating, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *reviewStorage) ReplyToReview(ctx context.Context, reviewId, reply string) (*entity.Review, error) {
+	now := time.Now()
+	var review entity.Review
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Review{}).
+			Where(entity.Review{Id: reviewId}).
+			Updates(map[string]interface{}{"reply": reply, "replied_at": now}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where(entity.Review{Id: reviewId}).First(&review).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *reviewStorage) SetReviewHidden(ctx context.Context, reviewId string, hidden bool) (*entity.Review, error) {
+	var review entity.Review
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(entity.Review{Id: reviewId}).First(&review).Error
+		if err != nil {
+			return err
+		}
+		if review.Hidden == hidden {
+			return nil
+		}
+
+		review.Hidden = hidden
+		err = tx.Model(&review).Update("hidden", hidden).Error
+		if err != nil {
+			return err
+		}
+
+		if hidden {
+			return updateRating(tx, review.CourseId, -review.Rating, -1)
+		}
+		return updateRating(tx, review.CourseId, review.Rating, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// updateRating adjusts course rating aggregates by given deltas, average is computed by database.
+func updateRating(tx *gorm.DB, courseId string, sumDelta, countDelta int) error {
+	return tx.Exec(
+		"UPDATE courses SET rating_sum = rating_sum + ?, rating_count = rating_count + ? WHERE id = ?",
+		sumDelta, countDelta, courseId,
+	).Error
+}
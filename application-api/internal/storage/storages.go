@@ -3,13 +3,85 @@ package storage
 import (
 	"context"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"time"
 )
 
 type Storages struct {
-	UserStorage    UserStorage
-	AccountStorage AccountStorage
-	NodeStorage    NodeStorage
-	CourseStorage  CourseStorage
+	UserStorage         UserStorage
+	AccountStorage      AccountStorage
+	NodeStorage         NodeStorage
+	CourseStorage       CourseStorage
+	TokenStorage        TokenStorage
+	LoginStorage        LoginStorage
+	TwoFactorStorage    TwoFactorStorage
+	IdentityStorage     IdentityStorage
+	APIKeyStorage       APIKeyStorage
+	EnrollmentStorage   EnrollmentStorage
+	ReviewStorage       ReviewStorage
+	CurriculumStorage   CurriculumStorage
+	ProgressStorage     ProgressStorage
+	CertificateStorage  CertificateStorage
+	QuizStorage         QuizStorage
+	AssignmentStorage   AssignmentStorage
+	DiscussionStorage   DiscussionStorage
+	OrderStorage        OrderStorage
+	LedgerStorage       LedgerStorage
+	RefundStorage       RefundStorage
+	CartStorage         CartStorage
+	BundleStorage       BundleStorage
+	SubscriptionStorage SubscriptionStorage
+	GiftCodeStorage     GiftCodeStorage
+	WishlistStorage     WishlistStorage
+	TeacherStorage      TeacherStorage
+	SubtitleStorage     SubtitleStorage
+
+	postgresql *database.PostgreSQL
+}
+
+// NewStorages creates all storages on top of given database connection.
+func NewStorages(postgresql *database.PostgreSQL) Storages {
+	return Storages{
+		UserStorage:         NewUserStorage(postgresql),
+		AccountStorage:      NewAccountStorage(postgresql),
+		NodeStorage:         NewNodeStorage(postgresql),
+		CourseStorage:       NewCourseStorage(postgresql),
+		TokenStorage:        NewTokenStorage(postgresql),
+		LoginStorage:        NewLoginStorage(postgresql),
+		TwoFactorStorage:    NewTwoFactorStorage(postgresql),
+		IdentityStorage:     NewIdentityStorage(postgresql),
+		APIKeyStorage:       NewAPIKeyStorage(postgresql),
+		EnrollmentStorage:   NewEnrollmentStorage(postgresql),
+		ReviewStorage:       NewReviewStorage(postgresql),
+		CurriculumStorage:   NewCurriculumStorage(postgresql),
+		ProgressStorage:     NewProgressStorage(postgresql),
+		CertificateStorage:  NewCertificateStorage(postgresql),
+		QuizStorage:         NewQuizStorage(postgresql),
+		AssignmentStorage:   NewAssignmentStorage(postgresql),
+		DiscussionStorage:   NewDiscussionStorage(postgresql),
+		OrderStorage:        NewOrderStorage(postgresql),
+		LedgerStorage:       NewLedgerStorage(postgresql),
+		RefundStorage:       NewRefundStorage(postgresql),
+		CartStorage:         NewCartStorage(postgresql),
+		BundleStorage:       NewBundleStorage(postgresql),
+		SubscriptionStorage: NewSubscriptionStorage(postgresql),
+		GiftCodeStorage:     NewGiftCodeStorage(postgresql),
+		WishlistStorage:     NewWishlistStorage(postgresql),
+		TeacherStorage:      NewTeacherStorage(postgresql),
+		SubtitleStorage:     NewSubtitleStorage(postgresql),
+
+		postgresql: postgresql,
+	}
+}
+
+// Transaction runs fn with storages sharing one database transaction, it is committed when fn returns nil
+// and rolled back otherwise.
+func (s *Storages) Transaction(ctx context.Context, fn func(storages *Storages) error) error {
+	return s.postgresql.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		storages := NewStorages(&database.PostgreSQL{DB: tx})
+		return fn(&storages)
+	})
 }
 
 type UserStorage interface {
@@ -17,6 +89,12 @@ type UserStorage interface {
 	GetUser(ctx context.Context, filter *GetUserFilter) (*entity.User, error)
 	// CreateUser provides creating user in the system.
 	CreateUser(ctx context.Context, user *entity.User) (*entity.User, error)
+	// UpdateUser provides updating existing user in storage.
+	UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error)
+	// GetUsers provides getting all users from storage.
+	GetUsers(ctx context.Context) ([]*entity.User, error)
+	// SaveUsers provides creating or overwriting users by their ids, empty password keeps password of existing user.
+	SaveUsers(ctx context.Context, users []*entity.User) error
 }
 
 type GetUserFilter struct {
@@ -29,8 +107,9 @@ type AccountStorage interface {
 	CreateAccount(ctx context.Context, account *entity.Account) (*entity.Account, error)
 	// GetAccount provides logic of getting account from storage.
 	GetAccount(ctx context.Context, filter *GetAccountFilter) (*entity.Account, error)
-	// UpdateAccount provides logic of updating account in storage.
-	UpdateAccount(ctx context.Context, account *entity.Account) (*entity.Account, error)
+	// SaveAccount provides overwriting settings and devices of account at once, nil settings or devices are kept.
+	// Settings and devices without id are created, devices of account missing from given ones are deleted.
+	SaveAccount(ctx context.Context, account *entity.Account) error
 }
 
 type GetAccountFilter struct {
@@ -48,11 +127,459 @@ type CourseStorage interface {
 	// CreateCourse provides creating course in the system.
 	CreateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error)
 	GetListByTeacherId(ctx context.Context, teacherId string) ([]*entity.Course, error)
+	// GetList provides getting published courses.
 	GetList() ([]*entity.Course, error)
+	// GetAll provides getting all courses including unpublished ones.
+	GetAll(ctx context.Context) ([]*entity.Course, error)
+	// UpdateCourse provides updating existing course in storage.
+	UpdateCourse(ctx context.Context, course *entity.Course) (*entity.Course, error)
+	// GetCourses provides getting courses by ids.
+	GetCourses(ctx context.Context, ids []string) ([]*entity.Course, error)
+	// RecordPriceChanges provides appending current price of every course which changed since the last record
+	// to price history. Recorded changes and released ones are taken for notification and returned with
+	// previous prices, each change is returned once unless it is released.
+	RecordPriceChanges(ctx context.Context) ([]*PriceChange, error)
+	// ReleasePriceChange provides returning change which couldn't be notified, next RecordPriceChanges takes it again.
+	ReleasePriceChange(ctx context.Context, id string) error
+	// SaveTranslation provides creating or overwriting translation of course to its language.
+	SaveTranslation(ctx context.Context, translation *entity.CourseTranslation) (*entity.CourseTranslation, error)
+	// GetTranslations provides getting all translations of course ordered by language.
+	GetTranslations(ctx context.Context, courseId string) ([]*entity.CourseTranslation, error)
+	// DeleteTranslation provides deleting translation of course, false is returned when there is none.
+	DeleteTranslation(ctx context.Context, courseId, language string) (bool, error)
+	// SaveCourses provides creating or overwriting courses by their ids, author is derived from teacher.
+	SaveCourses(ctx context.Context, courses []*entity.Course) error
+}
+
+// PriceChange - represents course price change in minor units, OldPrice is nil for the first record of course.
+type PriceChange struct {
+	// Id is id of price history record.
+	Id         string
+	CourseId   string
+	CourseName string
+	Published  bool
+	OldPrice   *int64
+	NewPrice   int64
 }
 
 type GetCourseFilter struct {
-	Name   string
-	Author string
-	Id     string
+	Name      string
+	Author    string
+	Id        string
+	TeacherId string
+}
+
+type TokenStorage interface {
+	// CreateToken provides storing new one-time token.
+	CreateToken(ctx context.Context, token *entity.OneTimeToken) (*entity.OneTimeToken, error)
+	// GetToken provides getting one-time token via requested filters.
+	GetToken(ctx context.Context, filter *GetTokenFilter) (*entity.OneTimeToken, error)
+	// UseToken marks token as used, returns false if it was already used.
+	UseToken(ctx context.Context, tokenId string) (bool, error)
+	// DeleteUserTokens provides removing all user tokens with given purpose.
+	DeleteUserTokens(ctx context.Context, userId, purpose string) error
+}
+
+type GetTokenFilter struct {
+	TokenHash string
+	Purpose   string
+}
+
+type LoginStorage interface {
+	// GetLoginFailures provides getting failure counters of given keys.
+	GetLoginFailures(ctx context.Context, keys []string) ([]*entity.LoginFailure, error)
+	// RegisterLoginFailure increments failure counter of the key, counter restarts when last failure is older than window.
+	RegisterLoginFailure(ctx context.Context, key string, window time.Duration) (*entity.LoginFailure, error)
+	// LockLogin provides blocking sign in for the key until given time.
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	// ResetLoginFailures provides removing failure counter of the key.
+	ResetLoginFailures(ctx context.Context, key string) error
+}
+
+type TwoFactorStorage interface {
+	// GetTwoFactor provides getting two-factor settings of user.
+	GetTwoFactor(ctx context.Context, userId string) (*entity.TwoFactor, error)
+	// SaveTwoFactor provides creating or overwriting two-factor settings of user.
+	SaveTwoFactor(ctx context.Context, twoFactor *entity.TwoFactor) (*entity.TwoFactor, error)
+	// DeleteTwoFactor provides removing two-factor settings and recovery codes of user.
+	DeleteTwoFactor(ctx context.Context, userId string) error
+	// UseTwoFactorStep records matched TOTP step, returns false if the same or later step was used already.
+	UseTwoFactorStep(ctx context.Context, userId string, step int64) (bool, error)
+	// ReplaceRecoveryCodes provides overwriting all recovery codes of user.
+	ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) error
+	// UseRecoveryCode marks recovery code as used, returns false if it is unknown or used already.
+	UseRecoveryCode(ctx context.Context, userId, codeHash string) (bool, error)
+}
+
+type IdentityStorage interface {
+	// GetIdentity provides getting external identity by provider issuer and subject.
+	GetIdentity(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error)
+	// CreateIdentity provides linking external identity to user.
+	CreateIdentity(ctx context.Context, identity *entity.UserIdentity) (*entity.UserIdentity, error)
+	// CreateOIDCState provides storing login attempt data until provider redirects back.
+	CreateOIDCState(ctx context.Context, state *entity.OIDCState) error
+	// TakeOIDCState provides getting and removing login attempt data, so it can be used once.
+	TakeOIDCState(ctx context.Context, stateHash string) (*entity.OIDCState, error)
+}
+
+type APIKeyStorage interface {
+	// CreateAPIKey provides storing new API key.
+	CreateAPIKey(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error)
+	// GetAPIKey provides getting API key by its hash.
+	GetAPIKey(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	// GetAPIKeys provides getting all API keys of user, newest first.
+	GetAPIKeys(ctx context.Context, userId string) ([]*entity.APIKey, error)
+	// RevokeAPIKey marks API key of user as revoked, returns false if it is unknown or revoked already.
+	RevokeAPIKey(ctx context.Context, userId, keyId string) (bool, error)
+	// TouchAPIKey records key usage, updates are skipped when last one is more recent than interval.
+	TouchAPIKey(ctx context.Context, keyId string, interval time.Duration) error
+}
+
+type EnrollmentStorage interface {
+	// CreateEnrollment provides granting user access to course, existing enrollment is returned as is.
+	CreateEnrollment(ctx context.Context, enrollment *entity.Enrollment) (*entity.Enrollment, error)
+	// GetEnrollment provides getting enrollment of user in course.
+	GetEnrollment(ctx context.Context, userId, courseId string) (*entity.Enrollment, error)
+	// GetUserEnrollments provides getting all enrollments of user, newest first.
+	GetUserEnrollments(ctx context.Context, userId string) ([]*entity.Enrollment, error)
+}
+
+type ReviewStorage interface {
+	// CreateReview provides storing review and adding its rating to course aggregates,
+	// returns nil if user has reviewed the course already.
+	CreateReview(ctx context.Context, review *entity.Review) (*entity.Review, error)
+	// GetReview provides getting review via requested filters.
+	GetReview(ctx context.Context, filter *GetReviewFilter) (*entity.Review, error)
+	// GetReviews provides getting visible reviews of course, newest first.
+	GetReviews(ctx context.Context, courseId string) ([]*entity.Review, error)
+	// UpdateReview provides changing rating and text of review, course aggregates are adjusted.
+	UpdateReview(ctx context.Context, reviewId string, rating int, text string) (*entity.Review, error)
+	// ReplyToReview provides setting teacher reply of review.
+	ReplyToReview(ctx context.Context, reviewId, reply string) (*entity.Review, error)
+	// SetReviewHidden provides hiding or restoring review, hidden reviews don't count towards course rating.
+	SetReviewHidden(ctx context.Context, reviewId string, hidden bool) (*entity.Review, error)
+}
+
+type GetReviewFilter struct {
+	Id       string
+	CourseId string
+	UserId   string
+}
+
+type CurriculumStorage interface {
+	// CreateSection provides adding section to course.
+	CreateSection(ctx context.Context, section *entity.Section) (*entity.Section, error)
+	// GetSection provides getting section by id.
+	GetSection(ctx context.Context, sectionId string) (*entity.Section, error)
+	// GetSections provides getting ordered sections of course.
+	GetSections(ctx context.Context, courseId string) ([]*entity.Section, error)
+	// CreateLesson provides adding lesson to section.
+	CreateLesson(ctx context.Context, lesson *entity.Lesson) (*entity.Lesson, error)
+	// GetLesson provides getting lesson by id.
+	GetLesson(ctx context.Context, lessonId string) (*entity.Lesson, error)
+	// GetLessons provides getting ordered lessons of course.
+	GetLessons(ctx context.Context, courseId string) ([]*entity.Lesson, error)
+}
+
+type ProgressStorage interface {
+	// SaveLessonProgress provides merging heartbeat into lesson progress of user and returns the result,
+	// true is returned when the heartbeat completed the lesson. Completion is derived from watched time limited
+	// by limits, so players can't complete lessons by reporting it.
+	SaveLessonProgress(ctx context.Context, progress *entity.LessonProgress, limits *WatchLimits) (*entity.LessonProgress, bool, error)
+	// GetLessonProgress provides getting progress of user in all lessons of course.
+	GetLessonProgress(ctx context.Context, userId, courseId string) ([]*entity.LessonProgress, error)
+	// GetCoursesProgress provides completion of given courses by user, courses without lessons and quizzes are omitted.
+	GetCoursesProgress(ctx context.Context, userId string, courseIds []string) ([]*entity.CourseProgress, error)
+	// GetWatchedPercent provides share of course lessons duration watched by user, rounded down.
+	GetWatchedPercent(ctx context.Context, userId, courseId string) (int, error)
+}
+
+// WatchLimits bound watched time reported by heartbeat to time which passed since the previous one.
+type WatchLimits struct {
+	// FirstHeartbeat is the most watched time the first heartbeat of lesson can report.
+	FirstHeartbeat time.Duration
+	// MaxPlaybackSpeed is how much faster than time between heartbeats watched time can grow.
+	MaxPlaybackSpeed float64
+	// CompletionSeconds is watched time completing the lesson.
+	CompletionSeconds int
+}
+
+type CertificateStorage interface {
+	// CreateCertificate provides issuing certificate, existing certificate of the user and course is returned instead.
+	CreateCertificate(ctx context.Context, certificate *entity.Certificate) (*entity.Certificate, error)
+	// GetCertificate provides getting certificate via requested filters.
+	GetCertificate(ctx context.Context, filter *GetCertificateFilter) (*entity.Certificate, error)
+	// GetUserCertificates provides getting certificates of user, newest first.
+	GetUserCertificates(ctx context.Context, userId string) ([]*entity.Certificate, error)
+	// RevokeCertificate provides marking certificate revoked and returns it, nil if it doesn't exist.
+	RevokeCertificate(ctx context.Context, certificateId, reason string) (*entity.Certificate, error)
+}
+
+type GetCertificateFilter struct {
+	Id       string
+	UserId   string
+	CourseId string
+}
+
+type QuizStorage interface {
+	// CreateQuiz provides adding quiz to section.
+	CreateQuiz(ctx context.Context, quiz *entity.Quiz) (*entity.Quiz, error)
+	// GetQuiz provides getting quiz by id.
+	GetQuiz(ctx context.Context, quizId string) (*entity.Quiz, error)
+	// GetQuizzes provides getting ordered quizzes of course.
+	GetQuizzes(ctx context.Context, courseId string) ([]*entity.Quiz, error)
+	// CreateQuestion provides adding question to quiz.
+	CreateQuestion(ctx context.Context, question *entity.QuizQuestion) (*entity.QuizQuestion, error)
+	// GetQuestions provides getting ordered questions of quiz.
+	GetQuestions(ctx context.Context, quizId string) ([]*entity.QuizQuestion, error)
+	// CreateAttempt provides starting attempt unless user made maxAttempts already, zero maxAttempts is unlimited.
+	// Nil is returned when no attempts are left.
+	CreateAttempt(ctx context.Context, attempt *entity.QuizAttempt, maxAttempts int) (*entity.QuizAttempt, error)
+	// GetAttempt provides getting attempt by id.
+	GetAttempt(ctx context.Context, attemptId string) (*entity.QuizAttempt, error)
+	// GetAttempts provides getting attempts of user in quiz, newest first.
+	GetAttempts(ctx context.Context, userId, quizId string) ([]*entity.QuizAttempt, error)
+	// SubmitAttempt provides saving graded open attempt, nil is returned when attempt was submitted already.
+	SubmitAttempt(ctx context.Context, attempt *entity.QuizAttempt) (*entity.QuizAttempt, error)
+}
+
+type AssignmentStorage interface {
+	// CreateAssignment provides adding assignment to course.
+	CreateAssignment(ctx context.Context, assignment *entity.Assignment) (*entity.Assignment, error)
+	// GetAssignment provides getting assignment by id.
+	GetAssignment(ctx context.Context, assignmentId string) (*entity.Assignment, error)
+	// GetAssignments provides getting assignments of course by due date.
+	GetAssignments(ctx context.Context, courseId string) ([]*entity.Assignment, error)
+	// SaveSubmission provides creating or replacing submission of user, nil is returned when it is graded already.
+	SaveSubmission(ctx context.Context, submission *entity.Submission) (*entity.Submission, error)
+	// GetSubmission provides getting submission via requested filters.
+	GetSubmission(ctx context.Context, filter *GetSubmissionFilter) (*entity.Submission, error)
+	// GradeSubmission provides saving grade of submission.
+	GradeSubmission(ctx context.Context, submission *entity.Submission) (*entity.Submission, error)
+	// GetGradingQueue provides getting ungraded submissions in courses of teacher, oldest first.
+	GetGradingQueue(ctx context.Context, teacherId string) ([]*entity.GradingQueueItem, error)
+}
+
+type GetSubmissionFilter struct {
+	Id           string
+	AssignmentId string
+	UserId       string
+}
+
+type DiscussionStorage interface {
+	// CreateThread provides adding thread with its opening post.
+	CreateThread(ctx context.Context, thread *entity.Thread) (*entity.Thread, error)
+	// GetThread provides getting thread by id, hidden threads are included.
+	GetThread(ctx context.Context, threadId string) (*entity.Thread, error)
+	// GetThreads provides getting visible threads of course or lesson page by page, most recently active first.
+	GetThreads(ctx context.Context, filter *GetThreadsFilter) ([]*entity.Thread, error)
+	// CreatePost provides adding reply to thread and bumping its activity.
+	CreatePost(ctx context.Context, post *entity.Post) (*entity.Post, error)
+	// GetPost provides getting post by id, hidden posts are included.
+	GetPost(ctx context.Context, postId string) (*entity.Post, error)
+	// GetPosts provides getting visible posts of thread page by page, oldest first.
+	GetPosts(ctx context.Context, filter *GetPostsFilter) ([]*entity.Post, error)
+	// AcceptPost provides marking post as accepted answer of thread, nil post id clears it.
+	AcceptPost(ctx context.Context, threadId string, postId *string) (*entity.Thread, error)
+	// SetVote provides adding or removing upvote of user and adjusting counter of target,
+	// false is returned when vote was in requested state already.
+	SetVote(ctx context.Context, userId, targetType, targetId string, up bool) (bool, error)
+	// CreateReport provides storing report, nil is returned when user reported target already.
+	CreateReport(ctx context.Context, report *entity.DiscussionReport) (*entity.DiscussionReport, error)
+	// GetReport provides getting report by id.
+	GetReport(ctx context.Context, reportId string) (*entity.DiscussionReport, error)
+	// GetModerationQueue provides getting reported targets with open reports, oldest report first.
+	GetModerationQueue(ctx context.Context) ([]*entity.ModerationQueueItem, error)
+	// ResolveReports provides closing all open reports of target with status, target is hidden for hidden status.
+	ResolveReports(ctx context.Context, targetType, targetId, status, adminId string) error
+}
+
+// GetThreadsFilter - threads after cursor are returned when AfterId is set.
+type GetThreadsFilter struct {
+	CourseId        string
+	LessonId        string
+	AfterActivityAt time.Time
+	AfterId         string
+	Limit           int
+}
+
+// GetPostsFilter - posts after cursor are returned when AfterId is set.
+type GetPostsFilter struct {
+	ThreadId       string
+	AfterCreatedAt time.Time
+	AfterId        string
+	Limit          int
+}
+
+type OrderStorage interface {
+	// CreateOrder provides storing pending order with its items, nil is returned when user owns one of ordered
+	// courses or has another pending order of it. Gift orders are always stored.
+	CreateOrder(ctx context.Context, order *entity.Order) (*entity.Order, error)
+	// GetOrder provides getting order with its items by id.
+	GetOrder(ctx context.Context, orderId string) (*entity.Order, error)
+	// GetUserOrders provides getting orders of user with their items, newest first.
+	GetUserOrders(ctx context.Context, userId string) ([]*entity.Order, error)
+	// PayOrder provides marking pending order paid, enrolling its user to ordered courses and posting
+	// ledger entries at once, nil is returned when order isn't pending anymore. Pending duplicate refunds
+	// are started for courses user owns already and returned.
+	PayOrder(ctx context.Context, orderId, chargeId string, entries []*entity.LedgerEntry) (*entity.Order, []*entity.Refund, error)
+	// FailOrder provides marking pending order failed.
+	FailOrder(ctx context.Context, orderId string) error
+	// GetPendingOrders provides getting orders with their items which are pending since before createdBefore.
+	GetPendingOrders(ctx context.Context, createdBefore time.Time) ([]*entity.Order, error)
+	// GetPaidOrderItem provides getting item of paid order of user for course, nil is returned when course
+	// wasn't purchased or its purchase is refunded. Gift orders aren't taken into account.
+	GetPaidOrderItem(ctx context.Context, userId, courseId string) (*entity.OrderItem, error)
+}
+
+type LedgerStorage interface {
+	// GetEarnings provides summary of teacher ledger account with per course and per month aggregates.
+	GetEarnings(ctx context.Context, teacherId string) (*entity.Earnings, error)
+	// CreatePayout provides requesting payout, nil is returned when it exceeds available balance of teacher.
+	CreatePayout(ctx context.Context, payout *entity.Payout) (*entity.Payout, error)
+	// GetPayout provides getting payout by id.
+	GetPayout(ctx context.Context, payoutId string) (*entity.Payout, error)
+	// GetPayouts provides getting payouts via requested filters, newest first.
+	GetPayouts(ctx context.Context, filter *GetPayoutsFilter) ([]*entity.Payout, error)
+	// ApprovePayout provides approving requested payout and posting its ledger entries,
+	// nil is returned when payout isn't requested anymore.
+	ApprovePayout(ctx context.Context, payoutId, adminId string, entries []*entity.LedgerEntry) (*entity.Payout, error)
+	// RejectPayout provides rejecting requested payout, nil is returned when payout isn't requested anymore.
+	RejectPayout(ctx context.Context, payoutId, adminId, note string) (*entity.Payout, error)
+}
+
+type RefundStorage interface {
+	// CreateRefund provides starting refund of order item, nil is returned when item is refunded or being refunded.
+	CreateRefund(ctx context.Context, refund *entity.Refund) (*entity.Refund, error)
+	// GetUserRefunds provides getting refunds of user, newest first.
+	GetUserRefunds(ctx context.Context, userId string) ([]*entity.Refund, error)
+	// GetOrderItemEntries provides getting ledger entries posted for order item.
+	GetOrderItemEntries(ctx context.Context, orderItemId string) ([]*entity.LedgerEntry, error)
+	// CompleteRefund provides completing pending refund, revoking enrollment and certificate of course
+	// and posting reversing ledger entries at once.
+	CompleteRefund(ctx context.Context, refund *entity.Refund, entries []*entity.LedgerEntry) (*entity.Refund, error)
+	// GetPendingRefunds provides getting refunds which are pending since before createdBefore.
+	GetPendingRefunds(ctx context.Context, createdBefore time.Time) ([]*entity.Refund, error)
+}
+
+type GetPayoutsFilter struct {
+	TeacherId string
+	Status    string
+}
+
+type CartStorage interface {
+	// AddCartItem provides adding course to cart of user, course in cart already keeps its price.
+	AddCartItem(ctx context.Context, item *entity.CartItem) error
+	// GetCartItems provides getting cart of user in order courses were added.
+	GetCartItems(ctx context.Context, userId string) ([]*entity.CartItem, error)
+	// UpdateCartItemPrice provides updating price of course in cart after it was revalidated.
+	UpdateCartItemPrice(ctx context.Context, userId, courseId string, price int64) error
+	// RemoveCartItems provides removing courses from cart of user.
+	RemoveCartItems(ctx context.Context, userId string, courseIds []string) error
+}
+
+type BundleStorage interface {
+	// CreateBundle provides storing new bundle with links to its courses.
+	CreateBundle(ctx context.Context, bundle *entity.Bundle) (*entity.Bundle, error)
+	// GetBundle provides getting bundle with its courses.
+	GetBundle(ctx context.Context, bundleId string) (*entity.Bundle, error)
+	// GetBundles provides listing bundles with their courses, newest first.
+	GetBundles(ctx context.Context, filter *GetBundlesFilter) ([]*entity.Bundle, error)
+}
+
+type GetBundlesFilter struct {
+	TeacherId string
+	Published bool
+}
+
+type SubscriptionStorage interface {
+	// CreatePlan provides storing new subscription plan.
+	CreatePlan(ctx context.Context, plan *entity.SubscriptionPlan) (*entity.SubscriptionPlan, error)
+	// GetPlan provides getting subscription plan.
+	GetPlan(ctx context.Context, planId string) (*entity.SubscriptionPlan, error)
+	// GetActivePlans provides listing plans available for subscribing, cheapest first.
+	GetActivePlans(ctx context.Context) ([]*entity.SubscriptionPlan, error)
+	// CreateSubscription provides storing started subscription, nil is returned when user has subscription already.
+	CreateSubscription(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error)
+	// GetLiveSubscription provides getting subscription of user which isn't canceled.
+	GetLiveSubscription(ctx context.Context, userId string) (*entity.Subscription, error)
+	// GetProviderSubscription provides getting subscription by its id at payment provider.
+	GetProviderSubscription(ctx context.Context, providerSubscriptionId string) (*entity.Subscription, error)
+	// CancelAtPeriodEnd provides marking subscription not to be renewed, nil is returned when it is canceled already.
+	CancelAtPeriodEnd(ctx context.Context, subscriptionId string) (*entity.Subscription, error)
+	// ApplyEvent provides changing subscription by provider webhook and posting ledger entries at once,
+	// nil is returned when event is processed already or subscription is unknown or canceled.
+	ApplyEvent(ctx context.Context, event *SubscriptionEvent, entries []*entity.LedgerEntry) (*entity.Subscription, error)
+}
+
+// SubscriptionEvent - represents change of subscription reported by provider webhook Id.
+type SubscriptionEvent struct {
+	Id                     string
+	Type                   string
+	ProviderSubscriptionId string
+	Status                 string
+	CurrentPeriodEnd       *time.Time
+}
+
+type GiftCodeStorage interface {
+	// CreateGiftCodes provides storing new gift codes at once.
+	CreateGiftCodes(ctx context.Context, codes []*entity.GiftCode) ([]*entity.GiftCode, error)
+	// GetGiftCode provides getting gift code by its code.
+	GetGiftCode(ctx context.Context, code string) (*entity.GiftCode, error)
+	// GetGiftCodes provides listing gift codes, newest first.
+	GetGiftCodes(ctx context.Context, filter *GetGiftCodesFilter) ([]*entity.GiftCode, error)
+	// RedeemGiftCode provides marking gift code redeemed and enrolling user at once, nil is returned when code
+	// is redeemed already or its order isn't paid.
+	RedeemGiftCode(ctx context.Context, codeId, userId string) (*entity.Enrollment, error)
+}
+
+type GetGiftCodesFilter struct {
+	CourseId  string
+	BatchId   string
+	CreatedBy string
+	// Gifts limits codes to ones bought as gifts.
+	Gifts bool
+}
+
+type WishlistStorage interface {
+	// AddWishlistItem provides saving course to wishlist of user, adding it twice is ignored.
+	AddWishlistItem(ctx context.Context, item *entity.WishlistItem) error
+	// GetWishlistItems provides listing wishlist of user with current course prices, newest first.
+	GetWishlistItems(ctx context.Context, userId string) ([]*entity.WishlistItem, error)
+	// RemoveWishlistItem provides removing course from wishlist of user.
+	RemoveWishlistItem(ctx context.Context, userId, courseId string) error
+	// GetPriceDropRecipients provides getting users with verified email who wishlisted course, aren't enrolled
+	// in it and haven't turned price drop alerts off.
+	GetPriceDropRecipients(ctx context.Context, courseId string) ([]*entity.User, error)
+}
+
+type TeacherStorage interface {
+	// GetProfile provides getting profile of teacher, nil is returned when teacher hasn't created one.
+	GetProfile(ctx context.Context, userId string) (*entity.TeacherProfile, error)
+	// SaveProfile provides creating or overwriting profile of teacher and renaming author of teacher courses at once,
+	// nil is returned when display name is taken by another teacher regardless of case.
+	SaveProfile(ctx context.Context, profile *entity.TeacherProfile) (*entity.TeacherProfile, error)
+	// GetStats provides aggregating published courses of teacher.
+	GetStats(ctx context.Context, teacherId string) (*entity.TeacherStats, error)
+}
+
+type SubtitleStorage interface {
+	// SaveSubtitle provides creating or overwriting subtitle of lesson in language and replacing its transcript at once.
+	SaveSubtitle(ctx context.Context, subtitle *entity.LessonSubtitle, cues []*entity.TranscriptCue) (*entity.LessonSubtitle, error)
+	// GetSubtitle provides getting subtitle of lesson in language with its content.
+	GetSubtitle(ctx context.Context, lessonId, language string) (*entity.LessonSubtitle, error)
+	// GetSubtitles provides listing subtitles of all lessons of course without their content.
+	GetSubtitles(ctx context.Context, courseId string) ([]*entity.LessonSubtitle, error)
+	// DeleteSubtitle provides deleting subtitle of lesson in language together with its transcript.
+	DeleteSubtitle(ctx context.Context, lessonId, language string) (bool, error)
+	// SearchTranscripts provides full-text search of transcripts of published courses, one match per lesson.
+	SearchTranscripts(ctx context.Context, filter *SearchTranscriptsFilter) ([]*entity.TranscriptMatch, error)
+}
+
+type SearchTranscriptsFilter struct {
+	// Query is web search syntax: words, "quoted phrases", or and -excluded words.
+	Query    string
+	CourseId string
+	Language string
+	Limit    int
 }
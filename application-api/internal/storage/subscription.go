@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type subscriptionStorage struct {
+	*database.PostgreSQL
+}
+
+var _ SubscriptionStorage = (*subscriptionStorage)(nil)
+
+func NewSubscriptionStorage(postgresql *database.PostgreSQL) SubscriptionStorage {
+	return &subscriptionStorage{postgresql}
+}
+
+func (s *subscriptionStorage) CreatePlan(ctx context.Context, plan *entity.SubscriptionPlan) (*entity.SubscriptionPlan, error) {
+	err := s.DB.WithContext(ctx).Create(plan).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *subscriptionStorage) GetPlan(ctx context.Context, planId string) (*entity.SubscriptionPlan, error) {
+	var plan entity.SubscriptionPlan
+	err := s.DB.
+		WithContext(ctx).
+		Where(entity.SubscriptionPlan{Id: planId}).
+		First(&plan).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+func (s *subscriptionStorage) GetActivePlans(ctx context.Context) ([]*entity.SubscriptionPlan, error) {
+	var plans []*entity.SubscriptionPlan
+	err := s.DB.
+		WithContext(ctx).
+		Where("active").
+		Order("price").
+		Find(&plans).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func (s *subscriptionStorage) CreateSubscription(ctx context.Context, subscription *entity.Subscription) (*entity.Subscription, error) {
+	// partial unique index allows one subscription per user which isn't canceled
+	result := s.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(subscription)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return subscription, nil
+}
+
+func (s *subscriptionStorage) GetLiveSubscription(ctx context.Context, userId string) (*entity.Subscription, error) {
+	var subscription entity.Subscription
+	err := s.DB.
+		WithContext(ctx).
+		Where("user_id = ? AND status <> ?", userId, entity.SubscriptionCanceled).
+		First(&subscription).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (s *subscriptionStorage) GetProviderSubscription(ctx context.Context, providerSubscriptionId string) (*entity.Subscription, error) {
+	var subscription entity.Subscription
+	err := s.DB.
+		WithContext(ctx).
+		Where(entity.Subscription{ProviderSubscriptionId: providerSubscriptionId}).
+		First(&subscription).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (s *subscriptionStorage) CancelAtPeriodEnd(ctx context.Context, subscriptionId string) (*entity.Subscription, error) {
+	var subscription entity.Subscription
+	result := s.DB.
+		WithContext(ctx).
+		Model(&subscription).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND status <> ?", subscriptionId, entity.SubscriptionCanceled).
+		Update("cancel_at_period_end", true)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &subscription, nil
+}
+
+func (s *subscriptionStorage) ApplyEvent(ctx context.Context, event *SubscriptionEvent, entries []*entity.LedgerEntry) (*entity.Subscription, error) {
+	var subscription *entity.Subscription
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// canceled subscription doesn't change anymore
+		var found entity.Subscription
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider_subscription_id = ? AND status <> ?", event.ProviderSubscriptionId, entity.SubscriptionCanceled).
+			First(&found).
+			Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Exec("INSERT INTO payment_events (id, type) VALUES (?, ?) ON CONFLICT DO NOTHING", event.Id, event.Type)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		updates := map[string]interface{}{"status": event.Status, "updated_at": gorm.Expr("now()")}
+		if event.CurrentPeriodEnd != nil {
+			updates["current_period_end"] = *event.CurrentPeriodEnd
+		}
+		if event.Status == entity.SubscriptionCanceled {
+			updates["canceled_at"] = gorm.Expr("now()")
+		}
+		err = tx.Model(&found).Clauses(clause.Returning{}).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+
+		if len(entries) > 0 {
+			err = tx.Create(entries).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		subscription = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
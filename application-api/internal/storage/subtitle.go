@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// _transcriptCuesBatchSize keeps inserts of long transcripts below limit of query parameters.
+const _transcriptCuesBatchSize = 500
+
+type subtitleStorage struct {
+	*database.PostgreSQL
+}
+
+var _ SubtitleStorage = (*subtitleStorage)(nil)
+
+func NewSubtitleStorage(postgresql *database.PostgreSQL) SubtitleStorage {
+	return &subtitleStorage{postgresql}
+}
+
+func (s *subtitleStorage) SaveSubtitle(ctx context.Context, subtitle *entity.LessonSubtitle, cues []*entity.TranscriptCue) (*entity.LessonSubtitle, error) {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(subtitle).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Where("lesson_id = ? AND language = ?", subtitle.LessonId, subtitle.Language).
+			Delete(&entity.TranscriptCue{}).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(cues) == 0 {
+			return nil
+		}
+
+		return tx.CreateInBatches(cues, _transcriptCuesBatchSize).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subtitle, nil
+}
+
+func (s *subtitleStorage) GetSubtitle(ctx context.Context, lessonId, language string) (*entity.LessonSubtitle, error) {
+	var subtitle entity.LessonSubtitle
+	err := s.DB.
+		WithContext(ctx).
+		Where(entity.LessonSubtitle{LessonId: lessonId, Language: language}).
+		First(&subtitle).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &subtitle, nil
+}
+
+func (s *subtitleStorage) GetSubtitles(ctx context.Context, courseId string) ([]*entity.LessonSubtitle, error) {
+	var subtitles []*entity.LessonSubtitle
+	err := s.DB.
+		WithContext(ctx).
+		Omit("content").
+		Where(entity.LessonSubtitle{CourseId: courseId}).
+		Order("lesson_id, language").
+		Find(&subtitles).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subtitles, nil
+}
+
+func (s *subtitleStorage) DeleteSubtitle(ctx context.Context, lessonId, language string) (bool, error) {
+	// cues of transcript are deleted by cascade
+	result := s.DB.
+		WithContext(ctx).
+		Where("lesson_id = ? AND language = ?", lessonId, language).
+		Delete(&entity.LessonSubtitle{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (s *subtitleStorage) SearchTranscripts(ctx context.Context, filter *SearchTranscriptsFilter) ([]*entity.TranscriptMatch, error) {
+	conditions := "c.search @@ q.query AND co.published"
+	args := []interface{}{filter.Query}
+	if filter.CourseId != "" {
+		conditions += " AND c.course_id = ?"
+		args = append(args, filter.CourseId)
+	}
+	if filter.Language != "" {
+		conditions += " AND c.language = ?"
+		args = append(args, filter.Language)
+	}
+	args = append(args, filter.Limit)
+
+	// best matching cue of every lesson, the earliest one of equally ranked cues
+	var matches []*entity.TranscriptMatch
+	err := s.DB.
+		WithContext(ctx).
+		Raw(`SELECT lesson_id, lesson_title, course_id, course_name, language, start_ms, text FROM (
+				SELECT DISTINCT ON (c.lesson_id) c.lesson_id, l.title AS lesson_title, c.course_id, co.name AS course_name,
+					c.language, c.start_ms, c.text, ts_rank(c.search, q.query) AS rank
+				FROM transcript_cues c
+				CROSS JOIN websearch_to_tsquery('simple', ?) q(query)
+				JOIN lessons l ON l.id = c.lesson_id
+				JOIN courses co ON co.id = c.course_id
+				WHERE `+conditions+`
+				ORDER BY c.lesson_id, rank DESC, c.start_ms
+			) matches
+			ORDER BY rank DESC, course_name, lesson_title
+			LIMIT ?`,
+			args...,
+		).
+		Scan(&matches).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
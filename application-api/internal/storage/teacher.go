@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type teacherStorage struct {
+	*database.PostgreSQL
+}
+
+var _ TeacherStorage = (*teacherStorage)(nil)
+
+func NewTeacherStorage(postgresql *database.PostgreSQL) TeacherStorage {
+	return &teacherStorage{postgresql}
+}
+
+func (t *teacherStorage) GetProfile(ctx context.Context, userId string) (*entity.TeacherProfile, error) {
+	var profile entity.TeacherProfile
+	err := t.DB.
+		WithContext(ctx).
+		Where(entity.TeacherProfile{UserId: userId}).
+		First(&profile).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// _displayNameConstraint is unique index of display names regardless of case.
+const _displayNameConstraint = "teacher_profiles_display_name_key"
+
+func (t *teacherStorage) SaveProfile(ctx context.Context, profile *entity.TeacherProfile) (*entity.TeacherProfile, error) {
+	err := t.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(profile).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// author of courses follows display name, so it can't be claimed by anyone else
+		return tx.
+			Model(&entity.Course{}).
+			Where("teacher_id = ? AND author <> ?", profile.UserId, profile.DisplayName).
+			Update("author", profile.DisplayName).
+			Error
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == _displayNameConstraint {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (t *teacherStorage) GetStats(ctx context.Context, teacherId string) (*entity.TeacherStats, error) {
+	var stats entity.TeacherStats
+	err := t.DB.
+		WithContext(ctx).
+		Raw(`SELECT count(*) AS courses,
+				COALESCE(sum(rating_count), 0) AS rating_count,
+				CASE WHEN COALESCE(sum(rating_count), 0) = 0 THEN 0
+					ELSE sum(rating_sum)::double precision / sum(rating_count) END AS rating_average,
+				(SELECT count(DISTINCT e.user_id) FROM enrollments e
+					JOIN courses ec ON ec.id = e.course_id
+					WHERE ec.teacher_id = ? AND ec.published) AS students
+			FROM courses
+			WHERE teacher_id = ? AND published`, teacherId, teacherId).
+		Scan(&stats).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
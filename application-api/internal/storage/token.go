@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+type tokenStorage struct {
+	*database.PostgreSQL
+}
+
+var _ TokenStorage = (*tokenStorage)(nil)
+
+func NewTokenStorage(postgresql *database.PostgreSQL) TokenStorage {
+	return &tokenStorage{postgresql}
+}
+
+func (t *tokenStorage) CreateToken(ctx context.Context, token *entity.OneTimeToken) (*entity.OneTimeToken, error) {
+	err := t.DB.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (t *tokenStorage) GetToken(ctx context.Context, filter *GetTokenFilter) (*entity.OneTimeToken, error) {
+	stmt := t.DB.Where(entity.OneTimeToken{TokenHash: filter.TokenHash})
+
+	if filter.Purpose != "" {
+		stmt = stmt.Where(entity.OneTimeToken{Purpose: filter.Purpose})
+	}
+
+	var token entity.OneTimeToken
+	err := stmt.
+		WithContext(ctx).
+		First(&token).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (t *tokenStorage) UseToken(ctx context.Context, tokenId string) (bool, error) {
+	// conditional update, so concurrent requests can't use the same token twice
+	result := t.DB.
+		WithContext(ctx).
+		Model(&entity.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL", tokenId).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (t *tokenStorage) DeleteUserTokens(ctx context.Context, userId, purpose string) error {
+	return t.DB.
+		WithContext(ctx).
+		Where(entity.OneTimeToken{UserId: userId, Purpose: purpose}).
+		Delete(&entity.OneTimeToken{}).
+		Error
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type twoFactorStorage struct {
+	*database.PostgreSQL
+}
+
+var _ TwoFactorStorage = (*twoFactorStorage)(nil)
+
+func NewTwoFactorStorage(postgresql *database.PostgreSQL) TwoFactorStorage {
+	return &twoFactorStorage{postgresql}
+}
+
+func (t *twoFactorStorage) GetTwoFactor(ctx context.Context, userId string) (*entity.TwoFactor, error) {
+	var twoFactor entity.TwoFactor
+	err := t.DB.
+		WithContext(ctx).
+		Where(entity.TwoFactor{UserId: userId}).
+		First(&twoFactor).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactor, nil
+}
+
+func (t *twoFactorStorage) SaveTwoFactor(ctx context.Context, twoFactor *entity.TwoFactor) (*entity.TwoFactor, error) {
+	err := t.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(twoFactor).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return twoFactor, nil
+}
+
+func (t *twoFactorStorage) DeleteTwoFactor(ctx context.Context, userId string) error {
+	return t.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(entity.RecoveryCode{UserId: userId}).Delete(&entity.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where(entity.TwoFactor{UserId: userId}).Delete(&entity.TwoFactor{}).Error
+	})
+}
+
+func (t *twoFactorStorage) UseTwoFactorStep(ctx context.Context, userId string, step int64) (bool, error) {
+	// conditional update, so a code can't be replayed even by concurrent requests
+	result := t.DB.
+		WithContext(ctx).
+		Model(&entity.TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userId, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (t *twoFactorStorage) ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) error {
+	codes := make([]*entity.RecoveryCode, 0, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		codes = append(codes, &entity.RecoveryCode{UserId: userId, CodeHash: codeHash})
+	}
+
+	return t.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(entity.RecoveryCode{UserId: userId}).Delete(&entity.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+
+		return tx.Create(codes).Error
+	})
+}
+
+func (t *twoFactorStorage) UseRecoveryCode(ctx context.Context, userId, codeHash string) (bool, error) {
+	result := t.DB.
+		WithContext(ctx).
+		Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
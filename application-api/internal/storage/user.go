@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"github.com/a631807682/zerofield"
 	"github.com/vovk404/course-platform/application-api/internal/entity"
 	"github.com/vovk404/course-platform/application-api/pkg/database"
 	"gorm.io/gorm"
@@ -52,3 +53,52 @@ func (u *userStorage) GetUser(ctx context.Context, filter *GetUserFilter) (*enti
 
 	return &user, nil
 }
+
+func (u *userStorage) UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	err := u.DB.
+		WithContext(ctx).
+		Scopes(zerofield.UpdateScopes()).
+		Where(&entity.User{Id: user.Id}).
+		Updates(user).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *userStorage) GetUsers(ctx context.Context) ([]*entity.User, error) {
+	var users []*entity.User
+	err := u.DB.
+		WithContext(ctx).
+		Order("email").
+		Find(&users).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (u *userStorage) SaveUsers(ctx context.Context, users []*entity.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	return u.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: append(
+				clause.AssignmentColumns([]string{"username", "email", "type", "email_verified"}),
+				clause.Assignment{
+					Column: clause.Column{Name: "password"},
+					Value:  gorm.Expr("CASE WHEN excluded.password = '' THEN users.password ELSE excluded.password END"),
+				},
+			),
+		}).
+		Create(users).
+		Error
+}
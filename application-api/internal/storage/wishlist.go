@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"github.com/vovk404/course-platform/application-api/internal/entity"
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+	"gorm.io/gorm/clause"
+)
+
+type wishlistStorage struct {
+	*database.PostgreSQL
+}
+
+var _ WishlistStorage = (*wishlistStorage)(nil)
+
+func NewWishlistStorage(postgresql *database.PostgreSQL) WishlistStorage {
+	return &wishlistStorage{postgresql}
+}
+
+func (w *wishlistStorage) AddWishlistItem(ctx context.Context, item *entity.WishlistItem) error {
+	return w.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(item).
+		Error
+}
+
+func (w *wishlistStorage) GetWishlistItems(ctx context.Context, userId string) ([]*entity.WishlistItem, error) {
+	var items []*entity.WishlistItem
+	err := w.DB.
+		WithContext(ctx).
+		Select("wishlist_items.*, c.name AS course_name, c.price").
+		Joins("JOIN courses c ON c.id = wishlist_items.course_id").
+		Where("wishlist_items.user_id = ?", userId).
+		Order("wishlist_items.added_at DESC").
+		Find(&items).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (w *wishlistStorage) RemoveWishlistItem(ctx context.Context, userId, courseId string) error {
+	return w.DB.
+		WithContext(ctx).
+		Where("user_id = ? AND course_id = ?", userId, courseId).
+		Delete(&entity.WishlistItem{}).
+		Error
+}
+
+func (w *wishlistStorage) GetPriceDropRecipients(ctx context.Context, courseId string) ([]*entity.User, error) {
+	var users []*entity.User
+	err := w.DB.
+		WithContext(ctx).
+		Select("users.*").
+		Joins("JOIN wishlist_items w ON w.user_id = users.id").
+		Where("w.course_id = ? AND users.email_verified", courseId).
+		Where("NOT EXISTS (SELECT 1 FROM enrollments e WHERE e.user_id = users.id AND e.course_id = w.course_id)").
+		Where(`NOT EXISTS (SELECT 1 FROM accounts a JOIN account_settings s ON s.account_id = a.id
+			WHERE a.user_id = users.id AND s.notifications -> ? ->> ? = 'false')`, entity.NotificationChannelEmail, entity.NotificationPriceDrop).
+		Find(&users).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
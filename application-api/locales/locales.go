@@ -0,0 +1,10 @@
+// Package locales embeds translations of API messages.
+package locales
+
+import "embed"
+
+// FS contains catalogs of messages by errs code named by language tag, e.g. uk.json.
+// Messages are written in English, so there is no catalog for it.
+//
+//go:embed *.json
+var FS embed.FS
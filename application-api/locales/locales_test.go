@@ -0,0 +1,127 @@
+package locales
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// errorMessages returns messages by errs code of errs.New calls and httpResponseError literals of the sources,
+// messages built at runtime are returned as their source expression.
+func errorMessages(t *testing.T, root string) map[string]map[string]bool {
+	t.Helper()
+
+	messages := make(map[string]map[string]bool)
+	add := func(fset *token.FileSet, source []byte, code, message ast.Expr) {
+		literal, ok := code.(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return
+		}
+		value, err := strconv.Unquote(literal.Value)
+		if err != nil {
+			t.Fatalf("failed to unquote %s: %v", literal.Value, err)
+		}
+		if messages[value] == nil {
+			messages[value] = make(map[string]bool)
+		}
+		messages[value][string(source[fset.Position(message.Pos()).Offset:fset.Position(message.End()).Offset])] = true
+	}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, source, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.CallExpr:
+				selector, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || selector.Sel.Name != "New" || len(node.Args) != 2 {
+					return true
+				}
+				if pkg, ok := selector.X.(*ast.Ident); ok && pkg.Name == "errs" {
+					add(fset, source, node.Args[1], node.Args[0])
+				}
+			case *ast.CompositeLit:
+				if typ, ok := node.Type.(*ast.Ident); !ok || typ.Name != "httpResponseError" {
+					return true
+				}
+				fields := make(map[string]ast.Expr)
+				for _, element := range node.Elts {
+					if field, ok := element.(*ast.KeyValueExpr); ok {
+						fields[field.Key.(*ast.Ident).Name] = field.Value
+					}
+				}
+				if fields["Code"] != nil && fields["Message"] != nil {
+					add(fset, source, fields["Code"], fields["Message"])
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read sources: %v", err)
+	}
+	return messages
+}
+
+func TestCatalogs(t *testing.T) {
+	messages := errorMessages(t, "../internal")
+	if len(messages) == 0 {
+		t.Fatal("no errs codes found")
+	}
+
+	for code, sources := range messages {
+		if len(sources) > 1 {
+			keys := make([]string, 0, len(sources))
+			for source := range sources {
+				keys = append(keys, source)
+			}
+			t.Errorf("code %q is shared by several messages: %s", code, strings.Join(keys, "; "))
+		}
+	}
+
+	names, err := fs.Glob(FS, "*.json")
+	if err != nil || len(names) == 0 {
+		t.Fatalf("failed to list catalogs: %v", err)
+	}
+	for _, name := range names {
+		data, err := fs.ReadFile(FS, name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		var catalog map[string]string
+		err = json.Unmarshal(data, &catalog)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", name, err)
+		}
+
+		for code := range messages {
+			if catalog[code] == "" {
+				t.Errorf("%s has no message of code %q", name, code)
+			}
+		}
+		for code := range catalog {
+			if messages[code] == nil {
+				t.Errorf("%s has message of unknown code %q", name, code)
+			}
+		}
+	}
+}
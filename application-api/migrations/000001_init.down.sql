@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS nodes;
+DROP TABLE IF EXISTS account_settings;
+DROP TABLE IF EXISTS account_devices;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS courses;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,59 @@
+-- Initial schema. Statements are idempotent so databases previously created by
+-- gorm AutoMigrate are adopted as is.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS users (
+    id       uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    username text,
+    email    text,
+    type     bigint,
+    password text
+);
+
+CREATE TABLE IF NOT EXISTS courses (
+    id              uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name            text,
+    teacher_id      text,
+    author          text,
+    description     text,
+    price           decimal,
+    course_language text
+);
+CREATE INDEX IF NOT EXISTS idx_courses_name ON courses (name);
+CREATE INDEX IF NOT EXISTS idx_courses_teacher_id ON courses (teacher_id);
+CREATE INDEX IF NOT EXISTS idx_courses_author ON courses (author);
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id      uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id uuid
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id ON accounts (user_id);
+
+CREATE TABLE IF NOT EXISTS account_devices (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id  uuid,
+    name        text,
+    os          text,
+    mac_address text,
+    active      boolean,
+    CONSTRAINT fk_accounts_account_devices FOREIGN KEY (account_id)
+        REFERENCES accounts (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_account_devices_account_id ON account_devices (account_id);
+
+CREATE TABLE IF NOT EXISTS account_settings (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id uuid,
+    language   text,
+    CONSTRAINT fk_accounts_account_settings FOREIGN KEY (account_id)
+        REFERENCES accounts (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_account_settings_account_id ON account_settings (account_id);
+
+CREATE TABLE IF NOT EXISTS nodes (
+    id                   uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sender_email         text,
+    sender_mac_address   text,
+    receiver_email       text,
+    receiver_mac_address text
+);
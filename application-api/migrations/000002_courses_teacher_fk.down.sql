@@ -0,0 +1,5 @@
+ALTER TABLE courses
+    DROP CONSTRAINT IF EXISTS fk_courses_teacher;
+
+ALTER TABLE courses
+    ALTER COLUMN teacher_id TYPE text USING teacher_id::text;
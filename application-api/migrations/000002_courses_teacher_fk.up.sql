@@ -0,0 +1,13 @@
+-- courses with malformed teacher ids or ids of missing users lose their teacher, so neither the cast nor the key fails
+UPDATE courses
+SET teacher_id = NULL
+WHERE teacher_id IS NOT NULL
+  AND (teacher_id::text !~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+    OR NOT EXISTS (SELECT 1 FROM users WHERE users.id::text = lower(courses.teacher_id::text)));
+
+ALTER TABLE courses
+    ALTER COLUMN teacher_id TYPE uuid USING teacher_id::uuid;
+
+ALTER TABLE courses
+    ADD CONSTRAINT fk_courses_teacher FOREIGN KEY (teacher_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE RESTRICT;
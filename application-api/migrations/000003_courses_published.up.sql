@@ -0,0 +1,4 @@
+ALTER TABLE courses
+    ADD COLUMN published boolean NOT NULL DEFAULT true;
+
+CREATE INDEX idx_courses_published ON courses (published);
@@ -0,0 +1,17 @@
+ALTER TABLE users
+    ADD COLUMN email_verified boolean NOT NULL DEFAULT false;
+
+-- users registered before verification existed had no way to verify
+UPDATE users SET email_verified = true;
+
+CREATE TABLE one_time_tokens (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    purpose    text NOT NULL,
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_one_time_tokens_token_hash ON one_time_tokens (token_hash);
+CREATE INDEX idx_one_time_tokens_user_id ON one_time_tokens (user_id);
@@ -0,0 +1,6 @@
+CREATE TABLE login_failures (
+    key             text PRIMARY KEY,
+    failures        bigint NOT NULL DEFAULT 0,
+    last_failure_at timestamptz NOT NULL,
+    locked_until    timestamptz
+);
@@ -0,0 +1,7 @@
+CREATE TABLE rate_limit_buckets (
+    key        text PRIMARY KEY,
+    tokens     double precision NOT NULL,
+    allowed    boolean NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
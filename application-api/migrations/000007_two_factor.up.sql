@@ -0,0 +1,17 @@
+CREATE TABLE two_factors (
+    user_id        uuid PRIMARY KEY REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    secret         text NOT NULL,
+    enabled        boolean NOT NULL DEFAULT false,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    enabled_at     timestamptz,
+    created_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE recovery_codes (
+    id        uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id   uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    used_at   timestamptz
+);
+CREATE UNIQUE INDEX idx_recovery_codes_code_hash ON recovery_codes (code_hash);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
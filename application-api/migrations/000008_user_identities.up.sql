@@ -0,0 +1,17 @@
+CREATE TABLE user_identities (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    issuer     text NOT NULL,
+    subject    text NOT NULL,
+    email      text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_user_identities_issuer_subject ON user_identities (issuer, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+
+CREATE TABLE oidc_states (
+    state_hash    text PRIMARY KEY,
+    nonce         text NOT NULL,
+    code_verifier text NOT NULL,
+    expires_at    timestamptz NOT NULL
+);
@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id           uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id      uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    name         text NOT NULL,
+    prefix       text NOT NULL,
+    key_hash     text NOT NULL,
+    scopes       text NOT NULL DEFAULT '',
+    expires_at   timestamptz,
+    last_used_at timestamptz,
+    revoked_at   timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
@@ -0,0 +1,8 @@
+CREATE TABLE enrollments (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id  uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_enrollments_user_course ON enrollments (user_id, course_id);
+CREATE INDEX idx_enrollments_course_id ON enrollments (course_id);
@@ -0,0 +1,20 @@
+CREATE TABLE reviews (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id  uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    rating     integer NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text       text NOT NULL DEFAULT '',
+    reply      text NOT NULL DEFAULT '',
+    replied_at timestamptz,
+    hidden     boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_reviews_course_user ON reviews (course_id, user_id);
+
+-- aggregates are updated together with reviews, so course lists don't compute them
+ALTER TABLE courses
+    ADD COLUMN rating_count   integer NOT NULL DEFAULT 0,
+    ADD COLUMN rating_sum     integer NOT NULL DEFAULT 0,
+    ADD COLUMN rating_average double precision GENERATED ALWAYS AS
+        (CASE WHEN rating_count = 0 THEN 0 ELSE rating_sum::double precision / rating_count END) STORED;
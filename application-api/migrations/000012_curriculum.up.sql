@@ -0,0 +1,20 @@
+CREATE TABLE sections (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id  uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    title      text NOT NULL,
+    position   integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_sections_course_id ON sections (course_id);
+
+CREATE TABLE lessons (
+    id               uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id        uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    section_id       uuid NOT NULL REFERENCES sections (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    title            text NOT NULL,
+    position         integer NOT NULL DEFAULT 0,
+    duration_seconds integer NOT NULL DEFAULT 0,
+    created_at       timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_lessons_course_id ON lessons (course_id);
+CREATE INDEX idx_lessons_section_id ON lessons (section_id);
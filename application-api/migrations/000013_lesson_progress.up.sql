@@ -0,0 +1,11 @@
+CREATE TABLE lesson_progress (
+    user_id         uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    lesson_id       uuid NOT NULL REFERENCES lessons (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id       uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    watched_seconds integer NOT NULL DEFAULT 0,
+    last_position   integer NOT NULL DEFAULT 0,
+    completed       boolean NOT NULL DEFAULT false,
+    updated_at      timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, lesson_id)
+);
+CREATE INDEX idx_lesson_progress_user_course ON lesson_progress (user_id, course_id, updated_at DESC);
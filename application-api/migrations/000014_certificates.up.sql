@@ -0,0 +1,13 @@
+CREATE TABLE certificates (
+    id             uuid PRIMARY KEY,
+    user_id        uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id      uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    username       text NOT NULL,
+    course_name    text NOT NULL,
+    author         text NOT NULL DEFAULT '',
+    issued_at      timestamptz NOT NULL DEFAULT now(),
+    signature      text NOT NULL,
+    revoked_at     timestamptz,
+    revoked_reason text NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX idx_certificates_user_course ON certificates (user_id, course_id);
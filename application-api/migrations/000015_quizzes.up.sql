@@ -0,0 +1,43 @@
+CREATE TABLE quizzes (
+    id                 uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id          uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    section_id         uuid NOT NULL REFERENCES sections (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    title              text NOT NULL,
+    position           integer NOT NULL DEFAULT 0,
+    passing_score      integer NOT NULL DEFAULT 0 CHECK (passing_score BETWEEN 0 AND 100),
+    max_attempts       integer NOT NULL DEFAULT 0,
+    time_limit_seconds integer NOT NULL DEFAULT 0,
+    created_at         timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_quizzes_course_id ON quizzes (course_id);
+CREATE INDEX idx_quizzes_section_id ON quizzes (section_id);
+
+CREATE TABLE quiz_questions (
+    id       uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quiz_id  uuid NOT NULL REFERENCES quizzes (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    position integer NOT NULL DEFAULT 0,
+    type     text NOT NULL,
+    text     text NOT NULL,
+    options  jsonb NOT NULL DEFAULT '[]',
+    answers  jsonb NOT NULL DEFAULT '[]',
+    points   integer NOT NULL DEFAULT 1
+);
+CREATE INDEX idx_quiz_questions_quiz_id ON quiz_questions (quiz_id);
+
+CREATE TABLE quiz_attempts (
+    id           uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quiz_id      uuid NOT NULL REFERENCES quizzes (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_id      uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id    uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    started_at   timestamptz NOT NULL DEFAULT now(),
+    expires_at   timestamptz,
+    submitted_at timestamptz,
+    answers      jsonb NOT NULL DEFAULT '{}',
+    score        integer NOT NULL DEFAULT 0,
+    max_score    integer NOT NULL DEFAULT 0,
+    percent      integer NOT NULL DEFAULT 0,
+    passed       boolean NOT NULL DEFAULT false
+);
+CREATE INDEX idx_quiz_attempts_user_quiz ON quiz_attempts (user_id, quiz_id);
+-- course completion looks up passed quizzes only
+CREATE INDEX idx_quiz_attempts_passed ON quiz_attempts (user_id, course_id) WHERE passed;
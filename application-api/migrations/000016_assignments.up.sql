@@ -0,0 +1,35 @@
+CREATE TABLE assignments (
+    id                   uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id            uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    title                text NOT NULL,
+    description          text NOT NULL DEFAULT '',
+    max_score            integer NOT NULL,
+    due_at               timestamptz NOT NULL,
+    late_days            integer NOT NULL DEFAULT 0,
+    late_penalty_percent integer NOT NULL DEFAULT 0,
+    created_at           timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_assignments_course_id ON assignments (course_id);
+
+CREATE TABLE submissions (
+    id            uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    assignment_id uuid NOT NULL REFERENCES assignments (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_id       uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id     uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    file_name     text NOT NULL,
+    content_type  text NOT NULL,
+    size          bigint NOT NULL,
+    blob_key      text NOT NULL,
+    comment       text NOT NULL DEFAULT '',
+    submitted_at  timestamptz NOT NULL DEFAULT now(),
+    days_late     integer NOT NULL DEFAULT 0,
+    status        text NOT NULL DEFAULT 'submitted',
+    score         integer,
+    final_score   integer,
+    feedback      text NOT NULL DEFAULT '',
+    graded_by     uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    graded_at     timestamptz
+);
+CREATE UNIQUE INDEX idx_submissions_assignment_user ON submissions (assignment_id, user_id);
+-- grading queue lists ungraded submissions of teacher courses, oldest first
+CREATE INDEX idx_submissions_queue ON submissions (course_id, submitted_at) WHERE status = 'submitted';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS discussion_reports;
+DROP TABLE IF EXISTS discussion_votes;
+DROP TABLE IF EXISTS discussion_posts;
+DROP TABLE IF EXISTS discussion_threads;
@@ -0,0 +1,56 @@
+CREATE TABLE discussion_threads (
+    id               uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id        uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    lesson_id        uuid REFERENCES lessons (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_id          uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    title            text NOT NULL,
+    body             text NOT NULL,
+    upvotes          integer NOT NULL DEFAULT 0,
+    post_count       integer NOT NULL DEFAULT 0,
+    accepted_post_id uuid,
+    hidden           boolean NOT NULL DEFAULT false,
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    last_activity_at timestamptz NOT NULL DEFAULT now()
+);
+-- threads are paginated by last activity with id as tie breaker
+CREATE INDEX idx_discussion_threads_course ON discussion_threads (course_id, last_activity_at DESC, id DESC);
+CREATE INDEX idx_discussion_threads_lesson ON discussion_threads (lesson_id, last_activity_at DESC, id DESC) WHERE lesson_id IS NOT NULL;
+
+CREATE TABLE discussion_posts (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    thread_id  uuid NOT NULL REFERENCES discussion_threads (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    parent_id  uuid REFERENCES discussion_posts (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    body       text NOT NULL,
+    upvotes    integer NOT NULL DEFAULT 0,
+    hidden     boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_discussion_posts_thread ON discussion_posts (thread_id, created_at, id);
+
+ALTER TABLE discussion_threads
+    ADD CONSTRAINT fk_discussion_threads_accepted_post FOREIGN KEY (accepted_post_id)
+        REFERENCES discussion_posts (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+-- target is thread or post, their ids don't collide
+CREATE TABLE discussion_votes (
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    target_id  uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, target_id)
+);
+
+CREATE TABLE discussion_reports (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    target_type text NOT NULL,
+    target_id   uuid NOT NULL,
+    course_id   uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    reporter_id uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    reason      text NOT NULL DEFAULT '',
+    status      text NOT NULL DEFAULT 'open',
+    resolved_by uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    resolved_at timestamptz,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX idx_discussion_reports_target_reporter ON discussion_reports (target_id, reporter_id);
+CREATE INDEX idx_discussion_reports_open ON discussion_reports (target_id, created_at) WHERE status = 'open';
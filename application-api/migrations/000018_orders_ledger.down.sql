@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS ledger_entries;
+DROP FUNCTION IF EXISTS ledger_transaction_balanced();
+DROP FUNCTION IF EXISTS ledger_entries_append_only();
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,76 @@
+CREATE TABLE orders (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    status     text NOT NULL DEFAULT 'pending',
+    total      bigint NOT NULL,
+    currency   text NOT NULL,
+    charge_id  text NOT NULL DEFAULT '',
+    paid_at    timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_orders_user_id ON orders (user_id, created_at DESC);
+
+CREATE TABLE order_items (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id   uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id  uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE,
+    teacher_id uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE,
+    price      bigint NOT NULL
+);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+
+CREATE TABLE payouts (
+    id           uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    teacher_id   uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE,
+    amount       bigint NOT NULL CHECK (amount > 0),
+    currency     text NOT NULL,
+    status       text NOT NULL DEFAULT 'requested',
+    note         text NOT NULL DEFAULT '',
+    resolved_by  uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    resolved_at  timestamptz,
+    requested_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_payouts_teacher_id ON payouts (teacher_id, requested_at DESC);
+CREATE INDEX idx_payouts_requested ON payouts (requested_at) WHERE status = 'requested';
+
+-- amounts are in minor units, positive debits and negative credits the account
+CREATE TABLE ledger_entries (
+    id             uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id uuid NOT NULL,
+    account        text NOT NULL,
+    kind           text NOT NULL,
+    amount         bigint NOT NULL,
+    currency       text NOT NULL,
+    course_id      uuid REFERENCES courses (id) ON UPDATE CASCADE,
+    order_item_id  uuid REFERENCES order_items (id) ON UPDATE CASCADE,
+    payout_id      uuid REFERENCES payouts (id) ON UPDATE CASCADE,
+    created_at     timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_ledger_entries_account ON ledger_entries (account, created_at);
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+
+-- ledger is append-only, mistakes are corrected with reversing transactions
+CREATE FUNCTION ledger_entries_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries can not be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_append_only();
+
+-- every transaction must balance, checked once all its entries are inserted
+CREATE FUNCTION ledger_transaction_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT sum(amount) FROM ledger_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_ledger_transaction_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_transaction_balanced();
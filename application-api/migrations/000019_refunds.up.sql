@@ -0,0 +1,17 @@
+CREATE TABLE refunds (
+    id                 uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id           uuid NOT NULL REFERENCES orders (id) ON UPDATE CASCADE,
+    order_item_id      uuid NOT NULL REFERENCES order_items (id) ON UPDATE CASCADE,
+    user_id            uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id          uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE,
+    amount             bigint NOT NULL,
+    currency           text NOT NULL,
+    reason             text NOT NULL DEFAULT '',
+    status             text NOT NULL DEFAULT 'pending',
+    provider_refund_id text NOT NULL DEFAULT '',
+    completed_at       timestamptz,
+    created_at         timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_refunds_user_id ON refunds (user_id, created_at DESC);
+-- failed refunds can be requested again, pending and completed ones block new requests
+CREATE UNIQUE INDEX idx_refunds_order_item_active ON refunds (order_item_id) WHERE status <> 'failed';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS subscription_plans;
+ALTER TABLE order_items DROP COLUMN IF EXISTS bundle_id;
+DROP TABLE IF EXISTS bundle_courses;
+DROP TABLE IF EXISTS bundles;
@@ -0,0 +1,50 @@
+CREATE TABLE bundles (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    teacher_id  uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    name        text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    price       bigint NOT NULL CHECK (price > 0),
+    published   boolean NOT NULL DEFAULT false,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_bundles_teacher_id ON bundles (teacher_id);
+
+CREATE TABLE bundle_courses (
+    bundle_id uuid NOT NULL REFERENCES bundles (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (bundle_id, course_id)
+);
+
+ALTER TABLE order_items ADD COLUMN bundle_id uuid REFERENCES bundles (id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE TABLE subscription_plans (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name       text NOT NULL,
+    price      bigint NOT NULL CHECK (price > 0),
+    currency   text NOT NULL,
+    trial_days integer NOT NULL DEFAULT 0,
+    active     boolean NOT NULL DEFAULT true,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE subscriptions (
+    id                       uuid PRIMARY KEY,
+    user_id                  uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    plan_id                  uuid NOT NULL REFERENCES subscription_plans (id) ON UPDATE CASCADE,
+    status                   text NOT NULL,
+    provider_subscription_id text NOT NULL UNIQUE,
+    current_period_end       timestamptz NOT NULL,
+    cancel_at_period_end     boolean NOT NULL DEFAULT false,
+    canceled_at              timestamptz,
+    created_at               timestamptz NOT NULL DEFAULT now(),
+    updated_at               timestamptz NOT NULL DEFAULT now()
+);
+-- user has at most one subscription which isn't canceled
+CREATE UNIQUE INDEX idx_subscriptions_user_live ON subscriptions (user_id) WHERE status <> 'canceled';
+
+-- processed provider webhooks, redelivered events are skipped
+CREATE TABLE payment_events (
+    id          text PRIMARY KEY,
+    type        text NOT NULL,
+    received_at timestamptz NOT NULL DEFAULT now()
+);
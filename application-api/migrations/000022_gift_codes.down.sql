@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS gift_codes;
+ALTER TABLE orders DROP COLUMN IF EXISTS gift;
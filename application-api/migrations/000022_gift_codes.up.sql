@@ -0,0 +1,15 @@
+ALTER TABLE orders ADD COLUMN gift boolean NOT NULL DEFAULT false;
+
+CREATE TABLE gift_codes (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code        text NOT NULL UNIQUE,
+    course_id   uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    order_id    uuid REFERENCES orders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    batch_id    uuid,
+    created_by  uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    redeemed_by uuid REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    redeemed_at timestamptz,
+    created_at  timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_gift_codes_course_id ON gift_codes (course_id, created_at);
+CREATE INDEX idx_gift_codes_created_by ON gift_codes (created_by, created_at DESC);
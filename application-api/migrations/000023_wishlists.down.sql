@@ -0,0 +1,3 @@
+ALTER TABLE account_settings DROP COLUMN IF EXISTS price_drop_alerts;
+DROP TABLE IF EXISTS course_prices;
+DROP TABLE IF EXISTS wishlist_items;
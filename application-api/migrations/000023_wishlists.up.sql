@@ -0,0 +1,18 @@
+CREATE TABLE wishlist_items (
+    user_id   uuid NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    course_id uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    added_at  timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, course_id)
+);
+CREATE INDEX idx_wishlist_items_course_id ON wishlist_items (course_id);
+
+CREATE TABLE course_prices (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id   uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    price       bigint NOT NULL,
+    recorded_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_course_prices_course_id ON course_prices (course_id, recorded_at DESC);
+
+-- NULL means user hasn't changed the setting, alerts are sent unless it is false
+ALTER TABLE account_settings ADD COLUMN price_drop_alerts boolean;
@@ -0,0 +1,13 @@
+ALTER TABLE account_settings ADD COLUMN IF NOT EXISTS price_drop_alerts boolean;
+
+UPDATE account_settings
+SET price_drop_alerts = (notifications -> 'email' ->> 'price_drop')::boolean
+WHERE notifications -> 'email' ? 'price_drop';
+
+ALTER TABLE account_settings
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS notifications,
+    DROP COLUMN IF EXISTS playback_speed,
+    DROP COLUMN IF EXISTS playback_autoplay,
+    DROP COLUMN IF EXISTS playback_captions,
+    DROP COLUMN IF EXISTS privacy_public_profile;
@@ -0,0 +1,14 @@
+ALTER TABLE account_settings
+    ADD COLUMN timezone               text NOT NULL DEFAULT 'UTC',
+    ADD COLUMN notifications          jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN playback_speed         double precision NOT NULL DEFAULT 1,
+    ADD COLUMN playback_autoplay      boolean NOT NULL DEFAULT true,
+    ADD COLUMN playback_captions      boolean NOT NULL DEFAULT false,
+    ADD COLUMN privacy_public_profile boolean NOT NULL DEFAULT false;
+
+-- price drop opt-out becomes one of notification preferences
+UPDATE account_settings
+SET notifications = jsonb_build_object('email', jsonb_build_object('price_drop', price_drop_alerts))
+WHERE price_drop_alerts IS NOT NULL;
+
+ALTER TABLE account_settings DROP COLUMN price_drop_alerts;
@@ -0,0 +1,17 @@
+CREATE TABLE teacher_profiles (
+    user_id      uuid PRIMARY KEY REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    display_name text NOT NULL,
+    bio          text NOT NULL DEFAULT '',
+    avatar_url   text NOT NULL DEFAULT '',
+    social_links jsonb NOT NULL DEFAULT '{}',
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
+
+-- existing teachers get profiles named after them and course authors are derived from the profiles
+INSERT INTO teacher_profiles (user_id, display_name)
+SELECT id, COALESCE(username, '') FROM users WHERE type = 2;
+
+UPDATE courses c
+SET author = p.display_name
+FROM teacher_profiles p
+WHERE p.user_id = c.teacher_id;
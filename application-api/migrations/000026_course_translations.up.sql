@@ -0,0 +1,8 @@
+CREATE TABLE course_translations (
+    course_id   uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    language    text NOT NULL,
+    name        text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (course_id, language)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transcript_cues;
+DROP TABLE IF EXISTS lesson_subtitles;
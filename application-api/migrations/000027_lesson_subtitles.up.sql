@@ -0,0 +1,27 @@
+CREATE TABLE lesson_subtitles (
+    lesson_id  uuid NOT NULL REFERENCES lessons (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    language   text NOT NULL,
+    course_id  uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    label      text NOT NULL,
+    content    text NOT NULL,
+    cue_count  integer NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (lesson_id, language)
+);
+CREATE INDEX idx_lesson_subtitles_course_id ON lesson_subtitles (course_id);
+
+-- simple configuration doesn't stem words, so transcripts in any language are indexed the same way
+CREATE TABLE transcript_cues (
+    id        uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    lesson_id uuid NOT NULL,
+    language  text NOT NULL,
+    course_id uuid NOT NULL REFERENCES courses (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    start_ms  bigint NOT NULL,
+    end_ms    bigint NOT NULL,
+    text      text NOT NULL,
+    search    tsvector GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED,
+    FOREIGN KEY (lesson_id, language) REFERENCES lesson_subtitles (lesson_id, language) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX idx_transcript_cues_lesson_id ON transcript_cues (lesson_id, language);
+CREATE INDEX idx_transcript_cues_course_id ON transcript_cues (course_id);
+CREATE INDEX idx_transcript_cues_search ON transcript_cues USING gin (search);
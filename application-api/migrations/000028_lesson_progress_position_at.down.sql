@@ -0,0 +1,2 @@
+ALTER TABLE lesson_progress
+    DROP COLUMN IF EXISTS position_at;
@@ -0,0 +1,4 @@
+ALTER TABLE lesson_progress
+    ADD COLUMN position_at timestamptz NOT NULL DEFAULT now();
+
+UPDATE lesson_progress SET position_at = updated_at;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS teacher_profiles_display_name_key;
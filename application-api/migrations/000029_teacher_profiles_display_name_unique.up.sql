@@ -0,0 +1,35 @@
+-- display names are author names of courses, later duplicates are numbered before they are made unique,
+-- numbers taken by other names are skipped
+DO $$
+DECLARE
+    duplicate record;
+    candidate text;
+    n         int;
+BEGIN
+    FOR duplicate IN
+        SELECT user_id, display_name
+        FROM (
+            SELECT user_id, display_name,
+                row_number() OVER (PARTITION BY lower(display_name) ORDER BY updated_at, user_id) AS position
+            FROM teacher_profiles
+        ) ranked
+        WHERE position > 1
+    LOOP
+        n := 2;
+        LOOP
+            candidate := duplicate.display_name || ' ' || n;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM teacher_profiles WHERE lower(display_name) = lower(candidate));
+            n := n + 1;
+        END LOOP;
+
+        UPDATE teacher_profiles SET display_name = candidate WHERE user_id = duplicate.user_id;
+    END LOOP;
+END;
+$$;
+
+UPDATE courses c
+SET author = p.display_name
+FROM teacher_profiles p
+WHERE p.user_id = c.teacher_id AND c.author <> p.display_name;
+
+CREATE UNIQUE INDEX teacher_profiles_display_name_key ON teacher_profiles (lower(display_name));
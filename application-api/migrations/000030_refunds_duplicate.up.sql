@@ -0,0 +1,3 @@
+-- duplicate refund returns price of course which user got another way while order was paid, access stays
+ALTER TABLE refunds
+    ADD COLUMN duplicate boolean NOT NULL DEFAULT false;
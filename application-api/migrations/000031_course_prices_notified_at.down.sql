@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_course_prices_unnotified;
+
+ALTER TABLE course_prices
+    DROP COLUMN IF EXISTS notified_at;
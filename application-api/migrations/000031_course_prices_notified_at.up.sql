@@ -0,0 +1,8 @@
+-- notified_at is set when price drop notification is taken by a check and cleared again when it fails, so the
+-- next check retries it
+ALTER TABLE course_prices
+    ADD COLUMN notified_at timestamptz;
+
+UPDATE course_prices SET notified_at = recorded_at;
+
+CREATE INDEX idx_course_prices_unnotified ON course_prices (recorded_at) WHERE notified_at IS NULL;
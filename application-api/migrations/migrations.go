@@ -0,0 +1,9 @@
+// Package migrations embeds versioned SQL migrations of the application database.
+package migrations
+
+import "embed"
+
+// FS contains up and down scripts named {version}_{name}.{up|down}.sql.
+//
+//go:embed *.sql
+var FS embed.FS
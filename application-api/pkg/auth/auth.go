@@ -1,8 +1,26 @@
 package auth
 
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+)
+
 type Authenticator interface {
 	GenerateToken(options *GenerateTokenClaimsOptions) (string, error)
 	ParseToken(accessToken string) (*ParseTokenClaimsOutput, error)
+	// PublicKeys returns keys tokens are verified with, to be published for other services.
+	PublicKeys() *jwks.Set
+}
+
+// Signer signs documents which don't expire, e.g. certificates, with keys separate from access token ones,
+// so rotating access token keys doesn't invalidate documents.
+type Signer interface {
+	// SignClaims signs document claims with the first key.
+	SignClaims(claims jwt.Claims) (string, error)
+	// ParseClaims verifies signature of document signed by SignClaims with any of keys and decodes its claims.
+	ParseClaims(signed string, claims jwt.Claims) error
+	// PublicKeys returns keys documents are verified with, so they can be verified offline.
+	PublicKeys() *jwks.Set
 }
 
 type GenerateTokenClaimsOptions struct {
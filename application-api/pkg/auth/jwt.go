@@ -1,18 +1,93 @@
 package auth
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+	"os"
 	"time"
 )
 
+const (
+	_issuer   = "application-api"
+	_audience = "application-api"
+)
+
+var _validMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
+// Config - represents token signing configuration.
+// First key signs new tokens, the rest only verify, so rotated keys stay valid until issued tokens expire.
+type Config struct {
+	KeyFiles []string
+	TTL      time.Duration
+}
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+}
+
 type jwtAuthenticator struct {
-	signKey string
+	keys       []*signingKey
+	keysById   map[string]*signingKey
+	publicKeys *jwks.Set
+	ttl        time.Duration
 }
 
-func NewAuth() Authenticator {
-	return &jwtAuthenticator{}
+var (
+	_ Authenticator = (*jwtAuthenticator)(nil)
+	_ Signer        = (*jwtAuthenticator)(nil)
+)
+
+// NewAuth - creates authenticator signing tokens with RS256 or EdDSA keys read from PEM files.
+func NewAuth(config Config) (Authenticator, error) {
+	return newJWTAuthenticator(config)
+}
+
+// NewSigner - creates signer of documents with RS256 or EdDSA keys read from PEM files, the first one signs.
+// Documents don't expire, so keys are never removed: a new key is put in front and the old ones stay to verify.
+func NewSigner(keyFiles []string) (Signer, error) {
+	return newJWTAuthenticator(Config{KeyFiles: keyFiles})
+}
+
+func newJWTAuthenticator(config Config) (*jwtAuthenticator, error) {
+	if len(config.KeyFiles) == 0 {
+		return nil, fmt.Errorf("no signing key configured")
+	}
+
+	authenticator := &jwtAuthenticator{
+		keysById:   make(map[string]*signingKey, len(config.KeyFiles)),
+		publicKeys: &jwks.Set{Keys: make([]jwks.Key, 0, len(config.KeyFiles))},
+		ttl:        config.TTL,
+	}
+	for _, path := range config.KeyFiles {
+		private, err := readPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", path, err)
+		}
+
+		publicKey, err := jwks.NewKey(private.Public())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", path, err)
+		}
+		if _, ok := authenticator.keysById[publicKey.Kid]; ok {
+			return nil, fmt.Errorf("key %s is configured twice", path)
+		}
+
+		key := &signingKey{kid: publicKey.Kid, method: jwt.GetSigningMethod(publicKey.Alg), private: private}
+		authenticator.keys = append(authenticator.keys, key)
+		authenticator.keysById[key.kid] = key
+		authenticator.publicKeys.Keys = append(authenticator.publicKeys.Keys, publicKey)
+	}
+
+	return authenticator, nil
 }
 
 type MyCustomClaims struct {
@@ -22,24 +97,30 @@ type MyCustomClaims struct {
 }
 
 func (s *jwtAuthenticator) GenerateToken(tokenClaims *GenerateTokenClaimsOptions) (string, error) {
-	mySigningKey := []byte(s.signKey)
-
 	claims := MyCustomClaims{
 		Username: tokenClaims.UserName,
 		UserId:   tokenClaims.UserId,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(560 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "application-api",
-			Subject:   "client",
+			Issuer:    _issuer,
+			Subject:   tokenClaims.UserId,
 			ID:        uuid.NewString(),
-			Audience:  []string{"application-api"},
+			Audience:  []string{_audience},
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	signedToken, err := token.SignedString(mySigningKey)
+	return s.SignClaims(claims)
+}
+
+func (s *jwtAuthenticator) SignClaims(claims jwt.Claims) (string, error) {
+	key := s.keys[0]
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	signedToken, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
@@ -48,32 +129,85 @@ func (s *jwtAuthenticator) GenerateToken(tokenClaims *GenerateTokenClaimsOptions
 }
 
 func (s *jwtAuthenticator) ParseToken(accessToken string) (*ParseTokenClaimsOutput, error) {
-	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-		// Don't forget to validate the alg is what you expect:
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		return []byte(s.signKey), nil
-	})
+	claims := &MyCustomClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, s.keyFunc,
+		jwt.WithValidMethods(_validMethods),
+		jwt.WithIssuer(_issuer),
+		jwt.WithAudience(_audience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse jwt token: %w", err)
 	}
 
-	if !token.Valid {
+	if claims.UserId == "" || claims.Username == "" {
 		return nil, fmt.Errorf("token is not valid")
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
+	return &ParseTokenClaimsOutput{UserId: claims.UserId, Username: claims.Username}, nil
+}
 
-	username := claims["username"]
-	if username == nil {
-		return nil, fmt.Errorf("token is not valid")
+func (s *jwtAuthenticator) ParseClaims(signed string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(signed, claims, s.keyFunc, jwt.WithValidMethods(_validMethods))
+	if err != nil {
+		return fmt.Errorf("failed to parse signed claims: %w", err)
 	}
-	userId := claims["userId"]
-	if userId == nil {
-		return nil, fmt.Errorf("token is not valid")
+
+	return nil
+}
+
+func (s *jwtAuthenticator) PublicKeys() *jwks.Set {
+	return s.publicKeys
+}
+
+func (s *jwtAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keysById[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	// key decides the algorithm, so tokens can't pick a weaker one
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.private.Public(), nil
+}
+
+// readPrivateKey reads PKCS#8 RSA or Ed25519 key, or PKCS#1 RSA key from PEM file.
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA key must be at least 2048 bits")
+		}
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
 	}
 
-	return &ParseTokenClaimsOutput{UserId: fmt.Sprint(userId), Username: fmt.Sprint(username)}, nil
+	return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", key)
 }
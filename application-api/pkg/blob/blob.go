@@ -0,0 +1,20 @@
+// Package blob implements storage of uploaded files.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when there is no blob with given key.
+var ErrNotFound = errors.New("blob not found")
+
+type Storage interface {
+	// Put stores content under key replacing existing blob, returns number of bytes written.
+	Put(ctx context.Context, key string, content io.Reader) (int64, error)
+	// Get opens blob for reading, reader must be closed.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes blob, deleting missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
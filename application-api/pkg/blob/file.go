@@ -0,0 +1,105 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileStorage keeps blobs as files under root directory, keys are slash separated relative paths.
+type fileStorage struct {
+	root string
+}
+
+var _ Storage = (*fileStorage)(nil)
+
+// NewFile - creates storage keeping blobs in directory, it is created on first write.
+func NewFile(root string) Storage {
+	return &fileStorage{root: root}
+}
+
+func (s *fileStorage) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// readers never see partially written blob, it is renamed into place once complete
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		file.Close()
+		return 0, fmt.Errorf("failed to write blob: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save blob: %w", err)
+	}
+
+	return written, nil
+}
+
+func (s *fileStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return file, nil
+}
+
+func (s *fileStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// path maps key to file under root, keys escaping root are rejected.
+func (s *fileStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -11,6 +11,11 @@ func New(message, code string) *Err {
 	return &Err{Message: message, Code: code}
 }
 
+// WithDetails returns copy of the error with given details, so shared errors stay untouched.
+func (e *Err) WithDetails(details map[string]string) *Err {
+	return &Err{Message: e.Message, Code: e.Code, Details: details}
+}
+
 func (e *Err) Error() string {
 	return e.Message
 }
@@ -29,3 +34,12 @@ func GetCode(err error) string {
 	}
 	return v.Code
 }
+
+// GetDetails returns details of given error or nil if error is not custom
+func GetDetails(err error) map[string]string {
+	v, ok := err.(*Err)
+	if !ok {
+		return nil
+	}
+	return v.Details
+}
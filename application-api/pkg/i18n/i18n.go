@@ -0,0 +1,142 @@
+// Package i18n implements language negotiation and translation of messages by key.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Translator translates messages written in fallback language to languages it has catalogs for.
+type Translator struct {
+	fallback string
+	catalogs map[string]map[string]string
+}
+
+// New reads catalogs of messages by key from JSON files of files named by language tag, e.g. uk.json.
+// Messages of fallback language aren't read, they are passed to Translate.
+func New(files fs.FS, fallback string) (*Translator, error) {
+	names, err := fs.Glob(files, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+
+	translator := &Translator{fallback: fallback, catalogs: make(map[string]map[string]string, len(names))}
+	for _, name := range names {
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog %s: %w", name, err)
+		}
+
+		var catalog map[string]string
+		err = json.Unmarshal(data, &catalog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode catalog %s: %w", name, err)
+		}
+		translator.catalogs[strings.ToLower(strings.TrimSuffix(name, path.Ext(name)))] = catalog
+	}
+
+	return translator, nil
+}
+
+// Languages returns fallback language followed by languages with catalogs.
+func (t *Translator) Languages() []string {
+	languages := make([]string, 0, len(t.catalogs)+1)
+	languages = append(languages, t.fallback)
+	for language := range t.catalogs {
+		if language != t.fallback {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages[1:])
+	return languages
+}
+
+// Negotiate returns the first of preferred languages translator has messages in, or fallback language.
+func (t *Translator) Negotiate(preferred []string) string {
+	language, ok := Match(preferred, t.Languages())
+	if !ok {
+		return t.fallback
+	}
+	return language
+}
+
+// Translate returns message of key in language, message is returned as is when it isn't translated.
+func (t *Translator) Translate(language, key, message string) string {
+	translated, ok := t.catalogs[strings.ToLower(language)][key]
+	if !ok {
+		return message
+	}
+	return translated
+}
+
+// ParseAcceptLanguage returns language tags of Accept-Language header from the most preferred one,
+// wildcard and tags with zero quality are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	// equal qualities keep order of the header
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, tag.tag)
+	}
+	return result
+}
+
+// Match returns the first of preferred languages which is available. Preferred language matches available one
+// exactly or by its base language, so pt-BR matches pt when pt-BR isn't available. Tags are case-insensitive.
+func Match(preferred, available []string) (string, bool) {
+	for _, language := range preferred {
+		for _, candidate := range []string{language, Base(language)} {
+			for _, option := range available {
+				if strings.EqualFold(candidate, option) {
+					return option, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// Base returns base language of tag, e.g. pt of pt-BR.
+func Base(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return base
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{header: "", want: []string{}},
+		{header: "uk", want: []string{"uk"}},
+		{header: "uk-UA,uk;q=0.9,en;q=0.8", want: []string{"uk-UA", "uk", "en"}},
+		{header: "en;q=0.5, pt-BR", want: []string{"pt-BR", "en"}},
+		{header: "de;q=0.7,fr;q=0.7,uk;q=0.9", want: []string{"uk", "de", "fr"}},
+		{header: "*, uk;q=0.5", want: []string{"uk"}},
+		{header: "uk;q=0, en", want: []string{"en"}},
+		{header: "uk;q=abc, en", want: []string{"en"}},
+		{header: "uk ; q = 0.8 , ,en", want: []string{"en", "uk"}},
+		{header: "uk;level=1", want: []string{"uk"}},
+	}
+	for _, tt := range tests {
+		got := ParseAcceptLanguage(tt.header)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	available := []string{"en", "uk", "pt-BR"}
+	tests := []struct {
+		preferred []string
+		want      string
+		wantOk    bool
+	}{
+		{preferred: []string{"uk"}, want: "uk", wantOk: true},
+		{preferred: []string{"UK"}, want: "uk", wantOk: true},
+		{preferred: []string{"uk-UA"}, want: "uk", wantOk: true},
+		{preferred: []string{"pt-br"}, want: "pt-BR", wantOk: true},
+		{preferred: []string{"pt"}},
+		{preferred: []string{"de", "en"}, want: "en", wantOk: true},
+		{preferred: []string{"de-AT", "uk-UA", "en"}, want: "uk", wantOk: true},
+		{preferred: []string{"de"}},
+		{preferred: nil},
+	}
+	for _, tt := range tests {
+		got, ok := Match(tt.preferred, available)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("Match(%q) = %q, %t, want %q, %t", tt.preferred, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	translator, err := New(fstest.MapFS{
+		"uk.json": {Data: []byte(`{"course_not_found": "Курс не знайдено."}`)},
+		"DE.json": {Data: []byte(`{}`)},
+	}, "en")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got, want := translator.Languages(), []string{"en", "de", "uk"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Languages() = %q, want %q", got, want)
+	}
+
+	negotiated := []struct {
+		preferred []string
+		want      string
+	}{
+		{preferred: []string{"uk-UA", "en"}, want: "uk"},
+		{preferred: []string{"fr"}, want: "en"},
+		{preferred: nil, want: "en"},
+	}
+	for _, tt := range negotiated {
+		if got := translator.Negotiate(tt.preferred); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.preferred, got, tt.want)
+		}
+	}
+
+	translated := []struct {
+		language string
+		key      string
+		message  string
+		want     string
+	}{
+		{language: "uk", key: "course_not_found", message: "course not found", want: "Курс не знайдено."},
+		{language: "UK", key: "course_not_found", message: "course not found", want: "Курс не знайдено."},
+		{language: "uk", key: "lesson_not_found", message: "lesson not found", want: "lesson not found"},
+		{language: "de", key: "course_not_found", message: "course not found", want: "course not found"},
+		{language: "en", key: "course_not_found", message: "course not found", want: "course not found"},
+	}
+	for _, tt := range translated {
+		if got := translator.Translate(tt.language, tt.key, tt.message); got != tt.want {
+			t.Errorf("Translate(%q, %q, %q) = %q, want %q", tt.language, tt.key, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNewInvalidCatalog(t *testing.T) {
+	_, err := New(fstest.MapFS{"uk.json": {Data: []byte(`["not", "catalog"]`)}}, "en")
+	if err == nil {
+		t.Error("New() error = nil, want error")
+	}
+}
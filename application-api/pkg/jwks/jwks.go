@@ -0,0 +1,148 @@
+// Package jwks implements JSON Web Key sets (RFC 7517) of public signing keys.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Key - represents public JSON Web Key.
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC and OKP parameters
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// Set - represents JSON Web Key Set document.
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// NewKey - creates signing key for given public key, key id is its RFC 7638 thumbprint.
+func NewKey(publicKey crypto.PublicKey) (Key, error) {
+	var key Key
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		key = Key{
+			Kty: "RSA",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		key = Key{
+			Kty: "OKP",
+			Alg: "EdDSA",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+
+	key.Use = "sig"
+	key.Kid = key.Thumbprint()
+	return key, nil
+}
+
+// Thumbprint returns RFC 7638 thumbprint of the key, it depends on required public parameters only.
+func (k *Key) Thumbprint() string {
+	// json.Marshal sorts map keys, as the canonical form requires
+	members := map[string]string{"kty": k.Kty}
+	switch k.Kty {
+	case "RSA":
+		members["n"], members["e"] = k.N, k.E
+	case "EC":
+		members["crv"], members["x"], members["y"] = k.Crv, k.X, k.Y
+	case "OKP":
+		members["crv"], members["x"] = k.Crv, k.X
+	}
+
+	canonical, _ := json.Marshal(members)
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Find returns key with given id.
+func (s *Set) Find(kid string) (*Key, bool) {
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// PublicKey decodes key into *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func (k *Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// fileMailer writes messages to a file instead of delivering them, used for local development.
+type fileMailer struct {
+	mu   sync.Mutex
+	from string
+	path string
+}
+
+var _ Mailer = (*fileMailer)(nil)
+
+// NewFile - creates mailer appending messages to file at path, or writing them to stdout if path is empty.
+func NewFile(from, path string) Mailer {
+	return &fileMailer{from: from, path: path}
+}
+
+func (m *fileMailer) Send(ctx context.Context, message *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var w io.Writer = os.Stdout
+	if m.path != "" {
+		file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open mail file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	_, err := fmt.Fprintf(w, "%s\r\n\r\n", build(m.from, message))
+	if err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+// Package mailer implements sending of emails.
+package mailer
+
+import "context"
+
+type Mailer interface {
+	// Send delivers message to its recipient.
+	Send(ctx context.Context, message *Message) error
+}
+
+// Message - represents plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
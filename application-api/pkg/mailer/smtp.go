@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig - represents SMTP server config.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+var _ Mailer = (*smtpMailer)(nil)
+
+// NewSMTP - creates mailer sending messages through SMTP server.
+func NewSMTP(config SMTPConfig) Mailer {
+	return &smtpMailer{config: config}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message *Message) error {
+	var auth smtp.Auth
+	if m.config.User != "" {
+		auth = smtp.PlainAuth("", m.config.User, m.config.Password, m.config.Host)
+	}
+
+	err := smtp.SendMail(
+		net.JoinHostPort(m.config.Host, m.config.Port),
+		auth,
+		m.config.From,
+		[]string{message.To},
+		build(m.config.From, message),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// build formats message as RFC 5322 email.
+func build(from string, message *Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + message.To + "\r\n")
+	b.WriteString("Subject: " + message.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
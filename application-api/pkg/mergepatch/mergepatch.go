@@ -0,0 +1,62 @@
+// Package mergepatch implements JSON Merge Patch (RFC 7396).
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Apply returns document changed by patch. Members of patch object replace members of document object
+// recursively and null members remove them, any other patch value replaces the whole document.
+func Apply(document, patch []byte) ([]byte, error) {
+	var target interface{}
+	if len(bytes.TrimSpace(document)) > 0 {
+		err := decode(document, &target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+	}
+
+	var changes interface{}
+	err := decode(patch, &changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode patch: %w", err)
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{}, len(patchObject))
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = merge(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// decode keeps numbers as they are written, so patching doesn't round them.
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(v)
+	if err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
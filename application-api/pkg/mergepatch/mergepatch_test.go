@@ -0,0 +1,89 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	// RFC 7396 appendix A
+	tests := []struct {
+		document string
+		patch    string
+		want     string
+	}{
+		{document: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{document: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{document: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{document: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{document: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{document: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{document: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{document: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{document: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{document: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{document: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{document: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{document: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{document: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{document: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		// missing document is patched as if it were null
+		{document: ``, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+	}
+	for _, tt := range tests {
+		got, err := Apply([]byte(tt.document), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("Apply(%s, %s) error = %v", tt.document, tt.patch, err)
+			continue
+		}
+		if !equalJSON(t, got, []byte(tt.want)) {
+			t.Errorf("Apply(%s, %s) = %s, want %s", tt.document, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestApplyKeepsNumbers(t *testing.T) {
+	got, err := Apply([]byte(`{"id":12345678901234567890,"a":1}`), []byte(`{"a":0.1}`))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if want := `{"a":0.1,"id":12345678901234567890}`; string(got) != want {
+		t.Errorf("Apply() = %s, want %s", got, want)
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+	}{
+		{name: "invalid document", document: `{"a":`, patch: `{}`},
+		{name: "invalid patch", document: `{}`, patch: `{"a":`},
+		{name: "empty patch", document: `{}`, patch: ``},
+		{name: "data after patch", document: `{}`, patch: `{} {}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply([]byte(tt.document), []byte(tt.patch))
+			if err == nil {
+				t.Error("Apply() error = nil, want error")
+			}
+		})
+	}
+}
+
+// equalJSON reports whether documents are the same regardless of member order.
+func equalJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+
+	var av, bv interface{}
+	if err := json.Unmarshal(a, &av); err != nil {
+		t.Fatalf("failed to decode %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		t.Fatalf("failed to decode %s: %v", b, err)
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,43 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var nameSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes empty up and down scripts for a new migration into dir,
+// numbered after the latest existing one, and returns their paths.
+func Create(dir, name string) (string, string, error) {
+	name = strings.Trim(nameSanitizer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is empty")
+	}
+
+	existing, err := read(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	var version uint64 = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	prefix := fmt.Sprintf("%06d_%s", version, name)
+	upPath := filepath.Join(dir, prefix+".up.sql")
+	downPath := filepath.Join(dir, prefix+".down.sql")
+
+	for _, path := range []string{upPath, downPath} {
+		err = os.WriteFile(path, []byte("-- "+filepath.Base(path)+"\n"), 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %w", err)
+		}
+	}
+
+	return upPath, downPath, nil
+}
@@ -0,0 +1,312 @@
+// Package migrate implements versioned SQL migrations for PostgreSQL.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	_defaultTable = "schema_migrations"
+)
+
+// fileNamePattern matches migration files, e.g. 000001_init.up.sql.
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration - represents single versioned migration.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status - represents migration with its state in database.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator - applies and reverts migrations.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	table      string
+	lockKey    int64
+}
+
+// Option - represents migrator option.
+type Option func(*Migrator)
+
+// Table - configures name of the table used to track applied migrations.
+func Table(name string) Option {
+	return func(m *Migrator) {
+		m.table = name
+	}
+}
+
+// New - creates new instance of migrator with migrations read from source.
+func New(db *sql.DB, source fs.FS, opts ...Option) (*Migrator, error) {
+	m := &Migrator{
+		db:    db,
+		table: _defaultTable,
+	}
+
+	// apply custom options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// advisory lock is shared by all replicas using the same migrations table
+	h := fnv.New64a()
+	h.Write([]byte(m.table))
+	m.lockKey = int64(h.Sum64())
+
+	migrations, err := read(source)
+	if err != nil {
+		return nil, err
+	}
+	m.migrations = migrations
+
+	return m, nil
+}
+
+// Up applies all pending migrations and returns applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+
+			err = m.apply(ctx, conn, migration.Up,
+				fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2)`, m.table),
+				migration.Version, migration.Name,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return applied, err
+	}
+
+	return applied, nil
+}
+
+// Down reverts given number of the latest applied migrations and returns reverted ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+
+			err = m.apply(ctx, conn, migration.Down,
+				fmt.Sprintf(`DELETE FROM %s WHERE version = $1`, m.table),
+				migration.Version,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return reverted, err
+	}
+
+	return reverted, nil
+}
+
+// Status returns all known migrations with time they were applied at.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	versions, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if appliedAt, ok := versions[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding the migrations advisory lock,
+// so several replicas starting at once apply migrations one after another.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, m.lockKey)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migrations lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, m.lockKey)
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`, m.table))
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// apply executes migration script and bookkeeping statement in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// no arguments, so multiple statements are sent via simple protocol
+	_, err = tx.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, bookkeeping, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[uint64]time.Time, error) {
+	versions := make(map[uint64]time.Time)
+
+	// migrations table is created on first up
+	var exists bool
+	err := conn.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, m.table).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if !exists {
+		return versions, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(`SELECT version, applied_at FROM %s`, m.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version uint64
+		var appliedAt time.Time
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		versions[version] = appliedAt
+	}
+
+	return versions, rows.Err()
+}
+
+// read collects migrations from the root of source, sorted by version.
+func read(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
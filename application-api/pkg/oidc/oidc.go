@@ -0,0 +1,283 @@
+// Package oidc implements relying party side of OpenID Connect authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+)
+
+const (
+	_discoveryPath = "/.well-known/openid-configuration"
+	_httpTimeout   = 10 * time.Second
+	// _jwksMinRefresh - limits refetching keys when tokens with unknown key id are received.
+	_jwksMinRefresh = time.Minute
+)
+
+// Config - represents OIDC client registration.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims - represents verified ID token claims used for sign in.
+type Claims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider - represents single OpenID provider, discovery document and keys are fetched lazily and cached.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu          sync.Mutex
+	discovery   *discovery
+	keys        *jwks.Set
+	keysFetched time.Time
+}
+
+// New - creates provider for given client registration.
+func New(config Config) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: _httpTimeout},
+	}
+}
+
+// Issuer returns configured issuer URL.
+func (p *Provider) Issuer() string {
+	return p.config.IssuerURL
+}
+
+// AuthCodeURL returns URL user is redirected to for authentication.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return d.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades authorization code for tokens and returns raw ID token.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	err = p.do(request, &token)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange code: %w", err)
+	}
+	if token.IDToken == "" {
+		return "", fmt.Errorf("token response has no id_token")
+	}
+
+	return token.IDToken, nil
+}
+
+// VerifyIDToken checks signature, issuer, audience, expiration and nonce of ID token.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.getKey(ctx, d.JWKSURI, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid id token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid id token: subject is empty")
+	}
+
+	return claims, nil
+}
+
+// getDiscovery fetches discovery document once, failed attempts are retried on next call.
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.config.IssuerURL, "/")+_discoveryPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery request: %w", err)
+	}
+
+	d := &discovery{}
+	err = p.do(request, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discovery document: %w", err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(p.config.IssuerURL, "/") {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", d.Issuer, p.config.IssuerURL)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document is incomplete")
+	}
+
+	p.discovery = d
+	return d, nil
+}
+
+// getKey returns public key by id, keys are refetched when id is unknown to follow provider key rotation.
+func (p *Provider) getKey(ctx context.Context, jwksURI, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.findKey(kid)
+	if !ok && time.Since(p.keysFetched) > _jwksMinRefresh {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create jwks request: %w", err)
+		}
+
+		set := &jwks.Set{}
+		err = p.do(request, set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jwks: %w", err)
+		}
+		p.keys = set
+		p.keysFetched = time.Now()
+
+		key, ok = p.findKey(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	return key.PublicKey()
+}
+
+func (p *Provider) findKey(kid string) (*jwks.Key, bool) {
+	if p.keys == nil {
+		return nil, false
+	}
+	// tokens without kid are accepted only from providers publishing single key
+	if kid == "" {
+		if len(p.keys.Keys) == 1 {
+			return &p.keys.Keys[0], true
+		}
+		return nil, false
+	}
+	return p.keys.Find(kid)
+}
+
+func (p *Provider) do(request *http.Request, v interface{}) error {
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// RandomString returns URL safe random string, used for state, nonce and PKCE code verifier.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns PKCE S256 challenge for given code verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
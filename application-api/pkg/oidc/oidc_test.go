@@ -0,0 +1,274 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/vovk404/course-platform/application-api/pkg/jwks"
+)
+
+const (
+	_clientID     = "client"
+	_clientSecret = "secret"
+	_redirectURL  = "http://localhost:8083/callback"
+	_code         = "authorization-code"
+	_verifier     = "code-verifier"
+	_nonce        = "nonce"
+)
+
+// issuer is OpenID provider serving discovery, keys and token endpoint of single signing key.
+type issuer struct {
+	*httptest.Server
+	key     ed25519.PrivateKey
+	kid     string
+	idToken string
+}
+
+func newIssuer(t *testing.T) *issuer {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	key, err := jwks.NewKey(public)
+	if err != nil {
+		t.Fatalf("failed to create jwk: %v", err)
+	}
+
+	i := &issuer{key: private, kid: key.Kid}
+	mux := http.NewServeMux()
+	mux.HandleFunc(_discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{
+			Issuer:                i.URL,
+			AuthorizationEndpoint: i.URL + "/authorize",
+			TokenEndpoint:         i.URL + "/token",
+			JWKSURI:               i.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jwks.Set{Keys: []jwks.Key{key}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("code") != _code ||
+			r.PostForm.Get("code_verifier") != _verifier || r.PostForm.Get("client_id") != _clientID ||
+			r.PostForm.Get("client_secret") != _clientSecret || r.PostForm.Get("redirect_uri") != _redirectURL {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": i.idToken})
+	})
+	i.Server = httptest.NewServer(mux)
+	t.Cleanup(i.Close)
+
+	return i
+}
+
+// claims returns valid claims of ID token for the client.
+func (i *issuer) claims() *Claims {
+	now := time.Now()
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.URL,
+			Subject:   "subject",
+			Audience:  jwt.ClaimStrings{_clientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+		Nonce:         _nonce,
+		Email:         "user@example.com",
+		EmailVerified: true,
+	}
+}
+
+func (i *issuer) sign(t *testing.T, claims *Claims, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func newProvider(i *issuer) *Provider {
+	return New(Config{IssuerURL: i.URL, ClientID: _clientID, ClientSecret: _clientSecret, RedirectURL: _redirectURL})
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	i := newIssuer(t)
+
+	raw, err := newProvider(i).AuthCodeURL(context.Background(), "state", _nonce, CodeChallenge(_verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() = %s is not URL: %v", raw, err)
+	}
+	if got := parsed.Scheme + "://" + parsed.Host + parsed.Path; got != i.URL+"/authorize" {
+		t.Errorf("AuthCodeURL() endpoint = %s, want %s", got, i.URL+"/authorize")
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             _clientID,
+		"redirect_uri":          _redirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state",
+		"nonce":                 _nonce,
+		"code_challenge":        CodeChallenge(_verifier),
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := parsed.Query().Get(name); got != value {
+			t.Errorf("AuthCodeURL() %s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestDiscoveryErrors(t *testing.T) {
+	i := newIssuer(t)
+	i.Config.Handler.(*http.ServeMux).HandleFunc("/mismatched"+_discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{Issuer: i.URL, AuthorizationEndpoint: "a", TokenEndpoint: "t", JWKSURI: "j"})
+	})
+	i.Config.Handler.(*http.ServeMux).HandleFunc("/incomplete"+_discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{Issuer: i.URL + "/incomplete"})
+	})
+
+	tests := []struct {
+		name      string
+		issuerURL string
+	}{
+		{name: "missing document", issuerURL: i.URL + "/missing"},
+		{name: "issuer mismatch", issuerURL: i.URL + "/mismatched"},
+		{name: "incomplete document", issuerURL: i.URL + "/incomplete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(Config{IssuerURL: tt.issuerURL, ClientID: _clientID}).AuthCodeURL(context.Background(), "state", _nonce, "challenge")
+			if err == nil {
+				t.Error("AuthCodeURL() error = nil, want discovery error")
+			}
+		})
+	}
+}
+
+func TestExchange(t *testing.T) {
+	i := newIssuer(t)
+	i.idToken = "id-token"
+
+	tests := []struct {
+		name     string
+		code     string
+		verifier string
+		want     string
+		wantErr  bool
+	}{
+		{name: "valid code", code: _code, verifier: _verifier, want: "id-token"},
+		{name: "wrong code", code: "other", verifier: _verifier, wantErr: true},
+		{name: "wrong verifier", code: _code, verifier: "other", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newProvider(i).Exchange(context.Background(), tt.code, tt.verifier)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Exchange() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Exchange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeWithoutIDToken(t *testing.T) {
+	i := newIssuer(t)
+
+	_, err := newProvider(i).Exchange(context.Background(), _code, _verifier)
+	if err == nil {
+		t.Error("Exchange() error = nil, want error of missing id_token")
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	i := newIssuer(t)
+
+	tests := []struct {
+		name    string
+		modify  func(claims *Claims)
+		kid     string
+		noKid   bool
+		nonce   string
+		wantErr bool
+	}{
+		{name: "valid token"},
+		{name: "wrong nonce", nonce: "other", wantErr: true},
+		{name: "wrong audience", modify: func(c *Claims) { c.Audience = jwt.ClaimStrings{"other"} }, wantErr: true},
+		{name: "wrong issuer", modify: func(c *Claims) { c.Issuer = "https://other.example.com" }, wantErr: true},
+		{name: "expired", modify: func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }, wantErr: true},
+		{name: "without expiration", modify: func(c *Claims) { c.ExpiresAt = nil }, wantErr: true},
+		{name: "issued in future", modify: func(c *Claims) { c.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Hour)) }, wantErr: true},
+		{name: "empty subject", modify: func(c *Claims) { c.Subject = "" }, wantErr: true},
+		{name: "unknown key", kid: "other", wantErr: true},
+		{name: "without key id of single key", noKid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := i.claims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			kid := i.kid
+			if tt.kid != "" {
+				kid = tt.kid
+			}
+			if tt.noKid {
+				kid = ""
+			}
+			nonce := _nonce
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			got, err := newProvider(i).VerifyIDToken(context.Background(), i.sign(t, claims, kid), nonce)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyIDToken() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && (got.Subject != "subject" || got.Email != "user@example.com" || !got.EmailVerified) {
+				t.Errorf("VerifyIDToken() = %+v, want claims of issued token", got)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenOfOtherKey(t *testing.T) {
+	i := newIssuer(t)
+	other := newIssuer(t)
+
+	// same key id, signed by key the issuer doesn't publish
+	forged := other.sign(t, i.claims(), i.kid)
+	_, err := newProvider(i).VerifyIDToken(context.Background(), forged, _nonce)
+	if err == nil {
+		t.Error("VerifyIDToken() error = nil, want invalid signature")
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 appendix B
+	got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("CodeChallenge() = %s, want %s", got, want)
+	}
+}
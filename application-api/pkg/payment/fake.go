@@ -0,0 +1,120 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DeclinedToken is payment token fake provider always declines.
+const DeclinedToken = "tok_declined"
+
+// fakeProvider accepts every payment without moving money, used for local development.
+// Webhooks are signed with hex HMAC-SHA256 of payload keyed by webhook secret.
+type fakeProvider struct {
+	mu            sync.Mutex
+	webhookSecret string
+	charges       map[string]*Charge
+	refunds       map[string]*Refund
+	subscriptions map[string]*Subscription
+}
+
+var _ Provider = (*fakeProvider)(nil)
+
+// NewFake - creates provider approving all charges except ones paid with DeclinedToken,
+// webhooks are accepted only when webhookSecret isn't empty.
+func NewFake(webhookSecret string) Provider {
+	return &fakeProvider{
+		webhookSecret: webhookSecret,
+		charges:       map[string]*Charge{},
+		refunds:       map[string]*Refund{},
+		subscriptions: map[string]*Subscription{},
+	}
+}
+
+func (p *fakeProvider) Charge(ctx context.Context, request *ChargeRequest) (*Charge, error) {
+	if request.Token == DeclinedToken {
+		return nil, ErrDeclined
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[request.Reference]
+	if !ok {
+		charge = &Charge{Id: fmt.Sprintf("fake_ch_%s", request.Reference), Amount: request.Amount}
+		p.charges[request.Reference] = charge
+	}
+	return charge, nil
+}
+
+func (p *fakeProvider) GetCharge(ctx context.Context, reference string) (*Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.charges[reference], nil
+}
+
+func (p *fakeProvider) Refund(ctx context.Context, request *RefundRequest) (*Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refund, ok := p.refunds[request.Reference]
+	if !ok {
+		refund = &Refund{Id: fmt.Sprintf("fake_re_%s", request.Reference), Amount: request.Amount}
+		p.refunds[request.Reference] = refund
+	}
+	return refund, nil
+}
+
+func (p *fakeProvider) Subscribe(ctx context.Context, request *SubscribeRequest) (*Subscription, error) {
+	if request.Token == DeclinedToken {
+		return nil, ErrDeclined
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	subscription, ok := p.subscriptions[request.Reference]
+	if !ok {
+		subscription = &Subscription{
+			Id:               fmt.Sprintf("fake_sub_%s", request.Reference),
+			Status:           SubscriptionActive,
+			CurrentPeriodEnd: time.Now().AddDate(0, 1, 0),
+		}
+		if request.TrialDays > 0 {
+			subscription.Status = SubscriptionTrialing
+			subscription.CurrentPeriodEnd = time.Now().AddDate(0, 0, request.TrialDays)
+		}
+		p.subscriptions[request.Reference] = subscription
+	}
+	return subscription, nil
+}
+
+func (p *fakeProvider) CancelSubscription(ctx context.Context, subscriptionId string) error {
+	return nil
+}
+
+func (p *fakeProvider) ParseEvent(payload []byte, signature string) (*Event, error) {
+	if p.webhookSecret == "" {
+		return nil, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	err := json.Unmarshal(payload, &event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,99 @@
+// Package payment implements charging customers and refunding charges through payment provider.
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrDeclined is returned when provider refuses to charge payment method.
+	ErrDeclined = errors.New("payment is declined")
+	// ErrInvalidSignature is returned when webhook payload isn't signed by provider.
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+)
+
+// Subscription statuses reported by provider.
+const (
+	SubscriptionTrialing = "trialing"
+	SubscriptionActive   = "active"
+	SubscriptionPastDue  = "past_due"
+	SubscriptionCanceled = "canceled"
+)
+
+// Webhook event types, subscription lifecycle is driven by them.
+const (
+	// EventInvoicePaid - subscription period is paid, subscription is active until CurrentPeriodEnd.
+	EventInvoicePaid = "invoice.paid"
+	// EventInvoicePaymentFailed - renewal charge failed, provider keeps retrying.
+	EventInvoicePaymentFailed = "invoice.payment_failed"
+	// EventSubscriptionCanceled - subscription ended, it isn't renewed anymore.
+	EventSubscriptionCanceled = "subscription.canceled"
+)
+
+type Provider interface {
+	// Charge takes amount from payment method identified by token.
+	Charge(ctx context.Context, request *ChargeRequest) (*Charge, error)
+	// GetCharge finds charge made with reference, nil is returned when nothing was charged with it.
+	GetCharge(ctx context.Context, reference string) (*Charge, error)
+	// Refund returns amount of charge to customer, partial refunds are allowed.
+	Refund(ctx context.Context, request *RefundRequest) (*Refund, error)
+	// Subscribe starts monthly subscription charged from payment method identified by token.
+	Subscribe(ctx context.Context, request *SubscribeRequest) (*Subscription, error)
+	// CancelSubscription stops renewing subscription, it ends with current period.
+	CancelSubscription(ctx context.Context, subscriptionId string) error
+	// ParseEvent verifies signature of webhook payload and decodes event.
+	ParseEvent(payload []byte, signature string) (*Event, error)
+}
+
+// ChargeRequest - represents charge of Amount in minor units of Currency.
+// Reference is used as idempotency key, so retried request doesn't charge twice.
+type ChargeRequest struct {
+	Reference string
+	Token     string
+	Amount    int64
+	Currency  string
+}
+
+type Charge struct {
+	Id     string
+	Amount int64
+}
+
+// RefundRequest - represents refund of Amount in minor units, Reference is idempotency key.
+type RefundRequest struct {
+	Reference string
+	ChargeId  string
+	Amount    int64
+}
+
+type Refund struct {
+	Id     string
+	Amount int64
+}
+
+// SubscribeRequest - represents monthly subscription of Amount in minor units of Currency, first TrialDays
+// are free. Reference is idempotency key.
+type SubscribeRequest struct {
+	Reference string
+	Token     string
+	Amount    int64
+	Currency  string
+	TrialDays int
+}
+
+type Subscription struct {
+	Id               string
+	Status           string
+	CurrentPeriodEnd time.Time
+}
+
+// Event - represents webhook notification, Id is unique, so redelivered event can be skipped.
+type Event struct {
+	Id               string    `json:"id"`
+	Type             string    `json:"type"`
+	SubscriptionId   string    `json:"subscriptionId"`
+	Amount           int64     `json:"amount"`
+	CurrentPeriodEnd time.Time `json:"currentPeriodEnd"`
+}
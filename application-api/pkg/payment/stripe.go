@@ -0,0 +1,354 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_stripeAPIURL = "https://api.stripe.com"
+	// _stripeVersion - pins shape of API objects and webhook payloads decoded below.
+	_stripeVersion     = "2024-06-20"
+	_stripeHTTPTimeout = 30 * time.Second
+	// _stripeSignatureTolerance - limits age of signed webhook, so captured webhook can't be replayed later.
+	_stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeConfig - represents Stripe account, subscriptions are priced inline under SubscriptionProductId.
+type StripeConfig struct {
+	SecretKey             string
+	WebhookSecret         string
+	SubscriptionProductId string
+	// APIURL overrides Stripe API address, it is empty in production.
+	APIURL string
+}
+
+// stripeProvider charges cards through Stripe payment intents, references are sent as idempotency keys
+// and stored in metadata, so charges can be found after restart.
+type stripeProvider struct {
+	config StripeConfig
+	client *http.Client
+}
+
+var _ Provider = (*stripeProvider)(nil)
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type stripePaymentIntent struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+	Amount int64  `json:"amount"`
+}
+
+type stripeSubscription struct {
+	Id               string `json:"id"`
+	Status           string `json:"status"`
+	CurrentPeriodEnd int64  `json:"current_period_end"`
+}
+
+type stripeInvoice struct {
+	Subscription string `json:"subscription"`
+	AmountPaid   int64  `json:"amount_paid"`
+	Lines        struct {
+		Data []struct {
+			Period struct {
+				End int64 `json:"end"`
+			} `json:"period"`
+		} `json:"data"`
+	} `json:"lines"`
+}
+
+// NewStripe - creates provider of Stripe account, webhooks are accepted only when WebhookSecret isn't empty.
+func NewStripe(config StripeConfig) Provider {
+	if config.APIURL == "" {
+		config.APIURL = _stripeAPIURL
+	}
+
+	return &stripeProvider{
+		config: config,
+		client: &http.Client{Timeout: _stripeHTTPTimeout},
+	}
+}
+
+func (p *stripeProvider) Charge(ctx context.Context, request *ChargeRequest) (*Charge, error) {
+	form := url.Values{
+		"amount":                 {strconv.FormatInt(request.Amount, 10)},
+		"currency":               {request.Currency},
+		"payment_method":         {request.Token},
+		"payment_method_types[]": {"card"},
+		"confirm":                {"true"},
+		"metadata[reference]":    {request.Reference},
+	}
+
+	intent := &stripePaymentIntent{}
+	err := p.post(ctx, "/v1/payment_intents", "charge-"+request.Reference, form, intent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment intent: %w", err)
+	}
+
+	switch intent.Status {
+	case "succeeded":
+		return &Charge{Id: intent.Id, Amount: intent.Amount}, nil
+	case "processing":
+		// outcome isn't known yet, GetCharge reports it once card network answers
+		return nil, fmt.Errorf("payment intent %s is processing", intent.Id)
+	default:
+		// payments requiring customer action aren't supported, they are never captured
+		return nil, ErrDeclined
+	}
+}
+
+func (p *stripeProvider) GetCharge(ctx context.Context, reference string) (*Charge, error) {
+	query := url.Values{"query": {fmt.Sprintf("metadata['reference']:'%s'", reference)}}
+
+	var result struct {
+		Data []*stripePaymentIntent `json:"data"`
+	}
+	err := p.do(ctx, http.MethodGet, "/v1/payment_intents/search?"+query.Encode(), "", nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search payment intents: %w", err)
+	}
+
+	for _, intent := range result.Data {
+		switch intent.Status {
+		case "succeeded":
+			return &Charge{Id: intent.Id, Amount: intent.Amount}, nil
+		case "processing":
+			return nil, fmt.Errorf("payment intent %s is processing", intent.Id)
+		}
+	}
+	return nil, nil
+}
+
+func (p *stripeProvider) Refund(ctx context.Context, request *RefundRequest) (*Refund, error) {
+	form := url.Values{
+		"payment_intent":      {request.ChargeId},
+		"amount":              {strconv.FormatInt(request.Amount, 10)},
+		"metadata[reference]": {request.Reference},
+	}
+
+	var refund struct {
+		Id     string `json:"id"`
+		Status string `json:"status"`
+		Amount int64  `json:"amount"`
+	}
+	err := p.post(ctx, "/v1/refunds", "refund-"+request.Reference, form, &refund)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refund: %w", err)
+	}
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return nil, fmt.Errorf("refund %s is %s", refund.Id, refund.Status)
+	}
+
+	return &Refund{Id: refund.Id, Amount: refund.Amount}, nil
+}
+
+func (p *stripeProvider) Subscribe(ctx context.Context, request *SubscribeRequest) (*Subscription, error) {
+	customerForm := url.Values{
+		"payment_method": {request.Token},
+		"invoice_settings[default_payment_method]": {request.Token},
+		"metadata[reference]":                      {request.Reference},
+	}
+
+	var customer struct {
+		Id string `json:"id"`
+	}
+	err := p.post(ctx, "/v1/customers", "customer-"+request.Reference, customerForm, &customer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	subscriptionForm := url.Values{
+		"customer":                                  {customer.Id},
+		"items[0][price_data][currency]":            {request.Currency},
+		"items[0][price_data][product]":             {p.config.SubscriptionProductId},
+		"items[0][price_data][unit_amount]":         {strconv.FormatInt(request.Amount, 10)},
+		"items[0][price_data][recurring][interval]": {"month"},
+		// declined first payment fails the request instead of leaving incomplete subscription behind
+		"payment_behavior":    {"error_if_incomplete"},
+		"metadata[reference]": {request.Reference},
+	}
+	if request.TrialDays > 0 {
+		subscriptionForm.Set("trial_period_days", strconv.Itoa(request.TrialDays))
+	}
+
+	subscription := &stripeSubscription{}
+	err = p.post(ctx, "/v1/subscriptions", "subscription-"+request.Reference, subscriptionForm, subscription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	status := subscription.Status
+	if status == "unpaid" {
+		status = SubscriptionPastDue
+	}
+	return &Subscription{
+		Id:               subscription.Id,
+		Status:           status,
+		CurrentPeriodEnd: time.Unix(subscription.CurrentPeriodEnd, 0),
+	}, nil
+}
+
+func (p *stripeProvider) CancelSubscription(ctx context.Context, subscriptionId string) error {
+	form := url.Values{"cancel_at_period_end": {"true"}}
+
+	err := p.post(ctx, "/v1/subscriptions/"+url.PathEscape(subscriptionId), "", form, &stripeSubscription{})
+	if err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+	return nil
+}
+
+// ParseEvent verifies Stripe-Signature header, which is "t=<unix time>,v1=<hex HMAC-SHA256 of t.payload>",
+// and maps subscription events to provider events. Other events are returned with their Stripe type and
+// without subscription id.
+func (p *stripeProvider) ParseEvent(payload []byte, signature string) (*Event, error) {
+	if p.config.WebhookSecret == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(signedAt, 0)).Abs() > _stripeSignatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.config.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	valid := false
+	for _, s := range signatures {
+		if hmac.Equal([]byte(expected), []byte(s)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	var event struct {
+		Id   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	switch event.Type {
+	case EventInvoicePaid, EventInvoicePaymentFailed:
+		var invoice stripeInvoice
+		err = json.Unmarshal(event.Data.Object, &invoice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode invoice: %w", err)
+		}
+
+		var periodEnd int64
+		for _, line := range invoice.Lines.Data {
+			if line.Period.End > periodEnd {
+				periodEnd = line.Period.End
+			}
+		}
+		return &Event{
+			Id:               event.Id,
+			Type:             event.Type,
+			SubscriptionId:   invoice.Subscription,
+			Amount:           invoice.AmountPaid,
+			CurrentPeriodEnd: time.Unix(periodEnd, 0),
+		}, nil
+	case "customer.subscription.deleted":
+		var subscription stripeSubscription
+		err = json.Unmarshal(event.Data.Object, &subscription)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode subscription: %w", err)
+		}
+
+		return &Event{
+			Id:               event.Id,
+			Type:             EventSubscriptionCanceled,
+			SubscriptionId:   subscription.Id,
+			CurrentPeriodEnd: time.Unix(subscription.CurrentPeriodEnd, 0),
+		}, nil
+	default:
+		return &Event{Id: event.Id, Type: event.Type}, nil
+	}
+}
+
+func (p *stripeProvider) post(ctx context.Context, path, idempotencyKey string, form url.Values, result interface{}) error {
+	return p.do(ctx, http.MethodPost, path, idempotencyKey, form, result)
+}
+
+// do sends form encoded request and decodes JSON response into result, card errors are reported as ErrDeclined.
+func (p *stripeProvider) do(ctx context.Context, method, path, idempotencyKey string, form url.Values, result interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(p.config.APIURL, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	request.SetBasicAuth(p.config.SecretKey, "")
+	request.Header.Set("Stripe-Version", _stripeVersion)
+	if form != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		request.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		var stripeErr stripeError
+		err = json.NewDecoder(response.Body).Decode(&stripeErr)
+		if err != nil {
+			return fmt.Errorf("unexpected status %d", response.StatusCode)
+		}
+		if stripeErr.Error.Type == "card_error" {
+			return fmt.Errorf("%w: %s", ErrDeclined, stripeErr.Error.Message)
+		}
+		return fmt.Errorf("stripe error %s %s: %s", stripeErr.Error.Type, stripeErr.Error.Code, stripeErr.Error.Message)
+	}
+
+	err = json.NewDecoder(response.Body).Decode(result)
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const _webhookSecret = "whsec_test"
+
+func newStripeServer(t *testing.T, handler http.HandlerFunc) Provider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewStripe(StripeConfig{SecretKey: "sk_test", WebhookSecret: _webhookSecret, APIURL: server.URL})
+}
+
+func stripeSignature(payload string, signedAt time.Time) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(_webhookSecret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestStripeCharge(t *testing.T) {
+	provider := newStripeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if key, _, _ := r.BasicAuth(); key != "sk_test" {
+			t.Errorf("secret key = %q", key)
+		}
+		if r.Header.Get("Idempotency-Key") != "charge-order" {
+			t.Errorf("idempotency key = %q", r.Header.Get("Idempotency-Key"))
+		}
+		r.ParseForm()
+		if r.PostForm.Get("metadata[reference]") != "order" || r.PostForm.Get("amount") != "1500" {
+			t.Errorf("form = %v", r.PostForm)
+		}
+
+		if r.PostForm.Get("payment_method") == "pm_declined" {
+			w.WriteHeader(http.StatusPaymentRequired)
+			fmt.Fprint(w, `{"error":{"type":"card_error","code":"card_declined","message":"Your card was declined."}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"pi_1","status":"succeeded","amount":1500}`)
+	})
+
+	charge, err := provider.Charge(context.Background(), &ChargeRequest{Reference: "order", Token: "pm_card", Amount: 1500, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("Charge: %v", err)
+	}
+	if charge.Id != "pi_1" || charge.Amount != 1500 {
+		t.Errorf("charge = %+v", charge)
+	}
+
+	_, err = provider.Charge(context.Background(), &ChargeRequest{Reference: "order", Token: "pm_declined", Amount: 1500, Currency: "usd"})
+	if !errors.Is(err, ErrDeclined) {
+		t.Errorf("declined Charge error = %v, want ErrDeclined", err)
+	}
+}
+
+func TestStripeGetCharge(t *testing.T) {
+	intents := `[]`
+	provider := newStripeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != "metadata['reference']:'order'" {
+			t.Errorf("query = %q", r.URL.Query().Get("query"))
+		}
+		fmt.Fprintf(w, `{"data":%s}`, intents)
+	})
+
+	charge, err := provider.GetCharge(context.Background(), "order")
+	if err != nil || charge != nil {
+		t.Errorf("GetCharge without intents = %+v, %v, want nil, nil", charge, err)
+	}
+
+	intents = `[{"id":"pi_0","status":"canceled","amount":1500},{"id":"pi_1","status":"succeeded","amount":1500}]`
+	charge, err = provider.GetCharge(context.Background(), "order")
+	if err != nil || charge == nil || charge.Id != "pi_1" {
+		t.Errorf("GetCharge = %+v, %v, want pi_1", charge, err)
+	}
+
+	intents = `[{"id":"pi_1","status":"processing","amount":1500}]`
+	_, err = provider.GetCharge(context.Background(), "order")
+	if err == nil {
+		t.Error("GetCharge of processing intent succeeded, want error")
+	}
+}
+
+func TestStripeParseEvent(t *testing.T) {
+	provider := NewStripe(StripeConfig{WebhookSecret: _webhookSecret})
+	payload := `{"id":"evt_1","type":"invoice.paid","data":{"object":{"subscription":"sub_1","amount_paid":2900,
+		"lines":{"data":[{"period":{"end":1764547200}}]}}}}`
+
+	event, err := provider.ParseEvent([]byte(payload), stripeSignature(payload, time.Now()))
+	if err != nil {
+		t.Fatalf("ParseEvent: %v", err)
+	}
+	if event.Id != "evt_1" || event.Type != EventInvoicePaid || event.SubscriptionId != "sub_1" || event.Amount != 2900 ||
+		!event.CurrentPeriodEnd.Equal(time.Unix(1764547200, 0)) {
+		t.Errorf("event = %+v", event)
+	}
+
+	canceled := `{"id":"evt_2","type":"customer.subscription.deleted","data":{"object":{"id":"sub_1","current_period_end":1764547200}}}`
+	event, err = provider.ParseEvent([]byte(canceled), stripeSignature(canceled, time.Now()))
+	if err != nil || event.Type != EventSubscriptionCanceled || event.SubscriptionId != "sub_1" {
+		t.Errorf("ParseEvent canceled = %+v, %v", event, err)
+	}
+
+	tests := map[string]string{
+		"tampered payload": stripeSignature(payload+" ", time.Now()),
+		"stale signature":  stripeSignature(payload, time.Now().Add(-time.Hour)),
+		"missing header":   "",
+	}
+	for name, signature := range tests {
+		_, err = provider.ParseEvent([]byte(payload), signature)
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: error = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
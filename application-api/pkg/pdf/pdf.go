@@ -0,0 +1,242 @@
+// Package pdf implements minimal single page PDF writer, enough for generated documents like certificates.
+// Standard fonts need no embedding, so they show WinAnsi (Latin-1) characters only. TrueType fonts are
+// embedded as subsets of drawn glyphs and show every character they have glyph for.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// Font - represents font text is drawn with.
+type Font interface {
+	// advance returns width of character in thousandths of font size.
+	advance(r rune) int
+	// operand returns PDF string showing text.
+	operand(text string) string
+	// objects returns PDF objects of font numbered from first, font dictionary is the first of them.
+	// Runes are characters drawn with font in the document.
+	objects(first int, runes map[rune]struct{}) []string
+}
+
+// standardFont - represents one of standard PDF fonts, widths are of ASCII characters from 32 to 126.
+type standardFont struct {
+	name   string
+	widths []int
+}
+
+var (
+	Helvetica     Font = &standardFont{name: "Helvetica", widths: helveticaWidths}
+	HelveticaBold Font = &standardFont{name: "Helvetica-Bold", widths: helveticaBoldWidths}
+)
+
+// Page sizes in points.
+const (
+	A4Width  = 595.0
+	A4Height = 842.0
+)
+
+// Document - represents single page document, drawing operations are appended to page content.
+type Document struct {
+	width  float64
+	height float64
+	fonts  []Font
+	// runes are characters drawn with font of the same index.
+	runes   []map[rune]struct{}
+	content bytes.Buffer
+	title   string
+}
+
+// New - creates document with page of given size in points, swap sizes for landscape orientation.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Width returns page width in points.
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+// Height returns page height in points.
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// SetTitle sets title shown by PDF viewers.
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// Text draws text with baseline starting at x, y, origin is bottom left corner of the page.
+func (d *Document) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&d.content, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		d.fontName(font, text), number(size), number(x), number(y), font.operand(text))
+}
+
+// CenteredText draws text horizontally centered on the page.
+func (d *Document) CenteredText(y float64, font Font, size float64, text string) {
+	d.Text((d.width-TextWidth(font, size, text))/2, y, font, size, text)
+}
+
+// Rectangle strokes rectangle with given line width.
+func (d *Document) Rectangle(x, y, width, height, lineWidth float64) {
+	fmt.Fprintf(&d.content, "q %s w %s %s %s %s re S Q\n",
+		number(lineWidth), number(x), number(y), number(width), number(height))
+}
+
+// Line strokes line between two points.
+func (d *Document) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&d.content, "q %s w %s %s m %s %s l S Q\n",
+		number(lineWidth), number(x1), number(y1), number(x2), number(y2))
+}
+
+// Bytes renders document to PDF file.
+func (d *Document) Bytes() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+	}
+
+	// font objects follow page and its content
+	fonts := strings.Builder{}
+	var fontObjects []string
+	for i, font := range d.fonts {
+		first := 5 + len(fontObjects)
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, first)
+		fontObjects = append(fontObjects, font.objects(first, d.runes[i])...)
+	}
+	objects = append(objects,
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents 4 0 R >>",
+			number(d.width), number(d.height), fonts.String()),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", d.content.Len(), d.content.String()),
+	)
+	objects = append(objects, fontObjects...)
+	info := ""
+	if d.title != "" {
+		objects = append(objects, fmt.Sprintf("<< /Title %s /Producer (course-platform) >>", textString(d.title)))
+		info = fmt.Sprintf(" /Info %d 0 R", len(objects))
+	}
+
+	out := bytes.Buffer{}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R%s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, info, xref)
+
+	return out.Bytes()
+}
+
+// fontName returns resource name of font, registering it on first use, and records characters of text drawn with it.
+func (d *Document) fontName(font Font, text string) string {
+	index := -1
+	for i, registered := range d.fonts {
+		if registered == font {
+			index = i
+		}
+	}
+	if index < 0 {
+		d.fonts = append(d.fonts, font)
+		d.runes = append(d.runes, map[rune]struct{}{})
+		index = len(d.fonts) - 1
+	}
+
+	for _, r := range text {
+		d.runes[index][r] = struct{}{}
+	}
+	return fmt.Sprintf("F%d", index+1)
+}
+
+// TextWidth returns width of text in points.
+func TextWidth(font Font, size float64, text string) float64 {
+	total := 0
+	for _, r := range text {
+		total += font.advance(r)
+	}
+	return float64(total) * size / 1000
+}
+
+func (f *standardFont) advance(r rune) int {
+	c := encode(string(r))[0]
+	if c >= 32 && int(c-32) < len(f.widths) {
+		return f.widths[c-32]
+	}
+	return 556
+}
+
+func (f *standardFont) operand(text string) string {
+	return "(" + escape(encode(text)) + ")"
+}
+
+func (f *standardFont) objects(first int, runes map[rune]struct{}) []string {
+	return []string{fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name)}
+}
+
+// encode converts text to WinAnsi bytes, characters outside of printable Latin-1 are replaced with "?".
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		// WinAnsi uses 128-159 for other characters than Latin-1
+		if r > 255 || (r >= 128 && r < 160) {
+			r = '?'
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return encoded
+}
+
+// textString returns PDF text string, text outside of Latin-1 is written as UTF-16 with byte order mark.
+func textString(text string) string {
+	for _, r := range text {
+		if r > 255 || (r >= 128 && r < 160) {
+			encoded := strings.Builder{}
+			encoded.WriteString("<FEFF")
+			for _, unit := range utf16.Encode([]rune(text)) {
+				fmt.Fprintf(&encoded, "%04X", unit)
+			}
+			encoded.WriteString(">")
+			return encoded.String()
+		}
+	}
+	return "(" + escape(encode(text)) + ")"
+}
+
+// escape escapes PDF literal string delimiters.
+func escape(text []byte) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`, "\n", `\n`)
+	return replacer.Replace(string(text))
+}
+
+func number(f float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+}
+
+// Glyph widths of ASCII characters from 32 to 126 in thousandths of font size, from Adobe font metrics.
+var (
+	helveticaWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
@@ -0,0 +1,504 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrInvalidFont is returned when font file isn't TrueType font with glyphs of Unicode characters.
+var ErrInvalidFont = errors.New("invalid TrueType font")
+
+// _subsetTables - tables kept in embedded subsets, PDF viewers map characters to glyphs by the document itself.
+var _subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// TrueType - represents TrueType font, it is embedded as CID font with glyphs of drawn characters only.
+// Font is read only after parsing, so it can be shared by documents drawn concurrently.
+type TrueType struct {
+	name       string
+	tables     map[string][]byte
+	unitsPerEm int
+	numGlyphs  int
+	// glyphs maps characters to glyph ids, advances are glyph widths in font units.
+	glyphs    map[rune]uint16
+	advances  []int
+	offsets   []int
+	ascent    int
+	descent   int
+	capHeight int
+	bbox      [4]int
+}
+
+var _ Font = (*TrueType)(nil)
+
+// ParseTrueType reads TrueType font file, fonts with PostScript outlines (OpenType CFF) aren't supported.
+func ParseTrueType(data []byte) (*TrueType, error) {
+	if len(data) < 12 || binary.BigEndian.Uint32(data) != 0x00010000 {
+		return nil, ErrInvalidFont
+	}
+
+	font := &TrueType{tables: map[string][]byte{}}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*numTables {
+		return nil, ErrInvalidFont
+	}
+	for i := 0; i < numTables; i++ {
+		record := data[12+16*i:]
+		offset, length := int(binary.BigEndian.Uint32(record[8:])), int(binary.BigEndian.Uint32(record[12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, ErrInvalidFont
+		}
+		font.tables[string(record[:4])] = data[offset : offset+length]
+	}
+
+	head, hhea, maxp := font.tables["head"], font.tables["hhea"], font.tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, ErrInvalidFont
+	}
+	font.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	for i := range font.bbox {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+	font.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	font.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+	font.capHeight = font.ascent
+	if os2 := font.tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		font.capHeight = int(int16(binary.BigEndian.Uint16(os2[88:])))
+	}
+	font.numGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+	if font.unitsPerEm == 0 || font.numGlyphs == 0 {
+		return nil, ErrInvalidFont
+	}
+
+	err := font.parseMetrics(int(binary.BigEndian.Uint16(hhea[34:])))
+	if err != nil {
+		return nil, err
+	}
+	err = font.parseLocations(binary.BigEndian.Uint16(head[50:]) == 1)
+	if err != nil {
+		return nil, err
+	}
+	err = font.parseCharacterMap()
+	if err != nil {
+		return nil, err
+	}
+	font.name = font.postScriptName()
+
+	return font, nil
+}
+
+// parseMetrics reads glyph widths, glyphs after the last metric share its width.
+func (t *TrueType) parseMetrics(numMetrics int) error {
+	hmtx := t.tables["hmtx"]
+	if numMetrics == 0 || numMetrics > t.numGlyphs || len(hmtx) < 4*numMetrics {
+		return ErrInvalidFont
+	}
+
+	t.advances = make([]int, t.numGlyphs)
+	for i := range t.advances {
+		if i < numMetrics {
+			t.advances[i] = int(binary.BigEndian.Uint16(hmtx[4*i:]))
+		} else {
+			t.advances[i] = t.advances[numMetrics-1]
+		}
+	}
+	return nil
+}
+
+// parseLocations reads offsets of glyphs in glyf table, glyph i spans offsets i and i+1.
+func (t *TrueType) parseLocations(long bool) error {
+	loca, glyf := t.tables["loca"], t.tables["glyf"]
+	size := 2
+	if long {
+		size = 4
+	}
+	if len(loca) < size*(t.numGlyphs+1) {
+		return ErrInvalidFont
+	}
+
+	t.offsets = make([]int, t.numGlyphs+1)
+	for i := range t.offsets {
+		if long {
+			t.offsets[i] = int(binary.BigEndian.Uint32(loca[4*i:]))
+		} else {
+			t.offsets[i] = 2 * int(binary.BigEndian.Uint16(loca[2*i:]))
+		}
+		if t.offsets[i] > len(glyf) || (i > 0 && t.offsets[i] < t.offsets[i-1]) {
+			return ErrInvalidFont
+		}
+	}
+	return nil
+}
+
+// parseCharacterMap reads Unicode subtable of cmap, full repertoire subtable (format 12) is preferred
+// over basic multilingual plane one (format 4).
+func (t *TrueType) parseCharacterMap() error {
+	cmap := t.tables["cmap"]
+	if len(cmap) < 4 {
+		return ErrInvalidFont
+	}
+
+	var basic, full []byte
+	for i := 0; i < int(binary.BigEndian.Uint16(cmap[2:])); i++ {
+		if len(cmap) < 12+8*i {
+			return ErrInvalidFont
+		}
+		record := cmap[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(record), binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+2 > len(cmap) || (platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10))) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			basic = cmap[offset:]
+		case 12:
+			full = cmap[offset:]
+		}
+	}
+
+	t.glyphs = map[rune]uint16{}
+	switch {
+	case full != nil:
+		return t.parseSegmentedCoverage(full)
+	case basic != nil:
+		return t.parseSegmentMapping(basic)
+	default:
+		return ErrInvalidFont
+	}
+}
+
+// parseSegmentMapping reads cmap subtable format 4.
+func (t *TrueType) parseSegmentMapping(subtable []byte) error {
+	if len(subtable) < 14 {
+		return ErrInvalidFont
+	}
+	segments := int(binary.BigEndian.Uint16(subtable[6:])) / 2
+	ends, starts, deltas, ranges := 14, 16+2*segments, 16+4*segments, 16+6*segments
+	if len(subtable) < ranges+2*segments {
+		return ErrInvalidFont
+	}
+
+	for i := 0; i < segments; i++ {
+		end := int(binary.BigEndian.Uint16(subtable[ends+2*i:]))
+		start := int(binary.BigEndian.Uint16(subtable[starts+2*i:]))
+		delta := binary.BigEndian.Uint16(subtable[deltas+2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(subtable[ranges+2*i:]))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			glyph := uint16(c) + delta
+			if rangeOffset != 0 {
+				// offset is relative to the range offset entry itself
+				at := ranges + 2*i + rangeOffset + 2*(c-start)
+				if at+2 > len(subtable) {
+					return ErrInvalidFont
+				}
+				glyph = binary.BigEndian.Uint16(subtable[at:])
+				if glyph != 0 {
+					glyph += delta
+				}
+			}
+			t.addGlyph(rune(c), glyph)
+		}
+	}
+	return nil
+}
+
+// parseSegmentedCoverage reads cmap subtable format 12.
+func (t *TrueType) parseSegmentedCoverage(subtable []byte) error {
+	if len(subtable) < 16 {
+		return ErrInvalidFont
+	}
+	groups := int(binary.BigEndian.Uint32(subtable[12:]))
+	if groups < 0 || len(subtable) < 16+12*groups {
+		return ErrInvalidFont
+	}
+
+	for i := 0; i < groups; i++ {
+		group := subtable[16+12*i:]
+		start, end := binary.BigEndian.Uint32(group), binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		if end < start || end > 0x10FFFF {
+			return ErrInvalidFont
+		}
+		for c := start; c <= end; c++ {
+			t.addGlyph(rune(c), uint16(glyph+c-start))
+		}
+	}
+	return nil
+}
+
+func (t *TrueType) addGlyph(r rune, glyph uint16) {
+	if glyph != 0 && int(glyph) < t.numGlyphs {
+		t.glyphs[r] = glyph
+	}
+}
+
+// postScriptName returns name of font from name table, PDF names can't contain spaces.
+func (t *TrueType) postScriptName() string {
+	table := t.tables["name"]
+	if len(table) >= 6 {
+		count, storage := int(binary.BigEndian.Uint16(table[2:])), int(binary.BigEndian.Uint16(table[4:]))
+		for i := 0; i < count && len(table) >= 18+12*i; i++ {
+			record := table[6+12*i:]
+			platform, nameId := binary.BigEndian.Uint16(record), binary.BigEndian.Uint16(record[6:])
+			length, offset := int(binary.BigEndian.Uint16(record[8:])), int(binary.BigEndian.Uint16(record[10:]))
+			if nameId != 6 || storage+offset+length > len(table) {
+				continue
+			}
+
+			value := table[storage+offset : storage+offset+length]
+			name := string(value)
+			if platform == 3 || platform == 0 {
+				units := make([]uint16, len(value)/2)
+				for j := range units {
+					units[j] = binary.BigEndian.Uint16(value[2*j:])
+				}
+				name = string(utf16.Decode(units))
+			}
+			if name = strings.Join(strings.Fields(name), ""); name != "" {
+				return name
+			}
+		}
+	}
+	return "TrueType"
+}
+
+func (t *TrueType) advance(r rune) int {
+	return t.scale(t.advances[t.glyphs[r]])
+}
+
+// operand returns hex string of two byte glyph ids, CIDs of embedded font are its glyph ids.
+func (t *TrueType) operand(text string) string {
+	operand := strings.Builder{}
+	operand.WriteString("<")
+	for _, r := range text {
+		fmt.Fprintf(&operand, "%04X", t.glyphs[r])
+	}
+	operand.WriteString(">")
+	return operand.String()
+}
+
+func (t *TrueType) objects(first int, runes map[rune]struct{}) []string {
+	sorted := make([]rune, 0, len(runes))
+	for r := range runes {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	// characters sharing glyph are extracted as the first of them
+	unicodes := map[uint16]rune{}
+	glyphs := []uint16{0}
+	for _, r := range sorted {
+		glyph, ok := t.glyphs[r]
+		if _, seen := unicodes[glyph]; !ok || seen {
+			continue
+		}
+		unicodes[glyph] = r
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	// subset tag distinguishes subsets of the same font embedded into different documents
+	hash := fnv.New32a()
+	for _, glyph := range glyphs {
+		hash.Write([]byte{byte(glyph >> 8), byte(glyph)})
+	}
+	tag, sum := make([]byte, 6), hash.Sum32()
+	for i := range tag {
+		tag[i] = byte('A' + sum%26)
+		sum /= 26
+	}
+	name := string(tag) + "+" + t.name
+
+	widths := strings.Builder{}
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, t.scale(t.advances[glyph]))
+	}
+
+	file := t.subset(glyphs)
+	toUnicode := toUnicodeCMap(glyphs, unicodes)
+	return []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			name, first+1, first+4),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor %d 0 R /DW %d /W [%s] /CIDToGIDMap /Identity >>",
+			name, first+2, t.scale(t.advances[0]), strings.TrimSpace(widths.String())),
+		fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d "+
+			"/CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			name, t.scale(t.bbox[0]), t.scale(t.bbox[1]), t.scale(t.bbox[2]), t.scale(t.bbox[3]),
+			t.scale(t.ascent), t.scale(t.descent), t.scale(t.capHeight), first+3),
+		fmt.Sprintf("<< /Length %d /Length1 %d >>\nstream\n%s\nendstream", len(file), len(file), file),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(toUnicode), toUnicode),
+	}
+}
+
+// subset returns font file keeping outlines of given glyphs and components of composite ones, other glyphs
+// stay in place empty, so glyph ids don't change.
+func (t *TrueType) subset(glyphs []uint16) []byte {
+	glyf := t.tables["glyf"]
+	kept := map[int]bool{}
+	queue := make([]int, 0, len(glyphs))
+	for _, glyph := range glyphs {
+		queue = append(queue, int(glyph))
+	}
+	for len(queue) > 0 {
+		glyph := queue[0]
+		queue = queue[1:]
+		if kept[glyph] {
+			continue
+		}
+		if glyph >= t.numGlyphs {
+			continue
+		}
+		kept[glyph] = true
+		queue = append(queue, components(glyf[t.offsets[glyph]:t.offsets[glyph+1]])...)
+	}
+
+	subsetGlyf := []byte{}
+	loca := make([]byte, 4*(t.numGlyphs+1))
+	for glyph := 0; glyph < t.numGlyphs; glyph++ {
+		if kept[glyph] {
+			subsetGlyf = append(subsetGlyf, glyf[t.offsets[glyph]:t.offsets[glyph+1]]...)
+			for len(subsetGlyf)%4 != 0 {
+				subsetGlyf = append(subsetGlyf, 0)
+			}
+		}
+		binary.BigEndian.PutUint32(loca[4*(glyph+1):], uint32(len(subsetGlyf)))
+	}
+
+	// subset uses long offsets and its checksum adjustment is computed below
+	head := append([]byte{}, t.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": subsetGlyf, "loca": loca, "head": head}
+	for _, tag := range _subsetTables {
+		if _, ok := tables[tag]; !ok && t.tables[tag] != nil {
+			tables[tag] = t.tables[tag]
+		}
+	}
+	tags := make([]string, 0, len(tables))
+	for _, tag := range _subsetTables {
+		if tables[tag] != nil {
+			tags = append(tags, tag)
+		}
+	}
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	file := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(file, 0x00010000)
+	binary.BigEndian.PutUint16(file[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(file[6:], uint16(16<<entrySelector))
+	binary.BigEndian.PutUint16(file[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(file[10:], uint16(16*len(tags)-16<<entrySelector))
+
+	headOffset := 0
+	for i, tag := range tags {
+		table := tables[tag]
+		record := file[12+16*i:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(len(file)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+		if tag == "head" {
+			headOffset = len(file)
+		}
+		file = append(file, table...)
+		for len(file)%4 != 0 {
+			file = append(file, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(file[headOffset+8:], 0xB1B0AFBA-checksum(file))
+
+	return file
+}
+
+// components returns glyph ids composite glyph is made of, simple glyph has none.
+func components(glyph []byte) []int {
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+
+	var glyphs []int
+	for at := 10; at+4 <= len(glyph); {
+		flags := binary.BigEndian.Uint16(glyph[at:])
+		glyphs = append(glyphs, int(binary.BigEndian.Uint16(glyph[at+2:])))
+		at += 4
+		// arguments are words or bytes, then optional scale, x and y scales or 2x2 transformation
+		if flags&0x0001 != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&0x0008 != 0:
+			at += 2
+		case flags&0x0040 != 0:
+			at += 4
+		case flags&0x0080 != 0:
+			at += 8
+		}
+		if flags&0x0020 == 0 {
+			break
+		}
+	}
+	return glyphs
+}
+
+// checksum returns sum of big endian 32 bit words of data padded with zeros.
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		word := make([]byte, 4)
+		copy(word, data[i:])
+		sum += binary.BigEndian.Uint32(word)
+	}
+	return sum
+}
+
+// toUnicodeCMap returns CMap mapping glyph ids back to characters, so text can be copied and searched.
+func toUnicodeCMap(glyphs []uint16, unicodes map[uint16]rune) string {
+	cmap := strings.Builder{}
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	var mapped []uint16
+	for _, glyph := range glyphs {
+		if _, ok := unicodes[glyph]; ok {
+			mapped = append(mapped, glyph)
+		}
+	}
+	// bfchar blocks are limited to 100 entries
+	for start := 0; start < len(mapped); start += 100 {
+		block := mapped[start:]
+		if len(block) > 100 {
+			block = block[:100]
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", len(block))
+		for _, glyph := range block {
+			fmt.Fprintf(&cmap, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{unicodes[glyph]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return cmap.String()
+}
+
+// scale converts font units to thousandths of font size.
+func (t *TrueType) scale(value int) int {
+	return value * 1000 / t.unitsPerEm
+}
@@ -0,0 +1,103 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/vovk404/course-platform/application-api/fonts"
+)
+
+func parseDejaVu(t *testing.T) *TrueType {
+	t.Helper()
+
+	font, err := ParseTrueType(fonts.DejaVuSans)
+	if err != nil {
+		t.Fatalf("ParseTrueType: %v", err)
+	}
+	return font
+}
+
+func TestParseTrueType(t *testing.T) {
+	font := parseDejaVu(t)
+
+	if font.name != "DejaVuSans" {
+		t.Errorf("name = %q, want DejaVuSans", font.name)
+	}
+	for _, r := range "AzЖїΩ€" {
+		if font.glyphs[r] == 0 {
+			t.Errorf("no glyph of %q", r)
+		}
+	}
+	if TextWidth(font, 10, "Іван") <= 0 {
+		t.Error("width of Cyrillic text isn't positive")
+	}
+
+	_, err := ParseTrueType([]byte("not a font"))
+	if err != ErrInvalidFont {
+		t.Errorf("ParseTrueType of garbage error = %v, want ErrInvalidFont", err)
+	}
+}
+
+func TestTrueTypeSubset(t *testing.T) {
+	font := parseDejaVu(t)
+	glyphs := []uint16{0, font.glyphs['Ж'], font.glyphs['é']}
+
+	file := font.subset(glyphs)
+	if len(file) >= len(fonts.DejaVuSans)/10 {
+		t.Errorf("subset of 3 glyphs is %d bytes", len(file))
+	}
+	if checksum(file) != 0xB1B0AFBA {
+		t.Errorf("file checksum = %#x, want 0xB1B0AFBA", checksum(file))
+	}
+
+	tables := map[string][]byte{}
+	for i := 0; i < int(binary.BigEndian.Uint16(file[4:])); i++ {
+		record := file[12+16*i:]
+		offset, length := binary.BigEndian.Uint32(record[8:]), binary.BigEndian.Uint32(record[12:])
+		tables[string(record[:4])] = file[offset : offset+length]
+	}
+	for _, tag := range []string{"glyf", "head", "hhea", "hmtx", "loca", "maxp"} {
+		if tables[tag] == nil {
+			t.Errorf("subset has no %s table", tag)
+		}
+	}
+
+	// kept glyphs keep their outlines at the same ids, others are empty
+	loca, glyf := tables["loca"], tables["glyf"]
+	for _, glyph := range glyphs {
+		start, end := binary.BigEndian.Uint32(loca[4*glyph:]), binary.BigEndian.Uint32(loca[4*glyph+4:])
+		original := font.tables["glyf"][font.offsets[glyph]:font.offsets[glyph+1]]
+		if !bytes.HasPrefix(glyf[start:end], original) {
+			t.Errorf("outline of glyph %d differs", glyph)
+		}
+	}
+	other := font.glyphs['Q']
+	if binary.BigEndian.Uint32(loca[4*other:]) != binary.BigEndian.Uint32(loca[4*other+4:]) {
+		t.Errorf("glyph %d isn't drawn but kept", other)
+	}
+}
+
+func TestDocumentTrueTypeText(t *testing.T) {
+	font := parseDejaVu(t)
+	document := New(A4Width, A4Height)
+	document.SetTitle("Сертифікат")
+	document.Text(10, 10, font, 12, "Тарас")
+	document.Text(10, 30, Helvetica, 12, "Issued")
+	out := string(document.Bytes())
+
+	for _, want := range []string{
+		"/Subtype /Type0", "/Encoding /Identity-H", "/CIDToGIDMap /Identity", "/FontFile2", "/ToUnicode",
+		"+DejaVuSans", "/BaseFont /Helvetica",
+		// Т is U+0422 and title is UTF-16 with byte order mark
+		"<0422>", "/Title <FEFF0421",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("document has no %q", want)
+		}
+	}
+	if strings.Contains(out, "(?????)") {
+		t.Error("Cyrillic text is replaced with question marks")
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	_sweepInterval = time.Minute
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// fullAt is the time bucket is refilled to capacity, after it the bucket can be dropped.
+	fullAt time.Time
+}
+
+// memoryLimiter keeps buckets in process memory, limits are per replica.
+type memoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweptAt time.Time
+}
+
+var _ Limiter = (*memoryLimiter)(nil)
+
+// NewMemory - creates limiter storing buckets in memory.
+func NewMemory() Limiter {
+	return &memoryLimiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (m *memoryLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	// refill for the time passed since last request
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	result := newResult(allowed, b.tokens, limit)
+	b.fullAt = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweep drops full buckets, they are equal to missing ones.
+func (m *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.sweptAt) < _sweepInterval {
+		return
+	}
+	m.sweptAt = now
+
+	for key, b := range m.buckets {
+		if !now.Before(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/vovk404/course-platform/application-api/pkg/database"
+)
+
+const (
+	// _cleanupEvery - number of requests between removals of stale buckets.
+	_cleanupEvery = 1000
+)
+
+// postgreSQLLimiter keeps buckets in rate_limit_buckets table, so limits are shared by all replicas.
+type postgreSQLLimiter struct {
+	sql      *database.PostgreSQL
+	requests atomic.Uint64
+}
+
+var _ Limiter = (*postgreSQLLimiter)(nil)
+
+// NewPostgreSQL - creates limiter storing buckets in PostgreSQL.
+func NewPostgreSQL(postgresql *database.PostgreSQL) Limiter {
+	return &postgreSQLLimiter{sql: postgresql}
+}
+
+func (p *postgreSQLLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if p.requests.Add(1)%_cleanupEvery == 0 {
+		go p.cleanup()
+	}
+
+	// refill and take a token in one statement, database clock is shared by all replicas
+	var row struct {
+		Tokens  float64
+		Allowed bool
+	}
+	err := p.sql.DB.
+		WithContext(ctx).
+		Raw(`INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+			VALUES (@key, @burst - 1, @burst >= 1, now())
+			ON CONFLICT (key) DO UPDATE SET
+				tokens = CASE
+					WHEN LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate) >= 1
+						THEN LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate) - 1
+					ELSE LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate)
+				END,
+				allowed = LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate) >= 1,
+				updated_at = now()
+			RETURNING tokens, allowed`,
+			sql.Named("key", key),
+			sql.Named("burst", float64(limit.Burst)),
+			sql.Named("rate", limit.Rate),
+		).
+		Scan(&row).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to take token: %w", err)
+	}
+
+	return newResult(row.Allowed, row.Tokens, limit), nil
+}
+
+// cleanup removes buckets untouched for a day, they would be full by now anyway.
+func (p *postgreSQLLimiter) cleanup() {
+	p.sql.DB.Exec(`DELETE FROM rate_limit_buckets WHERE updated_at < now() - interval '1 day'`)
+}
@@ -0,0 +1,59 @@
+// Package ratelimit implements token bucket rate limiting with pluggable storage backends.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type Limiter interface {
+	// Allow takes one token from the bucket of the key and reports whether request is allowed.
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// Limit - represents token bucket parameters.
+// Bucket holds up to Burst tokens and is refilled with Rate tokens per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result - represents limiter decision.
+type Result struct {
+	Allowed bool
+	// Limit is the bucket capacity.
+	Limit int
+	// Remaining is the number of whole tokens left after the request.
+	Remaining int
+	// RetryAfter is the time until next token is available, zero for allowed requests.
+	RetryAfter time.Duration
+	// ResetAfter is the time until bucket is full again.
+	ResetAfter time.Duration
+}
+
+// newResult builds decision from tokens left in the bucket.
+func newResult(allowed bool, tokens float64, limit Limit) *Result {
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+	}
+	if limit.Rate <= 0 {
+		return result
+	}
+
+	result.ResetAfter = seconds((float64(limit.Burst) - tokens) / limit.Rate)
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
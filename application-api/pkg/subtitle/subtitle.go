@@ -0,0 +1,303 @@
+// Package subtitle validates WebVTT and SRT subtitles and converts SRT to WebVTT.
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Cue is timed text of subtitles, Text is WebVTT cue payload and may contain markup.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// PlainText returns text of cue without markup on a single line.
+func (c Cue) PlainText() string {
+	return strings.Join(strings.Fields(html.UnescapeString(_tagPattern.ReplaceAllString(c.Text, " "))), " ")
+}
+
+// Subtitles are validated subtitles, cues are ordered by start time.
+type Subtitles struct {
+	Cues []Cue
+	vtt  string
+}
+
+// VTT returns subtitles as WebVTT document. WebVTT is kept as uploaded, so styles and cue settings survive.
+func (s *Subtitles) VTT() string {
+	return s.vtt
+}
+
+// SyntaxError describes why subtitles are invalid, Line starts from 1.
+type SyntaxError struct {
+	Line   int
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+var (
+	_tagPattern    = regexp.MustCompile(`<[^<>]*>`)
+	_srtTagPattern = regexp.MustCompile(`</?([a-zA-Z]+)[^<>]*>`)
+	_assTagPattern = regexp.MustCompile(`\{\\[^{}]*\}`)
+	_vttTimestamp  = regexp.MustCompile(`^(?:(\d{2,}):)?([0-5]\d):([0-5]\d)\.(\d{3})$`)
+	_srtTimestamp  = regexp.MustCompile(`^(\d{1,}):([0-5]\d):([0-5]\d)[,.](\d{3})$`)
+)
+
+// Parse validates subtitles in WebVTT or SRT format, WebVTT is recognized by its header.
+func Parse(data []byte) (*Subtitles, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if !utf8.Valid(data) {
+		line := 1
+		for len(data) > 0 {
+			r, size := utf8.DecodeRune(data)
+			if r == utf8.RuneError && size <= 1 {
+				break
+			}
+			if r == '\n' {
+				line++
+			}
+			data = data[size:]
+		}
+		return nil, &SyntaxError{Line: line, Reason: "subtitles must be UTF-8 encoded"}
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	lines := strings.Split(text, "\n")
+
+	if isVTTHeader(lines[0]) {
+		cues, err := parseVTT(lines)
+		if err != nil {
+			return nil, err
+		}
+		return &Subtitles{Cues: cues, vtt: strings.TrimRight(text, "\n") + "\n"}, nil
+	}
+
+	cues, err := parseSRT(lines)
+	if err != nil {
+		return nil, err
+	}
+	return &Subtitles{Cues: cues, vtt: writeVTT(cues)}, nil
+}
+
+func isVTTHeader(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
+
+// block is group of lines separated from others by blank lines, first is number of its first line.
+type block struct {
+	first int
+	lines []string
+}
+
+func splitBlocks(lines []string, from int) []block {
+	var blocks []block
+	var current *block
+	for i := from; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, block{first: i + 1})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, lines[i])
+	}
+	return blocks
+}
+
+func parseVTT(lines []string) ([]Cue, error) {
+	// header may span several lines until the first blank one
+	from := 1
+	for from < len(lines) && strings.TrimSpace(lines[from]) != "" {
+		if strings.Contains(lines[from], "-->") {
+			return nil, &SyntaxError{Line: from + 1, Reason: "header must be followed by blank line"}
+		}
+		from++
+	}
+
+	var cues []Cue
+	for _, b := range splitBlocks(lines, from) {
+		first := strings.TrimSpace(b.lines[0])
+		if first == "NOTE" || strings.HasPrefix(b.lines[0], "NOTE ") || strings.HasPrefix(b.lines[0], "NOTE\t") {
+			continue
+		}
+		if (first == "STYLE" || first == "REGION") && !strings.Contains(strings.Join(b.lines, "\n"), "-->") {
+			continue
+		}
+
+		// cue identifier is optional
+		timing := 0
+		if !strings.Contains(b.lines[0], "-->") {
+			timing = 1
+		}
+		if timing >= len(b.lines) || !strings.Contains(b.lines[timing], "-->") {
+			return nil, &SyntaxError{Line: b.first + timing, Reason: "expected cue timing"}
+		}
+
+		start, end, err := parseTiming(b.lines[timing], _vttTimestamp)
+		if err != nil {
+			return nil, &SyntaxError{Line: b.first + timing, Reason: err.Error()}
+		}
+		if len(cues) > 0 && start < cues[len(cues)-1].Start {
+			return nil, &SyntaxError{Line: b.first + timing, Reason: "cue starts before previous cue"}
+		}
+		for i, line := range b.lines[timing+1:] {
+			if strings.Contains(line, "-->") {
+				return nil, &SyntaxError{Line: b.first + timing + 1 + i, Reason: "cue text can't contain -->"}
+			}
+		}
+
+		cues = append(cues, Cue{Start: start, End: end, Text: strings.Join(b.lines[timing+1:], "\n")})
+	}
+
+	if len(cues) == 0 {
+		return nil, &SyntaxError{Line: len(lines), Reason: "subtitles have no cues"}
+	}
+	return cues, nil
+}
+
+func parseSRT(lines []string) ([]Cue, error) {
+	var cues []Cue
+	for _, b := range splitBlocks(lines, 0) {
+		// cue number is optional, as many tools don't write it
+		timing := 0
+		if !strings.Contains(b.lines[0], "-->") {
+			if _, err := strconv.Atoi(strings.TrimSpace(b.lines[0])); err != nil {
+				if len(cues) == 0 {
+					return nil, &SyntaxError{Line: b.first, Reason: "expected WEBVTT header or SRT cue number"}
+				}
+				return nil, &SyntaxError{Line: b.first, Reason: "expected cue number"}
+			}
+			timing = 1
+		}
+		if timing >= len(b.lines) || !strings.Contains(b.lines[timing], "-->") {
+			return nil, &SyntaxError{Line: b.first + timing, Reason: "expected cue timing"}
+		}
+
+		start, end, err := parseTiming(b.lines[timing], _srtTimestamp)
+		if err != nil {
+			return nil, &SyntaxError{Line: b.first + timing, Reason: err.Error()}
+		}
+
+		cues = append(cues, Cue{Start: start, End: end, Text: srtTextToVTT(strings.Join(b.lines[timing+1:], "\n"))})
+	}
+
+	if len(cues) == 0 {
+		return nil, &SyntaxError{Line: len(lines), Reason: "subtitles have no cues"}
+	}
+	// unlike WebVTT, SRT doesn't require cues to be ordered
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	return cues, nil
+}
+
+// parseTiming parses "start --> end" line, anything after end timestamp is cue settings.
+func parseTiming(line string, timestamp *regexp.Regexp) (time.Duration, time.Duration, error) {
+	startText, rest, _ := strings.Cut(line, "-->")
+	endFields := strings.Fields(rest)
+	if len(endFields) == 0 {
+		return 0, 0, fmt.Errorf("cue has no end time")
+	}
+
+	start, ok := parseTimestamp(strings.TrimSpace(startText), timestamp)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid cue start time %q", strings.TrimSpace(startText))
+	}
+	end, ok := parseTimestamp(endFields[0], timestamp)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid cue end time %q", endFields[0])
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("cue must end after it starts")
+	}
+	return start, end, nil
+}
+
+func parseTimestamp(text string, timestamp *regexp.Regexp) (time.Duration, bool) {
+	parts := timestamp.FindStringSubmatch(text)
+	if parts == nil {
+		return 0, false
+	}
+
+	var hours int64
+	if parts[1] != "" {
+		var err error
+		hours, err = strconv.ParseInt(parts[1], 10, 16)
+		if err != nil {
+			return 0, false
+		}
+	}
+	minutes, _ := strconv.ParseInt(parts[2], 10, 64)
+	seconds, _ := strconv.ParseInt(parts[3], 10, 64)
+	milliseconds, _ := strconv.ParseInt(parts[4], 10, 64)
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(milliseconds)*time.Millisecond, true
+}
+
+// srtTextToVTT keeps italic, bold and underline of SRT text, other tags are removed
+// and the rest is escaped, so it is read as text by WebVTT parsers.
+func srtTextToVTT(text string) string {
+	text = _assTagPattern.ReplaceAllString(text, "")
+
+	var builder strings.Builder
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	last := 0
+	for _, match := range _srtTagPattern.FindAllStringSubmatchIndex(text, -1) {
+		builder.WriteString(escape.Replace(text[last:match[0]]))
+		last = match[1]
+
+		name := strings.ToLower(text[match[2]:match[3]])
+		if name == "i" || name == "b" || name == "u" {
+			if strings.HasPrefix(text[match[0]:], "</") {
+				builder.WriteString("</" + name + ">")
+			} else {
+				builder.WriteString("<" + name + ">")
+			}
+		}
+	}
+	builder.WriteString(escape.Replace(text[last:]))
+
+	return builder.String()
+}
+
+func writeVTT(cues []Cue) string {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		builder.WriteString("\n")
+		builder.WriteString(formatTimestamp(cue.Start))
+		builder.WriteString(" --> ")
+		builder.WriteString(formatTimestamp(cue.End))
+		builder.WriteString("\n")
+		if cue.Text != "" {
+			builder.WriteString(cue.Text)
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+func formatTimestamp(d time.Duration) string {
+	milliseconds := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		milliseconds/3600000,
+		milliseconds/60000%60,
+		milliseconds/1000%60,
+		milliseconds%1000,
+	)
+}
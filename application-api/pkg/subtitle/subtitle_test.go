@@ -0,0 +1,136 @@
+package subtitle
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSRT(t *testing.T) {
+	tests := []struct {
+		name string
+		srt  string
+		want string
+	}{
+		{
+			name: "numbered cues",
+			srt:  "1\n00:00:01,000 --> 00:00:02,500\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nTwo\nlines\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHello\n\n00:00:03.000 --> 00:00:04.000\nTwo\nlines\n",
+		},
+		{
+			name: "byte order mark and CRLF",
+			srt:  "\ufeff1\r\n00:00:01,000 --> 00:00:02,000\r\nHello\r\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n",
+		},
+		{
+			name: "without cue numbers",
+			srt:  "00:00:01,000 --> 00:00:02,000\nHello\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n",
+		},
+		{
+			name: "dot separated milliseconds",
+			srt:  "1\n00:00:01.000 --> 00:00:02.000\nHello\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n",
+		},
+		{
+			name: "cues out of order",
+			srt:  "1\n00:00:05,000 --> 00:00:06,000\nLater\n\n2\n00:00:01,000 --> 00:00:02,000\nEarlier\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nEarlier\n\n00:00:05.000 --> 00:00:06.000\nLater\n",
+		},
+		{
+			name: "hours over a day",
+			srt:  "1\n100:00:00,000 --> 100:00:01,001\nLong\n",
+			want: "WEBVTT\n\n100:00:00.000 --> 100:00:01.001\nLong\n",
+		},
+		{
+			name: "formatting tags",
+			srt:  "1\n00:00:01,000 --> 00:00:02,000\n<i>italic</i> <B>bold</B> <font color=\"red\">red</font> {\\an8}top\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n<i>italic</i> <b>bold</b> red top\n",
+		},
+		{
+			name: "escaped text",
+			srt:  "1\n00:00:01,000 --> 00:00:02,000\nTom & Jerry -> a < b\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nTom &amp; Jerry -&gt; a &lt; b\n",
+		},
+		{
+			name: "cue without text",
+			srt:  "1\n00:00:01,000 --> 00:00:02,000\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subtitles, err := Parse([]byte(tt.srt))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := subtitles.VTT(); got != tt.want {
+				t.Errorf("VTT() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVTT(t *testing.T) {
+	vtt := "WEBVTT - lesson\nKind: captions\n\nSTYLE\n::cue { color: yellow }\n\nNOTE written by hand\n\n" +
+		"intro\n00:01.000 --> 00:02.000 align:start\n<v Teacher>Hello &amp; welcome</v>\n\n" +
+		"01:00:00.000 --> 01:00:01.000\nBye"
+
+	subtitles, err := Parse([]byte(vtt))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := subtitles.VTT(), vtt+"\n"; got != want {
+		t.Errorf("VTT() = %q, want uploaded document %q", got, want)
+	}
+
+	want := []Cue{
+		{Start: time.Second, End: 2 * time.Second, Text: "<v Teacher>Hello &amp; welcome</v>"},
+		{Start: time.Hour, End: time.Hour + time.Second, Text: "Bye"},
+	}
+	if len(subtitles.Cues) != len(want) {
+		t.Fatalf("Cues = %+v, want %+v", subtitles.Cues, want)
+	}
+	for i := range want {
+		if subtitles.Cues[i] != want[i] {
+			t.Errorf("Cues[%d] = %+v, want %+v", i, subtitles.Cues[i], want[i])
+		}
+	}
+	if got, want := subtitles.Cues[0].PlainText(), "Hello & welcome"; got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		line int
+	}{
+		{name: "neither format", data: "hello\nworld\n", line: 1},
+		{name: "no cues", data: "WEBVTT\n\nNOTE only\n", line: 4},
+		{name: "empty srt", data: "\n\n", line: 3},
+		{name: "invalid utf-8", data: "1\n00:00:01,000 --> 00:00:02,000\n\xff\n", line: 3},
+		{name: "srt cue number", data: "1\n00:00:01,000 --> 00:00:02,000\nA\n\nx\n00:00:03,000 --> 00:00:04,000\nB\n", line: 5},
+		{name: "srt missing timing", data: "1\nHello\n", line: 2},
+		{name: "srt invalid start", data: "1\n00:00:1,000 --> 00:00:02,000\nA\n", line: 2},
+		{name: "srt ends before start", data: "1\n00:00:02,000 --> 00:00:01,000\nA\n", line: 2},
+		{name: "srt without end", data: "1\n00:00:01,000 -->\nA\n", line: 2},
+		{name: "vtt comma milliseconds", data: "WEBVTT\n\n00:00:01,000 --> 00:00:02,000\nA\n", line: 3},
+		{name: "vtt header without blank line", data: "WEBVTT\n00:00:01.000 --> 00:00:02.000\nA\n", line: 2},
+		{name: "vtt unordered cues", data: "WEBVTT\n\n00:05.000 --> 00:06.000\nA\n\n00:01.000 --> 00:02.000\nB\n", line: 6},
+		{name: "vtt arrow in text", data: "WEBVTT\n\n00:01.000 --> 00:02.000\nA --> B\n", line: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			var syntaxError *SyntaxError
+			if !errors.As(err, &syntaxError) {
+				t.Fatalf("Parse() error = %v, want SyntaxError", err)
+			}
+			if syntaxError.Line != tt.line {
+				t.Errorf("Parse() error = %v, want line %d", err, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// _rfcSecret is base32 of ASCII "12345678901234567890", the SHA1 key of RFC 6238 test vectors.
+const _rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, codes are the last six of eight digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(_rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	got, err := Code("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", Step(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("Code() = %s, %v, want 287082", got, err)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	_, err := Code("not base32!", 1)
+	if err == nil {
+		t.Error("Code() error = nil, want error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		skew     int
+		wantStep int64
+		wantOk   bool
+	}{
+		{name: "current step", code: "050471", at: now, skew: 1, wantStep: Step(now), wantOk: true},
+		{name: "spaces are ignored", code: "050 471", at: now, skew: 1, wantStep: Step(now), wantOk: true},
+		{name: "previous step within skew", code: "050471", at: now.Add(Period), skew: 1, wantStep: Step(now), wantOk: true},
+		{name: "next step within skew", code: "050471", at: now.Add(-Period), skew: 1, wantStep: Step(now), wantOk: true},
+		{name: "outside of skew", code: "050471", at: now.Add(2 * Period), skew: 1},
+		{name: "no skew", code: "050471", at: now.Add(Period), skew: 0},
+		{name: "wrong code", code: "000000", at: now, skew: 1},
+		{name: "wrong length", code: "50471", at: now, skew: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(_rfcSecret, tt.code, tt.at, tt.skew)
+			if ok != tt.wantOk || step != tt.wantStep {
+				t.Errorf("Validate() = %d, %t, want %d, %t", step, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if _, err := Code(secret, 1); err != nil {
+		t.Errorf("Code() of generated secret error = %v", err)
+	}
+}